@@ -20,6 +20,9 @@ var v2Codec = codec.BC
 
 const V2String = "2.0"
 
+//go:generate go run github.com/icon-project/goloop/cmd/codecgen
+
+//codec:generate
 type V2HeaderFormat struct {
 	Version                int
 	Height                 int64
@@ -32,109 +35,15 @@ type V2HeaderFormat struct {
 	NormalTransactionsHash []byte
 	LogsBloom              []byte
 	Result                 []byte
-	NSFilter               []byte
-}
-
-func (bh *V2HeaderFormat) RLPEncodeSelf(e codec.Encoder) error {
-	if bh.NSFilter == nil {
-		return e.EncodeListOf(
-			bh.Version,
-			bh.Height,
-			bh.Timestamp,
-			bh.Proposer,
-			bh.PrevID,
-			bh.VotesHash,
-			bh.NextValidatorsHash,
-			bh.PatchTransactionsHash,
-			bh.NormalTransactionsHash,
-			bh.LogsBloom,
-			bh.Result,
-		)
-	}
-	return e.EncodeListOf(
-		bh.Version,
-		bh.Height,
-		bh.Timestamp,
-		bh.Proposer,
-		bh.PrevID,
-		bh.VotesHash,
-		bh.NextValidatorsHash,
-		bh.PatchTransactionsHash,
-		bh.NormalTransactionsHash,
-		bh.LogsBloom,
-		bh.Result,
-		bh.NSFilter,
-	)
-}
-
-func (bh *V2HeaderFormat) RLPDecodeSelf(d codec.Decoder) error {
-	d2, err := d.DecodeList()
-	if err != nil {
-		return err
-	}
-	cnt, err := d2.DecodeMulti(
-		&bh.Version,
-		&bh.Height,
-		&bh.Timestamp,
-		&bh.Proposer,
-		&bh.PrevID,
-		&bh.VotesHash,
-		&bh.NextValidatorsHash,
-		&bh.PatchTransactionsHash,
-		&bh.NormalTransactionsHash,
-		&bh.LogsBloom,
-		&bh.Result,
-		&bh.NSFilter,
-	)
-	if cnt == 11 && err == io.EOF {
-		bh.NSFilter = nil
-		return nil
-	}
-	return err
+	NSFilter               []byte `codec:"optional"`
 }
 
+//codec:generate
 type V2BodyFormat struct {
 	PatchTransactions  [][]byte
 	NormalTransactions [][]byte
 	Votes              []byte
-	BTPDigest          []byte
-}
-
-func (bb *V2BodyFormat) RLPEncodeSelf(e codec.Encoder) error {
-	if bb.BTPDigest == nil {
-		return e.EncodeListOf(
-			bb.PatchTransactions,
-			bb.NormalTransactions,
-			bb.Votes,
-		)
-	}
-	return e.EncodeListOf(
-		bb.PatchTransactions,
-		bb.NormalTransactions,
-		bb.Votes,
-		bb.BTPDigest,
-	)
-}
-
-func (bb *V2BodyFormat) RLPDecodeSelf(d codec.Decoder) error {
-	d2, err := d.DecodeList()
-	if err != nil {
-		return err
-	}
-	cnt, err := d2.DecodeMulti(
-		&bb.PatchTransactions,
-		&bb.NormalTransactions,
-		&bb.Votes,
-		&bb.BTPDigest,
-	)
-	if cnt == 3 && err == io.EOF {
-		bb.BTPDigest = nil
-		return nil
-	}
-	if err != nil {
-		return err
-	}
-	return nil
+	BTPDigest          []byte `codec:"optional"`
 }
 
 type blockV2 struct {
@@ -0,0 +1,156 @@
+// Code generated by codecgen; DO NOT EDIT.
+
+package block
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/icon-project/goloop/common/codec"
+)
+
+func (o *V2BodyFormat) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.PatchTransactions); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.NormalTransactions); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.Votes); err != nil {
+		return err
+	}
+	if o.BTPDigest != nil {
+		if err := e2.Encode(o.BTPDigest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *V2BodyFormat) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.PatchTransactions); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.NormalTransactions); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Votes); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.BTPDigest); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.BTPDigest = nil
+		return nil
+	}
+	return nil
+}
+
+func (o *V2HeaderFormat) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Version)); err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Height)); err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Timestamp)); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.Proposer); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.PrevID); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.VotesHash); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.NextValidatorsHash); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.PatchTransactionsHash); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.NormalTransactionsHash); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.LogsBloom); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.Result); err != nil {
+		return err
+	}
+	if o.NSFilter != nil {
+		if err := e2.Encode(o.NSFilter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *V2HeaderFormat) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int); err == nil {
+		o.Version = int(v)
+	} else {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int64); err == nil {
+		o.Height = v
+	} else {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int64); err == nil {
+		o.Timestamp = v
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Proposer); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.PrevID); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.VotesHash); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.NextValidatorsHash); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.PatchTransactionsHash); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.NormalTransactionsHash); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.LogsBloom); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Result); err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.NSFilter); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.NSFilter = nil
+		return nil
+	}
+	return nil
+}
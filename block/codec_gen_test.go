@@ -0,0 +1,174 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package block_test
+
+import (
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/codec/codectest"
+)
+
+// handwritten encoders replaced by the generated ones
+
+type legacyHeaderFormat block.V2HeaderFormat
+
+func (bh *legacyHeaderFormat) RLPEncodeSelf(e codec.Encoder) error {
+	if bh.NSFilter == nil {
+		return e.EncodeListOf(
+			bh.Version,
+			bh.Height,
+			bh.Timestamp,
+			bh.Proposer,
+			bh.PrevID,
+			bh.VotesHash,
+			bh.NextValidatorsHash,
+			bh.PatchTransactionsHash,
+			bh.NormalTransactionsHash,
+			bh.LogsBloom,
+			bh.Result,
+		)
+	}
+	return e.EncodeListOf(
+		bh.Version,
+		bh.Height,
+		bh.Timestamp,
+		bh.Proposer,
+		bh.PrevID,
+		bh.VotesHash,
+		bh.NextValidatorsHash,
+		bh.PatchTransactionsHash,
+		bh.NormalTransactionsHash,
+		bh.LogsBloom,
+		bh.Result,
+		bh.NSFilter,
+	)
+}
+
+func (bh *legacyHeaderFormat) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := d.DecodeList()
+	if err != nil {
+		return err
+	}
+	cnt, err := d2.DecodeMulti(
+		&bh.Version,
+		&bh.Height,
+		&bh.Timestamp,
+		&bh.Proposer,
+		&bh.PrevID,
+		&bh.VotesHash,
+		&bh.NextValidatorsHash,
+		&bh.PatchTransactionsHash,
+		&bh.NormalTransactionsHash,
+		&bh.LogsBloom,
+		&bh.Result,
+		&bh.NSFilter,
+	)
+	if cnt == 11 && err == io.EOF {
+		bh.NSFilter = nil
+		return nil
+	}
+	return err
+}
+
+type legacyBodyFormat block.V2BodyFormat
+
+func (bb *legacyBodyFormat) RLPEncodeSelf(e codec.Encoder) error {
+	if bb.BTPDigest == nil {
+		return e.EncodeListOf(
+			bb.PatchTransactions,
+			bb.NormalTransactions,
+			bb.Votes,
+		)
+	}
+	return e.EncodeListOf(
+		bb.PatchTransactions,
+		bb.NormalTransactions,
+		bb.Votes,
+		bb.BTPDigest,
+	)
+}
+
+func (bb *legacyBodyFormat) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := d.DecodeList()
+	if err != nil {
+		return err
+	}
+	cnt, err := d2.DecodeMulti(
+		&bb.PatchTransactions,
+		&bb.NormalTransactions,
+		&bb.Votes,
+		&bb.BTPDigest,
+	)
+	if cnt == 3 && err == io.EOF {
+		bb.BTPDigest = nil
+		return nil
+	}
+	return err
+}
+
+func assertFormatEquivalent(t *testing.T, obj, legacy interface{}, bs []byte) {
+	for _, c := range codectest.Codecs {
+		if bs == nil {
+			assert.Equal(t, c.MustMarshalToBytes(legacy), c.MustMarshalToBytes(obj))
+			continue
+		}
+		_, err := c.UnmarshalFromBytes(bs, obj)
+		_, err2 := c.UnmarshalFromBytes(bs, legacy)
+		if assert.Equal(t, err2 == nil, err == nil, "err=%v err2=%v", err, err2) && err == nil {
+			assert.Equal(t, c.MustMarshalToBytes(legacy), c.MustMarshalToBytes(obj))
+		}
+	}
+}
+
+func testFormatEquivalent(t *testing.T, seed int64, bs []byte) {
+	r := rand.New(rand.NewSource(seed))
+
+	h := new(block.V2HeaderFormat)
+	codectest.Fill(r, h)
+	assertFormatEquivalent(t, h, (*legacyHeaderFormat)(h), nil)
+	assertFormatEquivalent(t, new(block.V2HeaderFormat), new(legacyHeaderFormat), bs)
+
+	b := new(block.V2BodyFormat)
+	codectest.Fill(r, b)
+	assertFormatEquivalent(t, b, (*legacyBodyFormat)(b), nil)
+	assertFormatEquivalent(t, new(block.V2BodyFormat), new(legacyBodyFormat), bs)
+}
+
+func TestV2Format_CodecGen(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var obj interface{} = new(block.V2HeaderFormat)
+		if i%2 == 1 {
+			obj = new(block.V2BodyFormat)
+		}
+		codectest.Fill(r, obj)
+		bs := codec.BC.MustMarshalToBytes(obj)
+		testFormatEquivalent(t, r.Int63(), bs)
+		testFormatEquivalent(t, r.Int63(), bs[:r.Intn(len(bs))])
+	}
+}
+
+func FuzzV2Format_CodecGen(f *testing.F) {
+	f.Add(int64(0), []byte{})
+	f.Fuzz(testFormatEquivalent)
+}
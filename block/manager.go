@@ -70,6 +70,7 @@ type ServiceManager interface {
 	SendTransactionAndWait(result []byte, height int64, tx interface{}) ([]byte, <-chan interface{}, error)
 	WaitTransactionResult(id []byte) (<-chan interface{}, error)
 	ExportResult(result []byte, vh []byte, dst db.Database) error
+	ExportState(result []byte, vh []byte, dst db.Database) error
 	BTPSectionFromResult(result []byte) (module.BTPSection, error)
 	NextProofContextMapFromResult(result []byte) (module.BTPProofContextMap, error)
 }
@@ -129,6 +130,8 @@ type manager struct {
 	handlers       handlerList
 	activeHandlers handlerList
 	handlerContext handlerContext
+
+	pruner *pruner
 }
 
 type handlerList []base.BlockHandler
@@ -630,6 +633,7 @@ func NewManager(
 		if err := m.initializePCM(); err != nil {
 			return nil, err
 		}
+		m.startPruner(chain)
 		return m, nil
 	} else if err != nil {
 		return nil, err
@@ -650,7 +654,15 @@ func NewManager(
 
 	var cid int
 	if gBlock, err := m.getBlockByHeight(0); err == nil {
-		if tx, err := gBlock.NormalTransactions().Get(0); err == nil {
+		if gt, _ := m.chain.GenesisStorage().Type(); gt == module.GenesisForked {
+			// forked genesis block has no transaction, but its result
+			// already has the chain ID.
+			if id, err := m.sm.GetChainID(gBlock.Result()); err != nil {
+				return nil, err
+			} else {
+				cid = int(id)
+			}
+		} else if tx, err := gBlock.NormalTransactions().Get(0); err == nil {
 			if gtx, ok := tx.(transaction.GenesisTransaction); ok {
 				cid = gtx.CID()
 			} else {
@@ -706,6 +718,7 @@ func NewManager(
 	if err := m.initializePCM(); err != nil {
 		return nil, err
 	}
+	m.startPruner(chain)
 	return m, nil
 }
 
@@ -733,6 +746,10 @@ func (m *manager) initializePCM() error {
 }
 
 func (m *manager) Term() {
+	if m.pruner != nil {
+		m.pruner.Stop()
+	}
+
 	m.syncer.begin()
 	defer m.syncer.end()
 
@@ -866,10 +883,44 @@ func (m *manager) finalizeGenesis() error {
 		return err
 	case module.GenesisPruned:
 		return errors.InvalidStateError.Errorf("start with PrunedGenesis without reset")
+	case module.GenesisForked:
+		return m.finalizeForkedGenesis()
 	}
 	return errors.InvalidStateError.Errorf("InvalidGenesisType(type=%d)", gt)
 }
 
+// finalizeForkedGenesis imports the state in the genesis storage, then
+// finalizes the genesis block without transactions on the state.
+func (m *manager) finalizeForkedGenesis() error {
+	if m.finalized != nil {
+		return errors.InvalidStateError.New("InvalidState")
+	}
+	storage := m.chain.GenesisStorage()
+	g, err := gs.NewForkedGenesis(storage.Genesis())
+	if err != nil {
+		return transaction.InvalidGenesisError.Wrap(err, "InvalidForkedGenesis")
+	}
+	if int(g.NID.Value) != m.chain.NID() {
+		return errors.InvalidNetworkError.Errorf(
+			"Invalid Network ID config=%#x genesis=%#x", m.chain.NID(), g.NID.Value)
+	}
+	if err := m.sm.ImportResult(g.Result, g.Validators, gs.NewDatabaseWithStorage(storage)); err != nil {
+		return transaction.InvalidGenesisError.Wrap(err, "FailToImportForkedState")
+	}
+	vl := m.sm.ValidatorListFromHash(g.Validators)
+	if vl == nil {
+		return transaction.InvalidGenesisError.Errorf("InvalidValidators(hash=%#x)", g.Validators)
+	}
+	mtr, err := m.sm.CreateInitialTransition(g.Result, vl)
+	if err != nil {
+		return err
+	}
+	txl := m.sm.TransactionListFromSlice(nil, m.activeHandlers.last().Version())
+	_, err = m.finalizeInitialBlock(mtr, txl, nil, g.Timestamp.Value,
+		m.chain.CommitVoteSetDecoder()(nil))
+	return err
+}
+
 func (m *manager) finalizeGenesisBlock(
 	proposer module.Address,
 	timestamp int64,
@@ -883,8 +934,6 @@ func (m *manager) finalizeGenesisBlock(
 	if err != nil {
 		return nil, err
 	}
-	in := newInitialTransition(mtr, m.chainContext)
-	ch := make(chan error)
 	gtxbs := m.chain.Genesis()
 	gtx, err := m.sm.GenesisTransactionFromBytes(
 		gtxbs, m.activeHandlers.last().Version(),
@@ -899,9 +948,23 @@ func (m *manager) finalizeGenesisBlock(
 	gtxl := m.sm.TransactionListFromSlice(
 		[]module.Transaction{gtx}, m.activeHandlers.last().Version(),
 	)
+	return m.finalizeInitialBlock(mtr, gtxl, proposer, timestamp, votes)
+}
+
+// finalizeInitialBlock finalizes the genesis block executing the
+// transactions on the initial transition.
+func (m *manager) finalizeInitialBlock(
+	mtr module.Transition,
+	txl module.TransactionList,
+	proposer module.Address,
+	timestamp int64,
+	votes module.CommitVoteSet,
+) (block module.Block, err error) {
+	in := newInitialTransition(mtr, m.chainContext)
+	ch := make(chan error)
 	m.syncer.begin()
 	csi := common.NewConsensusInfo(nil, nil, nil)
-	gtr, err := in.transit(gtxl, common.NewBlockInfo(0, timestamp), csi, &channelingCB{ch: ch}, true)
+	gtr, err := in.transit(txl, common.NewBlockInfo(0, timestamp), csi, &channelingCB{ch: ch}, true)
 	if err != nil {
 		m.syncer.end()
 		return nil, err
@@ -18,6 +18,7 @@ package block_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"testing"
@@ -26,11 +27,17 @@ import (
 
 	"github.com/icon-project/goloop/block"
 	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
 	"github.com/icon-project/goloop/service/platform/basic"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
 	"github.com/icon-project/goloop/test"
 )
 
@@ -508,6 +515,61 @@ func TestManager_ExportGenesis(t *testing.T) {
 	assert.EqualValues(blk.ID(), blk2.ID())
 }
 
+func TestManager_ForkedGenesis(t *testing.T) {
+	assert := assert.New(t)
+
+	nd := test.NewNode(t)
+	defer nd.Close()
+
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	blk := nd.GetLastBlock()
+
+	const cid = 0x99
+	w2 := wallet.New()
+	v, err := state.ValidatorFromAddress(w2.Address())
+	assert.NoError(err)
+	gb := newGenesisBuffer()
+	result, vh, err := service.ForkResult(nd.Chain.Database(), nd.Platform,
+		blk.Result(), nd.Chain.NID(), cid, []module.Validator{v},
+		gs.NewDatabaseWithWriter(gb))
+	assert.NoError(err)
+	fg := &gs.ForkedGenesis{
+		CID:        common.HexInt32{Value: cid},
+		NID:        common.HexInt32{Value: int32(nd.Chain.NID())},
+		Timestamp:  common.HexInt64{Value: blk.Timestamp()},
+		Result:     result,
+		Validators: vh,
+		Source: gs.ForkSource{
+			CID:    common.HexInt32{Value: int32(nd.Chain.CID())},
+			NID:    common.HexInt32{Value: int32(nd.Chain.NID())},
+			Height: common.HexInt64{Value: blk.Height()},
+			Block:  blk.ID(),
+		},
+	}
+	js, err := json.Marshal(fg)
+	assert.NoError(err)
+	assert.NoError(gb.WriteGenesis(js))
+	storage := newGenesisStorage(module.GenesisForked, cid, nd.Chain.NID(), 0, gb)
+
+	nd2 := test.NewNode(t, test.UseGenesisStorage(storage), test.UseWallet(w2))
+	defer nd2.Close()
+	blk2 := nd2.GetLastBlock()
+	assert.EqualValues(0, blk2.Height())
+	assert.Equal(blk.Timestamp(), blk2.Timestamp())
+	assert.Nil(blk2.NormalTransactions().Hash())
+	assert.EqualValues(vh, blk2.NextValidatorsHash())
+
+	wss, err := service.NewWorldSnapshot(nd2.Chain.Database(), nd2.Platform,
+		blk2.Result(), blk2.NextValidators())
+	assert.NoError(err)
+	as := scoredb.NewStateStoreWith(wss.GetAccountSnapshot(state.SystemID))
+	assert.EqualValues(cid, scoredb.NewVarDB(as, state.VarChainID).Int64())
+
+	nd2.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	assert.EqualValues(1, nd2.GetLastBlock().Height())
+}
+
 func TestManager_ExportBlocks(t *testing.T) {
 	assert := assert.New(t)
 	nd := test.NewNode(t)
@@ -0,0 +1,424 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package block
+
+import (
+	"time"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	keyPruningStatus = "block.pruning"
+
+	ConfigPruningInterval = 200 * time.Millisecond
+	// ConfigPruningTrackLimit is the maximum number of blocks tracked in
+	// a step.
+	ConfigPruningTrackLimit = 100
+	// ConfigPruningCommitSize is the number of changed counts to be
+	// written while it tracks the whole state at first.
+	ConfigPruningCommitSize = 100000
+)
+
+// pruningStatus is the status of the pruning stored in the database.
+// References of the blocks in [Base, Tracked] are tracked. State and
+// Receipts are the lowest heights keeping their states and receipts.
+type pruningStatus struct {
+	Base     int64
+	Tracked  int64
+	State    int64
+	Receipts int64
+}
+
+// pruner releases the states and the receipts of old blocks in the
+// background following the retention policy. It tracks references to the
+// entries of the blocks finalized, then it deletes the entries not
+// referenced any more by releasing references of old blocks.
+//
+// Releasing runs exclusively with other operations of the block manager,
+// and steps are throttled by the interval between them.
+type pruner struct {
+	m        *manager
+	policy   module.RetentionPolicy
+	interval time.Duration
+	log      log.Logger
+	metric   *metric.PruningMetric
+	status   pruningStatus
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// GetPruningStatus returns the status of the pruning by the retention
+// policy. States of the blocks in [base, state) and receipts of the blocks
+// in [base, receipts) are deleted. It returns zeros if it's never started.
+func GetPruningStatus(dbase db.Database) (base, state, receipts int64, err error) {
+	bk, err := db.NewCodedBucket(dbase, db.ChainProperty, nil)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	var st pruningStatus
+	if err := bk.Get(db.Raw(keyPruningStatus), &st); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return 0, 0, 0, nil
+		}
+		return 0, 0, 0, err
+	}
+	return st.Base, st.State, st.Receipts, nil
+}
+
+func (m *manager) exportReceipts(ctx *merkle.CopyContext, blk module.Block) error {
+	for _, g := range []module.TransactionGroup{
+		module.TransactionGroupPatch, module.TransactionGroupNormal,
+	} {
+		rl, err := m.sm.ReceiptListFromResult(blk.Result(), g)
+		if err != nil {
+			return err
+		}
+		txresult.NewReceiptListWithBuilder(ctx.Builder(), rl.Hash())
+	}
+	return ctx.Run()
+}
+
+func (m *manager) exportState(ctx *merkle.CopyContext, blk module.Block) error {
+	return m.sm.ExportState(blk.Result(), blk.NextValidatorsHash(), ctx.TargetDB())
+}
+
+// trackBlock adds references of the block. References of the block itself
+// and its transactions are never released.
+func (m *manager) trackBlock(ctx *merkle.CopyContext, blk module.Block) error {
+	if err := m._export(blk, ctx, exportBlock|exportTransaction); err != nil {
+		return err
+	}
+	if len(blk.Result()) == 0 {
+		return nil
+	}
+	if err := m.exportState(ctx, blk); err != nil {
+		return err
+	}
+	return m.exportReceipts(ctx, blk)
+}
+
+func (p *pruner) newContext(release bool) (*trie_manager.ReferenceTracker, *merkle.CopyContext, error) {
+	tracker, err := trie_manager.NewReferenceTracker(p.m.db(), release)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tracker, merkle.NewCopyContext(p.m.db(), tracker), nil
+}
+
+func (p *pruner) saveStatus() error {
+	bk, err := p.m.bucketFor(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return bk.Set(db.Raw(keyPruningStatus), &p.status)
+}
+
+func (p *pruner) checkStop() error {
+	select {
+	case <-p.stop:
+		return errors.ErrInterrupted
+	default:
+		return nil
+	}
+}
+
+// prepare loads the status. If it's not available, then it tracks the
+// whole state of the last block, and validators of the previous blocks.
+// It doesn't lock the manager, because nothing is released until it's
+// done.
+func (p *pruner) prepare() error {
+	last, err := p.m.GetLastBlock()
+	if err != nil {
+		return err
+	}
+	bk, err := p.m.bucketFor(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	err = bk.Get(db.Raw(keyPruningStatus), &p.status)
+	if err == nil && p.status.Tracked <= last.Height() {
+		p.log.Infof("PRUNING resume status=%+v", p.status)
+		return nil
+	} else if err != nil && !errors.NotFoundError.Equals(err) {
+		return err
+	}
+
+	height := last.Height()
+	p.log.Infof("PRUNING start tracking height=%d", height)
+	if pbk, err := p.m.db().GetBucket(db.ChainProperty); err != nil {
+		return err
+	} else if err := pbk.Delete([]byte(keyPruningStatus)); err != nil {
+		return err
+	}
+	if err := trie_manager.ClearReferences(p.m.db()); err != nil {
+		return err
+	}
+	tracker, ctx, err := p.newContext(false)
+	if err != nil {
+		return err
+	}
+	ctx.SetProgressCallback(func(height int64, resolved, unresolved int) error {
+		if err := p.checkStop(); err != nil {
+			return err
+		}
+		if tracker.Changes() >= ConfigPruningCommitSize {
+			_, err := tracker.Commit()
+			return err
+		}
+		return nil
+	})
+	if err := p.m.trackBlock(ctx, last); err != nil {
+		return err
+	}
+
+	// blocks are kept, so validators of the blocks should be kept.
+	validators := make(map[string]bool)
+	for h := height - 1; h >= 0; h-- {
+		if err := p.checkStop(); err != nil {
+			return err
+		}
+		blk, err := p.m.GetBlockByHeight(h)
+		if errors.NotFoundError.Equals(err) {
+			break
+		} else if err != nil {
+			return err
+		}
+		if vh := blk.NextValidatorsHash(); len(vh) > 0 {
+			validators[string(vh)] = true
+		}
+	}
+	for vh := range validators {
+		if err := ctx.Copy(db.BytesByHash, []byte(vh)); err != nil {
+			return err
+		}
+	}
+	if _, err := tracker.Commit(); err != nil {
+		return err
+	}
+
+	p.status = pruningStatus{
+		Base:     height,
+		Tracked:  height,
+		State:    height,
+		Receipts: height,
+	}
+	if err := p.saveStatus(); err != nil {
+		return err
+	}
+	p.log.Infof("PRUNING tracked height=%d validators=%d", height, len(validators))
+	return nil
+}
+
+// track adds references of the block. On failure, it may be tracked again,
+// which only leaves some garbage.
+func (p *pruner) track(height int64) error {
+	blk, err := p.m.GetBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	tracker, ctx, err := p.newContext(false)
+	if err != nil {
+		return err
+	}
+	if err := p.m.trackBlock(ctx, blk); err != nil {
+		return err
+	}
+	if _, err := tracker.Commit(); err != nil {
+		return err
+	}
+	p.status.Tracked = height
+	return p.saveStatus()
+}
+
+// release releases references of the state or the receipts of the block,
+// and deletes the entries not referenced any more. The status is stored
+// before the deletion, so the entries are never released twice.
+func (p *pruner) release(height int64, receipts bool) (int, error) {
+	blk, err := p.m.getBlockByHeight(height)
+	if err != nil {
+		return 0, err
+	}
+	tracker, ctx, err := p.newContext(true)
+	if err != nil {
+		return 0, err
+	}
+	if len(blk.Result()) > 0 {
+		if receipts {
+			err = p.m.exportReceipts(ctx, blk)
+		} else {
+			err = p.m.exportState(ctx, blk)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if receipts {
+		p.status.Receipts = height + 1
+	} else {
+		p.status.State = height + 1
+	}
+	if err := p.saveStatus(); err != nil {
+		return 0, err
+	}
+	return tracker.Commit()
+}
+
+// lastFinalized returns the height of the last finalized block. It returns
+// false if the manager is terminated.
+func (p *pruner) lastFinalized() (int64, bool) {
+	m := p.m
+	m.syncer.begin()
+	defer m.syncer.end()
+
+	if !m.running || m.finalized == nil {
+		return 0, false
+	}
+	return m.finalized.block.Height(), true
+}
+
+// releaseOld releases the state and the receipts of one old block if all
+// the blocks finalized are tracked. It returns false if the manager is
+// terminated.
+func (p *pruner) releaseOld() (int, bool, error) {
+	m := p.m
+	m.syncer.begin()
+	defer m.syncer.end()
+
+	if !m.running || m.finalized == nil {
+		return 0, false, nil
+	}
+
+	// states of the blocks finalized but not tracked may have entries
+	// released, so it releases only after tracking all of them.
+	last := m.finalized.block.Height()
+	if p.status.Tracked != last {
+		return 0, true, nil
+	}
+	deleted := 0
+	if p.policy.State > 0 && p.status.State <= last-p.policy.State {
+		n, err := p.release(p.status.State, false)
+		if err != nil {
+			return 0, false, errors.Wrapf(err, "FailToReleaseState(height=%d)", p.status.State)
+		}
+		deleted += n
+	}
+	if p.policy.Receipts > 0 && p.status.Receipts <= last-p.policy.Receipts {
+		n, err := p.release(p.status.Receipts, true)
+		if err != nil {
+			return 0, false, errors.Wrapf(err, "FailToReleaseReceipts(height=%d)", p.status.Receipts)
+		}
+		deleted += n
+	}
+	return deleted, true, nil
+}
+
+// step tracks the blocks finalized, then it releases the state and the
+// receipts of one old block. Blocks finalized are never changed, so it
+// tracks them without locking the manager. It returns false if the manager
+// is terminated.
+func (p *pruner) step() (bool, error) {
+	last, ok := p.lastFinalized()
+	if !ok {
+		return false, nil
+	}
+	start := time.Now()
+	for cnt := 0; p.status.Tracked < last && cnt < ConfigPruningTrackLimit; cnt++ {
+		if err := p.checkStop(); err != nil {
+			return true, nil
+		}
+		if err := p.track(p.status.Tracked + 1); err != nil {
+			return false, errors.Wrapf(err, "FailToTrack(height=%d)", p.status.Tracked+1)
+		}
+	}
+
+	deleted, ok, err := p.releaseOld()
+	if err != nil || !ok {
+		return false, err
+	}
+	d := time.Since(start)
+	p.metric.OnStep(p.status.Tracked, p.status.State, p.status.Receipts, deleted, d)
+	if deleted > 0 {
+		p.log.Debugf("PRUNING step status=%+v deleted=%d duration=%s", p.status, deleted, d)
+	}
+	return true, nil
+}
+
+func (p *pruner) run() {
+	defer close(p.done)
+
+	if err := p.prepare(); err != nil {
+		if !errors.InterruptedError.Equals(err) {
+			p.log.Errorf("FAIL to prepare pruning err=%+v", err)
+		}
+		return
+	}
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(p.interval):
+		}
+		if ok, err := p.step(); err != nil {
+			p.log.Errorf("FAIL to prune err=%+v", err)
+			return
+		} else if !ok {
+			return
+		}
+	}
+}
+
+// Stop stops the pruner, and waits for it. It shouldn't be called while
+// the manager is locked.
+func (p *pruner) Stop() {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	<-p.done
+}
+
+func (m *manager) startPruner(chain module.Chain) {
+	policy := chain.RetentionPolicy()
+	if !policy.Enabled() {
+		return
+	}
+	interval := ConfigPruningInterval
+	if policy.Interval > 0 {
+		interval = time.Duration(policy.Interval) * time.Millisecond
+	}
+	m.pruner = &pruner{
+		m:        m,
+		policy:   *policy,
+		interval: interval,
+		log:      m.log,
+		metric:   metric.NewPruningMetric(chain.MetricContext()),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	m.log.Infof("PRUNING policy=%+v", *policy)
+	go m.pruner.run()
+}
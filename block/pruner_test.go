@@ -0,0 +1,131 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package block_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/test"
+)
+
+type testPruningStatus struct {
+	Base     int64
+	State    int64
+	Receipts int64
+}
+
+func getPruningStatus(t *testing.T, dbase db.Database) *testPruningStatus {
+	base, state, receipts, err := block.GetPruningStatus(dbase)
+	assert.NoError(t, err)
+	return &testPruningStatus{base, state, receipts}
+}
+
+func waitPruningStatus(t *testing.T, dbase db.Database, state, receipts int64) {
+	for i := 0; i < 500; i++ {
+		st := getPruningStatus(t, dbase)
+		if st.State == state && st.Receipts == receipts {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.FailNow(t, "pruning isn't done", "status=%+v", getPruningStatus(t, dbase))
+}
+
+// finalizeBlocksForPruning finalizes blocks having the transactions as many
+// as the height, so the states and the receipts of the blocks are different.
+func finalizeBlocksForPruning(t *testing.T, nd *test.Node, to int64) {
+	for h := nd.LastBlock.Height() + 1; h <= to; h++ {
+		for i := int64(0); i < h; i++ {
+			v := fmt.Sprintf("%d-%d", h, i)
+			_, err := nd.SM.SendTransaction(nil, 0, test.NewTx().SetVarTest(&v).String())
+			assert.NoError(t, err)
+		}
+		nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+		assert.EqualValues(t, h, nd.LastBlock.Height())
+	}
+}
+
+func hasMerkleNode(t *testing.T, dbase db.Database, hash []byte) bool {
+	bk, err := dbase.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	ok, err := bk.Has(hash)
+	assert.NoError(t, err)
+	return ok
+}
+
+// assertPruned checks the states and the receipts of the blocks in
+// [from, to]. States below stateFrom and receipts below receiptsFrom are
+// deleted, and blocks are kept.
+func assertPruned(t *testing.T, nd *test.Node, from, to, stateFrom, receiptsFrom int64) {
+	dbase := nd.Chain.Database()
+	for h := from; h <= to; h++ {
+		blk, err := nd.BM.GetBlockByHeight(h)
+		assert.NoError(t, err)
+		wss, err := service.NewWorldSnapshot(dbase, nd.Platform, blk.Result(), nil)
+		assert.NoError(t, err)
+		rl, err := nd.SM.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+		assert.NoError(t, err)
+
+		assert.Equal(t, h >= stateFrom, hasMerkleNode(t, dbase, wss.StateHash()), "state height=%d", h)
+		assert.Equal(t, h >= receiptsFrom, hasMerkleNode(t, dbase, rl.Hash()), "receipts height=%d", h)
+		if h >= stateFrom {
+			as := scoredb.NewStateStoreWith(wss.GetAccountSnapshot(state.SystemID))
+			assert.Equal(t, fmt.Sprintf("%d-%d", h-1, h-2),
+				scoredb.NewVarDB(as, test.VarTest).String())
+		}
+		if h >= receiptsFrom {
+			_, err := rl.Get(int(h - 2))
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestPruner_RetentionPolicy(t *testing.T) {
+	dbase := db.NewMapDB()
+	w := wallet.New()
+	policy := &module.RetentionPolicy{State: 3, Receipts: 5, Interval: 1}
+
+	nd := test.NewNode(t, test.UseDB(dbase), test.UseWallet(w),
+		test.UseRetentionPolicy(policy))
+	finalizeBlocksForPruning(t, nd, 12)
+	waitPruningStatus(t, dbase, 10, 8)
+	assertPruned(t, nd, 2, 12, 10, 8)
+	st := getPruningStatus(t, dbase)
+	nd.Close()
+
+	// it resumes with the status after restart
+	nd = test.NewNode(t, test.UseDB(dbase), test.UseWallet(w),
+		test.UseRetentionPolicy(policy))
+	defer nd.Close()
+	assert.EqualValues(t, 12, nd.LastBlock.Height())
+	finalizeBlocksForPruning(t, nd, 16)
+	waitPruningStatus(t, dbase, 14, 12)
+	assertPruned(t, nd, 2, 16, 14, 12)
+	assert.Equal(t, st.Base, getPruningStatus(t, dbase).Base)
+}
@@ -44,6 +44,10 @@ func (c *testChain) MaxWaitTimeout() time.Duration {
 	return 0
 }
 
+func (c *testChain) RetentionPolicy() *module.RetentionPolicy {
+	return nil
+}
+
 func (c *testChain) Database() db.Database {
 	return c.database
 }
@@ -17,6 +17,7 @@
 package base
 
 import (
+	"encoding/json"
 	"math/big"
 
 	"github.com/icon-project/goloop/common/db"
@@ -46,6 +47,19 @@ type Platform interface {
 	Term()
 }
 
+// ConfigurablePlatform is implemented by Platform accepting platform
+// specific configuration of the chain.
+type ConfigurablePlatform interface {
+	Configure(cfg json.RawMessage) error
+}
+
+// ResultFinalizationHandler is implemented by Platform handling the
+// results finalized by the chain. It's not called for the results
+// synchronized from the other nodes.
+type ResultFinalizationHandler interface {
+	OnResultFinalization(height int64, ess state.ExtensionSnapshot, logger log.Logger)
+}
+
 type ExecutionResult interface {
 	PatchReceipts() module.ReceiptList
 	NormalReceipts() module.ReceiptList
@@ -24,6 +24,7 @@ import (
 	"github.com/icon-project/goloop/server"
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/archive"
 	"github.com/icon-project/goloop/service/eeproxy"
 	"github.com/icon-project/goloop/service/state"
 )
@@ -252,6 +253,14 @@ func (c *singleChain) TransactionTimeout() time.Duration {
 	return ConfigDefaultTxTimeout
 }
 
+func (c *singleChain) ConsensusTimeouts() *module.ConsensusTimeouts {
+	return c.cfg.ConsensusTimeouts
+}
+
+func (c *singleChain) RetentionPolicy() *module.RetentionPolicy {
+	return c.cfg.Retention
+}
+
 func (c *singleChain) ChildrenLimit() int {
 	if c.cfg.ChildrenLimit != nil && *c.cfg.ChildrenLimit >= 0 {
 		return *c.cfg.ChildrenLimit
@@ -435,6 +444,17 @@ func (c *singleChain) prepareDatabase(chainDir string) error {
 		_ = cdb.Close()
 		return errors.Wrap(err, "FailToAttachAPIInfoCache")
 	}
+	if c.cfg.Archive {
+		adb, err := archive.Attach(cdb)
+		if err != nil {
+			_ = cdb.Close()
+			return errors.Wrap(err, "FailToAttachArchiveIndex")
+		}
+		if _, _, ok := archive.IndexOf(adb).Range(); !ok {
+			c.logger.Warnf("ARCHIVE index is empty, run the archive task to build it")
+		}
+		cdb = adb
+	}
 	c.database = cdb
 	return nil
 }
@@ -470,6 +490,14 @@ func (c *singleChain) _init() error {
 	} else {
 		c.plt = plt
 	}
+	if len(c.cfg.PlatformConfig) > 0 {
+		if cp, ok := c.plt.(base.ConfigurablePlatform); !ok {
+			return errors.IllegalArgumentError.Errorf(
+				"NotConfigurablePlatform(platform=%s)", c.cfg.Platform)
+		} else if err := cp.Configure(c.cfg.PlatformConfig); err != nil {
+			return err
+		}
+	}
 
 	if err := c.prepareDatabase(chainDir); err != nil {
 		return err
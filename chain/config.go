@@ -35,7 +35,8 @@ type Config struct {
 	NID    int    `json:"nid"`
 	DBType string `json:"db_type"`
 
-	Platform string `json:"platform,omitempty"`
+	Platform       string          `json:"platform,omitempty"`
+	PlatformConfig json.RawMessage `json:"platform_config,omitempty"`
 
 	// static
 	SeedAddr         string `json:"seed_addr"`
@@ -49,6 +50,10 @@ type Config struct {
 	ChildrenLimit    *int   `json:"children_limit,omitempty"`
 	NephewsLimit     *int   `json:"nephews_limit,omitempty"`
 	ValidateTxOnSend bool   `json:"validate_tx_on_send,omitempty"`
+	Archive          bool   `json:"archive,omitempty"`
+
+	ConsensusTimeouts *module.ConsensusTimeouts `json:"consensus_timeouts,omitempty"`
+	Retention         *module.RetentionPolicy   `json:"retention,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+// ForkSource is the block of the chain where the state is forked from.
+type ForkSource struct {
+	CID    common.HexInt32 `json:"cid"`
+	NID    common.HexInt32 `json:"nid"`
+	Height common.HexInt64 `json:"height"`
+	Block  common.HexBytes `json:"block"`
+}
+
+// ForkedGenesis starts a new chain with the state of the other chain.
+// The state and the validators are stored in the genesis storage, and
+// the genesis block is made with them without transactions.
+type ForkedGenesis struct {
+	CID        common.HexInt32 `json:"cid"`
+	NID        common.HexInt32 `json:"nid"`
+	Timestamp  common.HexInt64 `json:"timestamp"`
+	Result     common.HexBytes `json:"result"`
+	Validators common.HexBytes `json:"validators"`
+	Source     ForkSource      `json:"source"`
+}
+
+func (g *ForkedGenesis) Verify() error {
+	if g.NID.Value == 0 {
+		return transaction.InvalidGenesisError.New("NIDIsZero")
+	}
+	if g.CID.Value == 0 {
+		return transaction.InvalidGenesisError.New("CIDIsZero")
+	}
+	if len(g.Result) == 0 {
+		return transaction.InvalidGenesisError.New("NoResult")
+	}
+	if len(g.Validators) != crypto.HashLen {
+		return transaction.InvalidGenesisError.Errorf("InvalidValidators(hash=%x)", g.Validators)
+	}
+	if len(g.Source.Block) != crypto.HashLen {
+		return transaction.InvalidGenesisError.Errorf("InvalidSourceBlock(id=%x)", g.Source.Block)
+	}
+	return nil
+}
+
+func NewForkedGenesis(js []byte) (*ForkedGenesis, error) {
+	g := new(ForkedGenesis)
+	if err := json.Unmarshal(js, g); err != nil {
+		return nil, err
+	}
+	return g, g.Verify()
+}
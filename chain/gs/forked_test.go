@@ -0,0 +1,71 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestForkedGenesis(t *testing.T) {
+	fg := &ForkedGenesis{
+		CID:        common.HexInt32{Value: 0x99},
+		NID:        common.HexInt32{Value: 0x98},
+		Timestamp:  common.HexInt64{Value: 1000},
+		Result:     []byte{0x01},
+		Validators: crypto.SHA3Sum256([]byte("validators")),
+		Source: ForkSource{
+			CID:    common.HexInt32{Value: 1},
+			NID:    common.HexInt32{Value: 1},
+			Height: common.HexInt64{Value: 10},
+			Block:  crypto.SHA3Sum256([]byte("block")),
+		},
+	}
+	js, err := json.Marshal(fg)
+	assert.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	gsw := NewGenesisStorageWriter(buf)
+	assert.NoError(t, gsw.WriteGenesis(js))
+	assert.NoError(t, gsw.Close())
+
+	s, err := New(buf.Bytes())
+	assert.NoError(t, err)
+	gt, err := s.Type()
+	assert.NoError(t, err)
+	assert.Equal(t, module.GenesisForked, gt)
+	cid, err := s.CID()
+	assert.NoError(t, err)
+	assert.Equal(t, 0x99, cid)
+	nid, err := s.NID()
+	assert.NoError(t, err)
+	assert.Equal(t, 0x98, nid)
+	assert.EqualValues(t, 0, s.Height())
+
+	fg.Validators = nil
+	js, err = json.Marshal(fg)
+	assert.NoError(t, err)
+	_, err = NewForkedGenesis(js)
+	assert.Error(t, err)
+}
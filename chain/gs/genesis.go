@@ -25,6 +25,7 @@ const (
 type genesisStorageImpl interface {
 	Genesis() []byte
 	Get(key []byte) ([]byte, error)
+	Keys() [][]byte
 }
 
 type genesisStorage struct {
@@ -43,6 +44,13 @@ func (gs *genesisStorage) ensureTypeAndIDs() error {
 			gs.height = pg.Height.Value
 			return nil
 		}
+		if fg, err := NewForkedGenesis(gs.Genesis()); err == nil {
+			gs.cid = int(fg.CID.Value)
+			gs.nid = int(fg.NID.Value)
+			gs.gType = module.GenesisForked
+			gs.height = 0
+			return nil
+		}
 		gtx, err := transaction.NewGenesisTransaction(gs.Genesis())
 		if err != nil {
 			return err
@@ -115,6 +123,14 @@ func (gs *genesisStorageWithDataDir) Get(key []byte) ([]byte, error) {
 	}
 }
 
+func (gs *genesisStorageWithDataDir) Keys() [][]byte {
+	keys := make([][]byte, 0, len(gs.dataMap))
+	for k := range gs.dataMap {
+		keys = append(keys, []byte(k))
+	}
+	return sortedKeys(keys)
+}
+
 const (
 	GenesisChunkSize = 1024 * 10
 )
@@ -160,6 +176,21 @@ func (gs *genesisStorageWithZip) Get(key []byte) ([]byte, error) {
 	}
 }
 
+func (gs *genesisStorageWithZip) Keys() [][]byte {
+	keys := make([][]byte, 0, len(gs.fileMap))
+	for k := range gs.fileMap {
+		keys = append(keys, []byte(k))
+	}
+	return sortedKeys(keys)
+}
+
+func sortedKeys(keys [][]byte) [][]byte {
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	return keys
+}
+
 type templateContext struct {
 	path   string
 	writer module.GenesisStorageWriter
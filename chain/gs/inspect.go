@@ -0,0 +1,344 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+var genesisTypeNames = map[module.GenesisType]string{
+	module.GenesisNormal: "normal",
+	module.GenesisPruned: "pruned",
+	module.GenesisForked: "forked",
+}
+
+// DataEntry is a genesis data in the storage. References are the paths of
+// the values in the genesis referring the data.
+type DataEntry struct {
+	Key         common.HexBytes `json:"key"`
+	Size        int             `json:"size"`
+	ContentType string          `json:"contentType,omitempty"`
+	References  []string        `json:"references,omitempty"`
+}
+
+type Inspection struct {
+	Type    string          `json:"type"`
+	CID     int             `json:"cid"`
+	NID     int             `json:"nid"`
+	Height  int64           `json:"height"`
+	Genesis json.RawMessage `json:"genesis"`
+	Data    []*DataEntry    `json:"data"`
+}
+
+func implOf(s module.GenesisStorage) (genesisStorageImpl, error) {
+	if gs, ok := s.(*genesisStorage); ok {
+		return gs.genesisStorageImpl, nil
+	}
+	return nil, errors.UnsupportedError.Errorf("UnknownGenesisStorage(type=%T)", s)
+}
+
+func decodeGenesis(bs []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewBuffer(bs))
+	d.UseNumber()
+	var obj interface{}
+	if err := d.Decode(&obj); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidGenesis")
+	}
+	return obj, nil
+}
+
+// keyedByName returns elements of the array by their names if all of them
+// are objects with unique names like accounts.
+func keyedByName(arr []interface{}) (map[string]interface{}, []string, bool) {
+	items := make(map[string]interface{}, len(arr))
+	names := make([]string, 0, len(arr))
+	for _, v := range arr {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, nil, false
+		}
+		name, ok := obj["name"].(string)
+		if !ok || name == "" {
+			return nil, nil, false
+		}
+		if _, ok := items[name]; ok {
+			return nil, nil, false
+		}
+		items[name] = v
+		names = append(names, name)
+	}
+	return items, names, true
+}
+
+func sortedMapKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func childPath(p, key string) string {
+	if p == "" {
+		return key
+	}
+	return p + "." + key
+}
+
+// walkStrings calls the function for the string values with their paths
+// and the objects containing them.
+func walkStrings(p string, parent map[string]interface{}, v interface{}, cb func(p string, parent map[string]interface{}, s string)) {
+	switch obj := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedMapKeys(obj) {
+			walkStrings(childPath(p, k), obj, obj[k], cb)
+		}
+	case []interface{}:
+		if items, names, ok := keyedByName(obj); ok {
+			for _, name := range names {
+				walkStrings(fmt.Sprintf("%s[%s]", p, name), parent, items[name], cb)
+			}
+		} else {
+			for i, item := range obj {
+				walkStrings(fmt.Sprintf("%s[%d]", p, i), parent, item, cb)
+			}
+		}
+	case string:
+		cb(p, parent, obj)
+	}
+}
+
+func guessContentType(bs []byte) string {
+	zr, err := zip.NewReader(bytes.NewReader(bs), int64(len(bs)))
+	if err != nil {
+		return ""
+	}
+	for _, f := range zr.File {
+		if strings.HasPrefix(f.Name, "META-INF/") {
+			return state.CTAppJava
+		}
+	}
+	return state.CTAppZip
+}
+
+// Inspect returns the information of the genesis and the data in the
+// genesis storage.
+func Inspect(s module.GenesisStorage) (*Inspection, error) {
+	impl, err := implOf(s)
+	if err != nil {
+		return nil, err
+	}
+	gt, err := s.Type()
+	if err != nil {
+		return nil, err
+	}
+	cid, err := s.CID()
+	if err != nil {
+		return nil, err
+	}
+	nid, err := s.NID()
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := decodeGenesis(s.Genesis())
+	if err != nil {
+		return nil, err
+	}
+	res := &Inspection{
+		Type:    genesisTypeNames[gt],
+		CID:     cid,
+		NID:     nid,
+		Height:  s.Height(),
+		Genesis: s.Genesis(),
+		Data:    []*DataEntry{},
+	}
+	entries := make(map[string]*DataEntry)
+	for _, key := range impl.Keys() {
+		value, err := impl.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		e := &DataEntry{
+			Key:         key,
+			Size:        len(value),
+			ContentType: guessContentType(value),
+		}
+		entries[hex.EncodeToString(key)] = e
+		res.Data = append(res.Data, e)
+	}
+	walkStrings("", nil, genesis, func(p string, parent map[string]interface{}, s string) {
+		s = strings.ToLower(s)
+		for k, e := range entries {
+			if !strings.Contains(s, k) {
+				continue
+			}
+			e.References = append(e.References, p)
+			if strings.HasSuffix(p, ".contentId") {
+				if ct, ok := parent["contentType"].(string); ok {
+					e.ContentType = ct
+				}
+			}
+		}
+	})
+	return res, nil
+}
+
+// Difference is a difference between two genesis storages. Left is nil
+// if it's added, and Right is nil if it's removed.
+type Difference struct {
+	Path  string      `json:"path"`
+	Left  interface{} `json:"left,omitempty"`
+	Right interface{} `json:"right,omitempty"`
+}
+
+func (d *Difference) String() string {
+	switch {
+	case d.Left == nil:
+		return fmt.Sprintf("+ %s: %v", d.Path, d.Right)
+	case d.Right == nil:
+		return fmt.Sprintf("- %s: %v", d.Path, d.Left)
+	default:
+		return fmt.Sprintf("~ %s: %v -> %v", d.Path, d.Left, d.Right)
+	}
+}
+
+func diffValue(p string, l, r interface{}, diffs []*Difference) []*Difference {
+	switch lo := l.(type) {
+	case map[string]interface{}:
+		ro, ok := r.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, k := range sortedMapKeys(lo) {
+			if rv, ok := ro[k]; ok {
+				diffs = diffValue(childPath(p, k), lo[k], rv, diffs)
+			} else {
+				diffs = append(diffs, &Difference{Path: childPath(p, k), Left: lo[k]})
+			}
+		}
+		for _, k := range sortedMapKeys(ro) {
+			if _, ok := lo[k]; !ok {
+				diffs = append(diffs, &Difference{Path: childPath(p, k), Right: ro[k]})
+			}
+		}
+		return diffs
+	case []interface{}:
+		ro, ok := r.([]interface{})
+		if !ok {
+			break
+		}
+		litems, lnames, lok := keyedByName(lo)
+		ritems, rnames, rok := keyedByName(ro)
+		if lok && rok {
+			for _, name := range lnames {
+				cp := fmt.Sprintf("%s[%s]", p, name)
+				if rv, ok := ritems[name]; ok {
+					diffs = diffValue(cp, litems[name], rv, diffs)
+				} else {
+					diffs = append(diffs, &Difference{Path: cp, Left: litems[name]})
+				}
+			}
+			for _, name := range rnames {
+				if _, ok := litems[name]; !ok {
+					cp := fmt.Sprintf("%s[%s]", p, name)
+					diffs = append(diffs, &Difference{Path: cp, Right: ritems[name]})
+				}
+			}
+			return diffs
+		}
+		for i := 0; i < len(lo) || i < len(ro); i++ {
+			cp := fmt.Sprintf("%s[%d]", p, i)
+			switch {
+			case i >= len(ro):
+				diffs = append(diffs, &Difference{Path: cp, Left: lo[i]})
+			case i >= len(lo):
+				diffs = append(diffs, &Difference{Path: cp, Right: ro[i]})
+			default:
+				diffs = diffValue(cp, lo[i], ro[i], diffs)
+			}
+		}
+		return diffs
+	}
+	if !reflect.DeepEqual(l, r) {
+		diffs = append(diffs, &Difference{Path: p, Left: l, Right: r})
+	}
+	return diffs
+}
+
+// Diff returns differences of the genesis and the data between two
+// genesis storages. Data are compared by their keys (hashes), and
+// the differences of the data have paths like "data[<key>]".
+func Diff(left, right module.GenesisStorage) ([]*Difference, error) {
+	limpl, err := implOf(left)
+	if err != nil {
+		return nil, err
+	}
+	rimpl, err := implOf(right)
+	if err != nil {
+		return nil, err
+	}
+	lg, err := decodeGenesis(left.Genesis())
+	if err != nil {
+		return nil, err
+	}
+	rg, err := decodeGenesis(right.Genesis())
+	if err != nil {
+		return nil, err
+	}
+	diffs := diffValue("", lg, rg, []*Difference{})
+
+	sizeOf := func(impl genesisStorageImpl, key []byte) (interface{}, error) {
+		value, err := impl.Get(key)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		return len(value), nil
+	}
+	var prev []byte
+	for _, key := range sortedKeys(append(limpl.Keys(), rimpl.Keys()...)) {
+		if bytes.Equal(key, prev) {
+			continue
+		}
+		prev = key
+		p := fmt.Sprintf("data[%#x]", key)
+		ls, err := sizeOf(limpl, key)
+		if err != nil {
+			return nil, err
+		}
+		rs, err := sizeOf(rimpl, key)
+		if err != nil {
+			return nil, err
+		}
+		if (ls == nil) != (rs == nil) {
+			diffs = append(diffs, &Difference{Path: p, Left: ls, Right: rs})
+		}
+	}
+	return diffs, nil
+}
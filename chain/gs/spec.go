@@ -0,0 +1,298 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+const (
+	accountGod      = "god"
+	accountTreasury = "treasury"
+	keyValidators   = "validatorList"
+)
+
+// SpecAccount is an account of the genesis spec.
+type SpecAccount struct {
+	Name    string            `json:"name,omitempty"`
+	Address *common.Address   `json:"address"`
+	Balance *common.HexInt    `json:"balance,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// SpecContract is a contract account of the genesis spec. Source is a file
+// or a directory relative to the spec file. A directory is archived as zip.
+type SpecContract struct {
+	SpecAccount
+	Owner       *common.Address `json:"owner"`
+	Source      string          `json:"source"`
+	ContentType string          `json:"contentType,omitempty"`
+	Params      json.RawMessage `json:"params,omitempty"`
+}
+
+// Spec is the declarative specification of the genesis storage.
+type Spec struct {
+	NID        *common.HexInt32       `json:"nid,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Accounts   []*SpecAccount         `json:"accounts"`
+	Contracts  []*SpecContract        `json:"contracts,omitempty"`
+	Validators []*common.Address      `json:"validators,omitempty"`
+	Chain      map[string]interface{} `json:"chain,omitempty"`
+	path       string
+}
+
+// ReadSpec reads the spec from the file.
+func ReadSpec(p string) (*Spec, error) {
+	bs, err := os.ReadFile(p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Fail to read %s", p)
+	}
+	d := json.NewDecoder(bytes.NewBuffer(bs))
+	d.UseNumber()
+	d.DisallowUnknownFields()
+	spec := new(Spec)
+	if err := d.Decode(spec); err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "Fail to decode %s", p)
+	}
+	spec.path, _ = path.Split(p)
+	return spec, nil
+}
+
+func checkStorage(addr *common.Address, storage map[string]string) error {
+	for k, v := range storage {
+		if key, err := hex.DecodeString(strings.TrimPrefix(k, "0x")); err != nil || len(key) == 0 {
+			return errors.IllegalArgumentError.Errorf(
+				"InvalidStorageKey(addr=%s,key=%q)", addr, k)
+		}
+		if _, err := hex.DecodeString(strings.TrimPrefix(v, "0x")); err != nil {
+			return errors.IllegalArgumentError.Errorf(
+				"InvalidStorageValue(addr=%s,key=%q)", addr, k)
+		}
+	}
+	return nil
+}
+
+func (s *Spec) allAccounts() []*SpecAccount {
+	accounts := make([]*SpecAccount, 0, len(s.Accounts)+len(s.Contracts))
+	accounts = append(accounts, s.Accounts...)
+	for _, c := range s.Contracts {
+		accounts = append(accounts, &c.SpecAccount)
+	}
+	return accounts
+}
+
+func (s *Spec) sourcePath(c *SpecContract) string {
+	if path.IsAbs(c.Source) {
+		return c.Source
+	}
+	return path.Join(s.path, c.Source)
+}
+
+// Validate checks the spec without building the storage.
+func (s *Spec) Validate() error {
+	names := make(map[string]bool)
+	addrs := make(map[string]bool)
+	for _, a := range s.allAccounts() {
+		if a.Address == nil {
+			return errors.IllegalArgumentError.Errorf("NoAddress(name=%s)", a.Name)
+		}
+		if addrs[a.Address.String()] {
+			return errors.IllegalArgumentError.Errorf("DuplicateAddress(addr=%s)", a.Address)
+		}
+		addrs[a.Address.String()] = true
+		if a.Name != "" {
+			if names[a.Name] {
+				return errors.IllegalArgumentError.Errorf("DuplicateName(name=%s)", a.Name)
+			}
+			names[a.Name] = true
+		}
+		if a.Balance != nil && a.Balance.Sign() < 0 {
+			return errors.IllegalArgumentError.Errorf("NegativeBalance(addr=%s)", a.Address)
+		}
+		if err := checkStorage(a.Address, a.Storage); err != nil {
+			return err
+		}
+	}
+	for _, a := range s.Accounts {
+		if a.Address.IsContract() {
+			return errors.IllegalArgumentError.Errorf("ContractInAccounts(addr=%s)", a.Address)
+		}
+	}
+	for _, name := range []string{accountGod, accountTreasury} {
+		if !names[name] {
+			return errors.IllegalArgumentError.Errorf("NoAccount(name=%s)", name)
+		}
+	}
+	for _, c := range s.Contracts {
+		if !c.Address.IsContract() {
+			return errors.IllegalArgumentError.Errorf("NotContractAddress(addr=%s)", c.Address)
+		}
+		if c.Owner == nil || c.Owner.IsContract() {
+			return errors.IllegalArgumentError.Errorf("InvalidOwner(addr=%s)", c.Address)
+		}
+		if c.Source == "" {
+			return errors.IllegalArgumentError.Errorf("NoSource(addr=%s)", c.Address)
+		}
+		if _, err := os.Stat(s.sourcePath(c)); err != nil {
+			return errors.IllegalArgumentError.Wrapf(err, "InvalidSource(addr=%s)", c.Address)
+		}
+	}
+	if _, ok := s.Chain[keyValidators]; ok && len(s.Validators) > 0 {
+		return errors.IllegalArgumentError.Errorf(
+			"DuplicateValidators(chain.%s)", keyValidators)
+	}
+	if _, ok := s.Chain[keyValidators]; !ok && len(s.Validators) == 0 {
+		return errors.IllegalArgumentError.New("NoValidators")
+	}
+	return nil
+}
+
+// readSource returns the content of the contract and its content type.
+func (s *Spec) readSource(c *SpecContract) ([]byte, string, error) {
+	p := s.sourcePath(c)
+	st, err := os.Stat(p)
+	if err != nil {
+		return nil, "", err
+	}
+	var content []byte
+	contentType := c.ContentType
+	if st.IsDir() {
+		if content, err = zipDirectory(p); err != nil {
+			return nil, "", err
+		}
+		if contentType == "" {
+			contentType = state.CTAppZip
+		}
+	} else {
+		if content, err = os.ReadFile(p); err != nil {
+			return nil, "", err
+		}
+		if contentType == "" {
+			if strings.HasSuffix(p, ".jar") {
+				contentType = state.CTAppJava
+			} else {
+				contentType = state.CTAppZip
+			}
+		}
+	}
+	return content, contentType, nil
+}
+
+func accountJSONOf(a *SpecAccount, balance bool) map[string]interface{} {
+	jso := map[string]interface{}{
+		"address": a.Address,
+	}
+	if a.Name != "" {
+		jso["name"] = a.Name
+	}
+	if a.Balance != nil {
+		jso["balance"] = a.Balance
+	} else if balance {
+		jso["balance"] = "0x0"
+	}
+	if len(a.Storage) > 0 {
+		jso["storage"] = a.Storage
+	}
+	return jso
+}
+
+// Build writes the genesis storage for the spec. The genesis transaction
+// is verified after the build.
+func (s *Spec) Build(w io.Writer) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	gsw := NewGenesisStorageWriter(w)
+	defer gsw.Close()
+
+	accounts := make([]interface{}, 0, len(s.Accounts)+len(s.Contracts))
+	for _, a := range s.Accounts {
+		accounts = append(accounts, accountJSONOf(a, true))
+	}
+	for _, c := range s.Contracts {
+		content, contentType, err := s.readSource(c)
+		if err != nil {
+			return errors.Wrapf(err, "Fail to read source of %s", c.Address)
+		}
+		key, err := gsw.WriteData(content)
+		if err != nil {
+			return err
+		}
+		score := map[string]interface{}{
+			"owner":       c.Owner,
+			"contentType": contentType,
+			"contentId":   "hash:0x" + hex.EncodeToString(key),
+		}
+		if len(c.Params) > 0 {
+			score["params"] = c.Params
+		}
+		jso := accountJSONOf(&c.SpecAccount, false)
+		jso["score"] = score
+		accounts = append(accounts, jso)
+	}
+
+	chain := make(map[string]interface{})
+	for k, v := range s.Chain {
+		chain[k] = v
+	}
+	if len(s.Validators) > 0 {
+		chain[keyValidators] = s.Validators
+	}
+	genesisObj := map[string]interface{}{
+		"accounts": accounts,
+		"chain":    chain,
+	}
+	if s.Message != "" {
+		genesisObj["message"] = s.Message
+	}
+	if s.NID != nil {
+		genesisObj["nid"] = s.NID
+	}
+	genesis, err := json.Marshal(genesisObj)
+	if err != nil {
+		return errors.Wrap(err, "Fail to marshal JSON")
+	}
+	if tx, err := transaction.NewGenesisTransaction(genesis); err != nil {
+		return errors.Wrap(err, "Fail to parse genesis")
+	} else if err := tx.Verify(); err != nil {
+		return errors.Wrap(err, "Fail to verify genesis")
+	}
+	if err := gsw.WriteGenesis(genesis); err != nil {
+		return errors.Wrap(err, "Fail to write genesis")
+	}
+	return nil
+}
+
+// WriteFromSpec writes the genesis storage for the spec file.
+func WriteFromSpec(w io.Writer, p string) error {
+	spec, err := ReadSpec(p)
+	if err != nil {
+		return err
+	}
+	return spec.Build(w)
+}
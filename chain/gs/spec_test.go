@@ -0,0 +1,134 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const testSpec = `{
+  "nid": "0x3",
+  "accounts": [
+    {"name": "god", "address": "hxb6b5791be0b5ef67063b3c10b840fb81514db2fd", "balance": "0x100"},
+    {"name": "treasury", "address": "hx1000000000000000000000000000000000000000"}
+  ],
+  "contracts": [
+    {"name": "token", "address": "cx0000000000000000000000000000000000000001",
+     "owner": "hxb6b5791be0b5ef67063b3c10b840fb81514db2fd", "source": "token.jar",
+     "params": {"supply": "0x10"}, "storage": {"0x01": "0x02"}}
+  ],
+  "validators": ["hxb6b5791be0b5ef67063b3c10b840fb81514db2fd"],
+  "chain": {"revision": "%s"}
+}`
+
+func buildTestStorage(t *testing.T, dir, revision string) module.GenesisStorage {
+	p := path.Join(dir, "spec-"+revision+".json")
+	spec := bytes.Replace([]byte(testSpec), []byte("%s"), []byte(revision), 1)
+	assert.NoError(t, os.WriteFile(p, spec, 0600))
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, WriteFromSpec(buf, p))
+	s, err := New(buf.Bytes())
+	assert.NoError(t, err)
+	return s
+}
+
+func TestSpec_BuildAndInspect(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte("java contract")
+	assert.NoError(t, os.WriteFile(path.Join(dir, "token.jar"), content, 0600))
+
+	s1 := buildTestStorage(t, dir, "0x5")
+	nid, err := s1.NID()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, nid)
+
+	key := crypto.SHA3Sum256(content)
+	value, err := s1.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, content, value)
+
+	res, err := Inspect(s1)
+	assert.NoError(t, err)
+	assert.Equal(t, "normal", res.Type)
+	assert.Len(t, res.Data, 1)
+	assert.EqualValues(t, key, res.Data[0].Key)
+	assert.Equal(t, len(content), res.Data[0].Size)
+	assert.Equal(t, state.CTAppJava, res.Data[0].ContentType)
+	assert.Equal(t, []string{"accounts[token].score.contentId"}, res.Data[0].References)
+
+	diffs, err := Diff(s1, s1)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	assert.NoError(t, os.WriteFile(path.Join(dir, "token.jar"), []byte("updated"), 0600))
+	s2 := buildTestStorage(t, dir, "0x6")
+	diffs, err = Diff(s1, s2)
+	assert.NoError(t, err)
+	var paths []string
+	for _, d := range diffs {
+		paths = append(paths, d.Path)
+	}
+	assert.Contains(t, paths, "chain.revision")
+	assert.Contains(t, paths, "accounts[token].score.contentId")
+	assert.Len(t, paths, 4)
+}
+
+func TestSpec_Validate(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(path.Join(dir, "token.jar"), []byte("jar"), 0600))
+	cases := map[string]string{
+		"no treasury": `{"accounts":[{"name":"god","address":"hx0000000000000000000000000000000000000001"}],
+			"validators":["hx0000000000000000000000000000000000000001"]}`,
+		"duplicate address": `{"accounts":[{"name":"god","address":"hx0000000000000000000000000000000000000001"},
+			{"name":"treasury","address":"hx0000000000000000000000000000000000000001"}],
+			"validators":["hx0000000000000000000000000000000000000001"]}`,
+		"no validators": `{"accounts":[{"name":"god","address":"hx0000000000000000000000000000000000000001"},
+			{"name":"treasury","address":"hx0000000000000000000000000000000000000002"}]}`,
+		"eoa contract": `{"accounts":[{"name":"god","address":"hx0000000000000000000000000000000000000001"},
+			{"name":"treasury","address":"hx0000000000000000000000000000000000000002"}],
+			"contracts":[{"address":"hx0000000000000000000000000000000000000003",
+			"owner":"hx0000000000000000000000000000000000000001","source":"token.jar"}],
+			"validators":["hx0000000000000000000000000000000000000001"]}`,
+		"no source": `{"accounts":[{"name":"god","address":"hx0000000000000000000000000000000000000001"},
+			{"name":"treasury","address":"hx0000000000000000000000000000000000000002"}],
+			"contracts":[{"address":"cx0000000000000000000000000000000000000003",
+			"owner":"hx0000000000000000000000000000000000000001","source":"none.jar"}],
+			"validators":["hx0000000000000000000000000000000000000001"]}`,
+		"invalid storage": `{"accounts":[{"name":"god","address":"hx0000000000000000000000000000000000000001",
+			"storage":{"zz":"0x01"}},
+			{"name":"treasury","address":"hx0000000000000000000000000000000000000002"}],
+			"validators":["hx0000000000000000000000000000000000000001"]}`,
+	}
+	for name, spec := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := path.Join(dir, "spec.json")
+			assert.NoError(t, os.WriteFile(p, []byte(spec), 0600))
+			err := WriteFromSpec(bytes.NewBuffer(nil), p)
+			assert.True(t, errors.IllegalArgumentError.Equals(err), "err=%+v", err)
+		})
+	}
+}
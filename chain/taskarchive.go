@@ -0,0 +1,151 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/archive"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type archiveParams struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// taskArchive builds the archive index for the states of the blocks in
+// the range. The range should be connected to the range already indexed,
+// and the states of the blocks should be available.
+type taskArchive struct {
+	chain  *singleChain
+	result resultStore
+	start  int64
+	end    int64
+
+	height int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (t *taskArchive) String() string {
+	return fmt.Sprintf("Archive(start=%d,end=%d)", t.start, t.end)
+}
+
+func (t *taskArchive) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("archive started height=%d",
+			atomic.LoadInt64(&t.height))
+	default:
+		return "archive " + s.String()
+	}
+}
+
+func (t *taskArchive) onProgress(height int64) error {
+	select {
+	case <-t.stop:
+		return errors.ErrInterrupted
+	default:
+		atomic.StoreInt64(&t.height, height)
+		return nil
+	}
+}
+
+func (t *taskArchive) stateAt(height int64) (state.WorldSnapshot, error) {
+	blk, err := t.chain.bm.GetBlockByHeight(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to get block(height=%d)", height)
+	}
+	return service.NewWorldSnapshotFromResult(t.chain.Database(), blk.Result())
+}
+
+func (t *taskArchive) doArchive() error {
+	defer t.chain.releaseManagers()
+
+	idx := archive.IndexOf(t.chain.Database())
+	if idx == nil {
+		return errors.InvalidStateError.New("ArchiveDisabled")
+	}
+	end := t.end
+	if last, err := t.chain.bm.GetLastBlock(); err != nil {
+		return err
+	} else if end == 0 || end > last.Height() {
+		end = last.Height()
+	}
+	if err := idx.Fill(t.start, end, t.stateAt, t.onProgress); err != nil {
+		return err
+	}
+	base, last, _ := idx.Range()
+	t.chain.Logger().Infof("ARCHIVE DONE start=%d end=%d base=%d last=%d",
+		t.start, end, base, last)
+	return nil
+}
+
+func (t *taskArchive) Start() error {
+	if err := t.chain.prepareManagers(); err != nil {
+		t.result.SetValue(err)
+		return err
+	}
+	go func() {
+		err := t.doArchive()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskArchive) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+func (t *taskArchive) Wait() error {
+	return t.result.Wait()
+}
+
+func taskArchiveFactory(chain *singleChain, param json.RawMessage) (chainTask, error) {
+	if !chain.cfg.Archive {
+		return nil, errors.InvalidStateError.New("ArchiveDisabled")
+	}
+	var p archiveParams
+	if len(param) > 0 {
+		if err := json.Unmarshal(param, &p); err != nil {
+			return nil, err
+		}
+	}
+	if p.Start < 0 || (p.End != 0 && p.End < p.Start) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidParameter(start=%d,end=%d)", p.Start, p.End)
+	}
+	return &taskArchive{
+		chain: chain,
+		start: p.Start,
+		end:   p.End,
+		stop:  make(chan struct{}),
+	}, nil
+}
+
+func init() {
+	registerTaskFactory("archive", taskArchiveFactory)
+}
@@ -0,0 +1,428 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	DefaultCheckFetchTimeout = 30 * time.Second
+	checkFetchPollInterval   = 200 * time.Millisecond
+)
+
+type checkParams struct {
+	Start   int64 `json:"start"`
+	End     int64 `json:"end"`
+	Fetch   bool  `json:"fetch"`
+	Timeout int64 `json:"timeout"`
+}
+
+// taskCheck verifies the blocks, the transactions, the locators and
+// the results of the blocks in the range. All keys verified are kept
+// in memory to verify shared data only once, so it may use a lot of
+// memory for a large range.
+type taskCheck struct {
+	chain   *singleChain
+	result  resultStore
+	start   int64
+	end     int64
+	fetch   bool
+	timeout time.Duration
+
+	// heights deleted by the retention policy, see block.GetPruningStatus
+	pruned struct {
+		base, state, receipts int64
+	}
+
+	height    int64
+	failures  int64
+	recovered int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (t *taskCheck) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+func (t *taskCheck) Wait() error {
+	return t.result.Wait()
+}
+
+func (t *taskCheck) String() string {
+	return fmt.Sprintf("Check(start=%d,end=%d,fetch=%v)",
+		t.start, t.end, t.fetch)
+}
+
+func (t *taskCheck) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("check started height=%d failures=%d recovered=%d",
+			atomic.LoadInt64(&t.height),
+			atomic.LoadInt64(&t.failures),
+			atomic.LoadInt64(&t.recovered))
+	default:
+		return "check " + s.String()
+	}
+}
+
+func (t *taskCheck) isStopped() bool {
+	select {
+	case <-t.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *taskCheck) onProgress(height int64, resolved, unresolved int) error {
+	if t.isStopped() {
+		return errors.ErrInterrupted
+	}
+	return nil
+}
+
+func (t *taskCheck) report(f *merkle.CheckFailure) {
+	atomic.AddInt64(&t.failures, 1)
+	t.chain.Logger().Warnf("CHECK FAIL %s", f)
+}
+
+func (t *taskCheck) reportAt(height int64, id db.BucketID, key []byte, reason merkle.FailureReason) {
+	t.report(&merkle.CheckFailure{
+		Height:   height,
+		BucketID: id,
+		Key:      key,
+		Reason:   reason,
+	})
+}
+
+// unrecovered returns the number of failures not recovered yet.
+func (t *taskCheck) unrecovered() int64 {
+	return atomic.LoadInt64(&t.failures) - atomic.LoadInt64(&t.recovered)
+}
+
+func (t *taskCheck) onFailure(f *merkle.CheckFailure) ([]byte, error) {
+	t.report(f)
+	if !t.fetch || f.BucketID.Hasher() == nil {
+		return nil, nil
+	}
+	value, err := t.fetchData(f)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	atomic.AddInt64(&t.recovered, 1)
+	t.chain.Logger().Infof("CHECK RECOVERED %s", f)
+	return value, nil
+}
+
+// fetchData requests the data to the peers, and waits until the data is
+// stored in the database. Corrupted data is removed before the request
+// since the syncer ignores the data already in the database.
+func (t *taskCheck) fetchData(f *merkle.CheckFailure) ([]byte, error) {
+	bk, err := t.chain.Database().GetBucket(f.BucketID)
+	if err != nil {
+		return nil, err
+	}
+	if f.Reason != merkle.FailureMissing {
+		if err := bk.Delete(f.Key); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.chain.ServiceManager().AddSyncRequest(f.BucketID, f.Key); err != nil {
+		t.chain.Logger().Warnf("FAIL to request data id=%q key=%#x err=%+v",
+			f.BucketID, f.Key, err)
+		return nil, nil
+	}
+	timer := time.NewTimer(t.timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(checkFetchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return nil, errors.ErrInterrupted
+		case <-timer.C:
+			t.chain.Logger().Warnf("FAIL to fetch data id=%q key=%#x err=timeout",
+				f.BucketID, f.Key)
+			return nil, nil
+		case <-ticker.C:
+			value, err := bk.Get(f.Key)
+			if err != nil {
+				return nil, err
+			}
+			if value != nil && bytes.Equal(f.BucketID.Hasher().Hash(value), f.Key) {
+				return value, nil
+			}
+		}
+	}
+}
+
+type nullRequester struct{}
+
+func (nullRequester) OnData(value []byte, builder merkle.Builder) error {
+	return nil
+}
+
+func (t *taskCheck) checkLocators(blk module.Block, txs module.TransactionList, group module.TransactionGroup) error {
+	bk, err := db.NewCodedBucket(t.chain.Database(), db.TransactionLocatorByHash, nil)
+	if err != nil {
+		return err
+	}
+	for itr := txs.Iterator(); itr.Has(); t.chain.Logger().Must(itr.Next()) {
+		tx, idx, err := itr.Get()
+		if err != nil {
+			return err
+		}
+		var loc module.TransactionLocator
+		if err := bk.Get(db.Raw(tx.ID()), &loc); err != nil {
+			reason := merkle.FailureInvalid
+			if errors.NotFoundError.Equals(err) {
+				reason = merkle.FailureMissing
+			}
+			t.reportAt(blk.Height(), db.TransactionLocatorByHash, tx.ID(), reason)
+			continue
+		}
+		if loc.BlockHeight != blk.Height() || loc.TransactionGroup != group || loc.IndexInGroup != idx {
+			t.chain.Logger().Warnf("INVALID Locator tx=%#x loc=%+v exp=(%d,%d,%d)",
+				tx.ID(), loc, blk.Height(), group, idx)
+			t.reportAt(blk.Height(), db.TransactionLocatorByHash, tx.ID(), merkle.FailureInvalid)
+		}
+	}
+	return nil
+}
+
+func (t *taskCheck) checkBlock(ctx *merkle.CopyContext, height int64, prev module.Block) (module.Block, error) {
+	ctx.SetHeight(height)
+	dbase := t.chain.Database()
+	hash, err := block.GetBlockHeaderHashByHeight(dbase, nil, height)
+	if err != nil || len(hash) == 0 {
+		if err != nil && !errors.NotFoundError.Equals(err) {
+			return nil, err
+		}
+		t.reportAt(height, db.BlockHeaderHashByHeight,
+			codec.BC.MustMarshalToBytes(height), merkle.FailureMissing)
+		return nil, nil
+	}
+
+	// the header should be verified before parsing the block.
+	failures := t.unrecovered()
+	ctx.Builder().RequestData(db.BytesByHash, hash, nullRequester{})
+	if err := ctx.Run(); err != nil {
+		return nil, err
+	}
+	if t.unrecovered() > failures {
+		return nil, nil
+	}
+	blk, err := t.chain.BlockManager().GetBlockByHeight(height)
+	if err != nil {
+		t.chain.Logger().Warnf("FAIL to get block height=%d err=%+v", height, err)
+		t.reportAt(height, db.BytesByHash, hash, merkle.FailureInvalid)
+		return nil, nil
+	}
+	if blk.Height() != height || !bytes.Equal(blk.ID(), hash) {
+		t.reportAt(height, db.BytesByHash, hash, merkle.FailureInvalid)
+		return nil, nil
+	}
+	if prev != nil && !bytes.Equal(blk.PrevID(), prev.ID()) {
+		t.chain.Logger().Warnf("INVALID PrevID height=%d prev=%#x exp=%#x",
+			height, blk.PrevID(), prev.ID())
+		t.reportAt(height, db.BytesByHash, hash, merkle.FailureInvalid)
+	}
+
+	if vh := blk.Votes().Hash(); len(vh) > 0 {
+		ctx.Builder().RequestData(db.BytesByHash, vh, nullRequester{})
+	}
+	if nvh := blk.NextValidatorsHash(); len(nvh) > 0 {
+		ctx.Builder().RequestData(db.BytesByHash, nvh, nullRequester{})
+	}
+	failures = t.unrecovered()
+	transaction.NewTransactionListWithBuilder(ctx.Builder(), blk.PatchTransactions().Hash())
+	transaction.NewTransactionListWithBuilder(ctx.Builder(), blk.NormalTransactions().Hash())
+	if err := ctx.Run(); err != nil {
+		return nil, err
+	}
+	// locators are checked only if the transaction lists are valid.
+	if t.unrecovered() == failures {
+		if err := t.checkLocators(blk, blk.PatchTransactions(), module.TransactionGroupPatch); err != nil {
+			return nil, err
+		}
+		if err := t.checkLocators(blk, blk.NormalTransactions(), module.TransactionGroupNormal); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.checkResult(ctx, blk); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
+// checkResult checks the state and the receipts of the block except ones
+// deleted by the retention policy.
+func (t *taskCheck) checkResult(ctx *merkle.CopyContext, blk module.Block) error {
+	height := blk.Height()
+	hasState := height < t.pruned.base || height >= t.pruned.state
+	hasReceipts := height < t.pruned.base || height >= t.pruned.receipts
+
+	sm := t.chain.ServiceManager()
+	if hasState && hasReceipts {
+		return sm.ExportResult(blk.Result(), blk.NextValidatorsHash(), ctx.TargetDB())
+	}
+	if hasState {
+		if err := sm.ExportState(blk.Result(), blk.NextValidatorsHash(), ctx.TargetDB()); err != nil {
+			return err
+		}
+	}
+	if hasReceipts {
+		for _, g := range []module.TransactionGroup{
+			module.TransactionGroupPatch, module.TransactionGroupNormal,
+		} {
+			rl, err := sm.ReceiptListFromResult(blk.Result(), g)
+			if err != nil {
+				return err
+			}
+			txresult.NewReceiptListWithBuilder(ctx.Builder(), rl.Hash())
+		}
+		return ctx.Run()
+	}
+	return nil
+}
+
+func (t *taskCheck) doCheck() error {
+	defer t.chain.releaseManagers()
+
+	bm := t.chain.BlockManager()
+	logger := t.chain.Logger()
+
+	end := t.end
+	if last, err := bm.GetLastBlock(); err != nil {
+		return err
+	} else if end == 0 || end > last.Height() {
+		end = last.Height()
+	}
+	base, state, receipts, err := block.GetPruningStatus(t.chain.Database())
+	if err != nil {
+		return err
+	}
+	t.pruned.base, t.pruned.state, t.pruned.receipts = base, state, receipts
+	if state > base || receipts > base {
+		logger.Infof("CHECK skip pruned states=[%d,%d) receipts=[%d,%d)",
+			base, state, base, receipts)
+	}
+
+	ctx := merkle.NewCheckContext(t.chain.Database(), t.onFailure)
+	ctx.SetProgressCallback(t.onProgress)
+
+	var prev module.Block
+	for height := t.start; height <= end; height++ {
+		if t.isStopped() {
+			return errors.ErrInterrupted
+		}
+		atomic.StoreInt64(&t.height, height)
+		blk, err := t.checkBlock(ctx, height, prev)
+		if err != nil {
+			return err
+		}
+		prev = blk
+	}
+
+	failures := atomic.LoadInt64(&t.failures)
+	recovered := atomic.LoadInt64(&t.recovered)
+	logger.Infof("CHECK DONE start=%d end=%d failures=%d recovered=%d",
+		t.start, end, failures, recovered)
+	if failures > recovered {
+		return errors.InvalidStateError.Errorf(
+			"IntegrityCheckFailed(failures=%d,recovered=%d)", failures, recovered)
+	}
+	return nil
+}
+
+func (t *taskCheck) Start() error {
+	if err := t.chain.prepareManagers(); err != nil {
+		t.result.SetValue(err)
+		return err
+	}
+	if t.fetch {
+		t.chain.sm.Start()
+		if err := t.chain.nm.Start(); err != nil {
+			t.chain.releaseManagers()
+			t.result.SetValue(err)
+			return err
+		}
+	}
+	go func() {
+		err := t.doCheck()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func taskCheckFactory(chain *singleChain, param json.RawMessage) (chainTask, error) {
+	var p checkParams
+	if len(param) > 0 {
+		if err := json.Unmarshal(param, &p); err != nil {
+			return nil, err
+		}
+	}
+	// blocks before the genesis of the pruned chain aren't available.
+	start := p.Start
+	if start == 0 {
+		if gs := chain.GenesisStorage(); gs != nil {
+			start = gs.Height()
+		}
+	}
+	if (p.End != 0 && p.End < start) || p.Start < 0 || p.Timeout < 0 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidParameter(start=%d,end=%d,timeout=%d)",
+			start, p.End, p.Timeout)
+	}
+	timeout := DefaultCheckFetchTimeout
+	if p.Timeout > 0 {
+		timeout = time.Duration(p.Timeout) * time.Second
+	}
+	task := &taskCheck{
+		chain:   chain,
+		start:   start,
+		end:     p.End,
+		fetch:   p.Fetch,
+		timeout: timeout,
+		stop:    make(chan struct{}),
+	}
+	return task, nil
+}
+
+func init() {
+	registerTaskFactory("check", taskCheckFactory)
+}
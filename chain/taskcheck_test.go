@@ -0,0 +1,161 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/test"
+)
+
+type checkTestSM struct {
+	module.ServiceManager
+}
+
+func (sm checkTestSM) Term() {
+	// nothing to do
+}
+
+type checkTestGS struct {
+	module.GenesisStorage
+	height int64
+}
+
+func (gs checkTestGS) Height() int64 {
+	return gs.height
+}
+
+func newCheckTestNode(t *testing.T, height int64, o ...test.FixtureOption) *test.Node {
+	nd := test.NewNode(t, o...)
+	for h := int64(1); h <= height; h++ {
+		v := fmt.Sprint(h)
+		nd.ProposeFinalizeBlockWithTX(consensus.NewEmptyCommitVoteList(),
+			test.NewTx().SetVarTest(&v).String())
+	}
+	return nd
+}
+
+func newCheckTestChain(nd *test.Node) *singleChain {
+	// managers are released by the task, so the chain is used only once.
+	return &singleChain{
+		database: nd.Chain.Database(),
+		bm:       nd.BM,
+		sm:       checkTestSM{nd.SM},
+		logger:   nd.Chain.Logger(),
+	}
+}
+
+func newCheckTask(t *testing.T, c *singleChain, param string) *taskCheck {
+	task, err := taskCheckFactory(c, json.RawMessage(param))
+	assert.NoError(t, err)
+	return task.(*taskCheck)
+}
+
+func TestTaskCheck_Factory(t *testing.T) {
+	c := &singleChain{}
+	task := newCheckTask(t, c, `{"end":10}`)
+	assert.EqualValues(t, 0, task.start)
+	assert.EqualValues(t, 10, task.end)
+	assert.Equal(t, DefaultCheckFetchTimeout, task.timeout)
+
+	// it starts with the genesis of the pruned chain by default
+	c.cfg.GenesisStorage = checkTestGS{height: 5}
+	task = newCheckTask(t, c, `{"timeout":3}`)
+	assert.EqualValues(t, 5, task.start)
+	assert.Equal(t, 3*time.Second, task.timeout)
+	task = newCheckTask(t, c, `{"start":7}`)
+	assert.EqualValues(t, 7, task.start)
+
+	for _, param := range []string{
+		`{"end":4}`, `{"start":-1}`, `{"start":7,"end":6}`, `{"timeout":-1}`,
+	} {
+		_, err := taskCheckFactory(c, json.RawMessage(param))
+		assert.True(t, errors.IllegalArgumentError.Equals(err), param)
+	}
+}
+
+func TestTaskCheck_Basics(t *testing.T) {
+	nd := newCheckTestNode(t, 3)
+	defer nd.Close()
+
+	task := newCheckTask(t, newCheckTestChain(nd), `{}`)
+	assert.NoError(t, task.doCheck())
+	assert.EqualValues(t, 3, task.height)
+	assert.EqualValues(t, 0, task.failures)
+}
+
+func TestTaskCheck_Failures(t *testing.T) {
+	nd := newCheckTestNode(t, 3)
+	defer nd.Close()
+
+	blk, err := nd.BM.GetBlockByHeight(2)
+	assert.NoError(t, err)
+	tx, err := blk.NormalTransactions().Get(0)
+	assert.NoError(t, err)
+	bk, err := nd.Chain.Database().GetBucket(db.TransactionLocatorByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Delete(tx.ID()))
+
+	task := newCheckTask(t, newCheckTestChain(nd), `{"start":1,"end":2}`)
+	err = task.doCheck()
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.EqualValues(t, 1, task.failures)
+	assert.EqualValues(t, 0, task.recovered)
+}
+
+func TestTaskCheck_Stop(t *testing.T) {
+	nd := newCheckTestNode(t, 1)
+	defer nd.Close()
+
+	task := newCheckTask(t, newCheckTestChain(nd), `{}`)
+	task.Stop()
+	task.Stop()
+	assert.ErrorIs(t, task.doCheck(), errors.ErrInterrupted)
+}
+
+func TestTaskCheck_Pruned(t *testing.T) {
+	policy := &module.RetentionPolicy{State: 2, Receipts: 2, Interval: 1}
+	nd := newCheckTestNode(t, 6, test.UseRetentionPolicy(policy))
+	defer nd.Close()
+
+	dbase := nd.Chain.Database()
+	for i := 0; ; i++ {
+		_, state, receipts, err := block.GetPruningStatus(dbase)
+		assert.NoError(t, err)
+		if state == 5 && receipts == 5 {
+			break
+		}
+		if i > 500 {
+			assert.FailNow(t, "pruning isn't done")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	task := newCheckTask(t, newCheckTestChain(nd), `{}`)
+	assert.NoError(t, task.doCheck())
+	assert.EqualValues(t, 0, task.failures)
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	DefaultForkGenesisStorage = "fork.zip"
+)
+
+type forkParams struct {
+	Height     int64             `json:"height"`
+	NID        common.HexInt32   `json:"nid"`
+	CID        common.HexInt32   `json:"cid"`
+	Validators []*common.Address `json:"validators"`
+	File       string            `json:"file"`
+}
+
+// taskFork writes the genesis storage for a new chain starting with
+// the state of the block. Network ID, chain ID and validators of the
+// state are replaced, and all accounts and contracts are kept.
+type taskFork struct {
+	chain      *singleChain
+	result     resultStore
+	height     int64
+	nid        int
+	cid        int
+	validators []module.Validator
+	gsfile     string
+}
+
+func (t *taskFork) String() string {
+	return fmt.Sprintf("Fork(height=%d,nid=%#x,cid=%#x,validators=%d)",
+		t.height, t.nid, t.cid, len(t.validators))
+}
+
+func (t *taskFork) DetailOf(s State) string {
+	return "fork " + s.String()
+}
+
+func (t *taskFork) Start() error {
+	if err := t.chain.prepareManagers(); err != nil {
+		t.result.SetValue(err)
+		return err
+	}
+	go func() {
+		err := t._fork()
+		t.chain.releaseManagers()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskFork) _fork() (rerr error) {
+	c := t.chain
+	blk, err := c.bm.GetBlockByHeight(t.height)
+	if err != nil {
+		return errors.Wrapf(err, "fail to get block(height=%d)", t.height)
+	}
+
+	gsTmp := t.gsfile + TempSuffix
+	fd, err := os.OpenFile(gsTmp, os.O_CREATE|os.O_WRONLY|os.O_EXCL|os.O_TRUNC, 0700)
+	if err != nil {
+		return err
+	}
+	gsw := gs.NewGenesisStorageWriter(fd)
+	defer func() {
+		_ = gsw.Close()
+		_ = fd.Close()
+		if rerr != nil {
+			_ = os.Remove(gsTmp)
+		}
+	}()
+
+	result, vh, err := service.ForkResult(c.Database(), c.plt, blk.Result(),
+		t.nid, t.cid, t.validators, gs.NewDatabaseWithWriter(gsw))
+	if err != nil {
+		return errors.Wrap(err, "fail to fork result")
+	}
+	fg := &gs.ForkedGenesis{
+		CID:        common.HexInt32{Value: int32(t.cid)},
+		NID:        common.HexInt32{Value: int32(t.nid)},
+		Timestamp:  common.HexInt64{Value: blk.Timestamp()},
+		Result:     result,
+		Validators: vh,
+		Source: gs.ForkSource{
+			CID:    common.HexInt32{Value: int32(c.CID())},
+			NID:    common.HexInt32{Value: int32(c.NID())},
+			Height: common.HexInt64{Value: t.height},
+			Block:  blk.ID(),
+		},
+	}
+	g, err := json.Marshal(fg)
+	if err != nil {
+		return errors.Wrapf(err, "fail to marshal genesis=%+v", fg)
+	}
+	if err := gsw.WriteGenesis(g); err != nil {
+		return errors.Wrap(err, "fail to write genesis")
+	}
+	if err := gsw.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(gsTmp, t.gsfile); err != nil {
+		return errors.UnknownError.Wrapf(err, "fail to rename %s to %s", gsTmp, t.gsfile)
+	}
+	c.logger.Infof("FORK DONE height=%d nid=%#x cid=%#x file=%s",
+		t.height, t.nid, t.cid, t.gsfile)
+	return nil
+}
+
+func (t *taskFork) Stop() {
+	// nothing to do
+}
+
+func (t *taskFork) Wait() error {
+	return t.result.Wait()
+}
+
+func taskForkFactory(chain *singleChain, param json.RawMessage) (chainTask, error) {
+	var p forkParams
+	if err := json.Unmarshal(param, &p); err != nil {
+		return nil, err
+	}
+	if p.Height < 0 || p.NID.Value == 0 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidParameter(height=%d,nid=%#x)", p.Height, p.NID.Value)
+	}
+	if p.CID.Value == 0 {
+		p.CID = p.NID
+	}
+	if int(p.NID.Value) == chain.NID() || int(p.CID.Value) == chain.CID() {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"SameNetwork(nid=%#x,cid=%#x)", p.NID.Value, p.CID.Value)
+	}
+	if p.File == "" {
+		p.File = DefaultForkGenesisStorage
+	} else if strings.ContainsAny(p.File, "/\\") {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidFile(file=%s)", p.File)
+	}
+
+	var validators []module.Validator
+	if len(p.Validators) == 0 {
+		v, err := state.ValidatorFromAddress(chain.Wallet().Address())
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, v)
+	}
+	for _, addr := range p.Validators {
+		if addr == nil || addr.IsContract() {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidValidator(addr=%s)", addr)
+		}
+		v, err := state.ValidatorFromAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, v)
+	}
+	return &taskFork{
+		chain:      chain,
+		height:     p.Height,
+		nid:        int(p.NID.Value),
+		cid:        int(p.CID.Value),
+		validators: validators,
+		gsfile:     path.Join(chain.cfg.AbsBaseDir(), p.File),
+	}, nil
+}
+
+func init() {
+	registerTaskFactory("fork", taskForkFactory)
+}
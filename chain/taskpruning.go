@@ -25,6 +25,7 @@ import (
 	"github.com/icon-project/goloop/chain/gs"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/archive"
 )
 
 var pruningStates = map[State]string{
@@ -141,7 +142,10 @@ func (t *taskPruning) _copyDatabase(dbpath, dbtype string, from, to int64) (rerr
 			os.RemoveAll(dbpath)
 		}
 	}()
-	return t.chain.bm.ExportBlocks(from, to, dbase, t.OnExport)
+	if err := t.chain.bm.ExportBlocks(from, to, dbase, t.OnExport); err != nil {
+		return err
+	}
+	return archive.Copy(t.chain.Database(), dbase)
 }
 
 func (t *taskPruning) _interrupted() bool {
@@ -469,7 +469,7 @@ func (t *taskReset) _resetToGenesis() (ret error) {
 		return err
 	}
 	switch gsType {
-	case module.GenesisNormal:
+	case module.GenesisNormal, module.GenesisForked:
 		return t._cleanUp()
 	case module.GenesisPruned:
 		c := t.chain
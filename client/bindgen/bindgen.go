@@ -0,0 +1,443 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bindgen generates typed Go bindings of SCOREs from their APIs
+// returned by icx_getScoreApi.
+package bindgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Field is a field of struct type in the SCORE API.
+type Field struct {
+	Name   string  `json:"name"`
+	Type   string  `json:"type"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Param is an input of a method or an event in the SCORE API.
+type Param struct {
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+	Indexed string          `json:"indexed,omitempty"`
+	Fields  []Field         `json:"fields,omitempty"`
+}
+
+func (p *Param) optional() bool {
+	return p.Default != nil
+}
+
+type Output struct {
+	Type string `json:"type"`
+}
+
+// Method is an item of the SCORE API.
+type Method struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Inputs   []Param  `json:"inputs"`
+	Outputs  []Output `json:"outputs,omitempty"`
+	ReadOnly string   `json:"readonly,omitempty"`
+	Payable  string   `json:"payable,omitempty"`
+}
+
+func (m *Method) readOnly() bool {
+	return m.ReadOnly == "0x1"
+}
+
+func (m *Method) payable() bool {
+	return m.Payable == "0x1"
+}
+
+const (
+	typeFunction = "function"
+	typeFallback = "fallback"
+	typeEvent    = "eventlog"
+)
+
+// ParseAPI parses the result of icx_getScoreApi.
+func ParseAPI(bs []byte) ([]Method, error) {
+	var methods []Method
+	if err := json.Unmarshal(bs, &methods); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidScoreAPI")
+	}
+	return methods, nil
+}
+
+type Config struct {
+	// Package is the name of the generated package.
+	Package string
+	// Type is the name of the binding type. Empty means the exported form
+	// of the package name.
+	Type string
+}
+
+type generator struct {
+	name    string
+	structs map[string]string
+	imports map[string]bool
+	names   map[string]bool
+}
+
+// exported returns the exported Go identifier for the name.
+func exported(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == ' ' || r == '.' {
+			upper = true
+			continue
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	s := sb.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
+
+// unexported returns the unexported Go identifier for the parameter name.
+func unexported(name string) string {
+	s := []rune(exported(name))
+	s[0] = unicode.ToLower(s[0])
+	id := string(s)
+	if token.Lookup(id).IsKeyword() {
+		id += "_"
+	}
+	return id
+}
+
+func parseType(t string) (string, int) {
+	depth := 0
+	for strings.HasPrefix(t, "[]") {
+		t = t[2:]
+		depth++
+	}
+	return t, depth
+}
+
+// goType returns Go type for the type in the SCORE API. Values of the type
+// can be nil except for str, so they can be omitted.
+func (g *generator) goType(t string, fields []Field, structName string) (string, error) {
+	tag, depth := parseType(t)
+	var gt string
+	switch tag {
+	case "int":
+		gt = "*common.HexInt"
+		g.imports["common"] = true
+	case "str":
+		gt = "string"
+	case "bytes":
+		gt = "common.HexBytes"
+		g.imports["common"] = true
+	case "bool":
+		gt = "*common.HexBool"
+		g.imports["common"] = true
+	case "Address":
+		gt = "*common.Address"
+		g.imports["common"] = true
+	case "list":
+		gt = "[]interface{}"
+	case "dict":
+		gt = "map[string]interface{}"
+	case "struct":
+		if len(fields) == 0 {
+			gt = "map[string]interface{}"
+		} else {
+			if err := g.addStruct(structName, fields); err != nil {
+				return "", err
+			}
+			gt = "*" + structName
+		}
+	default:
+		return "", errors.UnsupportedError.Errorf("UnknownType(type=%s)", t)
+	}
+	return strings.Repeat("[]", depth) + gt, nil
+}
+
+// paramType returns Go type of the parameter. Optional str parameters use
+// pointer type to be omitted.
+func (g *generator) paramType(p *Param, structName string) (string, error) {
+	gt, err := g.goType(p.Type, p.Fields, structName)
+	if err != nil {
+		return "", err
+	}
+	if p.optional() && gt == "string" {
+		gt = "*" + gt
+	}
+	return gt, nil
+}
+
+func (g *generator) addStruct(name string, fields []Field) error {
+	if _, ok := g.structs[name]; ok {
+		return nil
+	}
+	g.structs[name] = ""
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "type %s struct {\n", name)
+	for _, f := range fields {
+		gt, err := g.goType(f.Type, f.Fields, name+exported(f.Name))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&sb, "\t%s %s `json:\"%s\"`\n", exported(f.Name), gt, f.Name)
+	}
+	sb.WriteString("}\n")
+	g.structs[name] = sb.String()
+	return nil
+}
+
+func (g *generator) reserve(name string) error {
+	if g.names[name] {
+		return errors.InvalidStateError.Errorf("DuplicateName(name=%s)", name)
+	}
+	g.names[name] = true
+	return nil
+}
+
+func (g *generator) writeParams(w *bytes.Buffer, m *Method) ([]string, error) {
+	var args []string
+	for i := range m.Inputs {
+		p := &m.Inputs[i]
+		gt, err := g.paramType(p, exported(m.Name)+exported(p.Name))
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, unexported(p.Name)+" "+gt)
+	}
+	w.WriteString("\tparams := map[string]interface{}{}\n")
+	for i := range m.Inputs {
+		p := &m.Inputs[i]
+		if p.optional() {
+			fmt.Fprintf(w, "\tif %s != nil {\n\t\tparams[%q] = %s\n\t}\n",
+				unexported(p.Name), p.Name, unexported(p.Name))
+		} else {
+			fmt.Fprintf(w, "\tparams[%q] = %s\n", p.Name, unexported(p.Name))
+		}
+	}
+	return args, nil
+}
+
+func (g *generator) writeReadOnly(w *bytes.Buffer, m *Method) error {
+	name := exported(m.Name)
+	if err := g.reserve(name); err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	args, err := g.writeParams(&body, m)
+	if err != nil {
+		return err
+	}
+	rt := "interface{}"
+	if len(m.Outputs) == 1 {
+		if rt, err = g.goType(m.Outputs[0].Type, nil, name+"Result"); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "// %s calls read-only method %q.\n", name, m.Name)
+	fmt.Fprintf(w, "func (s *%s) %s(%s) (%s, error) {\n", g.name, name, strings.Join(args, ", "), rt)
+	w.Write(body.Bytes())
+	fmt.Fprintf(w, "\tvar ret %s\n", rt)
+	fmt.Fprintf(w, "\terr := s.Client.CallFor(s.Address, s.Height, %q, params, &ret)\n", m.Name)
+	w.WriteString("\treturn ret, err\n}\n\n")
+	return nil
+}
+
+func (g *generator) writeTransaction(w *bytes.Buffer, m *Method) error {
+	name := exported(m.Name) + "Tx"
+	if err := g.reserve(name); err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	args, err := g.writeParams(&body, m)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "// %s returns a transaction calling method %q.\n", name, m.Name)
+	if m.payable() {
+		w.WriteString("// The method is payable, so Value of the transaction can be set.\n")
+	}
+	g.imports["v3"] = true
+	fmt.Fprintf(w, "func (s *%s) %s(%s) *v3.TransactionParam {\n", g.name, name, strings.Join(args, ", "))
+	w.Write(body.Bytes())
+	fmt.Fprintf(w, "\treturn client.NewCallTransaction(s.Address, %q, params)\n}\n\n", m.Name)
+	return nil
+}
+
+func (g *generator) writeFallback(w *bytes.Buffer) error {
+	name := "FallbackTx"
+	if err := g.reserve(name); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "// %s returns a transaction transferring Value to the contract.\n", name)
+	g.imports["v3"] = true
+	fmt.Fprintf(w, "func (s *%s) %s() *v3.TransactionParam {\n", g.name, name)
+	w.WriteString("\treturn &v3.TransactionParam{\n")
+	w.WriteString("\t\tVersion:   v3.VersionValue,\n")
+	w.WriteString("\t\tToAddress: s.Address,\n")
+	w.WriteString("\t}\n}\n\n")
+	return nil
+}
+
+func (g *generator) writeEvent(w *bytes.Buffer, m *Method) error {
+	name := exported(m.Name) + "Event"
+	if err := g.reserve(name); err != nil {
+		return err
+	}
+	if err := g.reserve("Decode" + name); err != nil {
+		return err
+	}
+	types := make([]string, len(m.Inputs))
+	var fields, values []string
+	for i := range m.Inputs {
+		p := &m.Inputs[i]
+		types[i] = p.Type
+		gt, err := g.goType(p.Type, p.Fields, name+exported(p.Name))
+		if err != nil {
+			return err
+		}
+		fields = append(fields, fmt.Sprintf("\t%s %s\n", exported(p.Name), gt))
+		values = append(values, "&ev."+exported(p.Name))
+	}
+	sig := fmt.Sprintf("%s(%s)", m.Name, strings.Join(types, ","))
+
+	fmt.Fprintf(w, "// %sSignature is the signature of event %q.\n", name, m.Name)
+	fmt.Fprintf(w, "const %sSignature = %q\n\n", name, sig)
+	fmt.Fprintf(w, "// %s is a decoded event log of event %q.\n", name, m.Name)
+	fmt.Fprintf(w, "type %s struct {\n%s}\n\n", name, strings.Join(fields, ""))
+	fmt.Fprintf(w, "// Decode%s decodes the event log of event %q.\n", name, m.Name)
+	fmt.Fprintf(w, "func Decode%s(el *client.EventLog) (*%s, error) {\n", name, name)
+	fmt.Fprintf(w, "\tev := new(%s)\n", name)
+	fmt.Fprintf(w, "\tif err := client.DecodeEventLog(el, %sSignature", name)
+	for _, v := range values {
+		w.WriteString(", " + v)
+	}
+	w.WriteString("); err != nil {\n\t\treturn nil, err\n\t}\n\treturn ev, nil\n}\n\n")
+	return nil
+}
+
+// Generate returns the formatted source of the binding for the methods.
+func Generate(cfg *Config, methods []Method) ([]byte, error) {
+	if cfg.Package == "" || !token.IsIdentifier(cfg.Package) {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidPackage(%q)", cfg.Package)
+	}
+	name := cfg.Type
+	if name == "" {
+		name = exported(cfg.Package)
+	}
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidType(%q)", name)
+	}
+	g := &generator{
+		name:    name,
+		structs: make(map[string]string),
+		imports: make(map[string]bool),
+		names:   make(map[string]bool),
+	}
+	for _, n := range []string{name, "New" + name} {
+		if err := g.reserve(n); err != nil {
+			return nil, err
+		}
+	}
+
+	var body bytes.Buffer
+	for i := range methods {
+		m := &methods[i]
+		var err error
+		switch m.Type {
+		case typeFunction:
+			if m.readOnly() {
+				err = g.writeReadOnly(&body, m)
+			} else {
+				err = g.writeTransaction(&body, m)
+			}
+		case typeFallback:
+			err = g.writeFallback(&body)
+		case typeEvent:
+			err = g.writeEvent(&body, m)
+		default:
+			err = errors.UnsupportedError.Errorf("UnknownMethodType(type=%s)", m.Type)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Method(%s)", m.Name)
+		}
+	}
+	for n := range g.structs {
+		if err := g.reserve(n); err != nil {
+			return nil, err
+		}
+	}
+
+	var w bytes.Buffer
+	w.WriteString("// Code generated by goloop bind. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&w, "package %s\n\n", cfg.Package)
+	w.WriteString("import (\n")
+	w.WriteString("\t\"github.com/icon-project/goloop/client\"\n")
+	if g.imports["common"] {
+		w.WriteString("\t\"github.com/icon-project/goloop/common\"\n")
+	}
+	w.WriteString("\t\"github.com/icon-project/goloop/server/jsonrpc\"\n")
+	if g.imports["v3"] {
+		w.WriteString("\tv3 \"github.com/icon-project/goloop/server/v3\"\n")
+	}
+	w.WriteString(")\n\n")
+
+	fmt.Fprintf(&w, "// %s is a binding of the contract.\n", name)
+	fmt.Fprintf(&w, "type %s struct {\n", name)
+	w.WriteString("\tClient  *client.ClientV3\n")
+	w.WriteString("\tAddress jsonrpc.Address\n")
+	w.WriteString("\t// Height is the block height for read-only calls. Zero means the last.\n")
+	w.WriteString("\tHeight int64\n")
+	w.WriteString("}\n\n")
+	fmt.Fprintf(&w, "func New%s(c *client.ClientV3, addr jsonrpc.Address) *%s {\n", name, name)
+	fmt.Fprintf(&w, "\treturn &%s{Client: c, Address: addr}\n}\n\n", name)
+
+	snames := make([]string, 0, len(g.structs))
+	for n := range g.structs {
+		snames = append(snames, n)
+	}
+	sort.Strings(snames)
+	for _, n := range snames {
+		w.WriteString(g.structs[n])
+		w.WriteString("\n")
+	}
+	w.Write(body.Bytes())
+
+	src, err := format.Source(w.Bytes())
+	if err != nil {
+		return nil, errors.InvalidStateError.Wrap(err, "FailToFormat")
+	}
+	return src, nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bindgen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestGenerate(t *testing.T) {
+	bs, err := os.ReadFile("testdata/token.json")
+	assert.NoError(t, err)
+	methods, err := ParseAPI(bs)
+	assert.NoError(t, err)
+
+	src, err := Generate(&Config{Package: "token"}, methods)
+	assert.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "token.go", src, 0)
+	assert.NoError(t, err)
+
+	code := string(src)
+	for _, s := range []string{
+		"type Token struct",
+		"func NewToken(c *client.ClientV3, addr jsonrpc.Address) *Token",
+		"func (s *Token) BalanceOf(owner *common.Address) (*common.HexInt, error)",
+		"func (s *Token) IsOwner(addr *common.Address, type_ *string) (*common.HexBool, error)",
+		"func (s *Token) TransferTx(to *common.Address, value *common.HexInt, data common.HexBytes) *v3.TransactionParam",
+		"if data != nil {",
+		"func (s *Token) SetRoutesTx(routes []*SetRoutesRoutes) *v3.TransactionParam",
+		"Meta   *SetRoutesRoutesMeta `json:\"meta\"`",
+		"func (s *Token) FallbackTx() *v3.TransactionParam",
+		"const TransferEventSignature = \"Transfer(Address,Address,int,bytes)\"",
+		"func DecodeTransferEvent(el *client.EventLog) (*TransferEvent, error)",
+	} {
+		assert.Contains(t, code, s)
+	}
+
+	src, err = Generate(&Config{Package: "token", Type: "IRC2"}, methods)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "func (s *IRC2) Name() (string, error)")
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	_, err := Generate(&Config{Package: "my-token"}, nil)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	_, err = Generate(&Config{Package: "token", Type: "token"}, nil)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	_, err = Generate(&Config{Package: "token"}, []Method{
+		{Type: "function", Name: "get", Inputs: []Param{{Name: "v", Type: "float"}}},
+	})
+	assert.True(t, errors.UnsupportedError.Equals(err))
+
+	_, err = Generate(&Config{Package: "token"}, []Method{
+		{Type: "function", Name: "get_value", ReadOnly: "0x1"},
+		{Type: "function", Name: "getValue", ReadOnly: "0x1"},
+	})
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	_, err = ParseAPI([]byte("{}"))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+// Helpers used by the bindings generated with "goloop bind".
+
+// CallFor calls the read-only method of the contract, and decodes the
+// result into ret. Zero height means the last block.
+func (c *ClientV3) CallFor(to jsonrpc.Address, height int64, method string, params map[string]interface{}, ret interface{}) error {
+	data := map[string]interface{}{"method": method}
+	if len(params) > 0 {
+		data["params"] = params
+	}
+	param := &v3.CallParam{
+		ToAddress: to,
+		DataType:  "call",
+		Data:      data,
+	}
+	if height > 0 {
+		param.Height = jsonrpc.HexIntFromInt64(height)
+	}
+	_, err := c.Do("icx_call", param, ret)
+	return err
+}
+
+// NewCallTransaction returns a transaction calling the method of the
+// contract. The caller fills the others like from, stepLimit and nid before
+// sending it with ClientV3.SendTransaction.
+func NewCallTransaction(to jsonrpc.Address, method string, params map[string]interface{}) *v3.TransactionParam {
+	data := map[string]interface{}{"method": method}
+	if len(params) > 0 {
+		data["params"] = params
+	}
+	return &v3.TransactionParam{
+		Version:   v3.VersionValue,
+		ToAddress: to,
+		DataType:  "call",
+		Data:      data,
+	}
+}
+
+// DecodeEventLog decodes the values of the event log with the signature.
+// Values are pointers to the fields in the order of the event parameters.
+func DecodeEventLog(el *EventLog, signature string, values ...interface{}) error {
+	if len(el.Indexed) == 0 || el.Indexed[0] == nil || *el.Indexed[0] != signature {
+		return errors.IllegalArgumentError.Errorf("SignatureMismatch(exp=%s)", signature)
+	}
+	items := append(el.Indexed[1:len(el.Indexed):len(el.Indexed)], el.Data...)
+	if len(items) != len(values) {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidEventLog(sig=%s,exp=%d,real=%d)", signature, len(values), len(items))
+	}
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		bs, err := json.Marshal(*item)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(bs, values[i]); err != nil {
+			return errors.IllegalArgumentError.Wrapf(err,
+				"InvalidEventValue(sig=%s,idx=%d,value=%s)", signature, i, *item)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestDecodeEventLog(t *testing.T) {
+	const sig = "Transfer(Address,int,bool,str,bytes)"
+	el := &EventLog{
+		Addr: "cx0000000000000000000000000000000000000001",
+		Indexed: []*string{
+			strPtr(sig),
+			strPtr("hx0000000000000000000000000000000000000002"),
+			strPtr("0x10"),
+		},
+		Data: []*string{strPtr("0x1"), strPtr("memo"), nil},
+	}
+	var from *common.Address
+	var value *common.HexInt
+	var flag *common.HexBool
+	var memo string
+	var data common.HexBytes
+	err := DecodeEventLog(el, sig, &from, &value, &flag, &memo, &data)
+	assert.NoError(t, err)
+	assert.Equal(t, "hx0000000000000000000000000000000000000002", from.String())
+	assert.Equal(t, int64(16), value.Int64())
+	assert.True(t, flag.Value)
+	assert.Equal(t, "memo", memo)
+	assert.Nil(t, data)
+
+	err = DecodeEventLog(el, "Transfer(Address,int)", &from, &value)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	err = DecodeEventLog(el, sig, &from, &value)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	err = DecodeEventLog(el, sig, &value, &from, &flag, &memo, &data)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
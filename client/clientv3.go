@@ -164,6 +164,27 @@ type NetworkInfo struct {
 	StepPrice jsonrpc.HexInt `json:"stepPrice"`
 }
 
+//refer service/manager.go accountProof.ToJSON
+type AccountProof struct {
+	Address      jsonrpc.Address    `json:"address"`
+	StateHash    jsonrpc.HexBytes   `json:"stateHash"`
+	Account      *AccountInfo       `json:"account,omitempty"`
+	AccountProof []jsonrpc.HexBytes `json:"accountProof,omitempty"`
+	StorageProof []StorageProof     `json:"storageProof"`
+}
+
+type AccountInfo struct {
+	Balance     jsonrpc.HexInt   `json:"balance"`
+	StorageHash jsonrpc.HexBytes `json:"storageHash,omitempty"`
+	CodeHash    jsonrpc.HexBytes `json:"codeHash,omitempty"`
+}
+
+type StorageProof struct {
+	Key   jsonrpc.HexBytes   `json:"key"`
+	Value jsonrpc.HexBytes   `json:"value,omitempty"`
+	Proof []jsonrpc.HexBytes `json:"proof,omitempty"`
+}
+
 //refer service/state/btp.go:887 network.ToJSON
 //refer server/v3/api_v3.go:692 getBTPNetworkInfo
 type BTPNetworkInfo struct {
@@ -292,16 +313,11 @@ func (c *ClientV3) GetTransactionByHash(param *v3.TransactionHashParam) (*Transa
 var txSerializeExcludes = map[string]bool{"signature": true}
 
 func SignTransaction(w module.Wallet, param *v3.TransactionParam) error {
-	js, err := json.Marshal(param)
+	hash, err := TransactionHash(param)
 	if err != nil {
 		return err
 	}
-	bs, err := transaction.SerializeJSON(js, nil, txSerializeExcludes)
-	if err != nil {
-		return err
-	}
-	bs = append([]byte("icx_sendTransaction."), bs...)
-	sig, err := w.Sign(crypto.SHA3Sum256(bs))
+	sig, err := w.Sign(hash)
 	if err != nil {
 		return err
 	}
@@ -393,6 +409,15 @@ func (c *ClientV3) GetProofForEvents(param *v3.ProofEventsParam) ([][][]byte, er
 	return result, nil
 }
 
+func (c *ClientV3) GetProof(param *v3.AccountProofParam) (*AccountProof, error) {
+	result := &AccountProof{}
+	_, err := c.Do("icx_getProof", param, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *ClientV3) GetBTPNetworkInfo(param *v3.BTPQueryParam) (*BTPNetworkInfo, error) {
 	ni := &BTPNetworkInfo{}
 	if _, err := c.Do("btp_getNetworkInfo", param, ni); err != nil {
@@ -454,6 +479,15 @@ func (c *ClientV3) GetScoreStatus(param *v3.ScoreAddressParam) (interface{}, err
 	return result, nil
 }
 
+func (c *ClientV3) EstimateReward(param *v3.EstimateRewardParam) (interface{}, error) {
+	var result interface{}
+	_, err := c.Do("icx_estimateReward", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *ClientV3) GetNetworkInfo() (*NetworkInfo, error) {
 	var result *NetworkInfo
 	_, err := c.Do("icx_getNetworkInfo", nil, &result)
@@ -23,6 +23,9 @@ type JsonRpcClient struct {
 	Endpoint     string
 	CustomHeader map[string]string
 	Pre          func(req *http.Request) error
+
+	// router delivers requests for Do instead of Endpoint if it's set.
+	router func(method string, reqPtr, respPtr interface{}) (*Response, error)
 }
 
 type Response struct {
@@ -80,6 +83,9 @@ func (c *JsonRpcClient) _do(req *http.Request) (resp *http.Response, err error)
 }
 
 func (c *JsonRpcClient) Do(method string, reqPtr, respPtr interface{}) (jrResp *Response, err error) {
+	if c.router != nil {
+		return c.router(method, reqPtr, respPtr)
+	}
 	return c.DoURL(c.Endpoint, method, reqPtr, respPtr)
 }
 
@@ -0,0 +1,434 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	DefaultHealthCheckInterval = 5 * time.Second
+	DefaultMaxHeightLag        = 5
+)
+
+// DefaultQuorumMethods is the methods cross-checked by MultiClientV3 if
+// MultiClientConfig.QuorumMethods is empty.
+var DefaultQuorumMethods = []string{
+	"icx_getBalance",
+	"icx_getTransactionResult",
+}
+
+// heightMethods is the methods reading the state at the block given by the
+// optional "height" parameter.
+var heightMethods = map[string]bool{
+	"icx_call":               true,
+	"icx_getBalance":         true,
+	"icx_getScoreApi":        true,
+	"icx_getScoreStatus":     true,
+	"icx_getTotalSupply":     true,
+	"icx_getStorageAt":       true,
+	"icx_getProof":           true,
+	"icx_estimateReward":     true,
+	"btp_getNetworkInfo":     true,
+	"btp_getNetworkTypeInfo": true,
+}
+
+type MultiClientConfig struct {
+	// HealthCheckInterval is the interval of checking last block height of
+	// the endpoints. Zero means DefaultHealthCheckInterval.
+	HealthCheckInterval time.Duration
+
+	// MaxHeightLag is the maximum difference of the last block height from
+	// the highest one for a healthy endpoint. Zero means DefaultMaxHeightLag.
+	MaxHeightLag int64
+
+	// Quorum is the number of endpoints returning the same result to accept
+	// it for QuorumMethods. Zero or one disables cross-checks.
+	Quorum int
+
+	// QuorumMethods is the methods for cross-checks. Empty means
+	// DefaultQuorumMethods. Requests of the methods reading the state
+	// without the height are sent with the lowest last block height of
+	// the healthy endpoints, so all of them read the same state.
+	QuorumMethods []string
+}
+
+type EndpointStatus struct {
+	URL       string
+	Height    int64
+	Healthy   bool
+	LastError error
+	Requests  int
+	Failures  int
+}
+
+type endpoint struct {
+	url      string
+	checked  bool
+	height   int64
+	err      error
+	failed   bool
+	requests int
+	failures int
+}
+
+// MultiClientV3 is ClientV3 working with multiple endpoints of the same
+// chain. Requests are distributed among the healthy endpoints, and are
+// sent to the other endpoints on failures of the endpoint. Websocket
+// monitoring and debug APIs use the first endpoint.
+type MultiClientV3 struct {
+	*ClientV3
+
+	interval      time.Duration
+	maxLag        int64
+	quorum        int
+	quorumMethods map[string]bool
+
+	lock      sync.Mutex
+	endpoints []*endpoint
+	next      int
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+func NewMultiClientV3(endpoints []string, cfg *MultiClientConfig) (*MultiClientV3, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.IllegalArgumentError.New("NoEndpoints")
+	}
+	if cfg == nil {
+		cfg = &MultiClientConfig{}
+	}
+	if cfg.Quorum < 0 || cfg.Quorum > len(endpoints) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidQuorum(quorum=%d,endpoints=%d)", cfg.Quorum, len(endpoints))
+	}
+	m := &MultiClientV3{
+		ClientV3:      NewClientV3(endpoints[0]),
+		interval:      cfg.HealthCheckInterval,
+		maxLag:        cfg.MaxHeightLag,
+		quorum:        cfg.Quorum,
+		quorumMethods: make(map[string]bool),
+		stopCh:        make(chan struct{}),
+	}
+	if m.interval <= 0 {
+		m.interval = DefaultHealthCheckInterval
+	}
+	if m.maxLag <= 0 {
+		m.maxLag = DefaultMaxHeightLag
+	}
+	methods := cfg.QuorumMethods
+	if len(methods) == 0 {
+		methods = DefaultQuorumMethods
+	}
+	for _, method := range methods {
+		m.quorumMethods[method] = true
+	}
+	for _, url := range endpoints {
+		m.endpoints = append(m.endpoints, &endpoint{url: url})
+	}
+	m.router = m.do
+	go m.healthCheckLoop()
+	return m, nil
+}
+
+func (m *MultiClientV3) healthCheckLoop() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.CheckHealth()
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth checks last block height of all endpoints, and returns after
+// all of them are done.
+func (m *MultiClientV3) CheckHealth() {
+	var wg sync.WaitGroup
+	for _, ep := range m.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			blk := &Block{}
+			_, err := m.DoURL(ep.url, "icx_getLastBlock", nil, blk)
+
+			m.lock.Lock()
+			defer m.lock.Unlock()
+			ep.checked = true
+			ep.err = err
+			if err == nil {
+				ep.height = blk.Height
+				ep.failed = false
+			}
+		}(ep)
+	}
+	wg.Wait()
+}
+
+func (m *MultiClientV3) maxHeightInLock() int64 {
+	var height int64
+	for _, ep := range m.endpoints {
+		if ep.checked && ep.err == nil && ep.height > height {
+			height = ep.height
+		}
+	}
+	return height
+}
+
+func (m *MultiClientV3) isHealthyInLock(ep *endpoint, height int64) bool {
+	if ep.failed || ep.err != nil {
+		return false
+	}
+	// endpoints not checked yet are healthy until the first check.
+	return !ep.checked || height-ep.height <= m.maxLag
+}
+
+// candidates returns the endpoints in the order of trial. Healthy ones come
+// first in round-robin, and the others follow them as last resorts.
+func (m *MultiClientV3) candidates() []*endpoint {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	height := m.maxHeightInLock()
+	var healthy, others []*endpoint
+	for _, ep := range m.endpoints {
+		if m.isHealthyInLock(ep, height) {
+			healthy = append(healthy, ep)
+		} else {
+			others = append(others, ep)
+		}
+	}
+	if len(healthy) > 0 {
+		m.next = (m.next + 1) % len(healthy)
+		healthy = append(healthy[m.next:], healthy[:m.next]...)
+	}
+	return append(healthy, others...)
+}
+
+func (m *MultiClientV3) onResult(ep *endpoint, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ep.requests += 1
+	if err != nil {
+		ep.failures += 1
+		ep.failed = true
+	}
+}
+
+// isEndpointError returns whether the error comes from the endpoint itself,
+// so the request may succeed with other endpoints.
+func isEndpointError(err error) bool {
+	if je, ok := err.(*jsonrpc.Error); ok {
+		switch je.Code {
+		case jsonrpc.ErrorCodeInternal, jsonrpc.ErrorCodeServer,
+			jsonrpc.ErrorCodeSystem, jsonrpc.ErrorLackOfResource,
+			jsonrpc.ErrorCodeSystemTimeout:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+func (m *MultiClientV3) do(method string, reqPtr, respPtr interface{}) (*Response, error) {
+	if m.quorum > 1 && m.quorumMethods[method] {
+		return m.doWithQuorum(method, reqPtr, respPtr)
+	}
+	var err error
+	for _, ep := range m.candidates() {
+		var resp *Response
+		resp, err = m.DoURL(ep.url, method, reqPtr, respPtr)
+		if err == nil || !isEndpointError(err) {
+			m.onResult(ep, nil)
+			return resp, err
+		}
+		m.onResult(ep, err)
+	}
+	return nil, err
+}
+
+type quorumResult struct {
+	resp  *Response
+	err   error
+	count int
+}
+
+// quorumHeight returns the lowest last block height of the healthy
+// endpoints, which all of them can read the state at.
+func (m *MultiClientV3) quorumHeight() (int64, error) {
+	m.lock.Lock()
+	unchecked := false
+	for _, ep := range m.endpoints {
+		if !ep.checked {
+			unchecked = true
+		}
+	}
+	m.lock.Unlock()
+	if unchecked {
+		m.CheckHealth()
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	max := m.maxHeightInLock()
+	height := int64(-1)
+	for _, ep := range m.endpoints {
+		if ep.checked && m.isHealthyInLock(ep, max) {
+			if height < 0 || ep.height < height {
+				height = ep.height
+			}
+		}
+	}
+	if height < 0 {
+		return 0, errors.InvalidStateError.New("NoHealthyEndpoint")
+	}
+	return height, nil
+}
+
+// withHeight returns the parameters having the height. The parameters are
+// returned as they are if they already have it.
+func withHeight(reqPtr interface{}, height int64) (interface{}, error) {
+	params := make(map[string]json.RawMessage)
+	if reqPtr != nil {
+		bs, err := json.Marshal(reqPtr)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(bs, &params); err != nil {
+			return nil, err
+		}
+	}
+	if h, ok := params["height"]; ok && string(h) != `""` && string(h) != "null" {
+		return reqPtr, nil
+	}
+	params["height"] = json.RawMessage(fmt.Sprintf(`"%#x"`, height))
+	return params, nil
+}
+
+func (m *MultiClientV3) doWithQuorum(method string, reqPtr, respPtr interface{}) (*Response, error) {
+	if heightMethods[method] {
+		height, err := m.quorumHeight()
+		if err != nil {
+			return nil, err
+		}
+		if reqPtr, err = withHeight(reqPtr, height); err != nil {
+			return nil, err
+		}
+	}
+	eps := m.candidates()
+	resps := make([]*Response, len(eps))
+	errs := make([]error, len(eps))
+	var wg sync.WaitGroup
+	for i, ep := range eps {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			resps[i], errs[i] = m.DoURL(ep.url, method, reqPtr, nil)
+			if errs[i] != nil && isEndpointError(errs[i]) {
+				m.onResult(ep, errs[i])
+			} else {
+				m.onResult(ep, nil)
+			}
+		}(i, ep)
+	}
+	wg.Wait()
+
+	results := make(map[string]*quorumResult)
+	var best *quorumResult
+	for i := range eps {
+		var key string
+		if errs[i] != nil {
+			if isEndpointError(errs[i]) {
+				continue
+			}
+			je := errs[i].(*jsonrpc.Error)
+			key = fmt.Sprintf("error:%d:%s", je.Code, je.Message)
+		} else {
+			buf := bytes.NewBuffer(nil)
+			if err := json.Compact(buf, resps[i].Result); err != nil {
+				continue
+			}
+			key = "result:" + buf.String()
+		}
+		r, ok := results[key]
+		if !ok {
+			r = &quorumResult{resp: resps[i], err: errs[i]}
+			results[key] = r
+		}
+		r.count += 1
+		if best == nil || r.count > best.count {
+			best = r
+		}
+	}
+	if best == nil || best.count < m.quorum {
+		count := 0
+		if best != nil {
+			count = best.count
+		}
+		return nil, errors.InvalidStateError.Errorf(
+			"NoQuorum(method=%s,quorum=%d,agreed=%d,endpoints=%d,results=%d)",
+			method, m.quorum, count, len(eps), len(results))
+	}
+	if best.err != nil {
+		return nil, best.err
+	}
+	if respPtr != nil {
+		if err := json.Unmarshal(best.resp.Result, respPtr); err != nil {
+			return nil, err
+		}
+	}
+	return best.resp, nil
+}
+
+// Status returns status of the endpoints in the order of creation.
+func (m *MultiClientV3) Status() []EndpointStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	height := m.maxHeightInLock()
+	status := make([]EndpointStatus, len(m.endpoints))
+	for i, ep := range m.endpoints {
+		status[i] = EndpointStatus{
+			URL:       ep.url,
+			Height:    ep.height,
+			Healthy:   m.isHealthyInLock(ep, height),
+			LastError: ep.err,
+			Requests:  ep.requests,
+			Failures:  ep.failures,
+		}
+	}
+	return status
+}
+
+// Close stops health checks and closes websocket connections.
+func (m *MultiClientV3) Close() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+	m.Cleanup()
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+type testEndpoint struct {
+	lock     sync.Mutex
+	height   int64
+	balance  string
+	down     bool
+	requests int
+
+	// byHeight makes icx_getBalance return the height of the state read.
+	byHeight bool
+}
+
+func (e *testEndpoint) set(height int64, balance string, down bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.height, e.balance, e.down = height, balance, down
+}
+
+func (e *testEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var req jsonrpc.Request
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	resp := &jsonrpc.Response{Version: jsonrpc.Version, ID: req.ID}
+	switch *req.Method {
+	case "icx_getLastBlock":
+		resp.Result = map[string]interface{}{"height": e.height}
+	case "icx_getBalance":
+		e.requests += 1
+		if e.byHeight {
+			var param v3.AddressParam
+			_ = json.Unmarshal(req.Params, &param)
+			height := jsonrpc.HexInt(intconv.FormatInt(e.height))
+			if param.Height != "" {
+				height = param.Height
+			}
+			resp.Result = height
+		} else {
+			resp.Result = e.balance
+		}
+	case "icx_getTransactionResult":
+		e.requests += 1
+		resp.Error = jsonrpc.ErrorCodeNotFound.New("NotFound")
+	default:
+		resp.Error = jsonrpc.ErrorCodeMethodNotFound.New("MethodNotFound")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (e *testEndpoint) Requests() int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.requests
+}
+
+func newTestEndpoints(n int) ([]*testEndpoint, []string, func()) {
+	eps := make([]*testEndpoint, n)
+	urls := make([]string, n)
+	srvs := make([]*httptest.Server, n)
+	for i := range eps {
+		eps[i] = &testEndpoint{height: 100, balance: "0x10"}
+		srvs[i] = httptest.NewServer(eps[i])
+		urls[i] = srvs[i].URL + "/api/v3"
+	}
+	return eps, urls, func() {
+		for _, srv := range srvs {
+			srv.Close()
+		}
+	}
+}
+
+var testAddressParam = &v3.AddressParam{
+	Address: "hx0000000000000000000000000000000000000001",
+}
+
+func TestMultiClientV3_Failover(t *testing.T) {
+	eps, urls, closer := newTestEndpoints(3)
+	defer closer()
+
+	c, err := NewMultiClientV3(urls, &MultiClientConfig{
+		HealthCheckInterval: time.Hour,
+	})
+	assert.NoError(t, err)
+	defer c.Close()
+	c.CheckHealth()
+
+	// reads are distributed among the endpoints
+	for i := 0; i < 6; i++ {
+		b, err := c.GetBalance(testAddressParam)
+		assert.NoError(t, err)
+		assert.Equal(t, "0x10", string(*b))
+	}
+	for _, ep := range eps {
+		assert.Equal(t, 2, ep.Requests())
+	}
+
+	// lagging endpoint is not used
+	eps[0].set(90, "0x10", false)
+	c.CheckHealth()
+	status := c.Status()
+	assert.False(t, status[0].Healthy)
+	assert.True(t, status[1].Healthy)
+	for i := 0; i < 4; i++ {
+		_, err := c.GetBalance(testAddressParam)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, eps[0].Requests())
+
+	// failed endpoint is skipped until next health check
+	eps[1].set(100, "0x10", true)
+	for i := 0; i < 4; i++ {
+		_, err := c.GetBalance(testAddressParam)
+		assert.NoError(t, err)
+	}
+	status = c.Status()
+	assert.False(t, status[1].Healthy)
+	assert.Equal(t, 1, status[1].Failures)
+
+	// application errors are not retried
+	before := eps[2].Requests()
+	_, err = c.GetTransactionResult(&v3.TransactionHashParam{Hash: "0x01"})
+	je, ok := err.(*jsonrpc.Error)
+	if assert.True(t, ok) {
+		assert.Equal(t, jsonrpc.ErrorCodeNotFound, je.Code)
+	}
+	assert.Equal(t, before+1, eps[2].Requests())
+
+	// all of them are down
+	for _, ep := range eps {
+		ep.set(100, "0x10", true)
+	}
+	_, err = c.GetBalance(testAddressParam)
+	assert.Error(t, err)
+}
+
+func TestMultiClientV3_Quorum(t *testing.T) {
+	eps, urls, closer := newTestEndpoints(3)
+	defer closer()
+
+	_, err := NewMultiClientV3(urls, &MultiClientConfig{Quorum: 4})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	c, err := NewMultiClientV3(urls, &MultiClientConfig{
+		HealthCheckInterval: time.Hour,
+		Quorum:              2,
+	})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	eps[0].set(100, "0x11", false)
+	b, err := c.GetBalance(testAddressParam)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x10", string(*b))
+
+	eps[1].set(100, "0x12", false)
+	_, err = c.GetBalance(testAddressParam)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	eps[2].set(100, "0x11", false)
+	b, err = c.GetBalance(testAddressParam)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x11", string(*b))
+
+	// agreed errors are returned as they are
+	_, err = c.GetTransactionResult(&v3.TransactionHashParam{Hash: "0x01"})
+	_, ok := err.(*jsonrpc.Error)
+	assert.True(t, ok)
+
+	// unavailable endpoints are not counted
+	eps[0].set(100, "0x11", true)
+	eps[1].set(100, "0x11", false)
+	_, err = c.GetBalance(testAddressParam)
+	assert.NoError(t, err)
+	eps[2].set(100, "0x11", true)
+	_, err = c.GetBalance(testAddressParam)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+}
+
+func TestMultiClientV3_QuorumHeight(t *testing.T) {
+	eps, urls, closer := newTestEndpoints(3)
+	defer closer()
+
+	for i, ep := range eps {
+		ep.byHeight = true
+		ep.set(int64(100+i), "", false)
+	}
+	c, err := NewMultiClientV3(urls, &MultiClientConfig{
+		HealthCheckInterval: time.Hour,
+		Quorum:              3,
+	})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	// all of them read the state at the lowest height
+	b, err := c.GetBalance(testAddressParam)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x64", string(*b))
+
+	// specified height is kept
+	b, err = c.GetBalance(&v3.AddressParam{
+		Address: testAddressParam.Address,
+		Height:  "0x50",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "0x50", string(*b))
+
+	// lagging endpoint doesn't lower the height
+	eps[1].set(110, "", false)
+	eps[2].set(111, "", false)
+	c.CheckHealth()
+	b, err = c.GetBalance(testAddressParam)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x6e", string(*b))
+}
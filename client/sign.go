@@ -0,0 +1,157 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+// Helpers for signing transactions and proposals on offline machines.
+
+func recoverSigner(hash []byte, sig []byte) (*common.Address, error) {
+	s, err := crypto.ParseSignature(sig)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidSignature")
+	}
+	pk, err := s.RecoverPublicKey(hash)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidSignature")
+	}
+	return common.NewAccountAddressFromPublicKey(pk), nil
+}
+
+// TransactionHash returns the hash of the transaction for the signature.
+func TransactionHash(param *v3.TransactionParam) ([]byte, error) {
+	js, err := json.Marshal(param)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := transaction.SerializeJSON(js, nil, txSerializeExcludes)
+	if err != nil {
+		return nil, err
+	}
+	bs = append([]byte("icx_sendTransaction."), bs...)
+	return crypto.SHA3Sum256(bs), nil
+}
+
+// VerifyTransaction checks whether the transaction is signed by the sender.
+func VerifyTransaction(param *v3.TransactionParam) error {
+	if len(param.Signature) == 0 {
+		return errors.IllegalArgumentError.New("NoSignature")
+	}
+	from, err := common.NewAddressFromString(string(param.FromAddress))
+	if err != nil {
+		return errors.IllegalArgumentError.Wrapf(err, "InvalidFrom(from=%s)", param.FromAddress)
+	}
+	sig, err := base64.StdEncoding.DecodeString(param.Signature)
+	if err != nil {
+		return errors.IllegalArgumentError.Wrap(err, "InvalidSignature")
+	}
+	hash, err := TransactionHash(param)
+	if err != nil {
+		return err
+	}
+	signer, err := recoverSigner(hash, sig)
+	if err != nil {
+		return err
+	}
+	if !signer.Equal(from) {
+		return errors.IllegalArgumentError.Errorf(
+			"SignerMismatch(from=%s,signer=%s)", from, signer)
+	}
+	return nil
+}
+
+type ProposalSignature struct {
+	Signer    jsonrpc.Address `json:"signer"`
+	Signature common.HexBytes `json:"signature"`
+}
+
+// Proposal is the payload with the signatures collected from the signers
+// for multi-signature contracts. Each signature is the recoverable signature
+// (R|S|V) of SHA3-256 hash of the payload, which the contract can verify
+// with recoverKey() of the payload hash.
+type Proposal struct {
+	Payload    common.HexBytes     `json:"payload"`
+	Signatures []ProposalSignature `json:"signatures,omitempty"`
+}
+
+func NewProposal(payload []byte) *Proposal {
+	return &Proposal{Payload: payload}
+}
+
+func (p *Proposal) Hash() []byte {
+	return crypto.SHA3Sum256(p.Payload)
+}
+
+func (p *Proposal) add(signer jsonrpc.Address, sig []byte) {
+	for i := range p.Signatures {
+		if p.Signatures[i].Signer == signer {
+			p.Signatures[i].Signature = sig
+			return
+		}
+	}
+	p.Signatures = append(p.Signatures, ProposalSignature{signer, sig})
+}
+
+// Sign adds the signature of the wallet. It replaces the old signature of
+// the same signer.
+func (p *Proposal) Sign(w module.Wallet) error {
+	sig, err := w.Sign(p.Hash())
+	if err != nil {
+		return err
+	}
+	p.add(jsonrpc.Address(w.Address().String()), sig)
+	return nil
+}
+
+// Verify checks whether all signatures are made by their signers.
+func (p *Proposal) Verify() error {
+	hash := p.Hash()
+	for i, s := range p.Signatures {
+		signer, err := recoverSigner(hash, s.Signature)
+		if err != nil {
+			return err
+		}
+		if string(s.Signer) != signer.String() {
+			return errors.IllegalArgumentError.Errorf(
+				"SignerMismatch(idx=%d,signer=%s,real=%s)", i, s.Signer, signer)
+		}
+	}
+	return nil
+}
+
+// Merge adds the signatures of the other proposal for the same payload.
+func (p *Proposal) Merge(o *Proposal) error {
+	if !bytes.Equal(p.Payload, o.Payload) {
+		return errors.IllegalArgumentError.New("PayloadMismatch")
+	}
+	for _, s := range o.Signatures {
+		p.add(s.Signer, s.Signature)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+func TestVerifyTransaction(t *testing.T) {
+	w1 := wallet.New()
+	w2 := wallet.New()
+	param := &v3.TransactionParam{
+		Version:     v3.VersionValue,
+		FromAddress: jsonrpc.Address(w1.Address().String()),
+		ToAddress:   jsonrpc.Address(w2.Address().String()),
+		Value:       jsonrpc.HexIntFromInt64(10),
+		StepLimit:   jsonrpc.HexIntFromInt64(100000),
+		NetworkID:   jsonrpc.HexIntFromInt64(1),
+		Timestamp:   TimestampNow(),
+	}
+	err := VerifyTransaction(param)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	assert.NoError(t, SignTransaction(w1, param))
+	assert.NoError(t, VerifyTransaction(param))
+
+	// signed transaction survives the round trip through the file
+	bs, err := json.Marshal(param)
+	assert.NoError(t, err)
+	param2 := new(v3.TransactionParam)
+	assert.NoError(t, json.Unmarshal(bs, param2))
+	assert.NoError(t, VerifyTransaction(param2))
+
+	param2.Value = jsonrpc.HexIntFromInt64(11)
+	err = VerifyTransaction(param2)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	assert.NoError(t, SignTransaction(w2, param))
+	err = VerifyTransaction(param)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
+
+func TestProposal(t *testing.T) {
+	w1 := wallet.New()
+	w2 := wallet.New()
+	w3 := wallet.New()
+
+	p1 := NewProposal([]byte("proposal"))
+	p2 := NewProposal([]byte("proposal"))
+	assert.NoError(t, p1.Sign(w1))
+	assert.NoError(t, p2.Sign(w2))
+	assert.NoError(t, p2.Sign(w3))
+	assert.NoError(t, p2.Sign(w2))
+	assert.Len(t, p2.Signatures, 2)
+
+	assert.NoError(t, p1.Merge(p2))
+	assert.Len(t, p1.Signatures, 3)
+	assert.NoError(t, p1.Verify())
+
+	bs, err := json.Marshal(p1)
+	assert.NoError(t, err)
+	p3 := new(Proposal)
+	assert.NoError(t, json.Unmarshal(bs, p3))
+	assert.NoError(t, p3.Verify())
+	assert.Equal(t, p1, p3)
+
+	p3.Signatures[0].Signature, p3.Signatures[1].Signature =
+		p3.Signatures[1].Signature, p3.Signatures[0].Signature
+	err = p3.Verify()
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	err = p1.Merge(NewProposal([]byte("other")))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
@@ -0,0 +1,528 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+// VerifiedHeader is a block header verified with commit votes of the
+// validators which are known to the caller.
+type VerifiedHeader struct {
+	block.V2HeaderFormat
+	ID                []byte
+	StateHash         []byte
+	PatchReceiptHash  []byte
+	NormalReceiptHash []byte
+}
+
+type headerResult struct {
+	StateHash         []byte
+	PatchReceiptHash  []byte
+	NormalReceiptHash []byte
+}
+
+func (r *headerResult) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := d.DecodeList()
+	if err != nil {
+		return err
+	}
+	_, err = d2.DecodeMulti(
+		&r.StateHash, &r.PatchReceiptHash, &r.NormalReceiptHash)
+	return err
+}
+
+// headerData is the block data used for verifying commit votes.
+// CommitVoteSet.VerifyBlock refers only height and ID of the block.
+type headerData struct {
+	module.BlockData
+	height int64
+	id     []byte
+}
+
+func (h *headerData) Height() int64 {
+	return h.height
+}
+
+func (h *headerData) ID() []byte {
+	return h.id
+}
+
+// NewValidatorList returns validator list for the addresses. It can be
+// used for GetVerifiedHeader with a validator set known to the caller.
+func NewValidatorList(addrs []module.Address) (module.ValidatorList, error) {
+	vs := make([]module.Validator, len(addrs))
+	for i, addr := range addrs {
+		v, err := state.ValidatorFromAddress(addr)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidValidator(idx=%d)", i)
+		}
+		vs[i] = v
+	}
+	return state.ValidatorSnapshotFromSlice(db.NewMapDB(), vs)
+}
+
+// GetVerifiedHeader returns the header of the block at the height after
+// verifying it with the commit votes of the validators. The validators
+// must be the next validators of the previous block.
+func (c *ClientV3) GetVerifiedHeader(height int64, validators module.ValidatorList) (*VerifiedHeader, error) {
+	if validators == nil || validators.Len() == 0 {
+		return nil, errors.IllegalArgumentError.New("NoValidators")
+	}
+	param := &v3.BlockHeightParam{Height: jsonrpc.HexIntFromInt64(height)}
+	bs, err := c.GetBlockHeaderByHeight(param)
+	if err != nil {
+		return nil, err
+	}
+	vh := new(VerifiedHeader)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &vh.V2HeaderFormat); err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err, "InvalidHeader(height=%d)", height)
+	}
+	if vh.Height != height {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidHeaderHeight(exp=%d,real=%d)", height, vh.Height)
+	}
+	if vh.Version < module.BlockVersion2 {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedBlockVersion(height=%d,version=%d)", height, vh.Version)
+	}
+	vh.ID = crypto.SHA3Sum256(bs)
+
+	var hr headerResult
+	if len(vh.Result) > 0 {
+		if _, err := codec.BC.UnmarshalFromBytes(vh.Result, &hr); err != nil {
+			return nil, errors.InvalidStateError.Wrapf(err,
+				"InvalidHeaderResult(height=%d)", height)
+		}
+	}
+	vh.StateHash = hr.StateHash
+	vh.PatchReceiptHash = hr.PatchReceiptHash
+	vh.NormalReceiptHash = hr.NormalReceiptHash
+
+	vbs, err := c.GetVotesByHeight(param)
+	if err != nil {
+		return nil, err
+	}
+	votes := consensus.NewCommitVoteSetFromBytes(vbs)
+	if votes == nil {
+		return nil, errors.InvalidStateError.Errorf("InvalidVotes(height=%d)", height)
+	}
+	hd := &headerData{height: vh.Height, id: vh.ID}
+	if _, err := votes.VerifyBlock(hd, validators); err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err,
+			"InvalidVotes(height=%d,id=%#x)", height, vh.ID)
+	}
+	return vh, nil
+}
+
+// GetVerifiedNextValidators returns the next validators of the verified
+// header. It can be used for verifying the header of the next block.
+func (c *ClientV3) GetVerifiedNextValidators(vh *VerifiedHeader) (module.ValidatorList, error) {
+	if len(vh.NextValidatorsHash) == 0 {
+		return state.ValidatorSnapshotFromSlice(db.NewMapDB(), nil)
+	}
+	bs, err := c.GetDataByHash(&v3.DataHashParam{
+		Hash: jsonrpc.HexBytes(common.HexBytes(vh.NextValidatorsHash).String()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(crypto.SHA3Sum256(bs), vh.NextValidatorsHash) {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidNextValidators(height=%d,hash=%#x)", vh.Height, vh.NextValidatorsHash)
+	}
+	vl, err := state.NewValidatorListFromBytes(bs)
+	if err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err,
+			"InvalidNextValidators(height=%d)", vh.Height)
+	}
+	return vl, nil
+}
+
+// getVerifiedResultHeader returns the header of the next block of the
+// verified header after verifying it with the next validators. Receipts of
+// the transactions in a block are committed in the result of its next block.
+func (c *ClientV3) getVerifiedResultHeader(vh *VerifiedHeader) (*VerifiedHeader, error) {
+	nvl, err := c.GetVerifiedNextValidators(vh)
+	if err != nil {
+		return nil, err
+	}
+	rh, err := c.GetVerifiedHeader(vh.Height+1, nvl)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(rh.PrevID, vh.ID) {
+		return nil, mismatch("prevID", common.HexBytes(vh.ID), common.HexBytes(rh.PrevID))
+	}
+	return rh, nil
+}
+
+// proveReceiptWithEvents proves the receipt at the index and its events with
+// the result of the verified header. The header must be the one of the next
+// block of the block including the transaction.
+func proveReceiptWithEvents(c *ClientV3, rh *VerifiedHeader, index int, events []int) (txresult.Receipt, []module.EventLog, error) {
+	blockHash := jsonrpc.HexBytes(common.HexBytes(rh.ID).String())
+	idx := jsonrpc.HexIntFromInt64(int64(index))
+
+	var rp [][]byte
+	var eps [][][]byte
+	var err error
+	if len(events) > 0 {
+		param := &v3.ProofEventsParam{
+			BlockHash: blockHash,
+			Index:     idx,
+			Events:    make([]jsonrpc.HexInt, len(events)),
+		}
+		for i, ev := range events {
+			param.Events[i] = jsonrpc.HexIntFromInt64(int64(ev))
+		}
+		var proofs [][][]byte
+		if proofs, err = c.GetProofForEvents(param); err == nil {
+			if len(proofs) != len(events)+1 {
+				return nil, nil, errors.InvalidStateError.Errorf(
+					"InvalidEventProofs(exp=%d,real=%d)", len(events)+1, len(proofs))
+			}
+			rp, eps = proofs[0], proofs[1:]
+		}
+	}
+	if rp == nil {
+		// receipts before UseMPTOnEvents have no proofs for events,
+		// but they have events in themselves.
+		var perr error
+		rp, perr = c.GetProofForResult(&v3.ProofResultParam{
+			BlockHash: blockHash,
+			Index:     idx,
+		})
+		if perr != nil {
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, perr
+		}
+	}
+
+	rct, perr := txresult.ProveReceipt(rh.NormalReceiptHash, index, rp)
+	if perr != nil {
+		return nil, nil, perr
+	}
+	logs := make([]module.EventLog, len(events))
+	for i, ev := range events {
+		var proof [][]byte
+		if eps != nil {
+			proof = eps[i]
+		}
+		if logs[i], perr = rct.ProveEvent(ev, proof); perr != nil {
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, perr
+		}
+	}
+	return rct, logs, nil
+}
+
+func eventLogToJSON(ev module.EventLog) (*EventLog, error) {
+	bs, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	jso := new(EventLog)
+	if err = json.Unmarshal(bs, jso); err != nil {
+		return nil, err
+	}
+	return jso, nil
+}
+
+// GetVerifiedEvents returns the event logs at the indexes in the result of
+// the transaction at the index of the verified block. The result is verified
+// with the header of the next block, which is verified with the next
+// validators of the verified block.
+func (c *ClientV3) GetVerifiedEvents(vh *VerifiedHeader, index int, events []int) ([]EventLog, error) {
+	rh, err := c.getVerifiedResultHeader(vh)
+	if err != nil {
+		return nil, err
+	}
+	_, logs, err := proveReceiptWithEvents(c, rh, index, events)
+	if err != nil {
+		return nil, err
+	}
+	jso := make([]EventLog, len(logs))
+	for i, ev := range logs {
+		el, err := eventLogToJSON(ev)
+		if err != nil {
+			return nil, err
+		}
+		jso[i] = *el
+	}
+	return jso, nil
+}
+
+func mismatch(field string, exp, real interface{}) error {
+	return errors.InvalidStateError.Errorf(
+		"ResultMismatch(field=%s,exp=%v,real=%v)", field, exp, real)
+}
+
+func verifyResult(tr *TransactionResult, rct txresult.Receipt, logs []module.EventLog) error {
+	if rct.Status() == module.StatusSuccess {
+		if v, err := tr.Status.Int64(); err != nil || v != 1 {
+			return mismatch("status", "0x1", tr.Status)
+		}
+		var addr *common.Address
+		if tr.SCOREAddress != "" {
+			var err error
+			if addr, err = common.NewAddressFromString(string(tr.SCOREAddress)); err != nil {
+				return mismatch("scoreAddress", rct.SCOREAddress(), tr.SCOREAddress)
+			}
+		}
+		if !addr.Equal(rct.SCOREAddress()) {
+			return mismatch("scoreAddress", rct.SCOREAddress(), tr.SCOREAddress)
+		}
+	} else {
+		if v, err := tr.Status.Int64(); err != nil || v != 0 {
+			return mismatch("status", "0x0", tr.Status)
+		}
+		if tr.Failure == nil {
+			return mismatch("failure", int(rct.Status()), nil)
+		}
+		if v, err := tr.Failure.CodeValue.Int64(); err != nil || v != int64(rct.Status()) {
+			return mismatch("failure", int(rct.Status()), tr.Failure.CodeValue)
+		}
+	}
+	if to, err := common.NewAddressFromString(string(tr.To)); err != nil || !to.Equal(rct.To()) {
+		return mismatch("to", rct.To(), tr.To)
+	}
+	for _, f := range []struct {
+		name string
+		exp  *common.HexInt
+		real jsonrpc.HexInt
+	}{
+		{"stepUsed", common.NewHexInt(0).SetValue(rct.StepUsed()), tr.StepUsed},
+		{"stepPrice", common.NewHexInt(0).SetValue(rct.StepPrice()), tr.StepPrice},
+		{"cumulativeStepUsed", common.NewHexInt(0).SetValue(rct.CumulativeStepUsed()), tr.CumulativeStepUsed},
+	} {
+		if v, err := f.real.BigInt(); err != nil || v.Cmp(f.exp.Value()) != 0 {
+			return mismatch(f.name, f.exp, f.real)
+		}
+	}
+	if len(tr.LogsBloom) > 0 {
+		lb := txresult.NewLogsBloom(tr.LogsBloom.Bytes())
+		if !lb.Equal(rct.LogsBloom()) {
+			return mismatch("logsBloom", rct.LogsBloom(), tr.LogsBloom)
+		}
+	}
+	for i, ev := range logs {
+		el, err := eventLogToJSON(ev)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(el, &tr.EventLogs[i]) {
+			return errors.InvalidStateError.Errorf(
+				"ResultMismatch(field=eventLogs[%d])", i)
+		}
+	}
+	return nil
+}
+
+// verifyTransactionAt checks that the transaction at the index in the
+// normal transactions of the verified block has the hash. Transactions
+// come from the block, and they are verified with the hash of the list in
+// the header. Legacy transactions kept in their original JSON can't be
+// rebuilt from the block, so their blocks fail to be verified.
+func (c *ClientV3) verifyTransactionAt(vh *VerifiedHeader, index int, hash []byte) error {
+	blk, err := c.GetBlockByHeight(&v3.BlockHeightParam{
+		Height: jsonrpc.HexIntFromInt64(vh.Height),
+	})
+	if err != nil {
+		return err
+	}
+	txs := make([]module.Transaction, len(blk.NormalTransactions))
+	for i, js := range blk.NormalTransactions {
+		tx, err := transaction.NewTransactionFromJSON(js)
+		if err != nil {
+			return errors.InvalidStateError.Wrapf(err,
+				"InvalidTransaction(height=%d,idx=%d)", vh.Height, i)
+		}
+		txs[i] = tx
+	}
+	tl := transaction.NewTransactionListFromSlice(db.NewMapDB(), txs)
+	if !bytes.Equal(tl.Hash(), vh.NormalTransactionsHash) {
+		return mismatch("normalTransactionsHash",
+			common.HexBytes(vh.NormalTransactionsHash), common.HexBytes(tl.Hash()))
+	}
+	if index < 0 || index >= len(txs) {
+		return errors.InvalidStateError.Errorf(
+			"InvalidTxIndex(height=%d,idx=%d,txs=%d)", vh.Height, index, len(txs))
+	}
+	if id := txs[index].ID(); !bytes.Equal(id, hash) {
+		return mismatch("txHash", common.HexBytes(id), common.HexBytes(hash))
+	}
+	return nil
+}
+
+// GetVerifiedTransactionResult returns the result of the transaction after
+// verifying it with the block header verified with the validators. The
+// validators must be the next validators of the previous block of the
+// block including the transaction. The result is verified with the header of
+// the next block, which commits the receipts of the block.
+//
+// The location of the transaction is verified with the transactions of the
+// block. The number of events comes from the endpoint, so the absence of
+// other events isn't verified. Use GetVerifiedEvents with an index of the
+// transaction already verified for those cases. Step usage details are not
+// verified, so they are not returned.
+func (c *ClientV3) GetVerifiedTransactionResult(param *v3.TransactionHashParam, validators module.ValidatorList) (*TransactionResult, error) {
+	tr, err := c.GetTransactionResult(param)
+	if err != nil {
+		return nil, err
+	}
+	height, err := tr.BlockHeight.Int64()
+	if err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err, "InvalidBlockHeight(%s)", tr.BlockHeight)
+	}
+	index, err := tr.TxIndex.ParseInt(32)
+	if err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err, "InvalidTxIndex(%s)", tr.TxIndex)
+	}
+	vh, err := c.GetVerifiedHeader(height, validators)
+	if err != nil {
+		return nil, err
+	}
+	if bh := tr.BlockHash.Bytes(); !bytes.Equal(bh, vh.ID) {
+		return nil, mismatch("blockHash", common.HexBytes(vh.ID), tr.BlockHash)
+	}
+	if err := c.verifyTransactionAt(vh, int(index), param.Hash.Bytes()); err != nil {
+		return nil, err
+	}
+	rh, err := c.getVerifiedResultHeader(vh)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]int, len(tr.EventLogs))
+	for i := range events {
+		events[i] = i
+	}
+	rct, logs, err := proveReceiptWithEvents(c, rh, int(index), events)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyResult(tr, rct, logs); err != nil {
+		return nil, err
+	}
+	tr.StepDetails = nil
+	return tr, nil
+}
+
+func hexBytesList(hbs []jsonrpc.HexBytes) [][]byte {
+	if hbs == nil {
+		return nil
+	}
+	bss := make([][]byte, len(hbs))
+	for i, hb := range hbs {
+		bss[i] = hb.Bytes()
+	}
+	return bss
+}
+
+func hexBytesOf(bs []byte) jsonrpc.HexBytes {
+	if len(bs) == 0 {
+		return ""
+	}
+	return jsonrpc.HexBytes(common.HexBytes(bs).String())
+}
+
+// GetVerifiedAccount returns the account and the values for the keys in its
+// storage after verifying them with the state hash of the verified header.
+// Keys are the keys of the storage as built by containerdb.KeyBuilder.
+//
+// It returns errors.NotFoundError if the absence of the account is proved,
+// and the values proved to be absent are returned as empty values. Every
+// value requires its proof, so it fails if any proof is omitted.
+func (c *ClientV3) GetVerifiedAccount(vh *VerifiedHeader, addr module.Address, keys [][]byte) (*AccountProof, error) {
+	param := &v3.AccountProofParam{
+		Address: jsonrpc.Address(addr.String()),
+		Keys:    make([]jsonrpc.HexBytes, len(keys)),
+		Height:  jsonrpc.HexIntFromInt64(vh.Height),
+	}
+	for i, k := range keys {
+		param.Keys[i] = hexBytesOf(k)
+	}
+	ap, err := c.GetProof(param)
+	if err != nil {
+		return nil, err
+	}
+	if sh := ap.StateHash.Bytes(); !bytes.Equal(sh, vh.StateHash) {
+		return nil, mismatch("stateHash", common.HexBytes(vh.StateHash), ap.StateHash)
+	}
+	ass, err := state.ProveAccount(vh.StateHash, addr.ID(), hexBytesList(ap.AccountProof))
+	if err != nil {
+		return nil, err
+	}
+	if ap.Account == nil {
+		return nil, errors.InvalidStateError.Errorf("AccountOmitted(addr=%s)", addr)
+	}
+	if ass.IsContract() != addr.IsContract() {
+		return nil, errors.InvalidStateError.Errorf("InvalidAddressPrefix(addr=%s)", addr)
+	}
+	if v, err := ap.Account.Balance.BigInt(); err != nil || v.Cmp(ass.GetBalance()) != 0 {
+		return nil, mismatch("balance", ass.GetBalance(), ap.Account.Balance)
+	}
+	storageHash := state.StorageHashOf(ass)
+	if sh := hexBytesOf(storageHash); sh != ap.Account.StorageHash {
+		return nil, mismatch("storageHash", sh, ap.Account.StorageHash)
+	}
+	var codeHash jsonrpc.HexBytes
+	if cs := ass.Contract(); cs != nil {
+		codeHash = hexBytesOf(cs.CodeHash())
+	}
+	if codeHash != ap.Account.CodeHash {
+		return nil, mismatch("codeHash", codeHash, ap.Account.CodeHash)
+	}
+	if len(ap.StorageProof) != len(keys) {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidStorageProofs(exp=%d,real=%d)", len(keys), len(ap.StorageProof))
+	}
+	for i, k := range keys {
+		sp := &ap.StorageProof[i]
+		if !bytes.Equal(sp.Key.Bytes(), k) {
+			return nil, mismatch(fmt.Sprintf("storageProof[%d].key", i), hexBytesOf(k), sp.Key)
+		}
+		value, err := state.ProveStorage(storageHash, k, hexBytesList(sp.Proof))
+		if err != nil && !errors.NotFoundError.Equals(err) {
+			return nil, err
+		}
+		sp.Value = hexBytesOf(value)
+	}
+	return ap, nil
+}
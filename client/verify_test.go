@@ -0,0 +1,524 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const testHeight = 10
+
+type testNode struct {
+	headers    map[int64][]byte
+	votes      map[int64][]byte
+	validators []byte
+	receipts   module.ReceiptList
+	rcts       []txresult.Receipt
+	txs        []module.Transaction
+	result     map[string]interface{}
+	world      state.WorldSnapshot
+	proof      map[string]interface{}
+}
+
+var (
+	testAccount    = common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	testStorageKey = containerdb.ToKey(containerdb.HashBuilder, "balances", "key").Build()
+)
+
+func newTestWorld(t *testing.T, mdb db.Database) state.WorldSnapshot {
+	ws := state.NewWorldState(mdb, nil, nil, nil, nil)
+	for i := 0; i < 8; i++ {
+		addr := common.MustNewAddressFromString(fmt.Sprintf("hx%040x", 0x100+i))
+		ws.GetAccountState(addr.ID()).SetBalance(big.NewInt(int64(i)))
+	}
+	as := ws.GetAccountState(testAccount.ID())
+	as.SetBalance(big.NewInt(1000))
+	_, err := as.SetValue(testStorageKey, []byte("value"))
+	assert.NoError(t, err)
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	return wss
+}
+
+func newTestTransaction(t *testing.T, w module.Wallet, i int) module.Transaction {
+	param := &v3.TransactionParam{
+		Version:     v3.VersionValue,
+		FromAddress: jsonrpc.Address(w.Address().String()),
+		ToAddress:   jsonrpc.Address(testAccount.String()),
+		Value:       jsonrpc.HexIntFromInt64(int64(i + 1)),
+		StepLimit:   jsonrpc.HexIntFromInt64(100000),
+		NetworkID:   jsonrpc.HexIntFromInt64(1),
+		Timestamp:   jsonrpc.HexIntFromInt64(int64(1000 + i)),
+	}
+	assert.NoError(t, SignTransaction(w, param))
+	bs, err := json.Marshal(param)
+	assert.NoError(t, err)
+	tx, err := transaction.NewTransactionFromJSON(bs)
+	assert.NoError(t, err)
+	return tx
+}
+
+func newTestNode(t *testing.T, rev module.Revision, wallets []module.Wallet) *testNode {
+	mdb := db.NewMapDB()
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	var txs []module.Transaction
+	var rcts []txresult.Receipt
+	for i := 0; i < 3; i++ {
+		txs = append(txs, newTestTransaction(t, wallets[0], i))
+		r := txresult.NewReceipt(mdb, rev, score)
+		for j := 0; j <= i; j++ {
+			r.AddLog(score, [][]byte{[]byte("Transfer(int)")}, [][]byte{{byte(j + 1)}})
+		}
+		r.SetCumulativeStepUsed(big.NewInt(int64(100 * (i + 1))))
+		r.SetResult(module.StatusSuccess, big.NewInt(100), big.NewInt(10), nil)
+		assert.NoError(t, r.Flush())
+		rcts = append(rcts, r)
+	}
+	rl := txresult.NewReceiptListFromSlice(mdb, rcts)
+	assert.NoError(t, rl.Flush())
+
+	vl, err := NewValidatorList([]module.Address{wallets[0].Address()})
+	assert.NoError(t, err)
+	world := newTestWorld(t, mdb)
+	n := &testNode{
+		headers:    make(map[int64][]byte),
+		votes:      make(map[int64][]byte),
+		validators: vl.Bytes(),
+		receipts:   rl,
+		rcts:       rcts,
+		txs:        txs,
+		world:      world,
+	}
+
+	// receipts of the transactions are committed in the next block as
+	// the block manager does.
+	id := n.addBlock(testHeight, crypto.SHA3Sum256([]byte("prev")),
+		transaction.NewTransactionListFromSlice(mdb, txs).Hash(),
+		codec.BC.MustMarshalToBytes([][]byte{world.StateHash(), nil, nil}),
+		vl, wallets)
+	n.addBlock(testHeight+1, id,
+		transaction.NewTransactionListFromSlice(mdb, nil).Hash(),
+		codec.BC.MustMarshalToBytes([][]byte{world.StateHash(), nil, rl.Hash()}),
+		vl, wallets[:1])
+	return n
+}
+
+func (n *testNode) addBlock(height int64, prevID, txsHash, result []byte, nvl module.ValidatorList, voters []module.Wallet) []byte {
+	header := codec.BC.MustMarshalToBytes(&block.V2HeaderFormat{
+		Version:                module.BlockVersion2,
+		Height:                 height,
+		Timestamp:              1000 * height,
+		Proposer:               voters[0].Address().Bytes(),
+		PrevID:                 prevID,
+		NextValidatorsHash:     nvl.Hash(),
+		NormalTransactionsHash: txsHash,
+		LogsBloom:              []byte{},
+		Result:                 result,
+	})
+	id := crypto.SHA3Sum256(header)
+	psid := &consensus.PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+	var msgs []*consensus.VoteMessage
+	for i, w := range voters {
+		msgs = append(msgs, consensus.NewVoteMessage(
+			w, consensus.VoteTypePrecommit, height, 0, id,
+			psid, 1000*height+int64(1+i), nil, nil, 0,
+		))
+	}
+	n.headers[height] = header
+	n.votes[height] = consensus.NewCommitVoteList(nil, msgs...).Bytes()
+	return id
+}
+
+func (n *testNode) idOf(height int64) []byte {
+	return crypto.SHA3Sum256(n.headers[height])
+}
+
+func hexProof(proof [][]byte) []string {
+	jso := make([]string, len(proof))
+	for i, p := range proof {
+		jso[i] = common.HexBytes(p).String()
+	}
+	return jso
+}
+
+func (n *testNode) proofOf(t *testing.T, addr module.Address, keys [][]byte) {
+	ap, err := state.GetAccountProof(n.world, addr.ID(), keys)
+	assert.NoError(t, err)
+	jso := map[string]interface{}{
+		"address":   addr.String(),
+		"stateHash": common.HexBytes(ap.StateHash).String(),
+	}
+	if ap.Account != nil {
+		jso["account"] = map[string]interface{}{
+			"balance":     common.NewHexInt(0).SetValue(ap.Account.GetBalance()).String(),
+			"storageHash": common.HexBytes(ap.StorageHash).String(),
+		}
+	}
+	if ap.Proof != nil {
+		jso["accountProof"] = hexProof(ap.Proof)
+	}
+	storage := make([]interface{}, len(ap.Storage))
+	for i, sp := range ap.Storage {
+		value := map[string]interface{}{"key": common.HexBytes(sp.Key).String()}
+		if sp.Value != nil {
+			value["value"] = common.HexBytes(sp.Value).String()
+		}
+		if sp.Proof != nil {
+			value["proof"] = hexProof(sp.Proof)
+		}
+		storage[i] = value
+	}
+	jso["storageProof"] = storage
+	n.proof = jso
+}
+
+func (n *testNode) resultOf(t *testing.T, idx int) {
+	bs, err := json.Marshal(n.rcts[idx])
+	assert.NoError(t, err)
+	var jso map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bs, &jso))
+	jso["blockHash"] = common.HexBytes(n.idOf(testHeight)).String()
+	jso["blockHeight"] = "0xa"
+	jso["txIndex"] = jsonrpc.HexIntFromInt64(int64(idx))
+	jso["txHash"] = common.HexBytes(n.txs[idx].ID()).String()
+	n.result = jso
+}
+
+func (n *testNode) blockOf() (interface{}, error) {
+	txs := make([]interface{}, len(n.txs))
+	for i, tx := range n.txs {
+		jso, err := tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		txs[i] = jso
+	}
+	return map[string]interface{}{
+		"height":                     testHeight,
+		"confirmed_transaction_list": txs,
+	}, nil
+}
+
+func heightOf(params json.RawMessage) int64 {
+	var param v3.BlockHeightParam
+	_ = json.Unmarshal(params, &param)
+	return param.Height.Value()
+}
+
+// receiptsOf returns the receipts committed in the result of the block.
+func (n *testNode) receiptsOf(blockHash jsonrpc.HexBytes) (module.ReceiptList, error) {
+	if id := common.HexBytes(n.idOf(testHeight + 1)).String(); string(blockHash) != id {
+		return nil, errors.NotFoundError.Errorf("NoReceipts(block=%s)", blockHash)
+	}
+	return n.receipts, nil
+}
+
+func (n *testNode) handle(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "icx_getBlockHeaderByHeight":
+		if h, ok := n.headers[heightOf(params)]; ok {
+			return h, nil
+		}
+		return nil, errors.NotFoundError.New("NoBlock")
+	case "icx_getBlockByHeight":
+		return n.blockOf()
+	case "icx_getVotesByHeight":
+		if v, ok := n.votes[heightOf(params)]; ok {
+			return v, nil
+		}
+		return nil, errors.NotFoundError.New("NoVotes")
+	case "icx_getDataByHash":
+		return n.validators, nil
+	case "icx_getTransactionResult":
+		return n.result, nil
+	case "icx_getProof":
+		return n.proof, nil
+	case "icx_getProofForResult":
+		var param v3.ProofResultParam
+		_ = json.Unmarshal(params, &param)
+		rl, err := n.receiptsOf(param.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		return rl.GetProof(int(param.Index.Value()))
+	case "icx_getProofForEvents":
+		var param v3.ProofEventsParam
+		_ = json.Unmarshal(params, &param)
+		idx := int(param.Index.Value())
+		rl, err := n.receiptsOf(param.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		rp, err := rl.GetProof(idx)
+		if err != nil {
+			return nil, err
+		}
+		proofs := [][][]byte{rp}
+		for _, ev := range param.Events {
+			ep, err := n.rcts[idx].GetProofOfEvent(int(ev.Value()))
+			if err != nil {
+				return nil, err
+			}
+			proofs = append(proofs, ep)
+		}
+		return proofs, nil
+	}
+	return nil, errors.NotFoundError.Errorf("UnknownMethod(%s)", method)
+}
+
+func (n *testNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req jsonrpc.Request
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	resp := &jsonrpc.Response{Version: jsonrpc.Version, ID: req.ID}
+	if res, err := n.handle(*req.Method, req.Params); err != nil {
+		resp.Error = jsonrpc.ErrorCodeSystem.Wrap(err, false)
+	} else {
+		resp.Result = res
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newTestWallets(n int) []module.Wallet {
+	ws := make([]module.Wallet, n)
+	for i := range ws {
+		ws[i] = wallet.New()
+	}
+	return ws
+}
+
+func validatorsOf(t *testing.T, ws []module.Wallet) module.ValidatorList {
+	addrs := make([]module.Address, len(ws))
+	for i, w := range ws {
+		addrs[i] = w.Address()
+	}
+	vl, err := NewValidatorList(addrs)
+	assert.NoError(t, err)
+	return vl
+}
+
+func TestClientV3_GetVerifiedTransactionResult(t *testing.T) {
+	for _, rev := range []module.Revision{0, module.UseMPTOnEvents} {
+		ws := newTestWallets(4)
+		n := newTestNode(t, rev, ws)
+		srv := httptest.NewServer(n)
+		c := NewClientV3(srv.URL)
+		vl := validatorsOf(t, ws)
+		param := &v3.TransactionHashParam{Hash: hexBytesOf(n.txs[2].ID())}
+
+		n.resultOf(t, 2)
+		tr, err := c.GetVerifiedTransactionResult(param, vl)
+		assert.NoError(t, err)
+		if assert.NotNil(t, tr) {
+			assert.Len(t, tr.EventLogs, 3)
+			assert.Equal(t, "0x12c", string(tr.CumulativeStepUsed))
+		}
+
+		// tampered step
+		n.result["stepUsed"] = "0x1"
+		_, err = c.GetVerifiedTransactionResult(param, vl)
+		assert.True(t, errors.InvalidStateError.Equals(err))
+		assert.Contains(t, err.Error(), "field=stepUsed")
+
+		// tampered event
+		n.resultOf(t, 2)
+		n.result["eventLogs"].([]interface{})[1].(map[string]interface{})["data"] = []string{"0x5"}
+		_, err = c.GetVerifiedTransactionResult(param, vl)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "eventLogs[1]")
+
+		// result of the other transaction
+		n.resultOf(t, 1)
+		n.result["txIndex"] = "0x2"
+		_, err = c.GetVerifiedTransactionResult(param, vl)
+		assert.Error(t, err)
+
+		// verified result of the other transaction for the hash
+		n.resultOf(t, 1)
+		n.result["txHash"] = string(param.Hash)
+		_, err = c.GetVerifiedTransactionResult(param, vl)
+		assert.True(t, errors.InvalidStateError.Equals(err))
+		assert.Contains(t, err.Error(), "field=txHash")
+
+		// transactions of the block not matching the header
+		n.resultOf(t, 2)
+		txs := n.txs
+		n.txs = []module.Transaction{txs[0], txs[2], txs[1]}
+		_, err = c.GetVerifiedTransactionResult(param, vl)
+		assert.True(t, errors.InvalidStateError.Equals(err))
+		assert.Contains(t, err.Error(), "field=normalTransactionsHash")
+		n.txs = txs
+
+		// next block not signed by the next validators
+		n.resultOf(t, 2)
+		header, votes := n.headers[testHeight+1], n.votes[testHeight+1]
+		n.votes[testHeight+1] = n.votes[testHeight]
+		_, err = c.GetVerifiedTransactionResult(param, vl)
+		assert.True(t, errors.InvalidStateError.Equals(err))
+		assert.Contains(t, err.Error(), "InvalidVotes(height=11")
+
+		// next block not following the block
+		var h block.V2HeaderFormat
+		_, err = codec.BC.UnmarshalFromBytes(header, &h)
+		assert.NoError(t, err)
+		n.addBlock(testHeight+1, crypto.SHA3Sum256([]byte("other")),
+			h.NormalTransactionsHash, h.Result, validatorsOf(t, ws[:1]), ws[:1])
+		_, err = c.GetVerifiedTransactionResult(param, vl)
+		assert.True(t, errors.InvalidStateError.Equals(err))
+		assert.Contains(t, err.Error(), "field=prevID")
+		n.headers[testHeight+1], n.votes[testHeight+1] = header, votes
+
+		// unknown validators
+		_, err = c.GetVerifiedTransactionResult(param, validatorsOf(t, newTestWallets(4)))
+		assert.True(t, errors.InvalidStateError.Equals(err))
+		assert.Contains(t, err.Error(), "InvalidVotes")
+
+		// not enough votes
+		_, err = c.GetVerifiedTransactionResult(param, validatorsOf(t, append(ws, newTestWallets(2)...)))
+		assert.Contains(t, err.Error(), "InvalidVotes")
+
+		srv.Close()
+	}
+}
+
+func TestClientV3_GetVerifiedEvents(t *testing.T) {
+	ws := newTestWallets(4)
+	n := newTestNode(t, module.UseMPTOnEvents, ws)
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+	c := NewClientV3(srv.URL)
+
+	vh, err := c.GetVerifiedHeader(testHeight, validatorsOf(t, ws))
+	assert.NoError(t, err)
+	assert.Empty(t, vh.NormalReceiptHash)
+
+	logs, err := c.GetVerifiedEvents(vh, 1, []int{1})
+	assert.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, "0x2", *logs[0].Data[0])
+	}
+
+	nvl, err := c.GetVerifiedNextValidators(vh)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, nvl.Len())
+	assert.Equal(t, 0, nvl.IndexOf(ws[0].Address()))
+
+	n.validators = n.validators[1:]
+	_, err = c.GetVerifiedNextValidators(vh)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+}
+
+func TestClientV3_GetVerifiedAccount(t *testing.T) {
+	ws := newTestWallets(4)
+	n := newTestNode(t, module.UseMPTOnEvents, ws)
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+	c := NewClientV3(srv.URL)
+
+	vh, err := c.GetVerifiedHeader(testHeight, validatorsOf(t, ws))
+	assert.NoError(t, err)
+
+	noKey := containerdb.ToKey(containerdb.HashBuilder, "balances", "none").Build()
+	keys := [][]byte{testStorageKey, noKey}
+	n.proofOf(t, testAccount, keys)
+	ap, err := c.GetVerifiedAccount(vh, testAccount, keys)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ap) {
+		assert.Equal(t, "0x3e8", string(ap.Account.Balance))
+		assert.Equal(t, []byte("value"), ap.StorageProof[0].Value.Bytes())
+		assert.Empty(t, ap.StorageProof[1].Value)
+	}
+
+	// value hidden by omitting the proof
+	storage := n.proof["storageProof"].([]interface{})
+	delete(storage[0].(map[string]interface{}), "value")
+	delete(storage[0].(map[string]interface{}), "proof")
+	_, err = c.GetVerifiedAccount(vh, testAccount, keys)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.Contains(t, err.Error(), "InvalidStorageProof")
+
+	// value comes from the proof, not from the endpoint
+	n.proofOf(t, testAccount, keys)
+	storage = n.proof["storageProof"].([]interface{})
+	delete(storage[0].(map[string]interface{}), "value")
+	ap, err = c.GetVerifiedAccount(vh, testAccount, keys)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("value"), ap.StorageProof[0].Value.Bytes())
+	}
+
+	// tampered balance
+	n.proofOf(t, testAccount, keys)
+	n.proof["account"].(map[string]interface{})["balance"] = "0x3e9"
+	_, err = c.GetVerifiedAccount(vh, testAccount, keys)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.Contains(t, err.Error(), "field=balance")
+
+	// value of the other key
+	n.proofOf(t, testAccount, [][]byte{testStorageKey, testStorageKey})
+	_, err = c.GetVerifiedAccount(vh, testAccount, keys)
+	assert.Error(t, err)
+
+	// proof of the other account
+	n.proofOf(t, common.MustNewAddressFromString("hx0000000000000000000000000000000000000101"), nil)
+	n.proof["address"] = testAccount.String()
+	_, err = c.GetVerifiedAccount(vh, testAccount, nil)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.Contains(t, err.Error(), "InvalidAccountProof")
+
+	// no account
+	other := common.MustNewAddressFromString("hx0000000000000000000000000000000000000004")
+	n.proofOf(t, other, nil)
+	_, err = c.GetVerifiedAccount(vh, other, nil)
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	// account hidden by omitting the proof
+	n.proofOf(t, testAccount, nil)
+	delete(n.proof, "account")
+	delete(n.proof, "accountProof")
+	_, err = c.GetVerifiedAccount(vh, testAccount, nil)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	// account hidden with its proof
+	n.proofOf(t, testAccount, nil)
+	delete(n.proof, "account")
+	_, err = c.GetVerifiedAccount(vh, testAccount, nil)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.Contains(t, err.Error(), "AccountOmitted")
+}
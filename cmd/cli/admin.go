@@ -10,6 +10,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gosuri/uitable"
 	"github.com/jroimartin/gocui"
@@ -21,6 +22,8 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/node"
 )
 
@@ -124,6 +127,12 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			param.Role, _ = fs.GetUint("role")
 			param.DBType, _ = fs.GetString("db_type")
 			param.Platform, _ = fs.GetString("platform")
+			if pc, _ := fs.GetString("platform_config"); len(pc) > 0 {
+				if !json.Valid([]byte(pc)) {
+					return errors.Errorf("invalid platform_config %s", pc)
+				}
+				param.PlatformConfig = json.RawMessage(pc)
+			}
 			param.ConcurrencyLevel, _ = fs.GetInt("concurrency")
 			param.NormalTxPoolSize, _ = fs.GetInt("normal_tx_pool")
 			param.PatchTxPoolSize, _ = fs.GetInt("patch_tx_pool")
@@ -145,6 +154,19 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 				param.NephewsLimit = &nephewsLimit
 			}
 			param.ValidateTxOnSend, _ = fs.GetBool("validate_tx_on_send")
+			param.Archive, _ = fs.GetBool("archive")
+			if ct, _ := fs.GetString("consensus_timeouts"); len(ct) > 0 {
+				param.ConsensusTimeouts = new(module.ConsensusTimeouts)
+				if err := json.Unmarshal([]byte(ct), param.ConsensusTimeouts); err != nil {
+					return errors.Errorf("invalid consensus_timeouts %s", ct)
+				}
+			}
+			if rp, _ := fs.GetString("retention"); len(rp) > 0 {
+				param.Retention = new(module.RetentionPolicy)
+				if err := json.Unmarshal([]byte(rp), param.Retention); err != nil {
+					return errors.Errorf("invalid retention %s", rp)
+				}
+			}
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -185,6 +207,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Uint("role", 3, "[0:None, 1:Seed, 2:Validator, 3:Both]")
 	joinFlags.String("db_type", "goleveldb", "Name of database system("+strings.Join(db.RegisteredBackendTypes(), ", ")+")")
 	joinFlags.String("platform", "", "Name of service platform")
+	joinFlags.String("platform_config", "", "Configuration of service platform in JSON")
 	joinFlags.Int("concurrency", 1, "Maximum number of executors to be used for concurrency")
 	joinFlags.Int("normal_tx_pool", 0, "Size of normal transaction pool")
 	joinFlags.Int("patch_tx_pool", 0, "Size of patch transaction pool")
@@ -202,6 +225,9 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	joinFlags.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	joinFlags.Bool("validate_tx_on_send", false, "Validate transaction on send")
+	joinFlags.Bool("archive", false, "Keep index of states by heights for historical queries")
+	joinFlags.String("consensus_timeouts", "", "Consensus timeouts in JSON (ex. {\"propose\":1000,\"backoff\":\"linear\"})")
+	joinFlags.String("retention", "", "Retention policy for pruning in JSON (ex. {\"state\":10000,\"receipts\":100000})")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -384,6 +410,63 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	pruneFlags.Int64("height", 0, "Block Height")
 	MarkAnnotationRequired(pruneFlags, "height")
 
+	archiveCmd := &cobra.Command{
+		Use:   "archive CID",
+		Short: "Start to build the archive index for the states of the blocks",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainArchiveParam{}
+			param.Start, _ = fs.GetInt64("start")
+			param.End, _ = fs.GetInt64("end")
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/archive"
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(archiveCmd)
+	archiveFlags := archiveCmd.Flags()
+	archiveFlags.Int64("start", 0, "First height to index")
+	archiveFlags.Int64("end", 0, "Last height to index(default:last block)")
+
+	forkCmd := &cobra.Command{
+		Use:   "fork CID",
+		Short: "Start to write the genesis storage for a new chain forked at the height",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainForkParam{}
+			param.Height, _ = fs.GetInt64("height")
+			param.NID, _ = fs.GetString("nid")
+			param.CID, _ = fs.GetString("cid")
+			param.Validators, _ = fs.GetStringSlice("validator")
+			param.File, _ = fs.GetString("file")
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/fork"
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(forkCmd)
+	forkFlags := forkCmd.Flags()
+	forkFlags.Int64("height", 0, "Block Height")
+	forkFlags.String("nid", "", "Network ID of the new chain")
+	forkFlags.String("cid", "", "Chain ID of the new chain(default:nid)")
+	forkFlags.StringSlice("validator", nil, "Address of the validator(default:wallet of the node)")
+	forkFlags.String("file", "", "File name of the genesis storage in the chain directory(default:fork.zip)")
+	MarkAnnotationRequired(forkFlags, "height", "nid")
+
 	backupCmd := &cobra.Command{
 		Use:   "backup CID",
 		Short: "Start to backup the channel",
@@ -479,6 +562,241 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	configFlags.String("value", "", "use if value starts with '-'.\n"+
 		"(if the third arg is used, this flag will be ignored)")
 
+	consensusCmd := &cobra.Command{
+		Use:   "consensus CID",
+		Short: "Inspect round state of the consensus",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reqUrl := node.UrlChain + "/" + args[0] + "/consensus"
+			v := &consensus.RoundState{}
+			resp, err := adminClient.Get(reqUrl, v)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(consensusCmd)
+
+	walCmd := &cobra.Command{
+		Use:   "wal",
+		Short: "Inspect and repair WAL of the consensus",
+	}
+	rootCmd.AddCommand(walCmd)
+
+	walVerifyCmd := &cobra.Command{
+		Use:   "verify CID",
+		Short: "Verify integrity of WALs",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reqUrl := node.UrlChain + "/" + args[0] + "/wal"
+			var v []*consensus.WALSummary
+			resp, err := adminClient.Get(reqUrl, &v)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}
+	walCmd.AddCommand(walVerifyCmd)
+
+	walInspectCmd := &cobra.Command{
+		Use:   "inspect CID WAL",
+		Short: "Decode messages in the WAL (WAL: round, lock or commit)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			raw, _ := fs.GetBool("raw")
+			asJson, _ := fs.GetBool("json")
+			output, _ := fs.GetString("output")
+
+			reqUrl := node.UrlChain + "/" + args[0] + "/wal/" + args[1]
+			if raw {
+				reqUrl += "?raw=true"
+			}
+			v := &consensus.WALDump{}
+			resp, err := adminClient.Get(reqUrl, v)
+			if err != nil {
+				return err
+			}
+			if len(output) > 0 {
+				if err = JsonPrettySaveFile(output, 0644, v); err != nil {
+					return err
+				}
+				fmt.Println(output)
+				return nil
+			}
+			if asJson {
+				if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+					return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+				}
+				return nil
+			}
+			for _, rec := range v.Entries {
+				fmt.Printf("#%d offset=%d size=%d", rec.Index, rec.Offset, rec.Size)
+				if rec.Message != nil {
+					fmt.Printf(" %s", rec.Message)
+				}
+				if len(rec.Error) > 0 {
+					fmt.Printf(" error=%q", rec.Error)
+				}
+				fmt.Println()
+				if rec.Message != nil {
+					for _, vote := range rec.Message.Votes {
+						fmt.Printf("    %s\n", vote)
+					}
+				}
+				if raw {
+					fmt.Printf("    raw=%s\n", rec.Raw)
+				}
+			}
+			fmt.Printf("records=%d invalid=%d size=%d validSize=%d\n",
+				v.Records, v.Invalid, v.Size, v.ValidSize)
+			if len(v.Error) > 0 {
+				fmt.Printf("error=%q\n", v.Error)
+			}
+			return nil
+		},
+	}
+	walCmd.AddCommand(walInspectCmd)
+	walInspectFlags := walInspectCmd.Flags()
+	walInspectFlags.Bool("raw", false, "Include raw bytes of records")
+	walInspectFlags.Bool("json", false, "Print records in JSON")
+	walInspectFlags.String("output", "", "Export records in JSON to the file")
+
+	walTruncateCmd := &cobra.Command{
+		Use:   "truncate CID WAL",
+		Short: "Truncate the WAL of the stopped chain (WAL: round, lock or commit)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainWALTruncateParam{}
+			param.Records, _ = fs.GetInt("records")
+
+			reqUrl := node.UrlChain + "/" + args[0] + "/wal/" + args[1] + "/truncate"
+			v := &consensus.WALSummary{}
+			resp, err := adminClient.PostWithJson(reqUrl, param, v)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}
+	walCmd.AddCommand(walTruncateCmd)
+	walTruncateFlags := walTruncateCmd.Flags()
+	walTruncateFlags.Int("records", -1, "Number of records to keep (negative value drops only broken records at the end)")
+
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Browse the database of the chain",
+	}
+	rootCmd.AddCommand(dbCmd)
+
+	dbRangeParams := func(cmd *cobra.Command) *url.Values {
+		fs := cmd.Flags()
+		params := &url.Values{}
+		for _, name := range []string{"prefix", "start", "end"} {
+			if v, _ := fs.GetString(name); v != "" {
+				params.Add(name, v)
+			}
+		}
+		return params
+	}
+	addDBRangeFlags := func(cmd *cobra.Command) {
+		fs := cmd.Flags()
+		fs.String("prefix", "", "Prefix of the keys in hex")
+		fs.String("start", "", "First key of the range in hex (inclusive)")
+		fs.String("end", "", "Last key of the range in hex (exclusive)")
+	}
+	dbBucketUrl := func(cid, bucket string) string {
+		return node.UrlDB + "/" + cid + "/" + url.PathEscape(bucket)
+	}
+
+	dbScanCmd := &cobra.Command{
+		Use:   "scan CID BUCKET",
+		Short: "Scan entries of the bucket (BUCKET: ID or name like MerkleTrie)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			limit, _ := fs.GetInt("limit")
+			all, _ := fs.GetBool("all")
+			decode, _ := fs.GetBool("decode")
+			asJson, _ := fs.GetBool("json")
+
+			params := dbRangeParams(cmd)
+			if limit > 0 {
+				params.Set("limit", strconv.Itoa(limit))
+			}
+			if decode {
+				params.Set("decode", "true")
+			}
+			reqUrl := dbBucketUrl(args[0], args[1])
+			for {
+				v := &node.DBScanView{}
+				resp, err := adminClient.Get(reqUrl, v, params)
+				if err != nil {
+					return err
+				}
+				if asJson {
+					if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+						return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+					}
+				} else {
+					for _, e := range v.Entries {
+						fmt.Printf("%s %s\n", e.Key, e.Value)
+						if e.Decoded != nil {
+							bs, _ := json.Marshal(e.Decoded)
+							fmt.Printf("    %s %s\n", e.Type, bs)
+						}
+					}
+				}
+				if len(v.Next) == 0 {
+					return nil
+				}
+				if !all {
+					if !asJson {
+						fmt.Printf("next=%s\n", v.Next)
+					}
+					return nil
+				}
+				params.Set("start", v.Next)
+			}
+		},
+	}
+	dbCmd.AddCommand(dbScanCmd)
+	addDBRangeFlags(dbScanCmd)
+	dbScanFlags := dbScanCmd.Flags()
+	dbScanFlags.Int("limit", 0, "Maximum number of entries in a page (0: server default)")
+	dbScanFlags.Bool("all", false, "Scan all pages in the range")
+	dbScanFlags.Bool("decode", false, "Decode values of known formats (blocks, tx locators and trie nodes)")
+	dbScanFlags.Bool("json", false, "Print pages in JSON")
+
+	dbCountCmd := &cobra.Command{
+		Use:   "count CID BUCKET",
+		Short: "Count keys of the bucket (BUCKET: ID or name like MerkleTrie)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := &node.DBCountView{}
+			_, err := adminClient.Get(dbBucketUrl(args[0], args[1])+"/count", v, dbRangeParams(cmd))
+			if err != nil {
+				return err
+			}
+			fmt.Println(v.Count)
+			return nil
+		},
+	}
+	dbCmd.AddCommand(dbCountCmd)
+	addDBRangeFlags(dbCountCmd)
+
 	rootCmd.Use = "chain TASK CID [PARAM]"
 	rootCmd.Args = ArgsWithDefaultErrorFunc(cobra.RangeArgs(2, 3))
 	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
@@ -577,10 +895,51 @@ func NewSystemCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comma
 
 	NewBackupCmd(rootCmd, &adminClient)
 	NewRestoreCmd(rootCmd, &adminClient)
+	NewAuditCmd(rootCmd, &adminClient)
 
 	return rootCmd, vc
 }
 
+func NewAuditCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query audit log of mutating admin requests",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			params := &url.Values{}
+			if user, _ := fs.GetString("user"); user != "" {
+				params.Add("user", user)
+			}
+			if since, _ := fs.GetString("since"); since != "" {
+				if d, err := time.ParseDuration(since); err == nil {
+					since = time.Now().Add(-d).Format(time.RFC3339)
+				} else if _, err = time.Parse(time.RFC3339, since); err != nil {
+					return errors.Errorf("invalid since=%s (RFC3339 or duration)", since)
+				}
+				params.Add("since", since)
+			}
+			if limit, _ := fs.GetInt("limit"); limit > 0 {
+				params.Add("limit", strconv.Itoa(limit))
+			}
+			l := make([]*node.AuditRecord, 0)
+			resp, err := client.Get(node.UrlSystem+"/audit", &l, params)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, l); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}
+	flags := auditCmd.Flags()
+	flags.String("user", "", "Address of the user")
+	flags.String("since", "", "Records since the time (RFC3339) or the duration ago (ex: 24h)")
+	flags.Int("limit", 0, "Maximum number of the latest records (0: no limit)")
+	parent.AddCommand(auditCmd)
+}
+
 func NewBackupCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
 	rootCmd := &cobra.Command{
 		Use:   "backup",
@@ -686,39 +1045,86 @@ func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command
 			return nil
 		},
 	}, &cobra.Command{
+		Use:   "inspect ADDRESS",
+		Short: "Inspect user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := &node.UserInfo{}
+			resp, err := adminClient.Get(node.UrlUser+"/"+args[0], v)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}, &cobra.Command{
+		Use:   "rm ADDRESS",
+		Short: "Remove user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reqUrl := node.UrlUser + "/" + args[0]
+			var v string
+			if _, err := adminClient.Delete(reqUrl, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	})
+
+	addCmd := &cobra.Command{
 		Use:   "add ADDRESS",
 		Short: "Add user",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			reqUrl := node.UrlUser
-			param := &struct {
-				Id string `json:"id"`
-			}{Id: args[0]}
+			param := &node.UserParam{Id: args[0]}
 			addr := &common.Address{}
 			if err := addr.SetString(param.Id); err != nil {
 				return errors.Wrap(err, "invalid Address format")
 			}
+			roleStr, _ := cmd.Flags().GetString("role")
+			role, err := node.ParseRole(roleStr)
+			if err != nil {
+				return err
+			}
+			param.Role = &role
 			var v string
-			if _, err := adminClient.PostWithJson(reqUrl, param, &v); err != nil {
+			if _, err = adminClient.PostWithJson(reqUrl, param, &v); err != nil {
 				return err
 			}
 			fmt.Println(v)
 			return nil
 		},
-	}, &cobra.Command{
-		Use:   "rm ADDRESS",
-		Short: "Remove user",
-		Args:  cobra.ExactArgs(1),
+	}
+	addCmd.Flags().String("role", node.RoleAdmin.String(), "Role of user for all chains (none, viewer, operator or admin)")
+	rootCmd.AddCommand(addCmd)
+
+	roleCmd := &cobra.Command{
+		Use:   "role ADDRESS ROLE",
+		Short: "Set role of user (none, viewer, operator or admin)",
+		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			reqUrl := node.UrlUser + "/" + args[0]
+			reqUrl := node.UrlUser + "/" + args[0] + "/role"
+			param := &node.UserRoleParam{}
+			role, err := node.ParseRole(args[1])
+			if err != nil {
+				return err
+			}
+			param.Role = role
+			param.Chain, _ = cmd.Flags().GetString("chain")
 			var v string
-			if _, err := adminClient.Delete(reqUrl, &v); err != nil {
+			if _, err = adminClient.PostWithJson(reqUrl, param, &v); err != nil {
 				return err
 			}
 			fmt.Println(v)
 			return nil
 		},
-	})
+	}
+	roleCmd.Flags().String("chain", "", "Chain ID or channel for the role (default: all chains)")
+	rootCmd.AddCommand(roleCmd)
 	return rootCmd, vc
 }
 
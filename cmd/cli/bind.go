@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/client/bindgen"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+func NewBindCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s [ADDRESS]", c),
+		Short: "Generate typed Go binding of SCORE",
+		Long: "Generate typed Go binding of SCORE from the result of icx_getScoreApi\n" +
+			"with ADDRESS on --uri, or from the local SCORE API JSON file (--api).",
+		Args: ArgsWithDefaultErrorFunc(cobra.MaximumNArgs(1)),
+	}
+	flags := cmd.Flags()
+	uri := flags.String("uri", "", "URI of JSON-RPC API")
+	height := flags.Int64("height", -1, "BlockHeight for getting SCORE API")
+	api := flags.String("api", "", "SCORE API JSON file ('-' for stdin)")
+	pkg := flags.StringP("package", "p", "", "Name of the generated package")
+	typeName := flags.String("type", "", "Name of the binding type (default: exported form of the package)")
+	out := flags.StringP("out", "o", "", "Output file path (default: stdout)")
+	MarkAnnotationRequired(flags, "package")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := ValidateFlags(flags); err != nil {
+			return err
+		}
+		var bs []byte
+		var err error
+		if len(*api) > 0 {
+			if len(args) > 0 {
+				return fmt.Errorf("ADDRESS can't be used with --api")
+			}
+			if bs, err = readFile(*api); err != nil {
+				return err
+			}
+		} else {
+			if len(args) != 1 || len(*uri) == 0 {
+				return fmt.Errorf("ADDRESS and --uri are required without --api")
+			}
+			param := &v3.ScoreAddressParam{Address: jsonrpc.Address(args[0])}
+			if *height != -1 {
+				param.Height = jsonrpc.HexInt(intconv.FormatInt(*height))
+			}
+			result, err := client.NewClientV3(*uri).GetScoreApi(param)
+			if err != nil {
+				return err
+			}
+			if bs, err = json.Marshal(result); err != nil {
+				return err
+			}
+		}
+		methods, err := bindgen.ParseAPI(bs)
+		if err != nil {
+			return err
+		}
+		src, err := bindgen.Generate(&bindgen.Config{
+			Package: *pkg,
+			Type:    *typeName,
+		}, methods)
+		if err != nil {
+			return err
+		}
+		if len(*out) == 0 {
+			_, err = os.Stdout.Write(src)
+			return err
+		}
+		return os.WriteFile(*out, src, 0644)
+	}
+	return cmd
+}
@@ -0,0 +1,830 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/server"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+const (
+	exploreViewBlocks = "blocks"
+	exploreViewDetail = "detail"
+	exploreViewStatus = "status"
+	exploreViewInput  = "input"
+
+	exploreBlocksWidth   = 28
+	exploreMaxBlocks     = 200
+	exploreMaxActivities = 200
+)
+
+var exploreHashPattern = regexp.MustCompile("^(0x)?[0-9a-fA-F]{64}$")
+
+// explorePage is the content of the detail view. Lines with links can be
+// opened with Enter.
+type explorePage struct {
+	title   string
+	address string
+	lines   []string
+	links   map[int]func() (*explorePage, error)
+	cy      int
+}
+
+func newExplorePage(format string, args ...interface{}) *explorePage {
+	return &explorePage{
+		title: fmt.Sprintf(format, args...),
+		links: make(map[int]func() (*explorePage, error)),
+	}
+}
+
+func (p *explorePage) add(format string, args ...interface{}) {
+	p.lines = append(p.lines, fmt.Sprintf(format, args...))
+}
+
+func (p *explorePage) link(open func() (*explorePage, error), format string, args ...interface{}) {
+	p.links[len(p.lines)] = open
+	p.add("> "+format, args...)
+}
+
+type exploreActivity struct {
+	height int64
+	hash   string
+	from   string
+	to     string
+	value  string
+}
+
+type explorer struct {
+	c   *client.ClientV3
+	uri string
+	g   *gocui.Gui
+
+	blocks     []*client.Block
+	page       *explorePage
+	stack      []*explorePage
+	follow     string
+	activities []exploreActivity
+	message    string
+
+	cancelCh chan bool
+}
+
+func hexIntString(v jsonrpc.HexInt) string {
+	if len(v) == 0 {
+		return "-"
+	}
+	if i, err := v.BigInt(); err == nil {
+		return i.String()
+	}
+	return string(v)
+}
+
+func parseExploreTx(raw json.RawMessage) *client.NormalTransaction {
+	tx := new(client.NormalTransaction)
+	if err := json.Unmarshal(raw, tx); err != nil {
+		return tx
+	}
+	if len(tx.TxHash) == 0 {
+		// legacy transactions
+		var legacy struct {
+			TxHash jsonrpc.HexBytes `json:"tx_hash"`
+		}
+		if err := json.Unmarshal(raw, &legacy); err == nil {
+			tx.TxHash = legacy.TxHash
+		}
+	}
+	if len(tx.TxHash) > 0 && !strings.HasPrefix(string(tx.TxHash), "0x") {
+		tx.TxHash = "0x" + tx.TxHash
+	}
+	return tx
+}
+
+func (e *explorer) blockPage(blk *client.Block) *explorePage {
+	p := newExplorePage("Block %d", blk.Height)
+	p.add("Height    : %d", blk.Height)
+	p.add("Hash      : 0x%s", strings.TrimPrefix(string(blk.BlockHash), "0x"))
+	p.add("Timestamp : %d", blk.Timestamp)
+	if len(blk.PrevID) > 0 {
+		prev := blk.Height - 1
+		p.link(func() (*explorePage, error) {
+			return e.blockPageByHeight(prev)
+		}, "Previous  : %d", prev)
+	}
+	if len(blk.Proposer) > 0 {
+		proposer := string(blk.Proposer)
+		p.link(func() (*explorePage, error) {
+			return e.addressPage(proposer)
+		}, "Proposer  : %s", proposer)
+	}
+	p.add("")
+	p.add("Transactions (%d)", len(blk.NormalTransactions))
+	for i, raw := range blk.NormalTransactions {
+		tx := parseExploreTx(raw)
+		hash := string(tx.TxHash)
+		p.link(func() (*explorePage, error) {
+			return e.txPage(hash)
+		}, "[%d] %s %s -> %s", i, hash, tx.From, tx.To)
+	}
+	return p
+}
+
+func (e *explorer) blockPageByHeight(height int64) (*explorePage, error) {
+	blk, err := e.c.GetBlockByHeight(&v3.BlockHeightParam{
+		Height: jsonrpc.HexIntFromInt64(height),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.blockPage(blk), nil
+}
+
+func (e *explorer) blockPageByHash(hash string) (*explorePage, error) {
+	blk, err := e.c.GetBlockByHash(&v3.BlockHashParam{
+		Hash: jsonrpc.HexBytes(hash),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.blockPage(blk), nil
+}
+
+func (e *explorer) addressLink(p *explorePage, name string, addr jsonrpc.Address) {
+	if len(addr) == 0 {
+		p.add("%-10s: -", name)
+		return
+	}
+	p.link(func() (*explorePage, error) {
+		return e.addressPage(string(addr))
+	}, "%-10s: %s", name, addr)
+}
+
+func (e *explorer) txPage(hash string) (*explorePage, error) {
+	param := &v3.TransactionHashParam{Hash: jsonrpc.HexBytes(hash)}
+	tx, err := e.c.GetTransactionByHash(param)
+	if err != nil {
+		return nil, err
+	}
+	p := newExplorePage("Transaction %s", hash)
+	p.add("Hash      : %s", hash)
+	height, _ := tx.BlockHeight.Int64()
+	p.link(func() (*explorePage, error) {
+		return e.blockPageByHeight(height)
+	}, "Block     : %d (index=%s)", height, hexIntString(tx.TxIndex))
+	e.addressLink(p, "From", tx.From)
+	e.addressLink(p, "To", tx.To)
+	p.add("Value     : %s", hexIntString(tx.Value))
+	p.add("StepLimit : %s", hexIntString(tx.StepLimit))
+	p.add("Timestamp : %s", hexIntString(tx.TimeStamp))
+	p.add("Nonce     : %s", hexIntString(tx.Nonce))
+	if len(tx.DataType) > 0 {
+		p.add("DataType  : %s", tx.DataType)
+	}
+	if len(tx.Data) > 0 {
+		data := string(tx.Data)
+		if len(data) > 512 {
+			data = data[:512] + "..."
+		}
+		p.add("Data      : %s", data)
+	}
+
+	p.add("")
+	r, err := e.c.GetTransactionResult(param)
+	if err != nil {
+		p.add("Receipt   : %v", err)
+		return p, nil
+	}
+	status := "SUCCESS"
+	if st, _ := r.Status.Int64(); st != 1 {
+		status = "FAILURE"
+	}
+	p.add("Status    : %s", status)
+	if r.Failure != nil {
+		p.add("Failure   : code=%s message=%s", hexIntString(r.Failure.CodeValue), r.Failure.MessageValue)
+	}
+	p.add("StepUsed  : %s (price=%s)", hexIntString(r.StepUsed), hexIntString(r.StepPrice))
+	if len(r.SCOREAddress) > 0 {
+		e.addressLink(p, "SCORE", r.SCOREAddress)
+	}
+	p.add("")
+	p.add("Events (%d)", len(r.EventLogs))
+	for i, el := range r.EventLogs {
+		addr := el.Addr
+		p.link(func() (*explorePage, error) {
+			return e.addressPage(string(addr))
+		}, "[%d] %s", i, addr)
+		p.add("    indexed: %s", exploreValues(el.Indexed))
+		p.add("    data   : %s", exploreValues(el.Data))
+	}
+	return p, nil
+}
+
+func exploreValues(values []*string) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		if v == nil {
+			items[i] = "null"
+		} else {
+			items[i] = *v
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+func (e *explorer) addressPage(addr string) (*explorePage, error) {
+	if _, err := common.NewAddressFromString(addr); err != nil {
+		return nil, err
+	}
+	p := newExplorePage("Address %s", addr)
+	p.address = addr
+	p.add("Address   : %s", addr)
+	balance, err := e.c.GetBalance(&v3.AddressParam{Address: jsonrpc.Address(addr)})
+	if err != nil {
+		return nil, err
+	}
+	p.add("Balance   : %s", hexIntString(*balance))
+	if e.follow == addr {
+		p.add("Following : yes (press 'a' for activities)")
+	} else {
+		p.add("Following : no (press 'f' to follow)")
+	}
+	if strings.HasPrefix(addr, "cx") {
+		p.add("")
+		api, err := e.c.GetScoreApi(&v3.ScoreAddressParam{Address: jsonrpc.Address(addr)})
+		if err != nil {
+			p.add("API       : %v", err)
+			return p, nil
+		}
+		p.add("API (%d)", len(api))
+		for _, item := range api {
+			p.add("  %s", exploreAPIString(item))
+		}
+	}
+	return p, nil
+}
+
+func exploreAPIString(item interface{}) string {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return fmt.Sprint(item)
+	}
+	var inputs []string
+	if ins, ok := m["inputs"].([]interface{}); ok {
+		for _, in := range ins {
+			if im, ok := in.(map[string]interface{}); ok {
+				inputs = append(inputs, fmt.Sprintf("%v %v", im["type"], im["name"]))
+			}
+		}
+	}
+	var outputs []string
+	if outs, ok := m["outputs"].([]interface{}); ok {
+		for _, out := range outs {
+			if om, ok := out.(map[string]interface{}); ok {
+				outputs = append(outputs, fmt.Sprint(om["type"]))
+			}
+		}
+	}
+	var flags []string
+	for _, f := range []string{"readonly", "payable", "isolated"} {
+		if v, ok := m[f]; ok && v != "0x0" {
+			flags = append(flags, f)
+		}
+	}
+	s := fmt.Sprintf("%-8v %v(%s)", m["type"], m["name"], strings.Join(inputs, ", "))
+	if len(outputs) > 0 {
+		s += " " + strings.Join(outputs, ", ")
+	}
+	if len(flags) > 0 {
+		s += " [" + strings.Join(flags, ",") + "]"
+	}
+	return s
+}
+
+func (e *explorer) activitiesPage() *explorePage {
+	p := newExplorePage("Activities of %s", e.follow)
+	if len(e.follow) == 0 {
+		p.add("No address is followed. Use ':follow ADDRESS' or 'f' on an address.")
+		return p
+	}
+	follow := e.follow
+	p.link(func() (*explorePage, error) {
+		return e.addressPage(follow)
+	}, "Address   : %s", follow)
+	p.add("")
+	p.add("Transactions (%d)", len(e.activities))
+	for _, a := range e.activities {
+		hash := a.hash
+		p.link(func() (*explorePage, error) {
+			return e.txPage(hash)
+		}, "%d %s %s -> %s %s", a.height, hash, a.from, a.to, a.value)
+	}
+	return p
+}
+
+// onBlock adds the new block on top of the block list, and records the
+// transactions of the followed address.
+func (e *explorer) onBlock(blk *client.Block) {
+	if len(e.blocks) > 0 && blk.Height <= e.blocks[0].Height {
+		return
+	}
+	e.blocks = append([]*client.Block{blk}, e.blocks...)
+	if len(e.blocks) > exploreMaxBlocks {
+		e.blocks = e.blocks[:exploreMaxBlocks]
+	}
+	if v, err := e.g.View(exploreViewBlocks); err == nil {
+		// keep the selected block unless the top one is selected.
+		_, oy := v.Origin()
+		_, cy := v.Cursor()
+		if oy+cy > 0 {
+			e.moveCursor(v, 1, len(e.blocks))
+		}
+	}
+	if len(e.follow) == 0 {
+		return
+	}
+	for _, raw := range blk.NormalTransactions {
+		tx := parseExploreTx(raw)
+		if string(tx.From) != e.follow && string(tx.To) != e.follow {
+			continue
+		}
+		e.activities = append([]exploreActivity{{
+			height: blk.Height,
+			hash:   string(tx.TxHash),
+			from:   string(tx.From),
+			to:     string(tx.To),
+			value:  hexIntString(tx.Value),
+		}}, e.activities...)
+		if len(e.activities) > exploreMaxActivities {
+			e.activities = e.activities[:exploreMaxActivities]
+		}
+		e.message = fmt.Sprintf("New transaction of %s at %d", e.follow, blk.Height)
+	}
+}
+
+func (e *explorer) monitor(height int64) {
+	err := e.c.MonitorBlock(&server.BlockRequest{
+		Height: common.HexInt64{Value: height},
+	}, func(bn *client.BlockNotification) {
+		blk, err := e.c.GetBlockByHash(&v3.BlockHashParam{Hash: bn.Hash})
+		e.g.Update(func(g *gocui.Gui) error {
+			if err != nil {
+				e.message = fmt.Sprintf("Fail to get block err=%v", err)
+				return nil
+			}
+			e.onBlock(blk)
+			return nil
+		})
+	}, e.cancelCh)
+	if err != nil {
+		e.g.Update(func(g *gocui.Gui) error {
+			e.message = fmt.Sprintf("Fail to monitor blocks err=%v", err)
+			return nil
+		})
+	}
+}
+
+func (e *explorer) open(p *explorePage, push bool) {
+	if push && e.page != nil {
+		if v, err := e.g.View(exploreViewDetail); err == nil {
+			_, oy := v.Origin()
+			_, cy := v.Cursor()
+			e.page.cy = oy + cy
+		}
+		e.stack = append(e.stack, e.page)
+	}
+	e.page = p
+	if v, err := e.g.View(exploreViewDetail); err == nil {
+		_ = v.SetOrigin(0, 0)
+		_ = v.SetCursor(0, 0)
+		e.moveCursor(v, p.cy, len(p.lines))
+	}
+	_, _ = e.g.SetCurrentView(exploreViewDetail)
+}
+
+func (e *explorer) openWith(push bool, f func() (*explorePage, error)) {
+	p, err := f()
+	if err != nil {
+		e.message = fmt.Sprintf("ERROR %v", err)
+		return
+	}
+	e.message = ""
+	e.open(p, push)
+}
+
+// execute runs the command typed in the input view.
+func (e *explorer) execute(line string) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return
+	}
+	cmd, arg := args[0], ""
+	if len(args) > 1 {
+		arg = args[1]
+	} else {
+		// guess the command with the argument only.
+		arg = cmd
+		switch {
+		case strings.HasPrefix(arg, "hx") || strings.HasPrefix(arg, "cx"):
+			cmd = "addr"
+		case exploreHashPattern.MatchString(arg):
+			cmd = "hash"
+		default:
+			if _, err := strconv.ParseInt(arg, 0, 64); err == nil {
+				cmd = "block"
+			}
+		}
+	}
+	switch cmd {
+	case "block", "b":
+		if exploreHashPattern.MatchString(arg) {
+			e.openWith(true, func() (*explorePage, error) {
+				return e.blockPageByHash(exploreHash(arg))
+			})
+			return
+		}
+		height, err := strconv.ParseInt(arg, 0, 64)
+		if err != nil {
+			e.message = fmt.Sprintf("ERROR invalid height %s", arg)
+			return
+		}
+		e.openWith(true, func() (*explorePage, error) {
+			return e.blockPageByHeight(height)
+		})
+	case "tx", "t":
+		e.openWith(true, func() (*explorePage, error) {
+			return e.txPage(exploreHash(arg))
+		})
+	case "hash":
+		e.openWith(true, func() (*explorePage, error) {
+			if p, err := e.txPage(exploreHash(arg)); err == nil {
+				return p, nil
+			}
+			return e.blockPageByHash(exploreHash(arg))
+		})
+	case "addr", "a":
+		e.openWith(true, func() (*explorePage, error) {
+			return e.addressPage(arg)
+		})
+	case "follow", "f":
+		e.setFollow(arg)
+	case "unfollow":
+		e.setFollow("")
+	default:
+		e.message = fmt.Sprintf("ERROR unknown command %s", cmd)
+	}
+}
+
+func exploreHash(s string) string {
+	if strings.HasPrefix(s, "0x") {
+		return s
+	}
+	return "0x" + s
+}
+
+func (e *explorer) setFollow(addr string) {
+	if len(addr) > 0 {
+		if _, err := common.NewAddressFromString(addr); err != nil {
+			e.message = fmt.Sprintf("ERROR invalid address %s", addr)
+			return
+		}
+	}
+	if e.follow != addr {
+		e.follow = addr
+		e.activities = nil
+	}
+	if len(addr) > 0 {
+		e.message = fmt.Sprintf("Following %s", addr)
+	} else {
+		e.message = "Unfollowed"
+	}
+}
+
+func (e *explorer) moveCursor(v *gocui.View, d, size int) {
+	_, oy := v.Origin()
+	_, cy := v.Cursor()
+	_, h := v.Size()
+	pos := oy + cy + d
+	if pos >= size {
+		pos = size - 1
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	if pos < oy {
+		oy = pos
+	} else if pos >= oy+h {
+		oy = pos - h + 1
+	}
+	_ = v.SetOrigin(0, oy)
+	_ = v.SetCursor(0, pos-oy)
+}
+
+func (e *explorer) viewSize(v *gocui.View) int {
+	switch v.Name() {
+	case exploreViewBlocks:
+		return len(e.blocks)
+	case exploreViewDetail:
+		if e.page != nil {
+			return len(e.page.lines)
+		}
+	}
+	return 0
+}
+
+func (e *explorer) selected(v *gocui.View) int {
+	_, oy := v.Origin()
+	_, cy := v.Cursor()
+	return oy + cy
+}
+
+func (e *explorer) layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	if v, err := g.SetView(exploreViewBlocks, 0, 0, exploreBlocksWidth, maxY-3); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Blocks"
+		v.Highlight = true
+		v.SelBgColor = gocui.ColorGreen
+		v.SelFgColor = gocui.ColorBlack
+		if _, err := g.SetCurrentView(exploreViewBlocks); err != nil {
+			return err
+		}
+	}
+	if v, err := g.SetView(exploreViewDetail, exploreBlocksWidth+1, 0, maxX-1, maxY-3); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Highlight = true
+		v.SelBgColor = gocui.ColorGreen
+		v.SelFgColor = gocui.ColorBlack
+	}
+	if v, err := g.SetView(exploreViewStatus, 0, maxY-3, maxX-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = true
+	}
+	return e.render(g)
+}
+
+func (e *explorer) render(g *gocui.Gui) error {
+	v, err := g.View(exploreViewBlocks)
+	if err != nil {
+		return err
+	}
+	v.Clear()
+	for _, blk := range e.blocks {
+		mark := " "
+		if len(e.follow) > 0 {
+			for _, a := range e.activities {
+				if a.height == blk.Height {
+					mark = "*"
+					break
+				}
+			}
+		}
+		fmt.Fprintf(v, "%s%-12d txs=%d\n", mark, blk.Height, len(blk.NormalTransactions))
+	}
+
+	v, err = g.View(exploreViewDetail)
+	if err != nil {
+		return err
+	}
+	v.Clear()
+	if e.page != nil {
+		v.Title = e.page.title
+		for _, line := range e.page.lines {
+			fmt.Fprintln(v, line)
+		}
+	} else {
+		v.Title = "Detail"
+		fmt.Fprintln(v, "Select a block and press Enter, or press ':' to type a command")
+		fmt.Fprintln(v, "")
+		fmt.Fprintln(v, "  block HEIGHT|HASH   open the block")
+		fmt.Fprintln(v, "  tx HASH             open the transaction and its receipt")
+		fmt.Fprintln(v, "  addr ADDRESS        open balance and SCORE API of the address")
+		fmt.Fprintln(v, "  follow ADDRESS      follow transactions of the address")
+		fmt.Fprintln(v, "  unfollow            stop following")
+	}
+
+	v, err = g.View(exploreViewStatus)
+	if err != nil {
+		return err
+	}
+	v.Clear()
+	height := int64(-1)
+	if len(e.blocks) > 0 {
+		height = e.blocks[0].Height
+	}
+	status := fmt.Sprintf("%s | height=%d", e.uri, height)
+	if len(e.follow) > 0 {
+		status += fmt.Sprintf(" | follow=%s(%d)", e.follow, len(e.activities))
+	}
+	if len(e.message) > 0 {
+		status += " | " + e.message
+	} else {
+		status += " | Tab:switch Enter:open Esc:back ':':command f:follow a:activities q:quit"
+	}
+	fmt.Fprint(v, status)
+	return nil
+}
+
+func (e *explorer) keybindings(g *gocui.Gui) error {
+	type binding struct {
+		views []string
+		key   interface{}
+		h     func(*gocui.Gui, *gocui.View) error
+	}
+	lists := []string{exploreViewBlocks, exploreViewDetail}
+	bindings := []binding{
+		{[]string{""}, gocui.KeyCtrlC, CuiQuitKeyEvtFunc},
+		{lists, 'q', CuiQuitKeyEvtFunc},
+		{lists, gocui.KeyArrowDown, func(g *gocui.Gui, v *gocui.View) error {
+			e.moveCursor(v, 1, e.viewSize(v))
+			return nil
+		}},
+		{lists, gocui.KeyArrowUp, func(g *gocui.Gui, v *gocui.View) error {
+			e.moveCursor(v, -1, e.viewSize(v))
+			return nil
+		}},
+		{lists, gocui.KeyPgdn, func(g *gocui.Gui, v *gocui.View) error {
+			_, h := v.Size()
+			e.moveCursor(v, h, e.viewSize(v))
+			return nil
+		}},
+		{lists, gocui.KeyPgup, func(g *gocui.Gui, v *gocui.View) error {
+			_, h := v.Size()
+			e.moveCursor(v, -h, e.viewSize(v))
+			return nil
+		}},
+		{lists, gocui.KeyTab, func(g *gocui.Gui, v *gocui.View) error {
+			next := exploreViewDetail
+			if v.Name() == exploreViewDetail {
+				next = exploreViewBlocks
+			}
+			_, err := g.SetCurrentView(next)
+			return err
+		}},
+		{lists, ':', func(g *gocui.Gui, v *gocui.View) error {
+			maxX, maxY := g.Size()
+			iv, err := g.SetView(exploreViewInput, 0, maxY-3, maxX-1, maxY-1)
+			if err != nil && err != gocui.ErrUnknownView {
+				return err
+			}
+			iv.Title = "Command"
+			iv.Editable = true
+			iv.Clear()
+			_ = iv.SetCursor(0, 0)
+			_, err = g.SetCurrentView(exploreViewInput)
+			return err
+		}},
+		{lists, 'a', func(g *gocui.Gui, v *gocui.View) error {
+			e.open(e.activitiesPage(), true)
+			return nil
+		}},
+		{[]string{exploreViewBlocks}, gocui.KeyEnter, func(g *gocui.Gui, v *gocui.View) error {
+			if idx := e.selected(v); idx < len(e.blocks) {
+				e.stack = nil
+				e.open(e.blockPage(e.blocks[idx]), false)
+			}
+			return nil
+		}},
+		{[]string{exploreViewDetail}, gocui.KeyEnter, func(g *gocui.Gui, v *gocui.View) error {
+			if e.page == nil {
+				return nil
+			}
+			if open, ok := e.page.links[e.selected(v)]; ok {
+				e.openWith(true, open)
+			}
+			return nil
+		}},
+		{[]string{exploreViewDetail}, 'f', func(g *gocui.Gui, v *gocui.View) error {
+			if e.page != nil && len(e.page.address) > 0 {
+				addr := e.page.address
+				if e.follow == addr {
+					e.setFollow("")
+				} else {
+					e.setFollow(addr)
+				}
+			}
+			return nil
+		}},
+		{[]string{exploreViewDetail}, gocui.KeyEsc, func(g *gocui.Gui, v *gocui.View) error {
+			if len(e.stack) > 0 {
+				p := e.stack[len(e.stack)-1]
+				e.stack = e.stack[:len(e.stack)-1]
+				e.open(p, false)
+			} else {
+				_, err := g.SetCurrentView(exploreViewBlocks)
+				return err
+			}
+			return nil
+		}},
+		{[]string{exploreViewInput}, gocui.KeyEnter, func(g *gocui.Gui, v *gocui.View) error {
+			line := strings.TrimSpace(v.Buffer())
+			if err := g.DeleteView(exploreViewInput); err != nil {
+				return err
+			}
+			if _, err := g.SetCurrentView(exploreViewDetail); err != nil {
+				return err
+			}
+			e.execute(line)
+			return nil
+		}},
+		{[]string{exploreViewInput}, gocui.KeyEsc, func(g *gocui.Gui, v *gocui.View) error {
+			if err := g.DeleteView(exploreViewInput); err != nil {
+				return err
+			}
+			_, err := g.SetCurrentView(exploreViewBlocks)
+			return err
+		}},
+	}
+	for _, b := range bindings {
+		for _, view := range b.views {
+			if err := g.SetKeybinding(view, b.key, gocui.ModNone, b.h); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func NewExploreCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command, *viper.Viper) {
+	var rpcClient client.ClientV3
+	rootCmd, vc := NewCommand(parentCmd, parentVc, "explore", "Interactive block explorer")
+	rootCmd.Long = "Interactive block explorer showing the latest blocks live.\n" +
+		"Select a block with the arrow keys and press Enter to see the transactions,\n" +
+		"and open transactions, receipts, events, balances and SCORE APIs with Enter.\n" +
+		"Press ':' to type a command (block, tx, addr, follow or unfollow)."
+	rootCmd.Args = cobra.NoArgs
+	rootCmd.PersistentPreRunE = RpcPersistentPreRunE(vc, &rpcClient)
+	AddRpcRequiredFlags(rootCmd)
+	rootPFlags := rootCmd.PersistentFlags()
+	rootPFlags.Int("blocks", 20, "Number of the recent blocks loaded at start")
+	rootPFlags.String("follow", "", "Address to follow")
+	BindPFlags(vc, rootCmd.PersistentFlags())
+
+	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		last, err := rpcClient.GetLastBlock()
+		if err != nil {
+			return err
+		}
+		e := &explorer{
+			c:        &rpcClient,
+			uri:      vc.GetString("uri"),
+			cancelCh: make(chan bool),
+		}
+		if follow := vc.GetString("follow"); len(follow) > 0 {
+			if _, err := common.NewAddressFromString(follow); err != nil {
+				return fmt.Errorf("invalid address to follow %s err=%+v", follow, err)
+			}
+			e.follow = follow
+		}
+		count := int64(vc.GetInt("blocks"))
+		for h := last.Height - count + 1; h < last.Height; h++ {
+			if h < 0 {
+				continue
+			}
+			blk, err := rpcClient.GetBlockByHeight(&v3.BlockHeightParam{
+				Height: jsonrpc.HexIntFromInt64(h),
+			})
+			if err != nil {
+				return err
+			}
+			e.blocks = append([]*client.Block{blk}, e.blocks...)
+		}
+		e.blocks = append([]*client.Block{last}, e.blocks...)
+
+		g, err := gocui.NewGui(gocui.OutputNormal)
+		if err != nil {
+			return err
+		}
+		defer g.Close()
+		e.g = g
+		g.SetManagerFunc(e.layout)
+		if err := e.keybindings(g); err != nil {
+			return err
+		}
+		go e.monitor(last.Height + 1)
+		defer func() {
+			close(e.cancelCh)
+			rpcClient.Cleanup()
+		}()
+		if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+			return err
+		}
+		return nil
+	}
+	return rootCmd, vc
+}
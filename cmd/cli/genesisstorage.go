@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/module"
 	"github.com/spf13/cobra"
 )
 
@@ -84,9 +85,115 @@ func newGStorageInfoCmd(c string) *cobra.Command {
 	return cmd
 }
 
+func newGStorageBuildCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s spec.json", c),
+		Short: "Build genesis storage from the genesis spec",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+	}
+	flags := cmd.Flags()
+	out := flags.StringP("out", "o", "gs.zip", "Output file path")
+	check := flags.Bool("check", false, "Validate the spec only")
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		spec, err := gs.ReadSpec(args[0])
+		if err != nil {
+			log.Panicf("Fail to read spec err=%+v", err)
+		}
+		if *check {
+			if err := spec.Validate(); err != nil {
+				log.Panicf("Invalid spec err=%+v", err)
+			}
+			fmt.Printf("Valid %s\n", args[0])
+			return
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := spec.Build(buf); err != nil {
+			log.Panicf("Fail to build genesis storage err=%+v", err)
+		}
+		if err := os.WriteFile(*out, buf.Bytes(), 0600); err != nil {
+			log.Panicf("Fail to write %s err=%+v", *out, err)
+		}
+		fmt.Printf("Generated %s with %s\n", *out, args[0])
+	}
+	return cmd
+}
+
+func mustOpenGStorage(p string) module.GenesisStorage {
+	f, err := os.Open(p)
+	if err != nil {
+		log.Panicf("Fail to open file=%s err=%+v", p, err)
+	}
+	s, err := gs.NewFromFile(f)
+	if err != nil {
+		log.Panicf("Fail to read genesis storage file=%s err=%+v", p, err)
+	}
+	return s
+}
+
+func newGStorageInspectCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s genesis_storage.zip", c),
+		Short: "List genesis data entries with their references",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+	}
+	flags := cmd.Flags()
+	asJson := flags.Bool("json", false, "Print in JSON")
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		res, err := gs.Inspect(mustOpenGStorage(args[0]))
+		if err != nil {
+			log.Panicf("Fail to inspect file=%s err=%+v", args[0], err)
+		}
+		if *asJson {
+			JsonPrettyPrintln(os.Stdout, res)
+			return
+		}
+		fmt.Printf("File       : %s\nType       : %s\nNetwork ID : %#x (%[3]d)\nChain   ID : %#x (%[4]d)\nHeight     : %d\n",
+			args[0], res.Type, res.NID, res.CID, res.Height)
+		fmt.Printf("Data       : %d entries\n", len(res.Data))
+		for _, e := range res.Data {
+			fmt.Printf("  %#x size=%d type=%s\n", []byte(e.Key), e.Size, e.ContentType)
+			for _, ref := range e.References {
+				fmt.Printf("    <- %s\n", ref)
+			}
+		}
+	}
+	return cmd
+}
+
+func newGStorageDiffCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s genesis_storage1.zip genesis_storage2.zip", c),
+		Short: "Show differences between two genesis storages",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+	}
+	flags := cmd.Flags()
+	asJson := flags.Bool("json", false, "Print in JSON")
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		diffs, err := gs.Diff(mustOpenGStorage(args[0]), mustOpenGStorage(args[1]))
+		if err != nil {
+			log.Panicf("Fail to compare err=%+v", err)
+		}
+		if *asJson {
+			JsonPrettyPrintln(os.Stdout, diffs)
+			return
+		}
+		if len(diffs) == 0 {
+			fmt.Println("No differences")
+			return
+		}
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+	}
+	return cmd
+}
+
 func NewGStorageCmd(c string) *cobra.Command {
 	cmd := &cobra.Command{Use: c, Short: "Genesis storage manipulation"}
 	cmd.AddCommand(newGStorageGenCmd("gen"))
 	cmd.AddCommand(newGStorageInfoCmd("info"))
+	cmd.AddCommand(newGStorageBuildCmd("build"))
+	cmd.AddCommand(newGStorageInspectCmd("inspect"))
+	cmd.AddCommand(newGStorageDiffCmd("diff"))
 	return cmd
 }
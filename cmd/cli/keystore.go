@@ -148,6 +148,8 @@ func NewKeystoreCmd(c string) *cobra.Command {
 	cmd.AddCommand(newVerifyCmd("verify"))
 	cmd.AddCommand(publickeyFromKeyStore("pubkey"))
 	cmd.AddCommand(newReEncryptCmd("encrypt"))
+	cmd.AddCommand(newSignCmd("sign"))
+	cmd.AddCommand(newProposalCmd("proposal"))
 	return cmd
 }
 
@@ -30,23 +30,27 @@ func RpcPersistentPreRunE(vc *viper.Viper, rpcClient *client.ClientV3) func(cmd
 		if err := ValidateFlagsWithViper(vc, cmd.Flags()); err != nil {
 			return err
 		}
-		*rpcClient = *client.NewClientV3(vc.GetString("uri"))
-		if uri := vc.GetString("debug_uri"); len(uri) > 0 {
-			rpcClient.DebugEndPoint = uri
-		}
-		if vc.GetBool("debug") {
-			opts := jsonrpc.IconOptions{}
-			opts.SetBool(jsonrpc.IconOptionsDebug, true)
-			rpcClient.CustomHeader[jsonrpc.HeaderKeyIconOptions] = opts.ToHeaderValue()
-			rpcClient.Pre = func(req *http.Request) error {
-				b, err := req.GetBody()
-				if err != nil {
-					return err
-				}
-				return JsonPrettyCopyAndClose(os.Stderr, b)
+		initRpcClient(vc, rpcClient)
+		return nil
+	}
+}
+
+func initRpcClient(vc *viper.Viper, rpcClient *client.ClientV3) {
+	*rpcClient = *client.NewClientV3(vc.GetString("uri"))
+	if uri := vc.GetString("debug_uri"); len(uri) > 0 {
+		rpcClient.DebugEndPoint = uri
+	}
+	if vc.GetBool("debug") {
+		opts := jsonrpc.IconOptions{}
+		opts.SetBool(jsonrpc.IconOptionsDebug, true)
+		rpcClient.CustomHeader[jsonrpc.HeaderKeyIconOptions] = opts.ToHeaderValue()
+		rpcClient.Pre = func(req *http.Request) error {
+			b, err := req.GetBody()
+			if err != nil {
+				return err
 			}
+			return JsonPrettyCopyAndClose(os.Stderr, b)
 		}
-		return nil
 	}
 }
 
@@ -479,6 +483,33 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 	flags = scoreStatusCmd.Flags()
 	flags.Int("height", -1, "BlockHeight")
 
+	proofCmd := &cobra.Command{
+		Use:   "proof ADDRESS [KEY...]",
+		Short: "Get merkle proofs of the account and the values for the storage keys",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &v3.AccountProofParam{Address: jsonrpc.Address(args[0])}
+			for _, k := range args[1:] {
+				param.Keys = append(param.Keys, jsonrpc.HexBytes(k))
+			}
+			height, err := intconv.ParseInt(cmd.Flag("height").Value.String(), 64)
+			if err != nil {
+				return err
+			}
+			if height != -1 {
+				param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+			}
+			proof, err := rpcClient.GetProof(param)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, proof)
+		},
+	}
+	rootCmd.AddCommand(proofCmd)
+	flags = proofCmd.Flags()
+	flags.Int("height", -1, "BlockHeight")
+
 	networkInfoCmd := &cobra.Command{
 		Use: "networkinfo",
 		Short: "Get network info of the endpoint",
@@ -660,6 +691,17 @@ func NewSendTxCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comman
 				}
 				return step, nil
 			}
+		} else if unsigned := vc.GetString("unsigned"); len(unsigned) > 0 {
+			rpcClientSendTx = func(w module.Wallet, p *v3.TransactionParam) (interface{}, error) {
+				p.Timestamp = client.TimestampNow()
+				if err := JsonPrettySaveFile(unsigned, 0644, p); err != nil {
+					return nil, err
+				}
+				return p, nil
+			}
+			if err := CheckFlagsWithViper(vc, cmd.Flags(), "step_limit"); err != nil {
+				return err
+			}
 		} else {
 			save := vc.GetString("save")
 			rpcClientSendTx = func(w module.Wallet, p *v3.TransactionParam) (interface{}, error) {
@@ -681,6 +723,17 @@ func NewSendTxCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comman
 		var kb, pb []byte
 		var err error
 		ksf := vc.GetString("key_store")
+		if ksf == "" {
+			if vc.GetString("unsigned") == "" {
+				return fmt.Errorf(`required flag(s) "key_store" not set`)
+			}
+			from, err := common.NewAddressFromString(vc.GetString("from"))
+			if err != nil {
+				return fmt.Errorf("fail to parse sender address, use --from or --key_store err=%+v", err)
+			}
+			rpcWallet = &addressWallet{address: from}
+			return nil
+		}
 		if kb, err = os.ReadFile(ksf); err != nil {
 			return fmt.Errorf("fail to open KeyStore file=%s err=%+v", ksf, err)
 		}
@@ -786,7 +839,7 @@ func NewSendTxCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comman
 	}
 	AddRpcRequiredFlags(rootCmd)
 	rootPFlags := rootCmd.PersistentFlags()
-	rootPFlags.String("key_store", "", "KeyStore file for wallet")
+	rootPFlags.String("key_store", "", "KeyStore file for wallet (not required with --unsigned)")
 	rootPFlags.String("key_secret", "", "Secret(password) file for KeyStore")
 	rootPFlags.String("key_password", "", "Password for the KeyStore file")
 	rootPFlags.String("nid", "", "Network ID")
@@ -796,7 +849,9 @@ func NewSendTxCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comman
 	rootPFlags.Int("wait_timeout", 10, "Timeout(sec) for wait transaction result")
 	rootPFlags.Bool("estimate", false, "Just estimate steps for the tx")
 	rootPFlags.String("save", "", "Store transaction to the file")
-	MarkAnnotationCustom(rootPFlags, "key_store", "nid")
+	rootPFlags.String("unsigned", "", "Store unsigned transaction to the file instead of sending it")
+	rootPFlags.String("from", "", "FromAddress for unsigned transaction without KeyStore")
+	MarkAnnotationCustom(rootPFlags, "nid")
 	BindPFlags(vc, rootCmd.PersistentFlags())
 	MarkAnnotationHidden(rootPFlags, "wait", "wait_interval", "wait_timeout")
 
@@ -897,6 +952,40 @@ func NewSendTxCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comman
 	}
 	rootCmd.AddCommand(raw3Cmd)
 
+	rawSignedCmd := &cobra.Command{
+		Use:   "raw-signed FILE",
+		Short: "Send transaction signed with 'ks sign' after verifying the signature",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// It requires neither KeyStore nor nid for the signed one.
+			if vc.GetString("uri") == "" {
+				return fmt.Errorf(`required flag(s) "uri" not set`)
+			}
+			initRpcClient(vc, &rpcClient)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := readFile(args[0])
+			if err != nil {
+				return err
+			}
+			param := &v3.TransactionParam{}
+			if err := json.Unmarshal(b, param); err != nil {
+				return err
+			}
+			if err := client.VerifyTransaction(param); err != nil {
+				return err
+			}
+			txHash, err := rpcClient.SendSignedTransaction(param)
+			if err != nil {
+				return err
+			}
+			vc.Set("txhash", txHash)
+			return JsonPrettyPrintln(os.Stdout, txHash)
+		},
+	}
+	rootCmd.AddCommand(rawSignedCmd)
+
 	transferCmd := &cobra.Command{
 		Use:   "transfer",
 		Short: "Coin Transfer Transaction",
@@ -139,6 +139,11 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	rootPFlags.String("p2p_listen", "", "Listen ip-port of P2P")
 	rootPFlags.String("rpc_addr", ":9080", "Listen ip-port of JSON-RPC")
 	rootPFlags.Bool("rpc_dump", false, "JSON-RPC Request, Response Dump flag")
+	rootPFlags.String("rpc_tls_cert", "", "TLS certificate file for JSON-RPC, reloaded on change")
+	rootPFlags.String("rpc_tls_key", "", "TLS private key file for JSON-RPC, reloaded on change")
+	rootPFlags.String("rpc_tls_client_ca", "", "CA certificates file to verify TLS client certificates")
+	rootPFlags.String("rpc_tls_client_auth", "",
+		"TLS client authentication (none,optional,require) (default: optional with client CA)")
 	rootPFlags.String("ee_socket", "", "Execution engine socket path")
 	rootPFlags.String("key_password", "", "Password for the KeyStore file")
 	rootPFlags.String("log_level", "debug", "Global log level (trace,debug,info,warn,error,fatal,panic)")
@@ -306,6 +311,10 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 	eeSocket := vc.GetString("ee_socket")
 	backupDir := vc.GetString("backup_dir")
 	lwFilename := vc.GetString("log_writer_filename")
+	tlsCert := vc.GetString("rpc_tls_cert")
+	tlsKey := vc.GetString("rpc_tls_key")
+	tlsClientCA := vc.GetString("rpc_tls_client_ca")
+	tlsClientAuth := vc.GetString("rpc_tls_client_auth")
 
 	if cfgFilePath != "" {
 		cfg.SetFilePath(cfgFilePath)
@@ -400,6 +409,18 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 	if backupDir != "" {
 		cfg.BackupDir = cfg.ResolveRelative(backupDir)
 	}
+	if tlsCert != "" {
+		cfg.RPCTLSCert = cfg.ResolveRelative(tlsCert)
+	}
+	if tlsKey != "" {
+		cfg.RPCTLSKey = cfg.ResolveRelative(tlsKey)
+	}
+	if tlsClientCA != "" {
+		cfg.RPCTLSClientCA = cfg.ResolveRelative(tlsClientCA)
+	}
+	if tlsClientAuth != "" {
+		cfg.RPCTLSClientAuth = tlsClientAuth
+	}
 
 	//config.KeyStorePass
 	//overwrite env.KeyStorePass
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeWithViper_RPCTLS(t *testing.T) {
+	cmd, vc := NewServerCmd(nil, nil, "test", "test", nil)
+	fs := cmd.PersistentFlags()
+	assert.NoError(t, fs.Set("rpc_tls_cert", "cert.pem"))
+	assert.NoError(t, fs.Set("rpc_tls_key", "/tls/key.pem"))
+	assert.NoError(t, fs.Set("rpc_tls_client_ca", "ca.pem"))
+	assert.NoError(t, fs.Set("rpc_tls_client_auth", "require"))
+
+	cfg := &ServerConfig{}
+	assert.NoError(t, MergeWithViper(vc, cfg))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, path.Join(wd, "cert.pem"), cfg.ResolveAbsolute(cfg.RPCTLSCert))
+	assert.Equal(t, "/tls/key.pem", cfg.ResolveAbsolute(cfg.RPCTLSKey))
+	assert.Equal(t, path.Join(wd, "ca.pem"), cfg.ResolveAbsolute(cfg.RPCTLSClientCA))
+	assert.Equal(t, "require", cfg.RPCTLSClientAuth)
+}
+
+func TestMergeWithViper_RPCTLSConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := path.Join(dir, "server.json")
+	assert.NoError(t, os.WriteFile(cfgFile, []byte(`{
+		"rpc_tls_cert": "cert.pem",
+		"rpc_tls_client_auth": "optional"
+	}`), 0600))
+
+	cmd, vc := NewServerCmd(nil, nil, "test", "test", nil)
+	assert.NoError(t, cmd.PersistentFlags().Set("config", cfgFile))
+
+	cfg := &ServerConfig{}
+	assert.NoError(t, MergeWithViper(vc, cfg))
+	assert.Equal(t, path.Join(dir, "cert.pem"), cfg.ResolveAbsolute(cfg.RPCTLSCert))
+	assert.Equal(t, "optional", cfg.RPCTLSClientAuth)
+}
+
+func TestMergeWithViper_RPCTLSEnv(t *testing.T) {
+	t.Setenv("SERVER_RPC_TLS_CLIENT_AUTH", "none")
+
+	_, vc := NewServerCmd(nil, nil, "test", "test", nil)
+	cfg := &ServerConfig{}
+	assert.NoError(t, MergeWithViper(vc, cfg))
+	assert.Equal(t, "none", cfg.RPCTLSClientAuth)
+}
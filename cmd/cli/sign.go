@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+// addressWallet is the wallet only with the address for making unsigned
+// transactions.
+type addressWallet struct {
+	address module.Address
+}
+
+func (w *addressWallet) Address() module.Address {
+	return w.address
+}
+
+func (w *addressWallet) Sign(data []byte) ([]byte, error) {
+	return nil, errors.UnsupportedError.New("NoKeyForSign")
+}
+
+func (w *addressWallet) PublicKey() []byte {
+	return nil
+}
+
+func writeJSONOutput(out string, v interface{}) error {
+	if len(out) == 0 {
+		return JsonPrettyPrintln(os.Stdout, v)
+	}
+	return JsonPrettySaveFile(out, 0644, v)
+}
+
+func readProposal(file string) (*client.Proposal, error) {
+	bs, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := new(client.Proposal)
+	if err := json.Unmarshal(bs, p); err != nil {
+		return nil, fmt.Errorf("fail to parse proposal file=%s err=%+v", file, err)
+	}
+	return p, nil
+}
+
+func signTransactionFile(w module.Wallet, file, timestamp string) (*v3.TransactionParam, error) {
+	bs, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	param := &v3.TransactionParam{}
+	if err := json.Unmarshal(bs, param); err != nil {
+		return nil, fmt.Errorf("fail to parse transaction file=%s err=%+v", file, err)
+	}
+	from := jsonrpc.Address(w.Address().String())
+	if param.FromAddress == "" {
+		param.FromAddress = from
+	} else if param.FromAddress != from {
+		return nil, fmt.Errorf("sender of the transaction(%s) is different from the keystore(%s)",
+			param.FromAddress, from)
+	}
+	switch timestamp {
+	case "":
+	case "now":
+		param.Timestamp = client.TimestampNow()
+	default:
+		ts, err := intconv.ParseInt(timestamp, 64)
+		if err != nil {
+			return nil, fmt.Errorf("fail to parse timestamp=%s err=%+v", timestamp, err)
+		}
+		param.Timestamp = jsonrpc.HexIntFromInt64(ts)
+	}
+	if param.Timestamp == "" {
+		return nil, fmt.Errorf("no timestamp in the transaction, use --timestamp")
+	}
+	if err := client.SignTransaction(w, param); err != nil {
+		return nil, err
+	}
+	return param, nil
+}
+
+func newSignCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s FILE", c),
+		Short: "Sign unsigned transaction or proposal file",
+		Long: "Sign the transaction stored with 'rpc sendtx --unsigned', which can be\n" +
+			"sent with 'rpc sendtx raw-signed'. With --proposal, it adds the signature\n" +
+			"to the proposal made with 'ks proposal new'.",
+		Args: ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+	}
+	flags := cmd.Flags()
+	keystorePath := flags.StringP("keystore", "k", "keystore.json", "Keystore file path")
+	interactive := flags.BoolP("interactive", "i", false, "Interactive mode for password input")
+	secret := flags.StringP("secret", "s", "", "KeySecret file path")
+	pass := flags.StringP("password", "p", "gochain", "Password for the keystore")
+	out := flags.StringP("out", "o", "", "Output file path (default: stdout)")
+	proposal := flags.Bool("proposal", false, "Sign the proposal instead of the transaction")
+	timestamp := flags.String("timestamp", "",
+		"Timestamp of the transaction in microseconds, or 'now' (default: keep the one in the file)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		kb, err := os.ReadFile(*keystorePath)
+		if err != nil {
+			return fmt.Errorf("fail to open keystore file err=%+v", err)
+		}
+		pb := getPasswordFromFlags("Password: ", interactive, secret, pass)
+		w, err := wallet.NewFromKeyStore(kb, pb)
+		if err != nil {
+			return fmt.Errorf("fail to decrypt KeyStore err=%+v", err)
+		}
+		if *proposal {
+			p, err := readProposal(args[0])
+			if err != nil {
+				return err
+			}
+			if err := p.Sign(w); err != nil {
+				return err
+			}
+			return writeJSONOutput(*out, p)
+		}
+		param, err := signTransactionFile(w, args[0], *timestamp)
+		if err != nil {
+			return err
+		}
+		return writeJSONOutput(*out, param)
+	}
+	return cmd
+}
+
+func newProposalNewCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s [PAYLOAD_FILE]", c),
+		Short: "Make a proposal with the payload",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MaximumNArgs(1)),
+	}
+	flags := cmd.Flags()
+	data := flags.String("data", "", "Payload in hex string instead of PAYLOAD_FILE")
+	out := flags.StringP("out", "o", "", "Output file path (default: stdout)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var payload []byte
+		var err error
+		if len(args) == 1 {
+			if len(*data) > 0 {
+				return fmt.Errorf("PAYLOAD_FILE can't be used with --data")
+			}
+			if payload, err = readFile(args[0]); err != nil {
+				return err
+			}
+		} else if len(*data) > 0 {
+			if payload, err = hex.DecodeString(strings.TrimPrefix(*data, "0x")); err != nil {
+				return fmt.Errorf("fail to parse data=%s err=%+v", *data, err)
+			}
+		}
+		if len(payload) == 0 {
+			return fmt.Errorf("empty payload, use PAYLOAD_FILE or --data")
+		}
+		return writeJSONOutput(*out, client.NewProposal(payload))
+	}
+	return cmd
+}
+
+func newProposalMergeCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s FILE...", c),
+		Short: "Merge signatures of the proposals for the same payload",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MinimumNArgs(1)),
+	}
+	out := cmd.Flags().StringP("out", "o", "", "Output file path (default: stdout)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var merged *client.Proposal
+		for _, file := range args {
+			p, err := readProposal(file)
+			if err != nil {
+				return err
+			}
+			if err := p.Verify(); err != nil {
+				return fmt.Errorf("invalid proposal file=%s err=%+v", file, err)
+			}
+			if merged == nil {
+				merged = p
+			} else if err := merged.Merge(p); err != nil {
+				return fmt.Errorf("fail to merge file=%s err=%+v", file, err)
+			}
+		}
+		return writeJSONOutput(*out, merged)
+	}
+	return cmd
+}
+
+func newProposalVerifyCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s FILE", c),
+		Short: "Verify signatures of the proposal",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+	}
+	flags := cmd.Flags()
+	signers := flags.StringSlice("signers", nil, "Addresses of the signers required")
+	threshold := flags.Int("threshold", 0, "Number of the signers required among --signers (default: all)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		p, err := readProposal(args[0])
+		if err != nil {
+			return err
+		}
+		if err := p.Verify(); err != nil {
+			return err
+		}
+		signed := make(map[string]bool)
+		for _, s := range p.Signatures {
+			signed[strings.ToLower(string(s.Signer))] = true
+		}
+		if len(*signers) > 0 {
+			count := 0
+			for _, s := range *signers {
+				if signed[strings.ToLower(s)] {
+					count += 1
+				}
+			}
+			required := *threshold
+			if required <= 0 {
+				required = len(*signers)
+			}
+			if count < required {
+				return fmt.Errorf("not enough signatures signed=%d required=%d", count, required)
+			}
+		}
+		for _, s := range p.Signatures {
+			fmt.Println(s.Signer)
+		}
+		fmt.Println("SUCCESS")
+		return nil
+	}
+	return cmd
+}
+
+func newProposalParamsCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s FILE", c),
+		Short: "Print parameters for 'rpc sendtx call --params' with the proposal",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+	}
+	flags := cmd.Flags()
+	payloadName := flags.String("payload_name", "_payload", "Parameter name for the payload")
+	signaturesName := flags.String("signatures_name", "_signatures", "Parameter name for the signatures")
+	out := flags.StringP("out", "o", "", "Output file path (default: stdout)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		p, err := readProposal(args[0])
+		if err != nil {
+			return err
+		}
+		if err := p.Verify(); err != nil {
+			return err
+		}
+		sigs := make([]common.HexBytes, len(p.Signatures))
+		for i, s := range p.Signatures {
+			sigs[i] = s.Signature
+		}
+		return writeJSONOutput(*out, map[string]interface{}{
+			*payloadName:    p.Payload,
+			*signaturesName: sigs,
+		})
+	}
+	return cmd
+}
+
+func newProposalCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c,
+		Short: "Collect signatures on a proposal for multi-signature contracts",
+	}
+	cmd.AddCommand(
+		newProposalNewCmd("new"),
+		newProposalMergeCmd("merge"),
+		newProposalVerifyCmd("verify"),
+		newProposalParamsCmd("params"),
+	)
+	return cmd
+}
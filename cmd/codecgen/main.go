@@ -0,0 +1,535 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/imports"
+)
+
+const (
+	annotation  = "//codec:generate"
+	codecPkg    = "github.com/icon-project/goloop/common/codec"
+	defaultFile = "codec_gen.go"
+)
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, strings.Join([]string{
+		"codecgen [-o <file>] [<dir>]",
+		"",
+		"Generates RLPEncodeSelf and RLPDecodeSelf for the structs annotated",
+		"with \"" + annotation + "\" in the package of the directory.",
+		"They produce the same bytes as the reflection of common/codec.",
+		"",
+		"Trailing fields with the tag `codec:\"optional\"` are omitted on",
+		"encoding if they are all nil, and they may be absent on decoding.",
+		"",
+		"Example:",
+		"    //go:generate go run github.com/icon-project/goloop/cmd/codecgen",
+		"",
+	}, "\n"))
+}
+
+// these methods are used by common/codec instead of reflection.
+var customMethods = []string{
+	"RLPEncodeSelf", "RLPDecodeSelf",
+	"RLPWriteSelf", "RLPReadSelf",
+	"MarshalBinary", "UnmarshalBinary",
+	"MarshalRLP", "UnmarshalRLP",
+	"EncodeMsgpack", "DecodeMsgpack",
+	"MarshalMsgpack", "UnmarshalMsgpack",
+}
+
+type field struct {
+	path     string
+	typ      types.Type
+	optional bool
+}
+
+type target struct {
+	name   string
+	fields []field
+}
+
+func (t *target) hasOptional() bool {
+	for _, f := range t.fields {
+		if f.optional {
+			return true
+		}
+	}
+	return false
+}
+
+type pkgInfo struct {
+	Name    string
+	PkgPath string
+	Syntax  []*ast.File
+	Types   *types.Package
+}
+
+type generator struct {
+	pkg     *pkgInfo
+	imports map[string]string
+	buf     bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) qualifier(p *types.Package) string {
+	if p.Path() == g.pkg.PkgPath {
+		return ""
+	}
+	g.imports[p.Path()] = p.Name()
+	return p.Name()
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+func hasCustom(t types.Type) bool {
+	ms := types.NewMethodSet(types.NewPointer(t))
+	for _, name := range customMethods {
+		if ms.Lookup(nil, name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+var byteSlice = types.NewSlice(types.Typ[types.Byte])
+
+// basicKind returns the kind of the type if it's encoded as a basic value.
+// It returns empty string for the others.
+func basicKind(t types.Type) string {
+	if hasCustom(t) {
+		return ""
+	}
+	if types.Identical(t, byteSlice) {
+		return "bytes"
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return ""
+	}
+	switch b.Kind() {
+	case types.Bool:
+		return "Bool"
+	case types.Int:
+		return "Int"
+	case types.Int8:
+		return "Int8"
+	case types.Int16:
+		return "Int16"
+	case types.Int32:
+		return "Int32"
+	case types.Int64:
+		return "Int64"
+	case types.Uint:
+		return "Uint"
+	case types.Uint8:
+		return "Uint8"
+	case types.Uint16:
+		return "Uint16"
+	case types.Uint32:
+		return "Uint32"
+	case types.Uint64:
+		return "Uint64"
+	case types.String:
+		return "String"
+	default:
+		return ""
+	}
+}
+
+func isNilable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Pointer, *types.Map, *types.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+func (g *generator) zeroOf(t types.Type) string {
+	if isNilable(t) {
+		return "nil"
+	}
+	if b, ok := t.Underlying().(*types.Basic); ok {
+		switch {
+		case b.Info()&types.IsBoolean != 0:
+			return "false"
+		case b.Info()&types.IsString != 0:
+			return "\"\""
+		case b.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	}
+	return g.typeString(t) + "{}"
+}
+
+// collectFields returns the fields in the order of reflection in
+// common/codec. Embedded structs are flattened, and embedded interfaces
+// and unexported fields are ignored.
+func collectFields(st *types.Struct, prefix string) []field {
+	var fields []field
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if f.Embedded() {
+			switch u := f.Type().Underlying().(type) {
+			case *types.Interface:
+				continue
+			case *types.Struct:
+				fields = append(fields, collectFields(u, prefix+f.Name()+".")...)
+				continue
+			}
+		}
+		if !f.Exported() {
+			continue
+		}
+		tag := reflect.StructTag(st.Tag(i)).Get("codec")
+		fields = append(fields, field{
+			path:     prefix + f.Name(),
+			typ:      f.Type(),
+			optional: tag == "optional",
+		})
+	}
+	return fields
+}
+
+func (g *generator) genEncode(t *target) {
+	g.printf("func (o *%s) RLPEncodeSelf(e codec.Encoder) error {\n", t.name)
+	g.printf("e2, err := e.EncodeList()\nif err != nil {\nreturn err\n}\n")
+	for i, f := range t.fields {
+		if f.optional {
+			var conds []string
+			for _, f2 := range t.fields[i:] {
+				conds = append(conds, fmt.Sprintf("o.%s != nil", f2.path))
+			}
+			g.printf("if %s {\n", strings.Join(conds, " || "))
+		}
+		switch kind := basicKind(f.typ); kind {
+		case "":
+			g.printf("if err := e2.Encode(&o.%s); err != nil {\nreturn err\n}\n", f.path)
+		case "bytes":
+			g.printf("if err := e2.Encode(o.%s); err != nil {\nreturn err\n}\n", f.path)
+		case "Bool", "String":
+			g.printf("if err := codec.Encode%s(e2, %s(o.%s)); err != nil {\nreturn err\n}\n",
+				kind, strings.ToLower(kind), f.path)
+		default:
+			if strings.HasPrefix(kind, "Uint") {
+				g.printf("if err := codec.EncodeUint(e2, uint64(o.%s)); err != nil {\nreturn err\n}\n", f.path)
+			} else {
+				g.printf("if err := codec.EncodeInt(e2, int64(o.%s)); err != nil {\nreturn err\n}\n", f.path)
+			}
+		}
+		if f.optional {
+			g.printf("}\n")
+		}
+	}
+	g.printf("return nil\n}\n\n")
+}
+
+func (g *generator) genOnEOF(t *target, i int) {
+	f := t.fields[i]
+	if f.optional {
+		for _, f2 := range t.fields[i:] {
+			g.printf("o.%s = nil\n", f2.path)
+		}
+		g.printf("return nil\n")
+	} else {
+		g.printf("o.%s = %s\n", f.path, g.zeroOf(f.typ))
+	}
+}
+
+func (g *generator) genDecode(t *target) {
+	strict := t.hasOptional()
+	g.printf("func (o *%s) RLPDecodeSelf(d codec.Decoder) error {\n", t.name)
+	g.printf("d2, err := codec.DecodeStruct(d)\nif err != nil {\nreturn err\n}\n")
+	for i, f := range t.fields {
+		// without optional fields, missing fields are zero like reflection.
+		handleEOF := f.optional || !strict
+		switch kind := basicKind(f.typ); kind {
+		case "", "bytes":
+			g.printf("if err := codec.DecodeField(d2, &o.%s); err != nil {\n", f.path)
+			if handleEOF {
+				g.printf("if err != io.EOF {\nreturn err\n}\n")
+				g.genOnEOF(t, i)
+			} else {
+				g.printf("return err\n")
+			}
+			g.printf("}\n")
+		default:
+			var call, conv string
+			ts := g.typeString(f.typ)
+			switch {
+			case kind == "Bool":
+				call, conv = "codec.DecodeBool(d2)", "bool"
+			case kind == "String":
+				call, conv = "codec.DecodeString(d2)", "string"
+			case strings.HasPrefix(kind, "Uint"):
+				call, conv = fmt.Sprintf("codec.DecodeUint(d2, reflect.%s)", kind), "uint64"
+			default:
+				call, conv = fmt.Sprintf("codec.DecodeInt(d2, reflect.%s)", kind), "int64"
+			}
+			value := "v"
+			if ts != conv {
+				value = fmt.Sprintf("%s(v)", ts)
+			}
+			g.printf("if v, err := %s; err == nil {\no.%s = %s\n", call, f.path, value)
+			if handleEOF {
+				g.printf("} else if err == io.EOF {\n")
+				g.genOnEOF(t, i)
+			}
+			g.printf("} else {\nreturn err\n}\n")
+		}
+	}
+	g.printf("return nil\n}\n\n")
+}
+
+func (g *generator) genRegister(targets []*target) {
+	var names []string
+	for _, t := range targets {
+		if !t.hasOptional() {
+			names = append(names, t.name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	g.printf("func init() {\ncodec.RegisterGenerated(\n")
+	for _, name := range names {
+		g.printf("(*%s)(nil),\n", name)
+	}
+	g.printf(")\n}\n")
+}
+
+func isAnnotated(docs ...*ast.CommentGroup) bool {
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		for _, c := range doc.List {
+			if strings.TrimSpace(c.Text) == annotation {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func findTargets(pkg *pkgInfo) ([]*target, error) {
+	var targets []*target
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				docs := []*ast.CommentGroup{ts.Doc}
+				if len(gd.Specs) == 1 {
+					docs = append(docs, gd.Doc)
+				}
+				if !isAnnotated(docs...) {
+					continue
+				}
+				obj := pkg.Types.Scope().Lookup(ts.Name.Name)
+				st, ok := obj.Type().Underlying().(*types.Struct)
+				if !ok {
+					return nil, fmt.Errorf("%s is not a struct", ts.Name.Name)
+				}
+				t := &target{
+					name:   ts.Name.Name,
+					fields: collectFields(st, ""),
+				}
+				if err := verifyOptional(t); err != nil {
+					return nil, err
+				}
+				targets = append(targets, t)
+			}
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].name < targets[j].name
+	})
+	return targets, nil
+}
+
+func verifyOptional(t *target) error {
+	for i, f := range t.fields {
+		if !f.optional {
+			if i > 0 && t.fields[i-1].optional {
+				return fmt.Errorf("%s.%s follows optional field", t.name, f.path)
+			}
+			continue
+		}
+		if !isNilable(f.typ) {
+			return fmt.Errorf("%s.%s is optional but not nilable", t.name, f.path)
+		}
+	}
+	return nil
+}
+
+// verifyEmbedding checks the structs embedding the targets. They would
+// use the generated methods through the promotion unless they have their
+// own methods.
+func verifyEmbedding(pkg *pkgInfo, targets []*target) error {
+	names := make(map[string]bool)
+	for _, t := range targets {
+		names[t.name] = true
+	}
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || names[name] {
+			continue
+		}
+		st, ok := tn.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			if !f.Embedded() {
+				continue
+			}
+			ft := f.Type()
+			if p, ok := ft.(*types.Pointer); ok {
+				ft = p.Elem()
+			}
+			named, ok := ft.(*types.Named)
+			if !ok || named.Obj().Pkg() != pkg.Types || !names[named.Obj().Name()] {
+				continue
+			}
+			sel := types.NewMethodSet(types.NewPointer(tn.Type())).Lookup(pkg.Types, "RLPEncodeSelf")
+			if sel == nil || len(sel.Index()) != 1 {
+				return fmt.Errorf("%s embeds %s without its own RLPEncodeSelf",
+					name, named.Obj().Name())
+			}
+		}
+	}
+	return nil
+}
+
+func main() {
+	var output string
+	flag.StringVar(&output, "o", defaultFile, "output file name")
+	flag.Usage = printUsage
+	flag.Parse()
+	dir := "."
+	if flag.NArg() > 1 {
+		printUsage()
+		os.Exit(1)
+	} else if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	if err := run(dir, output); err != nil {
+		fmt.Fprintf(os.Stderr, "codecgen: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadPackage parses and type-checks the package in the directory
+// except the output file.
+func loadPackage(dir, output string) (*pkgInfo, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	pkg := &pkgInfo{
+		Name:    bp.Name,
+		PkgPath: bp.ImportPath,
+	}
+	for _, name := range bp.GoFiles {
+		if name == output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkg.Syntax = append(pkg.Syntax, f)
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		// the output file is not loaded, so methods may be missing.
+		Error: func(error) {},
+	}
+	pkg.Types, _ = conf.Check(bp.ImportPath, fset, pkg.Syntax, nil)
+	return pkg, nil
+}
+
+func run(dir, output string) error {
+	outPath, err := filepath.Abs(filepath.Join(dir, output))
+	if err != nil {
+		return err
+	}
+	pkg, err := loadPackage(dir, output)
+	if err != nil {
+		return err
+	}
+	targets, err := findTargets(pkg)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no struct annotated with %s", annotation)
+	}
+	if err := verifyEmbedding(pkg, targets); err != nil {
+		return err
+	}
+
+	g := &generator{
+		pkg: pkg,
+		imports: map[string]string{
+			"io":      "io",
+			"reflect": "reflect",
+			codecPkg:  "codec",
+		},
+	}
+	for _, t := range targets {
+		g.genEncode(t)
+		g.genDecode(t)
+	}
+	g.genRegister(targets)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by codecgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", pkg.Name)
+	var paths []string
+	for p := range g.imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		fmt.Fprintf(&out, "%q\n", p)
+	}
+	fmt.Fprintf(&out, ")\n\n")
+	out.Write(g.buf.Bytes())
+
+	src, err := imports.Process(outPath, out.Bytes(), nil)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, src, 0644)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
 	"github.com/icon-project/goloop/server/metric"
@@ -98,6 +99,7 @@ var lwCfg log.WriterConfig
 var importMode bool
 var importMaxHeight int64
 var importDataSource string
+var retention string
 
 func main() {
 	cmd := &cobra.Command{
@@ -140,6 +142,8 @@ func main() {
 	flag.IntVar(&cfg.MaxBlockTxBytes, "max_block_tx_bytes", 0, "Maximum size of transactions in a block")
 	flag.StringVar(&cfg.NodeCache, "node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	flag.BoolVar(&cfg.ValidateTxOnSend, "validate_tx_on_send", false, "Validate transaction on send")
+	flag.BoolVar(&cfg.Archive, "archive", false, "Keep index of states by heights for historical queries")
+	flag.StringVar(&retention, "retention", "", "Retention policy for pruning in JSON (ex. {\"state\":10000,\"receipts\":100000})")
 	cfg.ChildrenLimit = flag.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	cfg.NephewsLimit = flag.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	flag.StringVar(&cfg.LogLevel, "log_level", "debug", "Main log level")
@@ -357,6 +361,16 @@ func Execute(cmd *cobra.Command, args []string) {
 		cfg.NephewsLimit = nil
 	}
 
+	if len(retention) > 0 {
+		cfg.Retention = new(module.RetentionPolicy)
+		if err := json.Unmarshal([]byte(retention), cfg.Retention); err != nil {
+			log.Panicf("Invalid retention %s err=%+v", retention, err)
+		}
+		if err := cfg.Retention.Verify(); err != nil {
+			log.Panicf("Invalid retention %s err=%+v", retention, err)
+		}
+	}
+
 	if saveFile != "" {
 		f, err := os.OpenFile(saveFile,
 			os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
@@ -46,10 +46,12 @@ func main() {
 	cli.NewStatsCmd(rootCmd, rootVc)
 	cli.NewRpcCmd(rootCmd, nil)
 	cli.NewDebugCmd(rootCmd, nil)
+	cli.NewExploreCmd(rootCmd, nil)
 	rootCmd.AddCommand(
 		cli.NewGStorageCmd("gs"),
 		cli.NewGenesisCmd("gn"),
-		cli.NewKeystoreCmd("ks"))
+		cli.NewKeystoreCmd("ks"),
+		cli.NewBindCmd("bind"))
 
 	genMdCmd := cli.NewGenerateMarkdownCommand(rootCmd, nil)
 	genMdCmd.Hidden = true
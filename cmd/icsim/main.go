@@ -0,0 +1,129 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/cmd/cli"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/icsim"
+)
+
+func printReport(w io.Writer, report *icsim.Report) error {
+	for _, term := range report.Terms {
+		fmt.Fprintf(w, "Term seq=%d height=%d~%d revision=%d\n",
+			term.Sequence, term.StartHeight, term.EndHeight, term.Revision)
+
+		if len(term.Rewards) > 0 {
+			names := make([]string, 0, len(term.Rewards))
+			for name := range term.Rewards {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			table := uitable.New()
+			table.AddRow("Account", "Reward(I-Score)")
+			for _, name := range names {
+				table.AddRow(name, term.Rewards[name].String())
+			}
+			fmt.Fprintln(w, table)
+		}
+		if len(term.PReps) > 0 {
+			table := uitable.New()
+			table.AddRow("Name", "Owner", "Grade", "Status", "Jailed", "Power", "Bonded", "Delegated", "VFail/VTotal")
+			for _, p := range term.PReps {
+				table.AddRow(p.Name, p.Owner, p.Grade, p.Status, p.Jailed,
+					p.Power.String(), p.Bonded.String(), p.Delegated.String(),
+					fmt.Sprintf("%d/%d", p.VFail, p.VTotal))
+			}
+			fmt.Fprintln(w, table)
+		}
+		for _, p := range term.Penalties {
+			fmt.Fprintf(w, "Penalty height=%d owner=%s type=%s\n", p.Height, p.Owner, p.Type)
+		}
+		for _, s := range term.Slashes {
+			fmt.Fprintf(w, "Slashed height=%d owner=%s bonder=%s amount=%s\n",
+				s.Height, s.Owner, s.Bonder, s.Amount.String())
+		}
+		for _, vc := range term.Validators {
+			fmt.Fprintf(w, "Validators height=%d added=%v removed=%v\n", vc.Height, vc.Added, vc.Removed)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, f := range report.Failures {
+		fmt.Fprintf(w, "FAIL %s\n", f)
+	}
+	_, err := fmt.Fprintf(w, "blockHeight=%d revision=%d failures=%d\n",
+		report.BlockHeight, report.Revision, len(report.Failures))
+	return err
+}
+
+func main() {
+	var format string
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s [scenario]", os.Args[0]),
+		Short: "Run IISS scenario on the simulator",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !verbose {
+				log.GlobalLogger().SetLevel(log.WarnLevel)
+			}
+			sc, err := icsim.LoadScenario(args[0])
+			if err != nil {
+				return err
+			}
+			report, err := icsim.RunScenario(sc)
+			if report != nil {
+				switch format {
+				case "json":
+					if err := cli.JsonPrettyPrintln(os.Stdout, report); err != nil {
+						return err
+					}
+				case "table":
+					if err := printReport(os.Stdout, report); err != nil {
+						return err
+					}
+				default:
+					return errors.IllegalArgumentError.Errorf("UnknownFormat(%s)", format)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if report.Failed() {
+				return errors.InvalidStateError.Errorf("ExpectationFailed(count=%d)", len(report.Failures))
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&format, "format", "f", "json", "Output format (json, table)")
+	flags.BoolVarP(&verbose, "verbose", "v", false, "Print simulator logs")
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
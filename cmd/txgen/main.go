@@ -1,12 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/icon-project/goloop/client"
 	"github.com/icon-project/goloop/common/wallet"
 )
 
@@ -25,6 +29,8 @@ func main() {
 	var index, last int64
 	var waitTimeout int64
 	var noWaitResult bool
+	var scenarioFile string
+	var reportFile string
 
 	cmd := &cobra.Command{
 		Use: fmt.Sprintf("%s [urls]", os.Args[0]),
@@ -45,8 +51,23 @@ func main() {
 	flags.Int64VarP(&last, "last", "l", 0, "Last index value to be used for generating transaction")
 	flags.Int64Var(&waitTimeout, "wait", 0, "Wait for specified time (in ms) for each TX (enable to use sendAndWait)")
 	flags.BoolVar(&noWaitResult, "nowaitresult", false, "No wait for result for confirm in COIN transfer")
+	flags.StringVar(&scenarioFile, "scenario", "", "Scenario file for the mix of transactions and TPS stages")
+	flags.StringVar(&reportFile, "report", "", "File path to write the report of the scenario in JSON")
 
 	cmd.RunE = func(cmd *cobra.Command, urls []string) error {
+		var scenario *Scenario
+		if len(scenarioFile) > 0 {
+			var err error
+			if scenario, err = LoadScenario(scenarioFile); err != nil {
+				return err
+			}
+			if len(urls) == 0 {
+				urls = scenario.Endpoints
+			}
+			if scenario.Concurrent > 0 {
+				concurrent = scenario.Concurrent
+			}
+		}
 		if len(urls) == 0 {
 			urls = []string{"http://localhost:9080/api/v3"}
 		}
@@ -74,7 +95,9 @@ func main() {
 		}
 
 		var maker TransactionMaker
-		if len(scorePath) > 0 && len(params) > 0 {
+		if scenario != nil {
+			maker = scenario.NewMaker(nid, godWallet, walletCount)
+		} else if len(scorePath) > 0 && len(params) > 0 {
 			maker = &CallMaker{
 				NID:           nid,
 				SourcePath:    scorePath,
@@ -105,7 +128,30 @@ func main() {
 		}
 
 		ctx := NewContext(concurrent, int64(tps), maker, waitTimeout)
-		return ctx.Run(urls)
+		if scenario == nil {
+			return ctx.Run(urls)
+		}
+
+		recorder := NewRecorder()
+		ctx.SetScenario(scenario.Stages, recorder)
+		if err := ctx.Run(urls); err != nil {
+			return err
+		}
+		log.Println("[#] Collecting results of transactions")
+		c := &Client{client.NewJsonRpcClient(&http.Client{}, urls[0])}
+		report, err := recorder.Report(c, time.Duration(scenario.ResultTimeout))
+		if err != nil {
+			return err
+		}
+		report.Print(os.Stdout)
+		if len(reportFile) > 0 {
+			js, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(reportFile, js, 0644)
+		}
+		return nil
 	}
 
 	_ = cmd.Execute()
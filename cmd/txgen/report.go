@@ -0,0 +1,328 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	resultWorkers        = 16
+	defaultResultTimeout = 30 * time.Second
+)
+
+type txRecord struct {
+	kind     string
+	hash     string
+	sent     time.Time
+	accepted time.Time
+}
+
+// Recorder records submissions of the transactions for the report.
+type Recorder struct {
+	lock     sync.Mutex
+	start    time.Time
+	end      time.Time
+	records  []*txRecord
+	rejected map[string]int
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		rejected: make(map[string]int),
+	}
+}
+
+func kindOf(tx interface{}) string {
+	if kt, ok := tx.(*KindTx); ok {
+		return kt.Kind
+	}
+	return ""
+}
+
+func (r *Recorder) OnStart() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.start = time.Now()
+}
+
+func (r *Recorder) OnEnd() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.end = time.Now()
+}
+
+// OnAccept records the transaction accepted by the endpoint. The result is
+// the hash of the transaction (icx_sendTransaction) or the transaction
+// result (icx_sendTransactionAndWait).
+func (r *Recorder) OnAccept(tx interface{}, sent time.Time, result json.RawMessage) {
+	rec := &txRecord{kind: kindOf(tx), sent: sent, accepted: time.Now()}
+	if err := json.Unmarshal(result, &rec.hash); err != nil {
+		var txr TransactionResult
+		if err := json.Unmarshal(result, &txr); err != nil {
+			log.Printf("Fail to parse result=%s err=%+v", result, err)
+			return
+		}
+		rec.hash = txr.TxHash.String()
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.records = append(r.records, rec)
+}
+
+// OnReject records the transaction rejected by the endpoint.
+func (r *Recorder) OnReject(tx interface{}, err *jsonrpc.Error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rejected[fmt.Sprintf("%d(%s)", err.Code, err.Code)] += 1
+}
+
+// LatencyStats is statistics of latencies in milliseconds.
+type LatencyStats struct {
+	Count int     `json:"count"`
+	Avg   float64 `json:"avg"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+func durationInMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func NewLatencyStats(ds []time.Duration) LatencyStats {
+	var s LatencyStats
+	if len(ds) == 0 {
+		return s
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		return ds[i] < ds[j]
+	})
+	percentile := func(p int) float64 {
+		idx := (len(ds)*p+99)/100 - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return durationInMS(ds[idx])
+	}
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	s.Count = len(ds)
+	s.Avg = durationInMS(sum / time.Duration(len(ds)))
+	s.P50 = percentile(50)
+	s.P90 = percentile(90)
+	s.P99 = percentile(99)
+	s.Max = durationInMS(ds[len(ds)-1])
+	return s
+}
+
+type BlockStats struct {
+	Height   int64   `json:"height"`
+	Included int     `json:"included"`
+	Total    int     `json:"total"`
+	Interval float64 `json:"interval"`
+	TPS      float64 `json:"tps"`
+}
+
+type Report struct {
+	Duration   float64        `json:"duration"`
+	Submitted  map[string]int `json:"submitted"`
+	Rejected   map[string]int `json:"rejected"`
+	Included   int            `json:"included"`
+	Failed     map[string]int `json:"failed"`
+	Missing    int            `json:"missing"`
+	SendTPS    float64        `json:"sendTPS"`
+	Submission LatencyStats   `json:"submissionLatency"`
+	Inclusion  LatencyStats   `json:"inclusionLatency"`
+	Blocks     []BlockStats   `json:"blocks"`
+}
+
+func (r *Recorder) collectResults(c *Client, timeout time.Duration) []*TransactionResult {
+	results := make([]*TransactionResult, len(r.records))
+	deadline := time.Now().Add(timeout)
+	ch := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < resultWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				wait := time.Until(deadline)
+				if wait < 0 {
+					wait = 0
+				}
+				txr, err := c.GetTxResult(r.records[idx].hash, wait)
+				if err == nil {
+					results[idx] = txr
+				}
+			}
+		}()
+	}
+	for i := range r.records {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+	return results
+}
+
+func getBlock(c *Client, height int64) (*client.Block, error) {
+	blk := new(client.Block)
+	param := map[string]interface{}{
+		"height": jsonrpc.HexIntFromInt64(height),
+	}
+	if _, err := c.Do("icx_getBlockByHeight", param, blk); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
+// Report reads the results of the recorded transactions with the client, and
+// returns the report about them.
+func (r *Recorder) Report(c *Client, timeout time.Duration) (*Report, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if timeout <= 0 {
+		timeout = defaultResultTimeout
+	}
+	results := r.collectResults(c, timeout)
+
+	blocks := make(map[int64]*client.Block)
+	for _, txr := range results {
+		if txr == nil {
+			continue
+		}
+		for _, height := range []int64{txr.BlockHeight.Value, txr.BlockHeight.Value - 1} {
+			if _, ok := blocks[height]; ok || height < 0 {
+				continue
+			}
+			blk, err := getBlock(c, height)
+			if err != nil {
+				return nil, err
+			}
+			blocks[height] = blk
+		}
+	}
+	return r.buildReport(results, blocks), nil
+}
+
+func (r *Recorder) buildReport(results []*TransactionResult, blocks map[int64]*client.Block) *Report {
+	rp := &Report{
+		Duration:  r.end.Sub(r.start).Seconds(),
+		Submitted: make(map[string]int),
+		Rejected:  r.rejected,
+		Failed:    make(map[string]int),
+	}
+	if rp.Duration > 0 {
+		rp.SendTPS = float64(len(r.records)) / rp.Duration
+	}
+
+	var submissions, inclusions []time.Duration
+	included := make(map[int64]int)
+	for i, rec := range r.records {
+		rp.Submitted[rec.kind] += 1
+		submissions = append(submissions, rec.accepted.Sub(rec.sent))
+		txr := results[i]
+		if txr == nil {
+			rp.Missing += 1
+			continue
+		}
+		rp.Included += 1
+		if txr.Status.Value != 1 {
+			code := module.StatusUnknownFailure
+			if txr.Failure != nil {
+				code = module.Status(txr.Failure.Code.Value)
+			}
+			rp.Failed[fmt.Sprintf("%d(%s)", code, code)] += 1
+		}
+		height := txr.BlockHeight.Value
+		included[height] += 1
+		if blk, ok := blocks[height]; ok {
+			ts := time.UnixMicro(blk.Timestamp)
+			inclusions = append(inclusions, ts.Sub(rec.sent))
+		}
+	}
+	rp.Submission = NewLatencyStats(submissions)
+	rp.Inclusion = NewLatencyStats(inclusions)
+
+	for height, count := range included {
+		bs := BlockStats{Height: height, Included: count}
+		if blk, ok := blocks[height]; ok {
+			bs.Total = len(blk.NormalTransactions)
+			if prev, ok := blocks[height-1]; ok {
+				interval := time.Duration(blk.Timestamp-prev.Timestamp) * time.Microsecond
+				bs.Interval = interval.Seconds()
+				if interval > 0 {
+					bs.TPS = float64(bs.Total) / bs.Interval
+				}
+			}
+		}
+		rp.Blocks = append(rp.Blocks, bs)
+	}
+	sort.Slice(rp.Blocks, func(i, j int) bool {
+		return rp.Blocks[i].Height < rp.Blocks[j].Height
+	})
+	return rp
+}
+
+func printLatency(w io.Writer, name string, s *LatencyStats) {
+	fmt.Fprintf(w, "%-20s count=%d avg=%.1fms p50=%.1fms p90=%.1fms p99=%.1fms max=%.1fms\n",
+		name, s.Count, s.Avg, s.P50, s.P90, s.P99, s.Max)
+}
+
+func printCounts(w io.Writer, name string, counts map[string]int) {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "%s\n", name)
+	for _, k := range keys {
+		fmt.Fprintf(w, "  %-30s %d\n", k, counts[k])
+	}
+}
+
+// Print writes the report in human-readable form.
+func (rp *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "[#] Report\n")
+	fmt.Fprintf(w, "Duration             %.1fs (send_TPS=%.2f)\n", rp.Duration, rp.SendTPS)
+	printCounts(w, "Submitted", rp.Submitted)
+	printCounts(w, "Rejected", rp.Rejected)
+	fmt.Fprintf(w, "Included             %d (missing=%d)\n", rp.Included, rp.Missing)
+	printCounts(w, "Failed", rp.Failed)
+	printLatency(w, "Submission latency", &rp.Submission)
+	printLatency(w, "Inclusion latency", &rp.Inclusion)
+	fmt.Fprintf(w, "%-10s %8s %8s %10s %10s\n", "Height", "Included", "Total", "Interval", "TPS")
+	for _, b := range rp.Blocks {
+		fmt.Fprintf(w, "%-10d %8d %8d %9.3fs %10.2f\n",
+			b.Height, b.Included, b.Total, b.Interval, b.TPS)
+	}
+}
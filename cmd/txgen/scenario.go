@@ -0,0 +1,272 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// Duration is time.Duration in the form of "30s" or "1m30s" in JSON.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.IllegalArgumentError.Wrapf(err, "InvalidDuration(value=%s)", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Stage is a period of the scenario. TPS changes linearly from From to To
+// during the period. TPS is a shortcut for the same From and To.
+type Stage struct {
+	Duration Duration `json:"duration"`
+	TPS      int64    `json:"tps,omitempty"`
+	From     int64    `json:"from,omitempty"`
+	To       int64    `json:"to,omitempty"`
+}
+
+// MixEntry is a kind of transactions in the scenario. Type is one of
+// "coin", "token" and "call". Weight is relative to the other entries.
+type MixEntry struct {
+	Type          string            `json:"type"`
+	Weight        int               `json:"weight"`
+	Wallets       int               `json:"wallets,omitempty"`
+	Score         string            `json:"score,omitempty"`
+	Method        string            `json:"method,omitempty"`
+	Params        map[string]string `json:"params,omitempty"`
+	InstallParams map[string]string `json:"installParams,omitempty"`
+}
+
+// Scenario is the load test described in the scenario file.
+//
+//	{
+//	  "endpoints": [ "http://node1:9080/api/v3", "http://node2:9080/api/v3" ],
+//	  "concurrent": 4,
+//	  "mix": [
+//	    { "type": "coin", "weight": 3 },
+//	    { "type": "token", "weight": 1, "score": "./token" }
+//	  ],
+//	  "stages": [
+//	    { "duration": "1m", "from": 100, "to": 1000 },
+//	    { "duration": "5m", "tps": 1000 },
+//	    { "duration": "1m", "from": 1000, "to": 100 }
+//	  ],
+//	  "resultTimeout": "1m"
+//	}
+type Scenario struct {
+	Endpoints     []string   `json:"endpoints,omitempty"`
+	Concurrent    int        `json:"concurrent,omitempty"`
+	Mix           []MixEntry `json:"mix"`
+	Stages        []Stage    `json:"stages"`
+	ResultTimeout Duration   `json:"resultTimeout,omitempty"`
+}
+
+func LoadScenario(file string) (*Scenario, error) {
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	s := new(Scenario)
+	if err := json.Unmarshal(bs, s); err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidScenario(file=%s)", file)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Scenario) Validate() error {
+	if len(s.Mix) == 0 {
+		return errors.IllegalArgumentError.New("NoTransactionMix")
+	}
+	for i, e := range s.Mix {
+		if e.Weight <= 0 {
+			return errors.IllegalArgumentError.Errorf("InvalidWeight(idx=%d,weight=%d)", i, e.Weight)
+		}
+		switch e.Type {
+		case "coin":
+		case "token", "call":
+			if len(e.Score) == 0 {
+				return errors.IllegalArgumentError.Errorf("NoScore(idx=%d,type=%s)", i, e.Type)
+			}
+		default:
+			return errors.IllegalArgumentError.Errorf("UnknownType(idx=%d,type=%s)", i, e.Type)
+		}
+	}
+	if len(s.Stages) == 0 {
+		return errors.IllegalArgumentError.New("NoStages")
+	}
+	for i := range s.Stages {
+		st := &s.Stages[i]
+		if st.Duration <= 0 {
+			return errors.IllegalArgumentError.Errorf("InvalidDuration(idx=%d)", i)
+		}
+		if st.TPS != 0 {
+			st.From, st.To = st.TPS, st.TPS
+		}
+		if st.From < 0 || st.To < 0 || (st.From == 0 && st.To == 0) {
+			return errors.IllegalArgumentError.Errorf("InvalidTPS(idx=%d,from=%d,to=%d)", i, st.From, st.To)
+		}
+	}
+	return nil
+}
+
+// TPSAt returns target TPS at the elapsed time from the start of the stages.
+// It returns false after the end of the stages.
+func TPSAt(stages []Stage, elapsed time.Duration) (int64, bool) {
+	for _, st := range stages {
+		d := time.Duration(st.Duration)
+		if elapsed < d {
+			tps := st.From + (st.To-st.From)*int64(elapsed)/int64(d)
+			if tps < 1 {
+				tps = 1
+			}
+			return tps, true
+		}
+		elapsed -= d
+	}
+	return 0, false
+}
+
+// NewMaker returns the transaction maker for the mix of the scenario.
+func (s *Scenario) NewMaker(nid int64, god module.Wallet, wallets int) TransactionMaker {
+	m := &WeightedMaker{}
+	for _, e := range s.Mix {
+		count := e.Wallets
+		if count == 0 {
+			count = wallets
+		}
+		var maker TransactionMaker
+		switch e.Type {
+		case "coin":
+			maker = &CoinTransferMaker{
+				NID:         nid,
+				WalletCount: count,
+				GodWallet:   god,
+			}
+		case "token":
+			method := e.Method
+			if len(method) == 0 {
+				method = "transfer"
+			}
+			maker = &TokenTransferMaker{
+				NID:         nid,
+				WalletCount: count,
+				SourcePath:  e.Score,
+				Method:      method,
+				GOD:         god,
+			}
+		case "call":
+			installParams := e.InstallParams
+			if installParams == nil {
+				installParams = make(map[string]string)
+			}
+			maker = &CallMaker{
+				NID:           nid,
+				SourcePath:    e.Score,
+				InstallParams: installParams,
+				Method:        e.Method,
+				CallParams:    e.Params,
+				GOD:           god,
+			}
+		}
+		m.Add(e.Type, e.Weight, maker)
+	}
+	return m
+}
+
+type weightedEntry struct {
+	kind   string
+	weight int
+	maker  TransactionMaker
+}
+
+// WeightedMaker makes transactions with the makers chosen randomly by
+// their weights.
+type WeightedMaker struct {
+	entries []weightedEntry
+	total   int
+}
+
+// KindTx is the transaction made by WeightedMaker. It's marshaled as the
+// transaction itself.
+type KindTx struct {
+	Kind  string
+	Tx    interface{}
+	maker TransactionMaker
+}
+
+func (t *KindTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Tx)
+}
+
+func (m *WeightedMaker) Add(kind string, weight int, maker TransactionMaker) {
+	m.entries = append(m.entries, weightedEntry{kind, weight, maker})
+	m.total += weight
+}
+
+func (m *WeightedMaker) Prepare(client *Client) error {
+	for _, e := range m.entries {
+		if err := e.maker.Prepare(client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *WeightedMaker) pick() *weightedEntry {
+	n := rand.Intn(m.total)
+	for i := range m.entries {
+		if n < m.entries[i].weight {
+			return &m.entries[i]
+		}
+		n -= m.entries[i].weight
+	}
+	return &m.entries[len(m.entries)-1]
+}
+
+func (m *WeightedMaker) MakeOne() (interface{}, error) {
+	e := m.pick()
+	tx, err := e.maker.MakeOne()
+	if err != nil {
+		return nil, err
+	}
+	return &KindTx{Kind: e.kind, Tx: tx, maker: e.maker}, nil
+}
+
+func (m *WeightedMaker) Dispose(tx interface{}) {
+	if kt, ok := tx.(*KindTx); ok {
+		kt.maker.Dispose(kt.Tx)
+	}
+}
@@ -146,7 +146,10 @@ type Context struct {
 	currentTxCount int64
 	resetCount     int64
 
-	maker TransactionMaker
+	maker    TransactionMaker
+	stages   []Stage
+	senders  int
+	recorder *Recorder
 }
 
 func NewContext(concurrent int, tps int64, maker TransactionMaker, timeout int64) *Context {
@@ -158,6 +161,29 @@ func NewContext(concurrent int, tps int64, maker TransactionMaker, timeout int64
 	}
 }
 
+// SetScenario makes the context follow TPS of the stages, and record the
+// transactions to the recorder.
+func (ctx *Context) SetScenario(stages []Stage, recorder *Recorder) {
+	ctx.stages = stages
+	ctx.recorder = recorder
+	if tps, ok := TPSAt(stages, 0); ok {
+		ctx.tps = tps
+	}
+}
+
+// delayAt returns the delay between the requests of each sender at the time.
+// It returns false after the end of the stages.
+func (ctx *Context) delayAt(now time.Time) (time.Duration, bool) {
+	if len(ctx.stages) == 0 {
+		return ctx.delay, true
+	}
+	tps, ok := TPSAt(ctx.stages, now.Sub(ctx.firstTime))
+	if !ok {
+		return 0, false
+	}
+	return (time.Second * time.Duration(ctx.senders)) / time.Duration(tps), true
+}
+
 func (ctx *Context) sendRequests(wg *sync.WaitGroup, client *Client) {
 	method := "icx_sendTransaction"
 	if ctx.timeout > 0 {
@@ -167,11 +193,15 @@ func (ctx *Context) sendRequests(wg *sync.WaitGroup, client *Client) {
 	nextTs := time.Now()
 	defer wg.Done()
 	for {
+		delay, ok := ctx.delayAt(nextTs)
+		if !ok {
+			return
+		}
 		current := time.Now()
 		if nextTs.After(current) {
 			time.Sleep(nextTs.Sub(current))
 		} else {
-			if current.Sub(nextTs) > ctx.delay*2 {
+			if current.Sub(nextTs) > delay*2 {
 				nextTs = current
 			}
 		}
@@ -184,18 +214,24 @@ func (ctx *Context) sendRequests(wg *sync.WaitGroup, client *Client) {
 			return
 		}
 
+		sent := time.Now()
 		for {
 			r, err := client.Do(method, tx, nil)
 			if err != nil {
 				if re, ok := err.(*jsonrpc.Error); ok {
 					if re.Code == jsonrpc.ErrorCodeTxPoolOverflow {
-						time.Sleep(ctx.delay / 3)
+						time.Sleep(delay / 3)
 						continue
 					}
 					if re.Code == jsonrpc.ErrorCodeTimeout || re.Code == jsonrpc.ErrorCodeSystemTimeout {
-						time.Sleep(ctx.delay / 3)
+						time.Sleep(delay / 3)
 						continue
 					}
+					if ctx.recorder != nil {
+						ctx.recorder.OnReject(tx, re)
+						ctx.maker.Dispose(tx)
+						break
+					}
 					js, _ := json.MarshalIndent(tx, "", "  ")
 					log.Panicf("Get ERROR on %s Code=%d Msg=%s TX=%s",
 						method, r.Error.Code, r.Error.Message, js)
@@ -211,12 +247,15 @@ func (ctx *Context) sendRequests(wg *sync.WaitGroup, client *Client) {
 				js, _ := json.MarshalIndent(tx, "", "  ")
 				log.Panicf("Fail to send TX err=%+v tx=%s", err, js)
 			} else {
+				if ctx.recorder != nil {
+					ctx.recorder.OnAccept(tx, sent, r.Result)
+				}
 				ctx.maker.Dispose(tx)
 			}
 			break
 		}
 
-		nextTs = nextTs.Add(ctx.delay)
+		nextTs = nextTs.Add(delay)
 	}
 }
 
@@ -246,7 +285,8 @@ func (ctx *Context) OnRequest() {
 }
 
 func (ctx *Context) Run(urls []string) error {
-	ctx.delay = (time.Second * time.Duration(ctx.concurrent*len(urls))) /
+	ctx.senders = ctx.concurrent * len(urls)
+	ctx.delay = (time.Second * time.Duration(ctx.senders)) /
 		time.Duration(ctx.tps)
 
 	headers := map[string]string{}
@@ -267,6 +307,9 @@ func (ctx *Context) Run(urls []string) error {
 
 	ctx.lastTime = time.Now()
 	ctx.firstTime = ctx.lastTime
+	if ctx.recorder != nil {
+		ctx.recorder.OnStart()
+	}
 
 	var wg sync.WaitGroup
 	for _, url := range urls {
@@ -280,6 +323,9 @@ func (ctx *Context) Run(urls []string) error {
 	}
 	wg.Wait()
 	log.Println("\n[#] End of transaction generation")
+	if ctx.recorder != nil {
+		ctx.recorder.OnEnd()
+	}
 	return nil
 }
 
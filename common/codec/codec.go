@@ -118,8 +118,9 @@ var (
 )
 
 type encoderImpl struct {
-	real  Writer
-	child *encoderImpl
+	real       Writer
+	child      *encoderImpl
+	reflective bool
 }
 
 func (e *encoderImpl) flush() error {
@@ -156,7 +157,7 @@ func (e *encoderImpl) encodeList() (*encoderImpl, error) {
 	if err != nil {
 		return nil, err
 	}
-	e.child = &encoderImpl{real: writer}
+	e.child = &encoderImpl{real: writer, reflective: e.reflective}
 	return e.child, nil
 }
 
@@ -165,11 +166,14 @@ func (e *encoderImpl) encodeMap() (*encoderImpl, error) {
 	if err != nil {
 		return nil, err
 	}
-	e.child = &encoderImpl{real: writer}
+	e.child = &encoderImpl{real: writer, reflective: e.reflective}
 	return e.child, nil
 }
 
 func (e *encoderImpl) tryCustom(v reflect.Value) (bool, error) {
+	if e.reflective && isGenerated(v.Type()) {
+		return false, nil
+	}
 	if v.CanInterface() {
 		switch value := v.Interface().(type) {
 		case EncodeSelfer:
@@ -386,8 +390,9 @@ func (e *encoderImpl) EncodeListOf(objs ...interface{}) error {
 }
 
 type decoderImpl struct {
-	real  Reader
-	child Reader
+	real       Reader
+	child      Reader
+	reflective bool
 }
 
 func (d *decoderImpl) decodeList() (*decoderImpl, error) {
@@ -396,7 +401,7 @@ func (d *decoderImpl) decodeList() (*decoderImpl, error) {
 		return nil, err
 	}
 	d.child = reader
-	return &decoderImpl{real: reader}, nil
+	return &decoderImpl{real: reader, reflective: d.reflective}, nil
 }
 
 func (d *decoderImpl) decodeMap() (*decoderImpl, error) {
@@ -405,7 +410,7 @@ func (d *decoderImpl) decodeMap() (*decoderImpl, error) {
 		return nil, err
 	}
 	d.child = reader
-	return &decoderImpl{real: reader}, nil
+	return &decoderImpl{real: reader, reflective: d.reflective}, nil
 }
 
 func (d *decoderImpl) flush() error {
@@ -506,6 +511,9 @@ func (d *decoderImpl) tryCustom(v reflect.Value) (consume bool, err error) {
 			err = cerrors.Wrapf(ErrPanicInCustom, "panic in custom decoder: %v", r)
 		}
 	}()
+	if d.reflective && isGenerated(v.Type()) {
+		return false, nil
+	}
 	if v.CanInterface() {
 		switch value := v.Interface().(type) {
 		case DecodeSelfer:
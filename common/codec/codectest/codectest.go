@@ -0,0 +1,190 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package codectest verifies the encoders generated by cmd/codecgen
+// against the reflection of common/codec.
+package codectest
+
+import (
+	"encoding"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+)
+
+var Codecs = []codec.Codec{codec.RLP, codec.MP}
+
+const maxDepth = 4
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+func hasCustom(v reflect.Value) bool {
+	if !v.CanAddr() {
+		return false
+	}
+	p := v.Addr().Interface()
+	if codec.IsGenerated(p) {
+		return false
+	}
+	switch p.(type) {
+	case codec.EncodeSelfer, codec.WriteSelfer, codec.Marshaler,
+		encoding.BinaryMarshaler:
+		return true
+	}
+	return false
+}
+
+func randomInt(r *rand.Rand, bits int) int64 {
+	if r.Intn(4) == 0 {
+		return int64(r.Intn(3) - 1)
+	}
+	return int64(r.Uint64()) >> (64 - bits)
+}
+
+func randomUint(r *rand.Rand, bits int) uint64 {
+	if r.Intn(4) == 0 {
+		return uint64(r.Intn(2))
+	}
+	return r.Uint64() >> (64 - bits)
+}
+
+func randomBytes(r *rand.Rand) []byte {
+	bs := make([]byte, r.Intn(40))
+	r.Read(bs)
+	return bs
+}
+
+func fill(r *rand.Rand, v reflect.Value, depth int) {
+	if !v.CanSet() {
+		return
+	}
+	if v.Type() == bigIntType {
+		v.Set(reflect.ValueOf(*new(big.Int).SetInt64(randomInt(r, 64))))
+		return
+	}
+	if depth > 0 && hasCustom(v) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(randomInt(r, v.Type().Bits()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(randomUint(r, v.Type().Bits()))
+	case reflect.String:
+		v.SetString(string(randomBytes(r)))
+	case reflect.Slice:
+		if r.Intn(4) == 0 || depth > maxDepth {
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(randomBytes(r))
+			return
+		}
+		n := r.Intn(4)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			fill(r, v.Index(i), depth+1)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fill(r, v.Index(i), depth+1)
+		}
+	case reflect.Ptr:
+		if r.Intn(4) == 0 || depth > maxDepth {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(r, v.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fill(r, v.Field(i), depth+1)
+		}
+	}
+}
+
+// Fill sets random values to the fields of the object. Fields of the
+// types having custom encoders other than generated ones, maps and
+// interfaces are left as they are.
+func Fill(r *rand.Rand, obj interface{}) {
+	fill(r, reflect.ValueOf(obj).Elem(), 0)
+}
+
+func newOf(obj interface{}) interface{} {
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface()
+}
+
+// AssertEquivalent asserts that the generated encoder of the object
+// produces the same bytes as the reflection for each codec, and the
+// generated decoder produces the same object as the reflection.
+func AssertEquivalent(t testing.TB, obj interface{}) bool {
+	ok := true
+	for _, c := range Codecs {
+		rc := codec.WithoutGenerated(c)
+		bs, err := c.MarshalToBytes(obj)
+		bs2, err2 := rc.MarshalToBytes(obj)
+		ok = assert.Equal(t, err2 == nil, err == nil, "%s: %T error=%v", c.Name(), obj, err) && ok
+		ok = assert.Equal(t, bs2, bs, "%s: %T encoding", c.Name(), obj) && ok
+		if err != nil {
+			continue
+		}
+		ok = AssertDecodeEquivalent(t, obj, bs, c) && ok
+	}
+	return ok
+}
+
+// AssertDecodeEquivalent asserts that the generated decoder and the
+// reflection agree on the bytes, which may be invalid.
+func AssertDecodeEquivalent(t testing.TB, obj interface{}, bs []byte, c codec.Codec) bool {
+	rc := codec.WithoutGenerated(c)
+	o1, o2 := newOf(obj), newOf(obj)
+	_, err := c.UnmarshalFromBytes(bs, o1)
+	_, err2 := rc.UnmarshalFromBytes(bs, o2)
+	ok := assert.Equal(t, err2 == nil, err == nil, "%s: %T decode error=%v error2=%v", c.Name(), obj, err, err2)
+	if err == nil && err2 == nil {
+		ok = assert.Equal(t, o2, o1, "%s: %T decoded", c.Name(), obj) && ok
+	}
+	return ok
+}
+
+// Fuzz verifies the objects created by the functions with random values
+// and random bytes.
+func Fuzz(f *testing.F, objs ...func() interface{}) {
+	r := rand.New(rand.NewSource(0))
+	for i := range objs {
+		for j := 0; j < 8; j++ {
+			obj := objs[i]()
+			Fill(r, obj)
+			bs, _ := codec.RLP.MarshalToBytes(obj)
+			f.Add(uint8(i), r.Int63(), bs)
+			if len(bs) > 0 {
+				f.Add(uint8(i), r.Int63(), bs[:r.Intn(len(bs))])
+			}
+		}
+	}
+	f.Fuzz(func(t *testing.T, idx uint8, seed int64, bs []byte) {
+		obj := objs[int(idx)%len(objs)]()
+		Fill(rand.New(rand.NewSource(seed)), obj)
+		AssertEquivalent(t, obj)
+		AssertDecodeEquivalent(t, obj, bs, codec.RLP)
+	})
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Helpers for the encoders generated by cmd/codecgen. They write and read
+// values exactly the same way as the reflection does, but they don't need
+// reflect.Value for the basic types. Decoding helpers don't close the
+// previous list or map, which is left open only on failure, as the
+// reflection does for the fields.
+
+type typedWriter interface {
+	writeBool(v bool) error
+	writeUint(v uint64) error
+	writeInt(v int64) error
+	writeString(v string) error
+}
+
+type typedReader interface {
+	readUint(kind reflect.Kind) (uint64, error)
+	readInt(kind reflect.Kind) (int64, error)
+	readString() (string, error)
+}
+
+func typedWriterOf(e Encoder) (typedWriter, error) {
+	if ei, ok := e.(*encoderImpl); ok {
+		if w, ok := ei.real.(typedWriter); ok {
+			if err := ei.flush(); err != nil {
+				return nil, err
+			}
+			return w, nil
+		}
+	}
+	return nil, nil
+}
+
+func typedReaderOf(d Decoder) typedReader {
+	if di, ok := d.(*decoderImpl); ok {
+		if r, ok := di.real.(typedReader); ok {
+			return r
+		}
+	}
+	return nil
+}
+
+func EncodeBool(e Encoder, v bool) error {
+	if w, err := typedWriterOf(e); w != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		return w.writeBool(v)
+	}
+	return e.Encode(v)
+}
+
+func EncodeUint(e Encoder, v uint64) error {
+	if w, err := typedWriterOf(e); w != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		return w.writeUint(v)
+	}
+	return e.Encode(v)
+}
+
+func EncodeInt(e Encoder, v int64) error {
+	if w, err := typedWriterOf(e); w != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		return w.writeInt(v)
+	}
+	return e.Encode(v)
+}
+
+func EncodeString(e Encoder, v string) error {
+	if w, err := typedWriterOf(e); w != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		return w.writeString(v)
+	}
+	return e.Encode(v)
+}
+
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:   reflect.TypeOf(false),
+	reflect.Uint:   reflect.TypeOf(uint(0)),
+	reflect.Uint8:  reflect.TypeOf(uint8(0)),
+	reflect.Uint16: reflect.TypeOf(uint16(0)),
+	reflect.Uint32: reflect.TypeOf(uint32(0)),
+	reflect.Uint64: reflect.TypeOf(uint64(0)),
+	reflect.Int:    reflect.TypeOf(int(0)),
+	reflect.Int8:   reflect.TypeOf(int8(0)),
+	reflect.Int16:  reflect.TypeOf(int16(0)),
+	reflect.Int32:  reflect.TypeOf(int32(0)),
+	reflect.Int64:  reflect.TypeOf(int64(0)),
+	reflect.String: reflect.TypeOf(""),
+}
+
+func decodeBasic(d Decoder, kind reflect.Kind) (reflect.Value, error) {
+	v := reflect.New(basicTypes[kind])
+	if err := DecodeField(d, v.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return v.Elem(), nil
+}
+
+// DecodeStruct returns the decoder for the fields of the struct.
+func DecodeStruct(d Decoder) (Decoder, error) {
+	if di, ok := d.(*decoderImpl); ok {
+		return di.decodeList()
+	}
+	return d.DecodeList()
+}
+
+// DecodeField decodes the field of the struct into the object.
+func DecodeField(d Decoder, o interface{}) error {
+	if di, ok := d.(*decoderImpl); ok {
+		return di.decode(o)
+	}
+	return d.Decode(o)
+}
+
+func DecodeBool(d Decoder) (bool, error) {
+	if r := typedReaderOf(d); r != nil {
+		v, err := r.readUint(reflect.Bool)
+		return v == 1, err
+	}
+	v, err := decodeBasic(d, reflect.Bool)
+	if err != nil {
+		return false, err
+	}
+	return v.Bool(), nil
+}
+
+// DecodeUint decodes unsigned integer for the kind. It returns an error if
+// the value overflows the kind.
+func DecodeUint(d Decoder, kind reflect.Kind) (uint64, error) {
+	if r := typedReaderOf(d); r != nil {
+		return r.readUint(kind)
+	}
+	v, err := decodeBasic(d, kind)
+	if err != nil {
+		return 0, err
+	}
+	return v.Uint(), nil
+}
+
+// DecodeInt decodes signed integer for the kind. It returns an error if
+// the value overflows the kind.
+func DecodeInt(d Decoder, kind reflect.Kind) (int64, error) {
+	if r := typedReaderOf(d); r != nil {
+		return r.readInt(kind)
+	}
+	v, err := decodeBasic(d, kind)
+	if err != nil {
+		return 0, err
+	}
+	return v.Int(), nil
+}
+
+func DecodeString(d Decoder) (string, error) {
+	if r := typedReaderOf(d); r != nil {
+		return r.readString()
+	}
+	v, err := decodeBasic(d, reflect.String)
+	if err != nil {
+		return "", err
+	}
+	return v.String(), nil
+}
+
+var generatedTypes sync.Map
+
+// RegisterGenerated registers the types of the objects having generated
+// RLPEncodeSelf and RLPDecodeSelf, which are equivalent to the reflection.
+// Codec returned by WithoutGenerated ignores them for verification.
+func RegisterGenerated(objs ...interface{}) {
+	for _, obj := range objs {
+		generatedTypes.Store(reflect.TypeOf(obj), true)
+	}
+}
+
+func isGenerated(t reflect.Type) bool {
+	_, ok := generatedTypes.Load(t)
+	return ok
+}
+
+// IsGenerated returns whether the type of the object is registered by
+// RegisterGenerated.
+func IsGenerated(obj interface{}) bool {
+	return isGenerated(reflect.TypeOf(obj))
+}
+
+type reflectiveCodec struct {
+	codecImpl
+}
+
+func (c *reflectiveCodec) NewDecoder(r io.Reader) DecodeAndCloser {
+	d := c.codecImpl.NewDecoder(r)
+	if di, ok := d.(*decoderImpl); ok {
+		di.reflective = true
+	}
+	return d
+}
+
+func (c *reflectiveCodec) NewEncoder(w io.Writer) EncodeAndCloser {
+	e := c.codecImpl.NewEncoder(w)
+	if ei, ok := e.(*encoderImpl); ok {
+		ei.reflective = true
+	}
+	return e
+}
+
+// WithoutGenerated returns the codec using the reflection instead of the
+// generated encoders registered by RegisterGenerated. It's used to verify
+// the generated encoders.
+func WithoutGenerated(c Codec) Codec {
+	if bw, ok := c.(*bytesWrapper); ok {
+		return bytesWrapperFrom(&reflectiveCodec{bw.codecImpl})
+	}
+	return c
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeWith(t *testing.T, c Codec, f func(e Encoder) error) []byte {
+	var bs []byte
+	e := c.NewEncoderBytes(&bs)
+	assert.NoError(t, f(e))
+	assert.NoError(t, e.Close())
+	return bs
+}
+
+func TestGenerated_Encode(t *testing.T) {
+	values := []interface{}{
+		true, false,
+		int8(math.MinInt8), int16(-1), int32(math.MaxInt32), int64(math.MinInt64), 0, 1024,
+		uint8(math.MaxUint8), uint16(0), uint32(7), uint64(math.MaxUint64), uint(1),
+		"", "test string",
+	}
+	for _, c := range codecsToTest {
+		for _, v := range values {
+			expected := encodeWith(t, c, func(e Encoder) error {
+				return e.EncodeListOf(v, v)
+			})
+			bs := encodeWith(t, c, func(e Encoder) error {
+				e2, err := e.EncodeList()
+				if err != nil {
+					return err
+				}
+				for i := 0; i < 2; i++ {
+					rv := reflect.ValueOf(v)
+					switch rv.Kind() {
+					case reflect.Bool:
+						err = EncodeBool(e2, rv.Bool())
+					case reflect.String:
+						err = EncodeString(e2, rv.String())
+					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+						err = EncodeInt(e2, rv.Int())
+					default:
+						err = EncodeUint(e2, rv.Uint())
+					}
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			assert.Equal(t, expected, bs, "%s: %T(%v)", c.Name(), v, v)
+		}
+	}
+}
+
+func TestGenerated_Decode(t *testing.T) {
+	values := []interface{}{
+		true, int64(-1), int64(math.MaxInt16 + 1), uint64(2), uint64(math.MaxUint32 + 1),
+		"string",
+	}
+	kinds := []reflect.Kind{
+		reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+	}
+	for _, c := range codecsToTest {
+		for _, v := range values {
+			bs := c.MustMarshalToBytes(v)
+			for _, kind := range kinds {
+				expected := reflect.New(basicTypes[kind])
+				_, err := c.UnmarshalFromBytes(bs, expected.Interface())
+
+				d := c.NewDecoder(bytes.NewReader(bs))
+				var value interface{}
+				var err2 error
+				switch kind {
+				case reflect.Bool:
+					value, err2 = DecodeBool(d)
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+					var iv int64
+					iv, err2 = DecodeInt(d, kind)
+					value = reflect.ValueOf(iv).Convert(basicTypes[kind]).Interface()
+				default:
+					var uv uint64
+					uv, err2 = DecodeUint(d, kind)
+					value = reflect.ValueOf(uv).Convert(basicTypes[kind]).Interface()
+				}
+				if assert.Equal(t, err == nil, err2 == nil, "%s: %v to %s err=%v err2=%v", c.Name(), v, kind, err, err2) && err == nil {
+					assert.Equal(t, expected.Elem().Interface(), value, "%s: %v to %s", c.Name(), v, kind)
+				}
+			}
+			var s string
+			_, err := c.UnmarshalFromBytes(bs, &s)
+			s2, err2 := DecodeString(c.NewDecoder(bytes.NewReader(bs)))
+			if assert.Equal(t, err == nil, err2 == nil) && err == nil {
+				assert.Equal(t, s, s2)
+			}
+		}
+	}
+}
+
+type generatedForTest struct {
+	Value int64
+}
+
+func (o *generatedForTest) RLPEncodeSelf(e Encoder) error {
+	return EncodeInt(e, o.Value)
+}
+
+func (o *generatedForTest) RLPDecodeSelf(d Decoder) error {
+	v, err := DecodeInt(d, reflect.Int64)
+	o.Value = v
+	return err
+}
+
+func TestGenerated_WithoutGenerated(t *testing.T) {
+	obj := &generatedForTest{Value: 7}
+	for _, c := range codecsToTest {
+		rc := WithoutGenerated(c)
+		assert.Equal(t, c.MustMarshalToBytes(obj), rc.MustMarshalToBytes(obj))
+
+		RegisterGenerated((*generatedForTest)(nil))
+		assert.True(t, IsGenerated(obj))
+		bs := c.MustMarshalToBytes(obj)
+		bs2 := rc.MustMarshalToBytes([]*generatedForTest{obj})
+		assert.Equal(t, c.MustMarshalToBytes(int64(7)), bs)
+		assert.Equal(t, c.MustMarshalToBytes([][]int64{{7}}), bs2)
+
+		var objs []*generatedForTest
+		rc.MustUnmarshalFromBytes(bs2, &objs)
+		assert.Equal(t, []*generatedForTest{obj}, objs)
+		generatedTypes.Delete(reflect.TypeOf(obj))
+	}
+}
@@ -211,6 +211,26 @@ func (w *mpWriter) WriteValue(v reflect.Value) error {
 	return w.real.EncodeValue(v)
 }
 
+func (w *mpWriter) writeBool(v bool) error {
+	w.countN(1)
+	return w.real.EncodeBool(v)
+}
+
+func (w *mpWriter) writeUint(v uint64) error {
+	w.countN(1)
+	return w.real.EncodeUint(v)
+}
+
+func (w *mpWriter) writeInt(v int64) error {
+	w.countN(1)
+	return w.real.EncodeInt(v)
+}
+
+func (w *mpWriter) writeString(v string) error {
+	w.countN(1)
+	return w.real.EncodeString(v)
+}
+
 func (w *mpWriter) WriteNull() error {
 	w.countN(1)
 	return w.real.EncodeNil()
@@ -226,77 +226,100 @@ func (r *rlpReader) readBytes() ([]byte, error) {
 	}
 }
 
-func (r *rlpReader) readUintValue(v reflect.Value) error {
+func (r *rlpReader) readUint(kind reflect.Kind) (uint64, error) {
 	bs, err := r.readBytes()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	value, ok := intconv.SafeBytesToUint64(bs)
 	if !ok {
-		return cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x)", bs)
+		return 0, cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x)", bs)
 	}
-	switch v.Kind() {
+	switch kind {
 	case reflect.Bool:
-		if value == 0 {
-			v.SetBool(false)
-		} else if value == 1 {
-			v.SetBool(true)
-		} else {
-			return cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=bool)", bs)
+		if value != 0 && value != 1 {
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=bool)", bs)
 		}
-		return nil
 	case reflect.Uint:
 		if value != uint64(uint(value)) {
-			return cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=uint)", bs)
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=uint)", bs)
 		}
 	case reflect.Uint8:
 		if value != uint64(uint8(value)) {
-			return cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=uint8)", bs)
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=uint8)", bs)
 		}
 	case reflect.Uint16:
 		if value != uint64(uint16(value)) {
-			return cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=uint16)", bs)
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=uint16)", bs)
 		}
 	case reflect.Uint32:
 		if value != uint64(uint32(value)) {
-			return cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=uint32)", bs)
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "UintOverflow(bs=%#x,type=uint32)", bs)
 		}
 	}
-	v.SetUint(value)
+	return value, nil
+}
+
+func (r *rlpReader) readUintValue(v reflect.Value) error {
+	value, err := r.readUint(v.Kind())
+	if err != nil {
+		return err
+	}
+	if v.Kind() == reflect.Bool {
+		v.SetBool(value == 1)
+	} else {
+		v.SetUint(value)
+	}
 	return nil
 }
 
-func (r *rlpReader) readIntValue(v reflect.Value) error {
+func (r *rlpReader) readInt(kind reflect.Kind) (int64, error) {
 	bs, err := r.readBytes()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	value, ok := intconv.SafeBytesToInt64(bs)
 	if !ok {
-		return cerrors.Wrapf(ErrInvalidFormat, "Int64Overflow(bs=%#x)", bs)
+		return 0, cerrors.Wrapf(ErrInvalidFormat, "Int64Overflow(bs=%#x)", bs)
 	}
-	switch v.Kind() {
+	switch kind {
 	case reflect.Int:
 		if value != int64(int(value)) {
-			return cerrors.Wrapf(ErrInvalidFormat, "IntOverflow(bs=%#x,type=int)", bs)
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "IntOverflow(bs=%#x,type=int)", bs)
 		}
 	case reflect.Int8:
 		if value != int64(int8(value)) {
-			return cerrors.Wrapf(ErrInvalidFormat, "IntOverflow(bs=%#x,type=int8)", bs)
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "IntOverflow(bs=%#x,type=int8)", bs)
 		}
 	case reflect.Int16:
 		if value != int64(int16(value)) {
-			return cerrors.Wrapf(ErrInvalidFormat, "IntOverflow(bs=%#x,type=int16)", bs)
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "IntOverflow(bs=%#x,type=int16)", bs)
 		}
 	case reflect.Int32:
 		if value != int64(int32(value)) {
-			return cerrors.Wrapf(ErrInvalidFormat, "IntOverflow(bs=%#x,type=int32)", bs)
+			return 0, cerrors.Wrapf(ErrInvalidFormat, "IntOverflow(bs=%#x,type=int32)", bs)
 		}
 	}
+	return value, nil
+}
+
+func (r *rlpReader) readIntValue(v reflect.Value) error {
+	value, err := r.readInt(v.Kind())
+	if err != nil {
+		return err
+	}
 	v.SetInt(value)
 	return nil
 }
 
+func (r *rlpReader) readString() (string, error) {
+	bs, err := r.readBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
 func (r *rlpReader) ReadValue(v reflect.Value) error {
 	switch v.Kind() {
 	case reflect.Bool:
@@ -306,11 +329,11 @@ func (r *rlpReader) ReadValue(v reflect.Value) error {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return r.readIntValue(v)
 	case reflect.String:
-		bs, err := r.readBytes()
+		s, err := r.readString()
 		if err != nil {
 			return err
 		}
-		v.SetString(string(bs))
+		v.SetString(s)
 		return nil
 	}
 	return cerrors.Wrapf(ErrIllegalType, "IllegalType(%s)", v.Type())
@@ -516,28 +539,48 @@ func (w *rlpWriter) WriteRaw(b []byte) error {
 	return w.writeAll(b)
 }
 
-func (w *rlpWriter) WriteValue(v reflect.Value) error {
+func (w *rlpWriter) writeBool(v bool) error {
+	w.countN(1)
+	var buffer [1]byte
+	if v {
+		buffer[0] = 1
+	} else {
+		buffer[0] = 0
+	}
+	return w.writeBytes(buffer[:])
+}
+
+func (w *rlpWriter) writeUint(v uint64) error {
 	w.countN(1)
+	return w.writeBytes(intconv.Uint64ToBytes(v))
+}
+
+func (w *rlpWriter) writeInt(v int64) error {
+	w.countN(1)
+	return w.writeBytes(intconv.Int64ToBytes(v))
+}
+
+func (w *rlpWriter) writeString(v string) error {
+	w.countN(1)
+	return w.writeBytes([]byte(v))
+}
+
+func (w *rlpWriter) WriteValue(v reflect.Value) error {
 	switch v.Kind() {
 	case reflect.Bool:
-		var buffer [1]byte
-		if v.Bool() {
-			buffer[0] = 1
-		} else {
-			buffer[0] = 0
-		}
-		return w.writeBytes(buffer[:])
+		return w.writeBool(v.Bool())
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return w.writeBytes(intconv.Uint64ToBytes(v.Uint()))
+		return w.writeUint(v.Uint())
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return w.writeBytes(intconv.Int64ToBytes(v.Int()))
+		return w.writeInt(v.Int())
 
 	case reflect.String:
-		return w.writeBytes([]byte(v.String()))
+		return w.writeString(v.String())
 
 	default:
+		w.countN(1)
 		return cerrors.Wrapf(ErrIllegalType, "IllegalType(%s)", v.Kind())
 	}
 }
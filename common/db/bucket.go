@@ -63,6 +63,14 @@ const (
 	// ChainProperty is general key value map for chain property.
 	ChainProperty BucketID = "C"
 
+	// ArchiveIndex maps values of accounts and storages by heights
+	// for the archive mode.
+	ArchiveIndex BucketID = "A"
+
+	// ReferenceCount maps number of references from bucket ID and key
+	// of the entry for the pruning.
+	ReferenceCount BucketID = "R"
+
 	// ListByMerkleRootBase is the base for the bucket that maps list
 	// from network type dependent merkle root(list)
 	ListByMerkleRootBase BucketID = "L"
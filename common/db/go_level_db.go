@@ -5,7 +5,9 @@ import (
 	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 const GoLevelDBBackend BackendType = "goleveldb"
@@ -109,3 +111,34 @@ func (bucket *goLevelBucket) Set(key []byte, value []byte) error {
 func (bucket *goLevelBucket) Delete(key []byte) error {
 	return bucket.db.Delete(internalKey(bucket.id, key), nil)
 }
+
+// NewIterator returns the iterator of the bucket. Note that the keys of
+// MerkleTrie bucket aren't prefixed, so the iterator of it returns the
+// keys of the other buckets as well.
+func (bucket *goLevelBucket) NewIterator(r *Range) Iterator {
+	rg := &util.Range{
+		Start: internalKey(bucket.id, r.Lower()),
+	}
+	if upper := r.Upper(); upper != nil {
+		rg.Limit = internalKey(bucket.id, upper)
+	} else {
+		rg.Limit = prefixLimit([]byte(bucket.id))
+	}
+	return &goLevelIterator{
+		Iterator: bucket.db.NewIterator(rg, nil),
+		prefix:   len(bucket.id),
+	}
+}
+
+type goLevelIterator struct {
+	iterator.Iterator
+	prefix int
+}
+
+func (i *goLevelIterator) Key() []byte {
+	key := i.Iterator.Key()
+	if key == nil {
+		return nil
+	}
+	return key[i.prefix:]
+}
@@ -0,0 +1,237 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Iterator iterates key and value pairs of the bucket in the order of
+// the keys. Returned key and value are valid until the next call of Next.
+// Release must be called after the use.
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// Range selects keys having Prefix, and being in [Start, Limit).
+// Empty Start or Limit means no bound.
+type Range struct {
+	Prefix []byte
+	Start  []byte
+	Limit  []byte
+}
+
+// Contains returns whether the key is in the range.
+func (r *Range) Contains(key []byte) bool {
+	if !bytes.HasPrefix(key, r.Prefix) {
+		return false
+	}
+	if len(r.Start) > 0 && bytes.Compare(key, r.Start) < 0 {
+		return false
+	}
+	if len(r.Limit) > 0 && bytes.Compare(key, r.Limit) >= 0 {
+		return false
+	}
+	return true
+}
+
+// Lower returns the smallest key of the range.
+func (r *Range) Lower() []byte {
+	if bytes.Compare(r.Start, r.Prefix) > 0 {
+		return r.Start
+	}
+	return r.Prefix
+}
+
+// Upper returns the key following the largest key of the range. It
+// returns nil if there is no upper bound.
+func (r *Range) Upper() []byte {
+	limit := prefixLimit(r.Prefix)
+	if len(r.Limit) > 0 && (limit == nil || bytes.Compare(r.Limit, limit) < 0) {
+		return r.Limit
+	}
+	return limit
+}
+
+// prefixLimit returns the smallest key greater than all keys having the
+// prefix. It returns nil if there is no such key.
+func prefixLimit(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			limit := make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i] += 1
+			return limit
+		}
+	}
+	return nil
+}
+
+// IterableBucket is implemented by the buckets supporting iteration.
+type IterableBucket interface {
+	Bucket
+	NewIterator(r *Range) Iterator
+}
+
+// NewIterator returns the iterator for the keys of the bucket in the range.
+// It returns UnsupportedError if the bucket doesn't support iteration.
+func NewIterator(bk Bucket, r *Range) (Iterator, error) {
+	if r == nil {
+		r = &Range{}
+	}
+	if ib, ok := bk.(IterableBucket); ok {
+		return ib.NewIterator(r), nil
+	}
+	return nil, errors.UnsupportedError.Errorf("NotIterable(bucket=%T)", bk)
+}
+
+type errorIterator struct {
+	err error
+}
+
+func (i *errorIterator) Next() bool    { return false }
+func (i *errorIterator) Key() []byte   { return nil }
+func (i *errorIterator) Value() []byte { return nil }
+func (i *errorIterator) Error() error  { return i.err }
+func (i *errorIterator) Release()      {}
+
+type kvItem struct {
+	key   string
+	value []byte
+}
+
+// sliceIterator iterates the items sorted by the keys.
+type sliceIterator struct {
+	items []kvItem
+	index int
+}
+
+func newSliceIterator(items []kvItem) *sliceIterator {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].key < items[j].key
+	})
+	return &sliceIterator{items: items, index: -1}
+}
+
+func (i *sliceIterator) Next() bool {
+	if i.index < len(i.items) {
+		i.index += 1
+	}
+	return i.index < len(i.items)
+}
+
+func (i *sliceIterator) Key() []byte {
+	if i.index < 0 || i.index >= len(i.items) {
+		return nil
+	}
+	return []byte(i.items[i.index].key)
+}
+
+func (i *sliceIterator) Value() []byte {
+	if i.index < 0 || i.index >= len(i.items) {
+		return nil
+	}
+	return i.items[i.index].value
+}
+
+func (i *sliceIterator) Error() error {
+	return nil
+}
+
+func (i *sliceIterator) Release() {
+	i.items = nil
+}
+
+// overlayIterator merges pending changes in the sorted items, whose nil
+// value means deletion, with the iterator of the base.
+type overlayIterator struct {
+	base    Iterator
+	baseOK  bool
+	items   []kvItem
+	index   int
+	key     []byte
+	value   []byte
+	started bool
+}
+
+func newOverlayIterator(items []kvItem, base Iterator) *overlayIterator {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].key < items[j].key
+	})
+	return &overlayIterator{base: base, items: items}
+}
+
+func (i *overlayIterator) Next() bool {
+	if !i.started {
+		i.started = true
+		i.baseOK = i.base.Next()
+	}
+	for {
+		hasItem := i.index < len(i.items)
+		if !hasItem && !i.baseOK {
+			i.key, i.value = nil, nil
+			return false
+		}
+		var cmp int
+		if !hasItem {
+			cmp = 1
+		} else if !i.baseOK {
+			cmp = -1
+		} else {
+			cmp = bytes.Compare([]byte(i.items[i.index].key), i.base.Key())
+		}
+		if cmp > 0 {
+			i.key = append([]byte(nil), i.base.Key()...)
+			i.value = append([]byte(nil), i.base.Value()...)
+			i.baseOK = i.base.Next()
+			return true
+		}
+		item := i.items[i.index]
+		i.index += 1
+		if cmp == 0 {
+			i.baseOK = i.base.Next()
+		}
+		if item.value != nil {
+			i.key, i.value = []byte(item.key), item.value
+			return true
+		}
+	}
+}
+
+func (i *overlayIterator) Key() []byte {
+	return i.key
+}
+
+func (i *overlayIterator) Value() []byte {
+	return i.value
+}
+
+func (i *overlayIterator) Error() error {
+	return i.base.Error()
+}
+
+func (i *overlayIterator) Release() {
+	i.base.Release()
+	i.items = nil
+}
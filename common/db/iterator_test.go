@@ -0,0 +1,121 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func collectKeys(t *testing.T, bk Bucket, r *Range) []string {
+	itr, err := NewIterator(bk, r)
+	assert.NoError(t, err)
+	defer itr.Release()
+	var keys []string
+	for itr.Next() {
+		value, err := bk.Get(itr.Key())
+		assert.NoError(t, err)
+		assert.Equal(t, value, itr.Value())
+		keys = append(keys, string(itr.Key()))
+	}
+	assert.NoError(t, itr.Error())
+	return keys
+}
+
+func testIterator_Range(t *testing.T, creator dbCreator) {
+	testDB, err := creator("test", t.TempDir())
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	other, err := testDB.GetBucket("b")
+	assert.NoError(t, err)
+	assert.NoError(t, other.Set([]byte("a1"), []byte("other")))
+
+	bk, err := testDB.GetBucket("a")
+	assert.NoError(t, err)
+	for _, k := range []string{"a1", "a2", "b1", "b2", "b3", "c", "\xff\xff"} {
+		assert.NoError(t, bk.Set([]byte(k), []byte("v"+k)))
+	}
+
+	assert.Equal(t, []string{"a1", "a2", "b1", "b2", "b3", "c", "\xff\xff"},
+		collectKeys(t, bk, nil))
+	assert.Equal(t, []string{"b1", "b2", "b3"},
+		collectKeys(t, bk, &Range{Prefix: []byte("b")}))
+	assert.Equal(t, []string{"b2", "b3", "c"},
+		collectKeys(t, bk, &Range{Start: []byte("b2"), Limit: []byte("d")}))
+	assert.Equal(t, []string{"b2"},
+		collectKeys(t, bk, &Range{Prefix: []byte("b"), Start: []byte("b2"), Limit: []byte("b3")}))
+	assert.Equal(t, []string{"a1", "a2"},
+		collectKeys(t, bk, &Range{Start: []byte("a"), Limit: []byte("a3")}))
+	assert.Equal(t, []string{"\xff\xff"},
+		collectKeys(t, bk, &Range{Prefix: []byte("\xff")}))
+	assert.Empty(t, collectKeys(t, bk, &Range{Prefix: []byte("d")}))
+}
+
+func TestIterator_Range(t *testing.T) {
+	for name, creator := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testIterator_Range(t, creator)
+		})
+	}
+	t.Run("layerdb", func(t *testing.T) {
+		var creator dbCreator = func(name string, dir string) (Database, error) {
+			return NewLayerDB(NewMapDB()), nil
+		}
+		testIterator_Range(t, creator)
+	})
+}
+
+func TestIterator_LayerDB(t *testing.T) {
+	origin := NewMapDB()
+	obk, _ := origin.GetBucket("a")
+	for _, k := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, obk.Set([]byte(k), []byte("o"+k)))
+	}
+
+	ldb := NewLayerDB(origin)
+	bk, _ := ldb.GetBucket("a")
+	assert.NoError(t, bk.Delete([]byte("a")))
+	assert.NoError(t, bk.Set([]byte("b"), []byte("lb")))
+	assert.NoError(t, bk.Set([]byte("bb"), []byte("lbb")))
+	assert.NoError(t, bk.Delete([]byte("d")))
+	assert.NoError(t, bk.Set([]byte("e"), []byte("le")))
+	assert.NoError(t, bk.Delete([]byte("f")))
+
+	assert.Equal(t, []string{"b", "bb", "c", "e"}, collectKeys(t, bk, nil))
+	assert.Equal(t, []string{"bb", "c"},
+		collectKeys(t, bk, &Range{Start: []byte("ba"), Limit: []byte("d")}))
+
+	assert.NoError(t, ldb.Flush(true))
+	assert.Equal(t, []string{"b", "bb", "c", "e"}, collectKeys(t, obk, nil))
+}
+
+func TestIterator_Unsupported(t *testing.T) {
+	dbase := NewProxyDB()
+	bk, _ := dbase.GetBucket("a")
+	itr, err := NewIterator(bk, nil)
+	assert.NoError(t, err)
+	assert.False(t, itr.Next())
+	assert.Error(t, itr.Error())
+	itr.Release()
+
+	_, err = NewIterator(struct{ Bucket }{bk}, nil)
+	assert.True(t, errors.UnsupportedError.Equals(err))
+}
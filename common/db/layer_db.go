@@ -238,3 +238,25 @@ func Unwrap(database Database) Database {
 		return database
 	}
 }
+
+// NewIterator returns the iterator reflecting the changes not flushed yet.
+// The changes made after the call are not visible to the iterator.
+func (bk *layerBucket) NewIterator(r *Range) Iterator {
+	bk.lock.Lock()
+	defer bk.lock.Unlock()
+
+	base, err := NewIterator(bk.real, r)
+	if err != nil {
+		return &errorIterator{err}
+	}
+	if bk.data == nil {
+		return base
+	}
+	var items []kvItem
+	for k, element := range bk.data {
+		if r.Contains([]byte(k)) {
+			items = append(items, kvItem{k, element.Value.(*layerBucketItem).value})
+		}
+	}
+	return newOverlayIterator(items, base)
+}
@@ -115,3 +115,15 @@ func (t *mapBucket) Delete(k []byte) error {
 	delete(t.real, string(k))
 	return nil
 }
+
+func (t *mapBucket) NewIterator(r *Range) Iterator {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var items []kvItem
+	for k, v := range t.real {
+		if r.Contains([]byte(k)) {
+			items = append(items, kvItem{k, []byte(v)})
+		}
+	}
+	return newSliceIterator(items)
+}
@@ -78,3 +78,14 @@ func NewProxyDB() *proxyDB {
 		buckets: make(map[string]*proxyBucket),
 	}
 }
+
+func (bk *proxyBucket) NewIterator(r *Range) Iterator {
+	if bk.real == nil {
+		return &errorIterator{errors.New("ProxyIsNotRealized")}
+	}
+	itr, err := NewIterator(bk.real, r)
+	if err != nil {
+		return &errorIterator{err}
+	}
+	return itr
+}
@@ -20,6 +20,7 @@
 package db
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"path"
@@ -316,3 +317,91 @@ func (b *RocksBucket) Set(key []byte, value []byte) error {
 func (b *RocksBucket) Delete(key []byte) error {
 	return b.db.deleteValue(b.cf, key)
 }
+
+func bytesOf(p *C.char, l C.size_t) []byte {
+	var org []byte
+	sH := (*reflect.SliceHeader)(unsafe.Pointer(&org))
+	sH.Cap, sH.Len, sH.Data = int(l), int(l), uintptr(unsafe.Pointer(p))
+	value := make([]byte, int(l))
+	copy(value, org)
+	return value
+}
+
+// RocksIterator holds read lock of the database until it's released, so
+// the database can't be closed while the iterator is in use.
+type RocksIterator struct {
+	db    *RocksDB
+	itr   *C.rocksdb_iterator_t
+	rg    Range
+	seek  bool
+	key   []byte
+	value []byte
+	err   error
+}
+
+func (i *RocksIterator) Next() bool {
+	if i.itr == nil {
+		return false
+	}
+	if !i.seek {
+		i.seek = true
+		if lower := i.rg.Lower(); len(lower) > 0 {
+			C.rocksdb_iter_seek(i.itr, (*C.char)(unsafePointerOf(lower)), C.size_t(len(lower)))
+		} else {
+			C.rocksdb_iter_seek_to_first(i.itr)
+		}
+	} else {
+		C.rocksdb_iter_next(i.itr)
+	}
+	i.key, i.value = nil, nil
+	if C.rocksdb_iter_valid(i.itr) == 0 {
+		var cErr *C.char
+		C.rocksdb_iter_get_error(i.itr, &cErr)
+		if cErr != nil {
+			defer C.rocksdb_free(unsafe.Pointer(cErr))
+			i.err = errors.New(C.GoString(cErr))
+		}
+		return false
+	}
+	var kLen, vLen C.size_t
+	key := bytesOf(C.rocksdb_iter_key(i.itr, &kLen), kLen)
+	if upper := i.rg.Upper(); upper != nil && bytes.Compare(key, upper) >= 0 {
+		return false
+	}
+	i.key = key
+	i.value = bytesOf(C.rocksdb_iter_value(i.itr, &vLen), vLen)
+	return true
+}
+
+func (i *RocksIterator) Key() []byte {
+	return i.key
+}
+
+func (i *RocksIterator) Value() []byte {
+	return i.value
+}
+
+func (i *RocksIterator) Error() error {
+	return i.err
+}
+
+func (i *RocksIterator) Release() {
+	if i.itr != nil {
+		C.rocksdb_iter_destroy(i.itr)
+		i.itr = nil
+		i.db.lock.RUnlock()
+	}
+}
+
+func (b *RocksBucket) NewIterator(r *Range) Iterator {
+	b.db.lock.RLock()
+	if b.db.db == nil {
+		b.db.lock.RUnlock()
+		return &errorIterator{ErrAlreadyClosed}
+	}
+	return &RocksIterator{
+		db:  b.db,
+		itr: C.rocksdb_create_iterator_cf(b.db.db, b.db.ro, b.cf),
+		rg:  *r,
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+type FailureReason string
+
+const (
+	FailureMissing   FailureReason = "missing"
+	FailureCorrupted FailureReason = "corrupted"
+	FailureInvalid   FailureReason = "invalid"
+)
+
+// CheckFailure describes the data failed to be verified.
+type CheckFailure struct {
+	Height   int64
+	BucketID db.BucketID
+	Key      []byte
+	Reason   FailureReason
+}
+
+func (f *CheckFailure) String() string {
+	return fmt.Sprintf("%s(height=%d,bucket=%q,key=%#x)",
+		f.Reason, f.Height, f.BucketID, f.Key)
+}
+
+// FailureHandler is called for the data failed to be verified. It may
+// return the data recovered from other sources to continue verification
+// of the data referenced by it. If it returns nil, then the data and the
+// data referenced by it are skipped.
+type FailureHandler func(f *CheckFailure) ([]byte, error)
+
+func (e *CopyContext) checkData(id db.BucketID, key, value []byte) error {
+	if hasher := id.Hasher(); hasher != nil && !bytes.Equal(hasher.Hash(value), key) {
+		return e.handleFailure(id, key, FailureCorrupted)
+	}
+	if err := e.builder.OnData(id, value); err != nil {
+		return e.handleFailure(id, key, FailureInvalid)
+	}
+	return nil
+}
+
+func (e *CopyContext) handleFailure(id db.BucketID, key []byte, reason FailureReason) error {
+	value, err := e.onFailure(&CheckFailure{
+		Height:   e.height,
+		BucketID: id,
+		Key:      key,
+		Reason:   reason,
+	})
+	if err != nil {
+		return err
+	}
+	if value != nil && bytes.Equal(id.Hasher().Hash(value), key) {
+		if err := e.builder.OnData(id, value); err == nil {
+			return nil
+		}
+	}
+	e.skipped[string(key)] = struct{}{}
+	return nil
+}
+
+type checkedKey struct {
+	id  db.BucketID
+	key string
+}
+
+// checkDB is used as the target of CheckContext. It doesn't store the data,
+// but it returns the data of the source once it's verified, so the builder
+// doesn't request the data already verified again.
+type checkDB struct {
+	src     db.Database
+	lock    sync.Mutex
+	checked map[checkedKey]struct{}
+}
+
+func (d *checkDB) GetBucket(id db.BucketID) (db.Bucket, error) {
+	return &checkBucket{d, id}, nil
+}
+
+func (d *checkDB) Close() error {
+	return nil
+}
+
+func (d *checkDB) isChecked(id db.BucketID, key []byte) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	_, ok := d.checked[checkedKey{id, string(key)}]
+	return ok
+}
+
+func (d *checkDB) setChecked(id db.BucketID, key []byte, checked bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if checked {
+		d.checked[checkedKey{id, string(key)}] = struct{}{}
+	} else {
+		delete(d.checked, checkedKey{id, string(key)})
+	}
+}
+
+type checkBucket struct {
+	database *checkDB
+	id       db.BucketID
+}
+
+func (b *checkBucket) Get(key []byte) ([]byte, error) {
+	if !b.database.isChecked(b.id, key) {
+		return nil, nil
+	}
+	return db.DoGetWithBucketID(b.database.src, b.id, key)
+}
+
+func (b *checkBucket) Has(key []byte) (bool, error) {
+	return b.database.isChecked(b.id, key), nil
+}
+
+func (b *checkBucket) Set(key []byte, value []byte) error {
+	b.database.setChecked(b.id, key, true)
+	return nil
+}
+
+func (b *checkBucket) Delete(key []byte) error {
+	b.database.setChecked(b.id, key, false)
+	return nil
+}
+
+// NewCheckContext returns CopyContext verifying that all the data
+// requested through its builder exist in the database with valid hashes.
+// Unlike copying, failures are passed to the handler, and it continues
+// with the other data. Data verified once aren't verified again, so the
+// data shared by the blocks are verified only once.
+func NewCheckContext(src db.Database, handler FailureHandler) *CopyContext {
+	dst := &checkDB{
+		src:     src,
+		checked: make(map[checkedKey]struct{}),
+	}
+	ctx := NewCopyContext(src, dst)
+	ctx.onFailure = handler
+	ctx.skipped = make(map[string]struct{})
+	return ctx
+}
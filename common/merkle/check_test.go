@@ -0,0 +1,119 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merkle_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+func newTestTrie(t *testing.T, dbase db.Database, n int) []byte {
+	m := trie_manager.New(dbase).NewMutable(nil)
+	for i := 0; i < n; i++ {
+		k := []byte(fmt.Sprintf("key%03d", i))
+		_, err := m.Set(k, []byte(fmt.Sprintf("value for the key %03d", i)))
+		assert.NoError(t, err)
+	}
+	s := m.GetSnapshot()
+	assert.NoError(t, s.Flush())
+	return s.Hash()
+}
+
+func checkTrie(t *testing.T, ctx *merkle.CopyContext, h []byte) {
+	trie_manager.New(ctx.Builder().Database()).NewImmutable(h).Resolve(ctx.Builder())
+	assert.NoError(t, ctx.Run())
+}
+
+func TestCheckContext(t *testing.T) {
+	dbase := db.NewMapDB()
+	h := newTestTrie(t, dbase, 100)
+	bk, _ := dbase.GetBucket(db.MerkleTrie)
+
+	var failures []*merkle.CheckFailure
+	handler := func(f *merkle.CheckFailure) ([]byte, error) {
+		failures = append(failures, f)
+		return nil, nil
+	}
+
+	ctx := merkle.NewCheckContext(dbase, handler)
+	checkTrie(t, ctx, h)
+	assert.Empty(t, failures)
+	checked := ctx.Builder().ResolvedCount()
+	assert.True(t, checked > 1)
+
+	// verified nodes are not requested again.
+	ctx.SetHeight(1)
+	checkTrie(t, ctx, h)
+	assert.Equal(t, checked, ctx.Builder().ResolvedCount())
+
+	// find missing and corrupted nodes
+	var keys [][]byte
+	itr, _ := db.NewIterator(bk, nil)
+	for itr.Next() {
+		if string(itr.Key()) != string(h) {
+			keys = append(keys, itr.Key())
+		}
+	}
+	itr.Release()
+	missing, corrupted := keys[0], keys[1]
+	value, _ := bk.Get(missing)
+	assert.NoError(t, bk.Delete(missing))
+	assert.NoError(t, bk.Set(corrupted, []byte{0xc0}))
+
+	ctx = merkle.NewCheckContext(dbase, handler)
+	ctx.SetHeight(2)
+	checkTrie(t, ctx, h)
+	assert.Len(t, failures, 2)
+	reasons := map[string]merkle.FailureReason{}
+	for _, f := range failures {
+		assert.Equal(t, int64(2), f.Height)
+		assert.Equal(t, db.MerkleTrie, f.BucketID)
+		reasons[string(f.Key)] = f.Reason
+	}
+	assert.Equal(t, merkle.FailureMissing, reasons[string(missing)])
+	assert.Equal(t, merkle.FailureCorrupted, reasons[string(corrupted)])
+
+	// the handler may recover the data
+	failures = nil
+	ctx = merkle.NewCheckContext(dbase, func(f *merkle.CheckFailure) ([]byte, error) {
+		failures = append(failures, f)
+		if string(f.Key) == string(missing) {
+			return value, nil
+		}
+		return nil, nil
+	})
+	checkTrie(t, ctx, h)
+	assert.Len(t, failures, 2)
+	assert.Equal(t, checked-1, ctx.Builder().ResolvedCount())
+}
+
+func TestCopyContext_FailOnMissing(t *testing.T) {
+	src := db.NewMapDB()
+	h := newTestTrie(t, src, 10)
+	bk, _ := src.GetBucket(db.MerkleTrie)
+	assert.NoError(t, bk.Delete(h))
+
+	ctx := merkle.NewCopyContext(src, db.NewMapDB())
+	trie_manager.New(ctx.Builder().Database()).NewImmutable(h).Resolve(ctx.Builder())
+	assert.Error(t, ctx.Run())
+}
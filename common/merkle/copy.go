@@ -21,6 +21,9 @@ type CopyContext struct {
 
 	height     int64
 	progressCB module.ProgressCallback
+
+	onFailure FailureHandler
+	skipped   map[string]struct{}
 }
 
 func (e *CopyContext) Builder() Builder {
@@ -46,10 +49,13 @@ func (e *CopyContext) Run() error {
 	if err := e.reportProgress(); err != nil {
 		return err
 	}
-	for e.builder.UnresolvedCount() > 0 {
+	for e.builder.UnresolvedCount() > len(e.skipped) {
 		itr := e.builder.Requests()
 		processed := 0
 		for itr.Next() {
+			if _, ok := e.skipped[string(itr.Key())]; ok {
+				continue
+			}
 			found := false
 			for _, id := range itr.BucketIDs() {
 				bk, err := e.src.GetBucket(id)
@@ -61,17 +67,28 @@ func (e *CopyContext) Run() error {
 					return err
 				}
 				if v1 != nil {
-					err := e.builder.OnData(id, v1)
-					if err != nil {
-						return err
+					if e.onFailure != nil {
+						if err := e.checkData(id, itr.Key(), v1); err != nil {
+							return err
+						}
+					} else {
+						err := e.builder.OnData(id, v1)
+						if err != nil {
+							return err
+						}
 					}
 					found = true
 					break
 				}
 			}
 			if !found {
-				_ = e.reportProgress()
-				return errors.NotFoundError.Errorf("FailToFindValue(key=%x)", itr.Key())
+				if e.onFailure == nil {
+					_ = e.reportProgress()
+					return errors.NotFoundError.Errorf("FailToFindValue(key=%x)", itr.Key())
+				}
+				if err := e.handleFailure(itr.BucketIDs()[0], itr.Key(), FailureMissing); err != nil {
+					return err
+				}
 			}
 
 			// Prevent massive memory usage by cumulated requests.
@@ -307,11 +307,14 @@ func (n *branch) getProof(m *mpt, keys []byte, proofs [][]byte) (nn node, proof
 		proofs = append(proofs, n.serialized)
 	}
 	if len(keys) == 0 {
+		if n.value == nil {
+			return n, proofs, errNoKey
+		}
 		return n, proofs, nil
 	}
 	child := n.children[keys[0]]
 	if child == nil {
-		return n, nil, nil
+		return n, proofs, errNoKey
 	}
 	nchild, proofs, err := child.getProof(m, keys[1:], proofs)
 	if nchild != child {
@@ -339,15 +342,16 @@ func (n *branch) prove(m *mpt, keys []byte, proof [][]byte) (nn node, obj trie.O
 	}
 
 	if len(keys) == 0 {
-		if n.value != nil {
-			value, changed, err := m.getObject(n.value)
-			if err != nil {
-				return n, nil, err
-			}
-			if changed {
-				lock.Migrate()
-				n.value = value
-			}
+		if n.value == nil {
+			return n, nil, common.ErrNotFound
+		}
+		value, changed, err := m.getObject(n.value)
+		if err != nil {
+			return n, nil, err
+		}
+		if changed {
+			lock.Migrate()
+			n.value = value
 		}
 		return n, n.value, nil
 	}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ompt
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie"
+)
+
+// ObjectDifferenceHandler is called for the different values of two tries
+// in the order of the keys. op is -1 if the key is only in the first trie,
+// 1 if it's only in the second trie, and 0 if the values are different.
+type ObjectDifferenceHandler func(op int, key []byte, v1, v2 trie.Object) error
+
+type BytesDifferenceHandler func(op int, key []byte, v1, v2 []byte) error
+
+// diffItem is a node or a value (if n is nil) at the nibbles k.
+type diffItem struct {
+	k string
+	n node
+	v trie.Object
+}
+
+// diffCursor visits nodes and values in the order of the nibbles.
+type diffCursor struct {
+	m     *mpt
+	stack []diffItem
+}
+
+func (c *diffCursor) top() *diffItem {
+	if len(c.stack) == 0 {
+		return nil
+	}
+	return &c.stack[len(c.stack)-1]
+}
+
+func (c *diffCursor) pop() diffItem {
+	l := len(c.stack)
+	item := c.stack[l-1]
+	c.stack = c.stack[:l-1]
+	return item
+}
+
+func (c *diffCursor) schedule(k string, n node) (node, error) {
+	c.stack = append(c.stack, diffItem{k: k, n: n})
+	return n, nil
+}
+
+// expand replaces the node on the top with its children and its value.
+func (c *diffCursor) expand() error {
+	item := c.pop()
+	k, v, err := item.n.traverse(c.m, item.k, c.schedule)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		c.stack = append(c.stack, diffItem{k: k, v: v})
+	}
+	return nil
+}
+
+func newDiffCursor(m *mpt) *diffCursor {
+	c := &diffCursor{m: m}
+	if m != nil && m.root != nil {
+		c.stack = append(c.stack, diffItem{n: m.root})
+	}
+	return c
+}
+
+func sameNode(n1, n2 node) bool {
+	if n1 == n2 {
+		return true
+	}
+	h1, h2 := n1.hash(), n2.hash()
+	return len(h1) > 0 && bytes.Equal(h1, h2)
+}
+
+// lessItem returns whether i1 should be visited before i2. Nil item comes
+// after all items.
+func lessItem(i1, i2 *diffItem) bool {
+	if i1 == nil {
+		return false
+	}
+	if i2 == nil {
+		return true
+	}
+	return i1.k < i2.k
+}
+
+func diffMPT(m1, m2 *mpt, handler ObjectDifferenceHandler) error {
+	c1, c2 := newDiffCursor(m1), newDiffCursor(m2)
+	for {
+		t1, t2 := c1.top(), c2.top()
+		if t1 == nil && t2 == nil {
+			return nil
+		}
+		if t1 != nil && t2 != nil && t1.k == t2.k {
+			switch {
+			case t1.n != nil && t2.n != nil:
+				if sameNode(t1.n, t2.n) {
+					c1.pop()
+					c2.pop()
+					continue
+				}
+				if err := c1.expand(); err != nil {
+					return err
+				}
+				if err := c2.expand(); err != nil {
+					return err
+				}
+			case t1.n != nil:
+				if err := c1.expand(); err != nil {
+					return err
+				}
+			case t2.n != nil:
+				if err := c2.expand(); err != nil {
+					return err
+				}
+			default:
+				i1, i2 := c1.pop(), c2.pop()
+				if !bytes.Equal(i1.v.Bytes(), i2.v.Bytes()) {
+					if err := handler(0, keysToBytes(i1.k), i1.v, i2.v); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		// the node may have the value of the other, so it should be
+		// expanded before handling the value.
+		if t1 != nil && t1.n != nil && (t2 == nil || t1.k < t2.k || strings.HasPrefix(t2.k, t1.k)) {
+			if err := c1.expand(); err != nil {
+				return err
+			}
+			continue
+		}
+		if t2 != nil && t2.n != nil && (t1 == nil || t2.k < t1.k || strings.HasPrefix(t1.k, t2.k)) {
+			if err := c2.expand(); err != nil {
+				return err
+			}
+			continue
+		}
+		if lessItem(t1, t2) {
+			i1 := c1.pop()
+			if err := handler(-1, keysToBytes(i1.k), i1.v, nil); err != nil {
+				return err
+			}
+		} else {
+			i2 := c2.pop()
+			if err := handler(1, keysToBytes(i2.k), nil, i2.v); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func mptOf(t interface{}) (*mpt, error) {
+	switch m := t.(type) {
+	case nil:
+		return nil, nil
+	case *mpt:
+		return m, nil
+	case *mptForBytes:
+		if m == nil {
+			return nil, nil
+		}
+		return m.mpt, nil
+	default:
+		return nil, errors.UnsupportedError.Errorf("UnknownTrie(type=%T)", t)
+	}
+}
+
+// DiffImmutableForObject calls the handler for the differences of two
+// tries. It skips the sub-tries having the same hash, so it's efficient
+// for the tries sharing most of the nodes. Nil trie is handled as empty.
+func DiffImmutableForObject(t1, t2 trie.ImmutableForObject, handler ObjectDifferenceHandler) error {
+	m1, err := mptOf(t1)
+	if err != nil {
+		return err
+	}
+	m2, err := mptOf(t2)
+	if err != nil {
+		return err
+	}
+	return diffMPT(m1, m2, handler)
+}
+
+// DiffImmutable is same as DiffImmutableForObject except that it's for
+// the tries of bytes.
+func DiffImmutable(t1, t2 trie.Immutable, handler BytesDifferenceHandler) error {
+	m1, err := mptOf(t1)
+	if err != nil {
+		return err
+	}
+	m2, err := mptOf(t2)
+	if err != nil {
+		return err
+	}
+	return diffMPT(m1, m2, func(op int, key []byte, v1, v2 trie.Object) error {
+		var b1, b2 []byte
+		if v1 != nil {
+			b1 = v1.Bytes()
+		}
+		if v2 != nil {
+			b2 = v2.Bytes()
+		}
+		return handler(op, key, b1, b2)
+	})
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ompt
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/trie"
+)
+
+type diffEntry struct {
+	op     int
+	k      string
+	v1, v2 string
+}
+
+func diffByMaps(m1, m2 map[string]string) map[string]diffEntry {
+	diffs := make(map[string]diffEntry)
+	for k, v1 := range m1 {
+		if v2, ok := m2[k]; !ok {
+			diffs[k] = diffEntry{-1, k, v1, ""}
+		} else if v1 != v2 {
+			diffs[k] = diffEntry{0, k, v1, v2}
+		}
+	}
+	for k, v2 := range m2 {
+		if _, ok := m1[k]; !ok {
+			diffs[k] = diffEntry{1, k, "", v2}
+		}
+	}
+	return diffs
+}
+
+func snapshotOf(t *testing.T, dbase db.Database, values map[string]string) trie.Immutable {
+	m := NewMPTForBytes(dbase, nil)
+	for k, v := range values {
+		_, err := m.Set([]byte(k), []byte(v))
+		assert.NoError(t, err)
+	}
+	s := m.GetSnapshot()
+	assert.NoError(t, s.Flush())
+	return NewMPTForBytes(dbase, s.Hash())
+}
+
+func TestDiffImmutable(t *testing.T) {
+	dbase := db.NewMapDB()
+	rnd := rand.New(rand.NewSource(1))
+	randomKey := func() string {
+		bs := make([]byte, 1+rnd.Intn(3))
+		rnd.Read(bs)
+		return string(bs)
+	}
+
+	m1 := make(map[string]string)
+	for i := 0; i < 300; i++ {
+		m1[randomKey()] = fmt.Sprintf("value%d", i)
+	}
+	for round := 0; round < 20; round++ {
+		m2 := make(map[string]string)
+		for k, v := range m1 {
+			m2[k] = v
+		}
+		for i := 0; i < round*3; i++ {
+			switch rnd.Intn(3) {
+			case 0:
+				m2[randomKey()] = fmt.Sprintf("added%d", i)
+			case 1:
+				for k := range m2 {
+					m2[k] = fmt.Sprintf("changed%d", i)
+					break
+				}
+			case 2:
+				for k := range m2 {
+					delete(m2, k)
+					break
+				}
+			}
+		}
+		s1 := snapshotOf(t, dbase, m1)
+		s2 := snapshotOf(t, dbase, m2)
+
+		expected := diffByMaps(m1, m2)
+		var prev []byte
+		err := DiffImmutable(s1, s2, func(op int, key []byte, v1, v2 []byte) error {
+			assert.True(t, prev == nil || string(prev) < string(key), "not in order")
+			prev = key
+			assert.Equal(t, expected[string(key)], diffEntry{op, string(key), string(v1), string(v2)})
+			delete(expected, string(key))
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, expected)
+		m1 = m2
+	}
+}
+
+func TestDiffImmutable_Empty(t *testing.T) {
+	dbase := db.NewMapDB()
+	values := map[string]string{"a": "1", "ab": "2", "b": "3"}
+	s := snapshotOf(t, dbase, values)
+
+	var added, removed []string
+	err := DiffImmutable(nil, s, func(op int, key []byte, v1, v2 []byte) error {
+		assert.Equal(t, 1, op)
+		added = append(added, string(key))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "ab", "b"}, added)
+
+	err = DiffImmutable(s, NewMPTForBytes(dbase, nil), func(op int, key []byte, v1, v2 []byte) error {
+		assert.Equal(t, -1, op)
+		removed = append(removed, string(key))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, added, removed)
+
+	err = DiffImmutable(s, s, func(op int, key []byte, v1, v2 []byte) error {
+		return fmt.Errorf("unexpected difference key=%q", key)
+	})
+	assert.NoError(t, err)
+}
@@ -220,12 +220,12 @@ func (n *leaf) getProof(m *mpt, keys []byte, items [][]byte) (node, [][]byte, er
 	if n.state < stateHashed {
 		return n, nil, fmt.Errorf("IllegaState %s", n.toString())
 	}
-	if _, match := compareKeys(n.keys, keys); !match {
-		return n, nil, nil
-	}
 	if n.hashValue != nil {
 		items = append(items, n.serialized)
 	}
+	if _, match := compareKeys(n.keys, keys); !match {
+		return n, items, errNoKey
+	}
 	return n, items, nil
 }
 
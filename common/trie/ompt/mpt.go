@@ -17,6 +17,10 @@ import (
 	"github.com/icon-project/goloop/common/trie/cache"
 )
 
+// errNoKey is returned by getProof of nodes with the proof for the absence
+// of the key.
+var errNoKey = errors.New("NoKey")
+
 const (
 	debugPrint = false
 	debugDump  = false
@@ -482,6 +486,30 @@ func (m *mpt) GetProof(k []byte) [][]byte {
 	return proofs
 }
 
+// GetAbsenceProof returns the nodes on the path for the key up to the node
+// where the path diverges from the key. Prove with the proof verifies them,
+// then it returns common.ErrNotFound.
+func (m *mpt) GetAbsenceProof(k []byte) [][]byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.root == nil {
+		return nil
+	}
+	m.root.getLink(true)
+
+	nibs := bytesToNibs(k)
+	defer freeNibbles(nibs)
+	root, proofs, err := m.root.getProof(m, nibs, nil)
+	if root != m.root {
+		m.root = root
+	}
+	if err != errNoKey {
+		return nil
+	}
+	return proofs
+}
+
 func (m *mpt) Prove(k []byte, proofs [][]byte) (trie.Object, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/merkle"
 
 	"github.com/icon-project/goloop/common/db"
@@ -250,6 +251,46 @@ func Test_GetPoof(t *testing.T) {
 	}
 }
 
+func Test_GetAbsenceProof(t *testing.T) {
+	dbase := db.NewMapDB()
+	m := NewMPTForBytes(dbase, nil)
+	keys := [][]byte{
+		{0x01}, {0x01, 0x22}, {0x01, 0x23}, {0x01, 0x23, 0x44}, {0x01, 0x23, 0x45},
+		{0x12, 0x34, 0x56, 0x78}, {0x12, 0x34, 0x56, 0x79},
+	}
+	for _, k := range keys {
+		_, err := m.Set(k, bytes.Repeat(k, 16))
+		assert.NoError(t, err)
+	}
+	s := m.GetSnapshot()
+	assert.NoError(t, s.Flush())
+	s = NewMPTForBytes(dbase, s.Hash())
+
+	for _, k := range keys {
+		assert.Nil(t, s.GetAbsenceProof(k), "key=%x", k)
+	}
+
+	for _, k := range [][]byte{
+		{0x02},             // no child in the branch
+		{0x01, 0x23, 0x46}, // mismatch in the leaf
+		{0x12, 0x35},       // mismatch in the extension
+		{0x01, 0x23, 0x4},  // no value in the branch
+		{0x12, 0x34, 0x56, 0x78, 0x90},
+	} {
+		assert.Nil(t, s.GetProof(k), "key=%x", k)
+		proof := s.GetAbsenceProof(k)
+		assert.NotNil(t, proof, "key=%x", k)
+
+		s2 := NewMPTForBytes(db.NewMapDB(), s.Hash())
+		_, err := s2.Prove(k, proof)
+		assert.Equal(t, common.ErrNotFound, err, "key=%x", k)
+
+		// not enough nodes to prove
+		_, err = s2.Prove(k, proof[:len(proof)-1])
+		assert.NotEqual(t, common.ErrNotFound, err, "key=%x", k)
+	}
+}
+
 // func TestIterateInOrder(t *testing.T) {
 // 	mp := new(codec.MsgpackHandle)
 // 	mp.Canonical = true
@@ -232,7 +232,7 @@ func deserialize(h, serialized []byte, state nodeState) (node, error) {
 	switch len(blist) {
 	case 2:
 		keyheader, err := rlpParseBytes(blist[0])
-		if err != nil {
+		if err != nil || len(keyheader) == 0 {
 			return nil, errors.New("fail to parse header of node")
 		}
 		if (keyheader[0] & 0x20) == 0 {
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ompt
+
+import (
+	"encoding/hex"
+)
+
+// NodeView is the JSON friendly view of the serialized trie node. Keys
+// are nibbles in hex, and the child is either the hash or the embedded node.
+type NodeView struct {
+	Type     string        `json:"type"`
+	Keys     string        `json:"keys,omitempty"`
+	Value    string        `json:"value,omitempty"`
+	Next     interface{}   `json:"next,omitempty"`
+	Children []interface{} `json:"children,omitempty"`
+}
+
+func linkViewOf(n node) interface{} {
+	switch nn := n.(type) {
+	case nil:
+		return nil
+	case *hash:
+		return "0x" + hex.EncodeToString(nn.value)
+	default:
+		return viewOf(n)
+	}
+}
+
+func nibblesToString(keys []byte) string {
+	const digits = "0123456789abcdef"
+	s := make([]byte, len(keys))
+	for i, k := range keys {
+		s[i] = digits[k&0xf]
+	}
+	return string(s)
+}
+
+func valueToString(o interface{ Bytes() []byte }) string {
+	if o == nil {
+		return ""
+	}
+	return "0x" + hex.EncodeToString(o.Bytes())
+}
+
+func viewOf(n node) *NodeView {
+	switch nn := n.(type) {
+	case *branch:
+		v := &NodeView{
+			Type:     "branch",
+			Children: make([]interface{}, len(nn.children)),
+		}
+		for i, child := range nn.children {
+			v.Children[i] = linkViewOf(child)
+		}
+		if nn.value != nil {
+			v.Value = valueToString(nn.value)
+		}
+		return v
+	case *extension:
+		return &NodeView{
+			Type: "extension",
+			Keys: nibblesToString(nn.keys),
+			Next: linkViewOf(nn.next),
+		}
+	case *leaf:
+		return &NodeView{
+			Type:  "leaf",
+			Keys:  nibblesToString(nn.keys),
+			Value: valueToString(nn.value),
+		}
+	default:
+		return nil
+	}
+}
+
+// ViewNode decodes the serialized trie node stored in the database.
+func ViewNode(serialized []byte) (*NodeView, error) {
+	n, err := deserialize(nil, serialized, stateFlushed)
+	if err != nil {
+		return nil, err
+	}
+	return viewOf(n), nil
+}
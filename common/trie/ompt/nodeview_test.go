@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ompt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestViewNode(t *testing.T) {
+	dbase := db.NewMapDB()
+	m := NewMPTForBytes(dbase, nil)
+	for _, k := range []string{"\x01\x23", "\x01\x24", "\x01\x23\x45", "\x99"} {
+		_, err := m.Set([]byte(k), bytes.Repeat([]byte(k), 16))
+		assert.NoError(t, err)
+	}
+	s := m.GetSnapshot()
+	assert.NoError(t, s.Flush())
+
+	bk, err := dbase.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	root, err := bk.Get(s.Hash())
+	assert.NoError(t, err)
+	v, err := ViewNode(root)
+	assert.NoError(t, err)
+	assert.Equal(t, "branch", v.Type)
+	assert.Len(t, v.Children, 16)
+	assert.NotNil(t, v.Children[0])
+	assert.NotNil(t, v.Children[9])
+
+	itr, err := db.NewIterator(bk, nil)
+	assert.NoError(t, err)
+	defer itr.Release()
+	types := map[string]int{}
+	for itr.Next() {
+		v, err := ViewNode(itr.Value())
+		assert.NoError(t, err)
+		types[v.Type] += 1
+	}
+	assert.NotZero(t, types["leaf"])
+
+	_, err = ViewNode([]byte{0xc2, 0x80, 0x80})
+	assert.Error(t, err)
+	_, err = ViewNode([]byte{0x01})
+	assert.Error(t, err)
+}
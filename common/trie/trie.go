@@ -16,6 +16,9 @@ type (
 		Get(k []byte) ([]byte, error)
 		Hash() []byte               // return nil if this Tree is empty
 		GetProof(k []byte) [][]byte // return nill of this Tree is empty
+		// GetAbsenceProof returns the proof for the absence of the key,
+		// or nil if the key exists or this Tree is empty.
+		GetAbsenceProof(k []byte) [][]byte
 		Iterator() Iterator
 		Filter(prefix []byte) Iterator
 		Equal(immutable Immutable, exact bool) bool
@@ -66,6 +69,9 @@ type (
 		Get(k []byte) (Object, error)
 		Hash() []byte
 		GetProof(k []byte) [][]byte // return nill of this Tree is empty
+		// GetAbsenceProof returns the proof for the absence of the key,
+		// or nil if the key exists or this Tree is empty.
+		GetAbsenceProof(k []byte) [][]byte
 		Iterator() IteratorForObject
 		Filter(prefix []byte) IteratorForObject
 		Equal(object ImmutableForObject, exact bool) bool
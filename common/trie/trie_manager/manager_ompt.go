@@ -40,3 +40,15 @@ func SetCacheOfMutable(mutable trie.Mutable, cache *cache.NodeCache) {
 func SetCacheOfMutableForObject(mutable trie.MutableForObject, cache *cache.NodeCache) {
 	ompt.SetCacheOfMutableForObject(mutable, cache)
 }
+
+// DiffImmutable calls the handler for the differences of the tries like
+// CompareImmutable, but it skips the sub-tries having the same hash.
+func DiffImmutable(exp, real trie.Immutable, handler ompt.BytesDifferenceHandler) error {
+	return ompt.DiffImmutable(exp, real, handler)
+}
+
+// DiffImmutableForObject is same as DiffImmutable except that it's for
+// the tries of objects.
+func DiffImmutableForObject(exp, real trie.ImmutableForObject, handler ompt.ObjectDifferenceHandler) error {
+	return ompt.DiffImmutableForObject(exp, real, handler)
+}
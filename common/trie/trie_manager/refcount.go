@@ -0,0 +1,266 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trie_manager
+
+import (
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// ReferenceTracker tracks number of references to the entries of the
+// buckets having the hasher (like MerkleTrie and BytesByHash). Counts are
+// stored in the ReferenceCount bucket.
+//
+// It's used as the target of merkle.CopyContext whose source is the
+// database of the tracker. Adding references, the builder requests only
+// the entries referenced at first, so their children are referenced once
+// for each entry. Releasing references, the builder requests only the
+// entries not referenced any more, so their children are released, and
+// they are deleted on Commit.
+type ReferenceTracker struct {
+	dbase   db.Database
+	counts  db.Bucket
+	release bool
+	buckets map[db.BucketID]db.Bucket
+
+	changes map[string]int64
+	pending map[string]bool
+	deletes []trackedEntry
+}
+
+type trackedEntry struct {
+	id  db.BucketID
+	key []byte
+}
+
+func refKeyOf(id db.BucketID, key []byte) string {
+	k := make([]byte, 0, 1+len(id)+len(key))
+	k = append(k, byte(len(id)))
+	k = append(k, id...)
+	k = append(k, key...)
+	return string(k)
+}
+
+func (t *ReferenceTracker) countOf(k string) (int64, error) {
+	if c, ok := t.changes[k]; ok {
+		return c, nil
+	}
+	bs, err := t.counts.Get([]byte(k))
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	var c int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &c); err != nil {
+		return 0, errors.CriticalFormatError.Wrapf(err, "InvalidCount(key=%x)", k)
+	}
+	return c, nil
+}
+
+type trackedBucket struct {
+	tracker *ReferenceTracker
+	id      db.BucketID
+	real    db.Bucket
+}
+
+// Get returns the value only if the entry is referenced already. On
+// releasing, it returns nil for the entry referenced last time, and it
+// returns the value for the entries not referenced (not tracked or
+// released already), so they are not requested.
+func (bk *trackedBucket) Get(key []byte) ([]byte, error) {
+	t := bk.tracker
+	k := refKeyOf(bk.id, key)
+	c, err := t.countOf(k)
+	if err != nil {
+		return nil, err
+	}
+	if !t.release {
+		if c == 0 {
+			return nil, nil
+		}
+		t.changes[k] = c + 1
+		return bk.real.Get(key)
+	}
+	if c == 1 {
+		t.changes[k] = 0
+		t.pending[k] = true
+		return nil, nil
+	}
+	if c > 1 {
+		t.changes[k] = c - 1
+	}
+	return bk.real.Get(key)
+}
+
+// Has returns whether the entry exists without changing references.
+func (bk *trackedBucket) Has(key []byte) (bool, error) {
+	return bk.real.Has(key)
+}
+
+// Set is called for the entries requested by the builder. It doesn't
+// write the value, because it comes from the database of the tracker.
+func (bk *trackedBucket) Set(key []byte, value []byte) error {
+	t := bk.tracker
+	k := refKeyOf(bk.id, key)
+	if t.release {
+		if t.pending[k] {
+			delete(t.pending, k)
+			t.deletes = append(t.deletes, trackedEntry{
+				id:  bk.id,
+				key: append([]byte(nil), key...),
+			})
+		}
+		return nil
+	}
+	c, err := t.countOf(k)
+	if err != nil {
+		return err
+	}
+	t.changes[k] = c + 1
+	return nil
+}
+
+func (bk *trackedBucket) Delete(key []byte) error {
+	return errors.UnsupportedError.New("DeleteOnTrackedBucket")
+}
+
+// untrackedBucket is for the buckets without the hasher. It reads the
+// database, and ignores updates.
+type untrackedBucket struct {
+	real db.Bucket
+}
+
+func (bk *untrackedBucket) Get(key []byte) ([]byte, error) {
+	return bk.real.Get(key)
+}
+
+func (bk *untrackedBucket) Has(key []byte) (bool, error) {
+	return bk.real.Has(key)
+}
+
+func (bk *untrackedBucket) Set(key []byte, value []byte) error {
+	return nil
+}
+
+func (bk *untrackedBucket) Delete(key []byte) error {
+	return nil
+}
+
+func (t *ReferenceTracker) GetBucket(id db.BucketID) (db.Bucket, error) {
+	if bk, ok := t.buckets[id]; ok {
+		return bk, nil
+	}
+	real, err := t.dbase.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	var bk db.Bucket
+	if id.Hasher() != nil {
+		bk = &trackedBucket{tracker: t, id: id, real: real}
+	} else {
+		bk = &untrackedBucket{real: real}
+	}
+	t.buckets[id] = bk
+	return bk, nil
+}
+
+func (t *ReferenceTracker) Close() error {
+	return nil
+}
+
+// Changes returns number of counts changed after the last commit.
+func (t *ReferenceTracker) Changes() int {
+	return len(t.changes)
+}
+
+// Commit writes changed counts, then it deletes the entries released.
+// It returns number of deleted entries.
+func (t *ReferenceTracker) Commit() (int, error) {
+	for k, c := range t.changes {
+		var err error
+		if c > 0 {
+			err = t.counts.Set([]byte(k), codec.BC.MustMarshalToBytes(c))
+		} else {
+			err = t.counts.Delete([]byte(k))
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, e := range t.deletes {
+		bk, err := t.dbase.GetBucket(e.id)
+		if err != nil {
+			return 0, err
+		}
+		if err := bk.Delete(e.key); err != nil {
+			return 0, err
+		}
+	}
+	deleted := len(t.deletes)
+	t.changes = make(map[string]int64)
+	t.pending = make(map[string]bool)
+	t.deletes = nil
+	return deleted, nil
+}
+
+// NewReferenceTracker returns the tracker adding references, or releasing
+// references if release is true.
+func NewReferenceTracker(dbase db.Database, release bool) (*ReferenceTracker, error) {
+	counts, err := dbase.GetBucket(db.ReferenceCount)
+	if err != nil {
+		return nil, err
+	}
+	return &ReferenceTracker{
+		dbase:   dbase,
+		counts:  counts,
+		release: release,
+		buckets: make(map[db.BucketID]db.Bucket),
+		changes: make(map[string]int64),
+		pending: make(map[string]bool),
+	}, nil
+}
+
+// ClearReferences removes all the counts of the references.
+func ClearReferences(dbase db.Database) error {
+	counts, err := dbase.GetBucket(db.ReferenceCount)
+	if err != nil {
+		return err
+	}
+	for {
+		itr, err := db.NewIterator(counts, nil)
+		if err != nil {
+			return err
+		}
+		var keys [][]byte
+		for len(keys) < 1000 && itr.Next() {
+			keys = append(keys, append([]byte(nil), itr.Key()...))
+		}
+		err = itr.Error()
+		itr.Release()
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		for _, k := range keys {
+			if err := counts.Delete(k); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trie_manager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/merkle"
+)
+
+func countEntries(t *testing.T, dbase db.Database, id db.BucketID) int {
+	bk, err := dbase.GetBucket(id)
+	assert.NoError(t, err)
+	itr, err := db.NewIterator(bk, nil)
+	assert.NoError(t, err)
+	defer itr.Release()
+	cnt := 0
+	for itr.Next() {
+		cnt++
+	}
+	return cnt
+}
+
+func walkReferences(t *testing.T, dbase db.Database, release bool, hash []byte) int {
+	tracker, err := NewReferenceTracker(dbase, release)
+	assert.NoError(t, err)
+	ctx := merkle.NewCopyContext(dbase, tracker)
+	NewImmutable(ctx.Builder().Database(), hash).Resolve(ctx.Builder())
+	assert.NoError(t, ctx.Run())
+	deleted, err := tracker.Commit()
+	assert.NoError(t, err)
+	return deleted
+}
+
+func checkTrie(t *testing.T, dbase db.Database, hash []byte, values map[string]string) {
+	snapshot := NewImmutable(dbase, hash)
+	for k, v := range values {
+		value, err := snapshot.Get([]byte(k))
+		if assert.NoError(t, err, "key=%s", k) {
+			assert.Equal(t, v, string(value), "key=%s", k)
+		}
+	}
+}
+
+func TestReferenceTracker(t *testing.T) {
+	dbase := db.NewMapDB()
+
+	values := map[string]string{}
+	trie := NewMutable(dbase, nil)
+	for i := 0; i < 100; i++ {
+		k, v := fmt.Sprintf("key%d", i), fmt.Sprintf("value%032d", i)
+		values[k] = v
+		_, err := trie.Set([]byte(k), []byte(v))
+		assert.NoError(t, err)
+	}
+	ss1 := trie.GetSnapshot()
+	assert.NoError(t, ss1.Flush())
+	values1 := values
+
+	values2 := map[string]string{}
+	for k, v := range values1 {
+		values2[k] = v
+	}
+	for i := 0; i < 10; i++ {
+		k, v := fmt.Sprintf("key%d", i*7), fmt.Sprintf("changed%032d", i)
+		values2[k] = v
+		_, err := trie.Set([]byte(k), []byte(v))
+		assert.NoError(t, err)
+	}
+	ss2 := trie.GetSnapshot()
+	assert.NoError(t, ss2.Flush())
+
+	nodes := countEntries(t, dbase, db.MerkleTrie)
+
+	assert.Equal(t, 0, walkReferences(t, dbase, false, ss1.Hash()))
+	assert.Equal(t, 0, walkReferences(t, dbase, false, ss2.Hash()))
+	assert.Equal(t, nodes, countEntries(t, dbase, db.ReferenceCount))
+
+	// release the old one, then only the nodes of the new one are left
+	deleted := walkReferences(t, dbase, true, ss1.Hash())
+	assert.True(t, deleted > 0)
+	assert.Equal(t, nodes-deleted, countEntries(t, dbase, db.MerkleTrie))
+	checkTrie(t, dbase, ss2.Hash(), values2)
+
+	// referenced twice, so it's kept after the first release
+	assert.Equal(t, 0, walkReferences(t, dbase, false, ss2.Hash()))
+	assert.Equal(t, 0, walkReferences(t, dbase, true, ss2.Hash()))
+	checkTrie(t, dbase, ss2.Hash(), values2)
+
+	assert.Equal(t, nodes-deleted, walkReferences(t, dbase, true, ss2.Hash()))
+	assert.Equal(t, 0, countEntries(t, dbase, db.MerkleTrie))
+	assert.Equal(t, 0, countEntries(t, dbase, db.ReferenceCount))
+}
+
+func TestClearReferences(t *testing.T) {
+	dbase := db.NewMapDB()
+	trie := NewMutable(dbase, nil)
+	for i := 0; i < 3000; i++ {
+		_, err := trie.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%032d", i)))
+		assert.NoError(t, err)
+	}
+	ss := trie.GetSnapshot()
+	assert.NoError(t, ss.Flush())
+
+	walkReferences(t, dbase, false, ss.Hash())
+	assert.True(t, countEntries(t, dbase, db.ReferenceCount) > 1000)
+
+	assert.NoError(t, ClearReferences(dbase))
+	assert.Equal(t, 0, countEntries(t, dbase, db.ReferenceCount))
+
+	// nothing is deleted without references
+	assert.Equal(t, 0, walkReferences(t, dbase, true, ss.Hash()))
+	assert.True(t, countEntries(t, dbase, db.MerkleTrie) > 0)
+}
@@ -12,6 +12,7 @@ type word = uint64
 
 const wordBits = 64
 
+//codec:generate
 type BitArray struct {
 	NumBits int
 	Words   []word
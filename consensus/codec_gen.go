@@ -0,0 +1,571 @@
+// Code generated by codecgen; DO NOT EDIT.
+
+package consensus
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+)
+
+func (o *BitArray) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.NumBits)); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.Words); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *BitArray) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int); err == nil {
+		o.NumBits = int(v)
+	} else if err == io.EOF {
+		o.NumBits = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Words); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.Words = nil
+	}
+	return nil
+}
+
+func (o *BlockPartMessage) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Height)); err != nil {
+		return err
+	}
+	if err := codec.EncodeUint(e2, uint64(o.Index)); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.BlockPart); err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Nonce)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *BlockPartMessage) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int64); err == nil {
+		o.Height = v
+	} else if err == io.EOF {
+		o.Height = 0
+	} else {
+		return err
+	}
+	if v, err := codec.DecodeUint(d2, reflect.Uint16); err == nil {
+		o.Index = uint16(v)
+	} else if err == io.EOF {
+		o.Index = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.BlockPart); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.BlockPart = nil
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int32); err == nil {
+		o.Nonce = int32(v)
+	} else if err == io.EOF {
+		o.Nonce = 0
+	} else {
+		return err
+	}
+	return nil
+}
+
+func (o *PartSetID) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeUint(e2, uint64(o.Count)); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.Hash); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *PartSetID) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeUint(d2, reflect.Uint16); err == nil {
+		o.Count = uint16(v)
+	} else if err == io.EOF {
+		o.Count = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Hash); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.Hash = nil
+	}
+	return nil
+}
+
+func (o *PartSetIDAndAppData) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeUint(e2, uint64(o.CountWord)); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.Hash); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *PartSetIDAndAppData) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeUint(d2, reflect.Uint64); err == nil {
+		o.CountWord = v
+	} else if err == io.EOF {
+		o.CountWord = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Hash); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.Hash = nil
+	}
+	return nil
+}
+
+func (o *RoundStateMessage) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.peerRoundState._HR.Height)); err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.peerRoundState._HR.Round)); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.peerRoundState.PrevotesMask); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.peerRoundState.PrecommitsMask); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.peerRoundState.BlockPartsMask); err != nil {
+		return err
+	}
+	if err := codec.EncodeBool(e2, bool(o.peerRoundState.Sync)); err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Timestamp)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *RoundStateMessage) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int64); err == nil {
+		o.peerRoundState._HR.Height = v
+	} else if err == io.EOF {
+		o.peerRoundState._HR.Height = 0
+	} else {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int32); err == nil {
+		o.peerRoundState._HR.Round = int32(v)
+	} else if err == io.EOF {
+		o.peerRoundState._HR.Round = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.peerRoundState.PrevotesMask); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.peerRoundState.PrevotesMask = nil
+	}
+	if err := codec.DecodeField(d2, &o.peerRoundState.PrecommitsMask); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.peerRoundState.PrecommitsMask = nil
+	}
+	if err := codec.DecodeField(d2, &o.peerRoundState.BlockPartsMask); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.peerRoundState.BlockPartsMask = nil
+	}
+	if v, err := codec.DecodeBool(d2); err == nil {
+		o.peerRoundState.Sync = v
+	} else if err == io.EOF {
+		o.peerRoundState.Sync = false
+	} else {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int64); err == nil {
+		o.Timestamp = v
+	} else if err == io.EOF {
+		o.Timestamp = 0
+	} else {
+		return err
+	}
+	return nil
+}
+
+func (o *VoteItem) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.PrototypeIndex)); err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Timestamp)); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.Signature); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.NTSDProofParts); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *VoteItem) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int16); err == nil {
+		o.PrototypeIndex = int16(v)
+	} else if err == io.EOF {
+		o.PrototypeIndex = 0
+	} else {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int64); err == nil {
+		o.Timestamp = v
+	} else if err == io.EOF {
+		o.Timestamp = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Signature); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.Signature = common.Signature{}
+	}
+	if err := codec.DecodeField(d2, &o.NTSDProofParts); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.NTSDProofParts = nil
+	}
+	return nil
+}
+
+func (o *VoteList) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.Prototypes); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.VoteItems); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *VoteList) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Prototypes); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.Prototypes = nil
+	}
+	if err := codec.DecodeField(d2, &o.VoteItems); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.VoteItems = nil
+	}
+	return nil
+}
+
+func (o *VoteListMessage) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.VoteList); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *VoteListMessage) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.VoteList); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.VoteList = nil
+	}
+	return nil
+}
+
+func (o *blockCommitVoteItem) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Timestamp)); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.Signature); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *blockCommitVoteItem) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int64); err == nil {
+		o.Timestamp = v
+	} else if err == io.EOF {
+		o.Timestamp = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Signature); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.Signature = common.Signature{}
+	}
+	return nil
+}
+
+func (o *blockCommitVoteList) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.Round)); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.BlockPartSetIDAndAppData); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.Items); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *blockCommitVoteList) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int32); err == nil {
+		o.Round = int32(v)
+	} else if err == io.EOF {
+		o.Round = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.BlockPartSetIDAndAppData); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.BlockPartSetIDAndAppData = nil
+	}
+	if err := codec.DecodeField(d2, &o.Items); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.Items = nil
+	}
+	return nil
+}
+
+func (o *partBinary) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeUint(e2, uint64(o.Index)); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.Proof); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *partBinary) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeUint(d2, reflect.Uint16); err == nil {
+		o.Index = uint16(v)
+	} else if err == io.EOF {
+		o.Index = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.Proof); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.Proof = nil
+	}
+	return nil
+}
+
+func (o *voteBase) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.blockVoteBase._HR.Height)); err != nil {
+		return err
+	}
+	if err := codec.EncodeInt(e2, int64(o.blockVoteBase._HR.Round)); err != nil {
+		return err
+	}
+	if err := codec.EncodeUint(e2, uint64(o.blockVoteBase.Type)); err != nil {
+		return err
+	}
+	if err := e2.Encode(o.blockVoteBase.BlockID); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.blockVoteBase.BlockPartSetIDAndNTSVoteCount); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.NTSVoteBases); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *voteBase) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int64); err == nil {
+		o.blockVoteBase._HR.Height = v
+	} else if err == io.EOF {
+		o.blockVoteBase._HR.Height = 0
+	} else {
+		return err
+	}
+	if v, err := codec.DecodeInt(d2, reflect.Int32); err == nil {
+		o.blockVoteBase._HR.Round = int32(v)
+	} else if err == io.EOF {
+		o.blockVoteBase._HR.Round = 0
+	} else {
+		return err
+	}
+	if v, err := codec.DecodeUint(d2, reflect.Uint8); err == nil {
+		o.blockVoteBase.Type = VoteType(v)
+	} else if err == io.EOF {
+		o.blockVoteBase.Type = 0
+	} else {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.blockVoteBase.BlockID); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.blockVoteBase.BlockID = nil
+	}
+	if err := codec.DecodeField(d2, &o.blockVoteBase.BlockPartSetIDAndNTSVoteCount); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.blockVoteBase.BlockPartSetIDAndNTSVoteCount = nil
+	}
+	if err := codec.DecodeField(d2, &o.NTSVoteBases); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.NTSVoteBases = nil
+	}
+	return nil
+}
+
+func init() {
+	codec.RegisterGenerated(
+		(*BitArray)(nil),
+		(*BlockPartMessage)(nil),
+		(*PartSetID)(nil),
+		(*PartSetIDAndAppData)(nil),
+		(*RoundStateMessage)(nil),
+		(*VoteItem)(nil),
+		(*VoteList)(nil),
+		(*VoteListMessage)(nil),
+		(*blockCommitVoteItem)(nil),
+		(*blockCommitVoteList)(nil),
+		(*partBinary)(nil),
+		(*voteBase)(nil),
+	)
+}
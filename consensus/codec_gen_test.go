@@ -0,0 +1,40 @@
+package consensus
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/icon-project/goloop/common/codec/codectest"
+)
+
+var codecGenObjects = []func() interface{}{
+	func() interface{} { return new(BlockPartMessage) },
+	func() interface{} { return new(RoundStateMessage) },
+	func() interface{} { return new(VoteListMessage) },
+	func() interface{} { return new(PartSetIDAndAppData) },
+	func() interface{} { return new(PartSetID) },
+	func() interface{} { return new(partBinary) },
+	func() interface{} { return new(BitArray) },
+	func() interface{} { return new(blockCommitVoteItem) },
+	func() interface{} { return new(blockCommitVoteList) },
+	func() interface{} { return new(VoteItem) },
+	func() interface{} { return new(VoteList) },
+	func() interface{} { return new(voteBase) },
+}
+
+func TestCodecGen(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, newObj := range codecGenObjects {
+		for i := 0; i < 100; i++ {
+			obj := newObj()
+			codectest.Fill(r, obj)
+			if !codectest.AssertEquivalent(t, obj) {
+				break
+			}
+		}
+	}
+}
+
+func FuzzCodecGen(f *testing.F) {
+	codectest.Fuzz(f, codecGenObjects...)
+}
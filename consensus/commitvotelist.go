@@ -19,11 +19,13 @@ import (
 
 var vlCodec = codec.BC
 
+//codec:generate
 type blockCommitVoteItem struct {
 	Timestamp int64
 	Signature common.Signature
 }
 
+//codec:generate
 type blockCommitVoteList struct {
 	Round                    int32
 	BlockPartSetIDAndAppData *PartSetIDAndAppData
@@ -95,6 +95,7 @@ type consensus struct {
 	srcUID         []byte
 	bpmCache       bpmCache
 	timeoutPropose time.Duration
+	timeouts       timeouts
 	dsmLog         dsmLog
 
 	lastBlock          module.Block
@@ -119,7 +120,8 @@ type consensus struct {
 	pcmForLastBlock    module.BTPProofContextMap
 	nextPCM            module.BTPProofContextMap
 
-	timer *time.Timer
+	clock common.Clock
+	timer *common.Timer
 
 	// commit cache
 	commitCache *commitCache
@@ -131,6 +133,9 @@ type consensus struct {
 	metric *metric.ConsensusMetric
 
 	lastVoteData *LastVoteData
+
+	// step transitions of the current height for inspection
+	stepLog []stepTime
 }
 
 func NewConsensus(
@@ -173,6 +178,7 @@ func New(
 		dsmLog:       makeDSMLog(configDSMLogSize),
 		lastVoteData:   lastVoteData,
 		timeoutPropose: tmoPropose,
+		clock:          &common.GoTimeClock{},
 	}
 	cs.log = c.Logger().WithFields(log.Fields{
 		log.FieldKeyModule: "CS",
@@ -181,6 +187,20 @@ func New(
 	return cs
 }
 
+// SetClock replaces the clock used for timers and timestamps of votes. It
+// shall be called before Start.
+func (cs *consensus) SetClock(cl common.Clock) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	cs.clock = cl
+}
+
+func (cs *consensus) afterFunc(d time.Duration, f func()) *common.Timer {
+	timer := cs.clock.AfterFunc(d, f)
+	return &timer
+}
+
 func (cs *consensus) _resetForNewHeight(prevBlock module.Block, votes *voteSet) {
 	cs.height = prevBlock.Height() + 1
 	cs.lastBlock = prevBlock
@@ -212,6 +232,7 @@ func (cs *consensus) _resetForNewHeight(prevBlock module.Block, votes *voteSet)
 	}
 	cs.minimizeBlockGen = cs.c.ServiceManager().GetMinimizeBlockGen(cs.lastBlock.Result())
 	cs.roundLimit = int32(cs.c.ServiceManager().GetRoundLimit(cs.lastBlock.Result(), cs.validators.Len()))
+	cs.timeouts = cs.loadTimeouts()
 	cs.sentPatch = false
 	cs.lastVotes = votes
 	cs.hvs.reset(cs.validators.Len())
@@ -241,6 +262,12 @@ func (cs *consensus) _resetForNewRound(round int32) {
 	cs.hvs.removeLowerRoundExcept(cs.round-1, cs.lockedRound)
 	cs.log.Infof("enter round Height:%d Round:%d\n", cs.height, cs.round)
 	cs.metric.OnRound(cs.round)
+	cs.metric.OnTimeouts(
+		cs.timeouts.proposeFor(cs.round),
+		cs.timeouts.prevoteFor(cs.round),
+		cs.timeouts.precommitFor(cs.round),
+		cs.timeouts.newRoundFor(cs.round),
+	)
 	if cs.cancelBlockRequest != nil {
 		cs.cancelBlockRequest.Cancel()
 		cs.cancelBlockRequest = nil
@@ -256,7 +283,7 @@ func (cs *consensus) resetForNewRound(round int32) {
 func (cs *consensus) resetForNewStep(step step) {
 	cs.endStep()
 	if cs.step < stepPropose && step > stepPropose {
-		now := time.Now()
+		now := cs.clock.Now()
 		cs.nextProposeTime = now
 		cs.c.Regulator().OnPropose(now)
 	}
@@ -290,6 +317,7 @@ func (cs *consensus) beginStep(step step) {
 		cs.log.Panicf("bad step transition %v->%v\n", cs.step, step)
 	}
 	cs.step = step
+	cs.onStep(step)
 	cs.log.Debugf("enterStep %v\n", cs.hrs)
 }
 
@@ -515,6 +543,7 @@ func (cs *consensus) ReceiveVoteMessage(msg *VoteMessage, unicast bool) (int, er
 	if !added {
 		return -1, nil
 	}
+	cs.onVote(index, msg)
 	if !unicast {
 		cs.consumedNonunicast = true
 	}
@@ -627,16 +656,16 @@ func (cs *consensus) processPrefetchItems() {
 func (cs *consensus) enterPropose() {
 	cs.resetForNewStep(stepPropose)
 
-	now := time.Now()
+	now := cs.clock.Now()
 	if int(cs.round) > cs.validators.Len()*configRoundTimeoutThresholdFactor {
-		cs.nextProposeTime = now.Add(timeoutNewRound)
+		cs.nextProposeTime = now.Add(cs.timeouts.newRoundFor(cs.round))
 	} else {
 		cs.nextProposeTime = now
 	}
 	cs.c.Regulator().OnPropose(now)
 
 	hrs := cs.hrs
-	cs.timer = time.AfterFunc(cs.timeoutPropose, func() {
+	cs.timer = cs.afterFunc(cs.timeouts.proposeFor(cs.round), func() {
 		cs.mutex.Lock()
 		defer cs.mutex.Unlock()
 
@@ -822,7 +851,7 @@ func (cs *consensus) enterPrevoteWait() {
 		cs.enterPrecommit()
 	} else {
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(timeoutPrevote, func() {
+		cs.timer = cs.afterFunc(cs.timeouts.prevoteFor(cs.round), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
@@ -922,7 +951,7 @@ func (cs *consensus) enterPrecommitWait() {
 	} else {
 		cs.log.Traceln("enterPrecommitWait: start timer")
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(timeoutPrecommit, func() {
+		cs.timer = cs.afterFunc(cs.timeouts.precommitFor(cs.round), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
@@ -991,7 +1020,7 @@ func (cs *consensus) enterCommit(precommits *voteSet, partSetID *PartSetID, roun
 		cs.log.Errorf("fail to sync WAL: cs.enterCommit: %+v\n", err)
 	}
 
-	cs.nextProposeTime = time.Now()
+	cs.nextProposeTime = cs.clock.Now()
 	if cs.consumedNonunicast || cs.validators.Len() == 1 {
 		if cs.timestamper == nil {
 			cs.nextProposeTime = cs.nextProposeTime.Add(cs.c.Regulator().CommitTimeout())
@@ -1020,10 +1049,10 @@ func (cs *consensus) enterNewRound() {
 	cs.resetForNewRound(cs.round + 1)
 	cs.notifySyncer()
 
-	now := time.Now()
+	now := cs.clock.Now()
 	if cs.nextProposeTime.After(now) {
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(cs.nextProposeTime.Sub(now), func() {
+		cs.timer = cs.afterFunc(cs.nextProposeTime.Sub(now), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
@@ -1072,10 +1101,10 @@ func (cs *consensus) enterNewHeight() {
 	cs.resetForNewHeight(cs.currentBlockParts.validatedBlock, votes)
 	cs.notifySyncer()
 
-	now := time.Now()
+	now := cs.clock.Now()
 	if cs.nextProposeTime.After(now) {
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(cs.nextProposeTime.Sub(now), func() {
+		cs.timer = cs.afterFunc(cs.nextProposeTime.Sub(now), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
@@ -1186,7 +1215,7 @@ func (cs *consensus) voteTimestamp() int64 {
 	} else if cs.currentBlockParts.HasBlockData() {
 		timestamp = cs.currentBlockParts.block.Timestamp() + blockIota
 	}
-	now := common.UnixMicroFromTime(time.Now())
+	now := common.UnixMicroFromTime(cs.clock.Now())
 	if now > timestamp {
 		timestamp = now
 	}
@@ -1850,7 +1879,7 @@ func (cs *consensus) Start() error {
 
 	cs.started = true
 	cs.log.Infof("Start consensus wallet:%v", common.HexPre(cs.c.Wallet().Address().ID()))
-	cs.syncer, err = newSyncer(cs, cs.log, cs.c.NetworkManager(), cs.c.BlockManager(), &cs.mutex, cs.c.Wallet().Address())
+	cs.syncer, err = newSyncer(cs, cs.log, cs.c.NetworkManager(), cs.c.BlockManager(), &cs.mutex, cs.c.Wallet().Address(), cs.clock)
 	if err != nil {
 		return err
 	}
@@ -31,6 +31,7 @@ import (
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/consensus/fastsync"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/notify"
 	"github.com/icon-project/goloop/service/platform/basic"
 	"github.com/icon-project/goloop/test"
 )
@@ -1406,3 +1407,59 @@ func TestConsensus_DSR(t *testing.T) {
 	_, _ = cs.OnReceive(consensus.ProtoVote, codec.MustMarshalToBytes(pc1), peer)
 	assert.True(reported)
 }
+
+func TestConsensus_InspectRoundState(t *testing.T) {
+	f := test.NewNode(t)
+	defer f.Close()
+
+	h := make([]*test.SimplePeerHandler, 3)
+	for i := 0; i < len(h); i++ {
+		_, h[i] = f.NM.NewPeerFor(module.ProtoConsensus)
+	}
+
+	f.ProposeImportFinalizeBlockWithTX(
+		consensus.NewEmptyCommitVoteList(),
+		test.NewTx().SetValidatorsAddresser(
+			h[0], h[1], h[2], f.Chain.Wallet(),
+		).String(),
+	)
+	f.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	inspector, ok := f.CS.(consensus.Inspector)
+	assert.True(t, ok)
+	assert.Nil(t, inspector.InspectRoundState())
+
+	sub := notify.Subscribe(f.Chain.CID(), notify.TopicConsensus, 64)
+	defer sub.Close()
+
+	err := f.CS.Start()
+	assert.NoError(t, err)
+
+	var pm consensus.ProposalMessage
+	h[0].Receive(consensus.ProtoProposal, nil, &pm)
+
+	// wait for the prevote of the node itself
+	for {
+		ev := (<-sub.C).(*consensus.Event)
+		if ev.Type == consensus.EventVote && ev.Validator.Equal(f.Chain.Wallet().Address()) {
+			assert.EqualValues(t, 3, ev.Height)
+			assert.Equal(t, consensus.VoteTypePrevote.String(), ev.VoteType)
+			assert.NotNil(t, ev.BlockID)
+			break
+		}
+	}
+
+	rs := inspector.InspectRoundState()
+	assert.EqualValues(t, 3, rs.Height)
+	assert.EqualValues(t, 0, rs.Round)
+	assert.Len(t, rs.Validators, 4)
+	assert.True(t, rs.IsProposer)
+	assert.True(t, rs.Proposer.Equal(f.Chain.Wallet().Address()))
+	assert.NotNil(t, rs.Proposal)
+	assert.True(t, rs.Proposal.Complete)
+	assert.Len(t, rs.Rounds, 1)
+	assert.Len(t, rs.Rounds[0].Votes, 4)
+	assert.True(t, rs.Rounds[0].Prevotes >= 1)
+	assert.NotEmpty(t, rs.Steps)
+	assert.Equal(t, "stepNewHeight", rs.Steps[0].Step)
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"sort"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/notify"
+)
+
+const configStepLogCap = 256
+
+const (
+	EventStep = "step"
+	EventVote = "vote"
+)
+
+// Inspector is implemented by consensus engines exposing the round state.
+type Inspector interface {
+	// InspectRoundState returns the round state of the current height.
+	// It returns nil if the engine is not running.
+	InspectRoundState() *RoundState
+}
+
+type VoteInfo struct {
+	BlockID   common.HexBytes `json:"blockID,omitempty"`
+	Nil       bool            `json:"nil,omitempty"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+type ValidatorVotes struct {
+	Address   *common.Address `json:"address"`
+	Prevote   *VoteInfo       `json:"prevote"`
+	Precommit *VoteInfo       `json:"precommit"`
+}
+
+type RoundVotes struct {
+	Round      int32             `json:"round"`
+	Prevotes   int               `json:"prevotes"`
+	Precommits int               `json:"precommits"`
+	Votes      []*ValidatorVotes `json:"votes"`
+}
+
+type ProposalInfo struct {
+	PartSetHash common.HexBytes `json:"partSetHash"`
+	Parts       int             `json:"parts"`
+	Received    int             `json:"received"`
+	Complete    bool            `json:"complete"`
+}
+
+type StepTiming struct {
+	Round   int32     `json:"round"`
+	Step    string    `json:"step"`
+	Start   time.Time `json:"start"`
+	Elapsed int64     `json:"elapsedMs"`
+}
+
+type RoundState struct {
+	Height           int64                     `json:"height"`
+	Round            int32                     `json:"round"`
+	Step             string                    `json:"step"`
+	Proposer         *common.Address           `json:"proposer"`
+	IsProposer       bool                      `json:"isProposer"`
+	LockedRound      int32                     `json:"lockedRound"`
+	ProposalPOLRound int32                     `json:"proposalPOLRound"`
+	CommitRound      int32                     `json:"commitRound"`
+	Syncing          bool                      `json:"syncing"`
+	Timeouts         *module.ConsensusTimeouts `json:"timeouts"`
+	Proposal         *ProposalInfo             `json:"proposal"`
+	Validators       []*common.Address         `json:"validators"`
+	Rounds           []*RoundVotes             `json:"rounds"`
+	Steps            []*StepTiming             `json:"steps"`
+}
+
+// Event is published to notify.TopicConsensus on step transitions and
+// arrivals of votes for the current height.
+type Event struct {
+	Type      string          `json:"type"`
+	Height    int64           `json:"height"`
+	Round     int32           `json:"round"`
+	Step      string          `json:"step,omitempty"`
+	VoteType  string          `json:"voteType,omitempty"`
+	Validator *common.Address `json:"validator,omitempty"`
+	Index     *int            `json:"index,omitempty"`
+	BlockID   common.HexBytes `json:"blockID,omitempty"`
+	Time      time.Time       `json:"time"`
+}
+
+type stepTime struct {
+	round int32
+	step  step
+	start time.Time
+}
+
+func (cs *consensus) onStep(step step) {
+	now := cs.clock.Now()
+	if step == stepNewHeight || len(cs.stepLog) >= configStepLogCap {
+		cs.stepLog = cs.stepLog[:0]
+	}
+	cs.stepLog = append(cs.stepLog, stepTime{cs.round, step, now})
+
+	if notify.HasSubscriber(cs.c.CID(), notify.TopicConsensus) {
+		notify.Publish(cs.c.CID(), notify.TopicConsensus, &Event{
+			Type:   EventStep,
+			Height: cs.height,
+			Round:  cs.round,
+			Step:   step.String(),
+			Time:   now,
+		})
+	}
+}
+
+func (cs *consensus) onVote(index int, msg *VoteMessage) {
+	if !notify.HasSubscriber(cs.c.CID(), notify.TopicConsensus) {
+		return
+	}
+	ev := &Event{
+		Type:      EventVote,
+		Height:    msg.Height,
+		Round:     msg.Round,
+		VoteType:  msg.Type.String(),
+		Validator: common.AddressToPtr(msg.address()),
+		Index:     &index,
+		Time:      cs.clock.Now(),
+	}
+	if msg.BlockPartSetIDAndNTSVoteCount != nil {
+		ev.BlockID = msg.BlockID
+	}
+	notify.Publish(cs.c.CID(), notify.TopicConsensus, ev)
+}
+
+func voteInfoOf(msg *VoteMessage) *VoteInfo {
+	if msg == nil {
+		return nil
+	}
+	vi := &VoteInfo{Timestamp: msg.Timestamp}
+	if msg.BlockPartSetIDAndNTSVoteCount != nil {
+		vi.BlockID = msg.BlockID
+	} else {
+		vi.Nil = true
+	}
+	return vi
+}
+
+func (cs *consensus) InspectRoundState() *RoundState {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if !cs.started || cs.validators == nil {
+		return nil
+	}
+
+	rs := &RoundState{
+		Height:           cs.height,
+		Round:            cs.round,
+		Step:             cs.step.String(),
+		IsProposer:       cs.isProposer(),
+		LockedRound:      cs.lockedRound,
+		ProposalPOLRound: cs.proposalPOLRound,
+		CommitRound:      cs.commitRound,
+		Syncing:          cs.syncing,
+		Timeouts:         cs.timeouts.viewFor(cs.round),
+	}
+	nv := cs.validators.Len()
+	for i := 0; i < nv; i++ {
+		v, _ := cs.validators.Get(i)
+		rs.Validators = append(rs.Validators, common.AddressToPtr(v.Address()))
+	}
+	if idx := cs.getProposerIndex(cs.height, cs.round); idx >= 0 && idx < nv {
+		rs.Proposer = rs.Validators[idx]
+	}
+
+	if bp := cs.currentBlockParts; bp.PartSet != nil {
+		pi := &ProposalInfo{
+			PartSetHash: bp.ID().Hash,
+			Parts:       bp.Parts(),
+			Complete:    bp.IsComplete(),
+		}
+		mask := bp.GetMask()
+		for i := 0; i < mask.Len(); i++ {
+			if mask.Get(i) {
+				pi.Received++
+			}
+		}
+		rs.Proposal = pi
+	}
+
+	rounds := make([]int32, 0, len(cs.hvs._votes))
+	for round := range cs.hvs._votes {
+		rounds = append(rounds, round)
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i] < rounds[j]
+	})
+	for _, round := range rounds {
+		votes := cs.hvs._votes[round]
+		rv := &RoundVotes{Round: round}
+		for i := 0; i < nv; i++ {
+			vv := &ValidatorVotes{Address: rs.Validators[i]}
+			if vs := votes[VoteTypePrevote]; vs != nil && i < len(vs.msgs) {
+				vv.Prevote = voteInfoOf(vs.msgs[i])
+			}
+			if vs := votes[VoteTypePrecommit]; vs != nil && i < len(vs.msgs) {
+				vv.Precommit = voteInfoOf(vs.msgs[i])
+			}
+			if vv.Prevote != nil {
+				rv.Prevotes++
+			}
+			if vv.Precommit != nil {
+				rv.Precommits++
+			}
+			rv.Votes = append(rv.Votes, vv)
+		}
+		rs.Rounds = append(rs.Rounds, rv)
+	}
+
+	now := cs.clock.Now()
+	for i, st := range cs.stepLog {
+		end := now
+		if i+1 < len(cs.stepLog) {
+			end = cs.stepLog[i+1].start
+		}
+		rs.Steps = append(rs.Steps, &StepTiming{
+			Round:   st.round,
+			Step:    st.step.String(),
+			Start:   st.start,
+			Elapsed: end.Sub(st.start).Milliseconds(),
+		})
+	}
+	return rs
+}
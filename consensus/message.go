@@ -12,6 +12,8 @@ import (
 	"github.com/icon-project/goloop/module"
 )
 
+//go:generate go run github.com/icon-project/goloop/cmd/codecgen
+
 var msgCodec = codec.BC
 
 const (
@@ -215,6 +217,7 @@ func (msg *ProposalMessage) String() string {
 	return fmt.Sprintf("ProposalMessage{H:%d R:%d BPSID:%v Addr:%v}", msg.Height, msg.Round, msg.BlockPartSetID, common.HexPre(id))
 }
 
+//codec:generate
 type BlockPartMessage struct {
 	// V1 Fields
 	// for debugging
@@ -536,6 +539,7 @@ func (prs peerRoundState) String() string {
 	return fmt.Sprintf("PeerRoundState{H:%v R:%v PV:%v PC:%v BP:%v Sync:%t}", prs.Height, prs.Round, prs.PrevotesMask, prs.PrecommitsMask, prs.BlockPartsMask, prs.Sync)
 }
 
+//codec:generate
 type RoundStateMessage struct {
 	peerRoundState
 	Timestamp int64
@@ -577,6 +581,7 @@ func (msg *RoundStateMessage) subprotocol() uint16 {
 	return uint16(ProtoRoundState)
 }
 
+//codec:generate
 type VoteListMessage struct {
 	VoteList *VoteList
 }
@@ -40,6 +40,7 @@ const (
 	countMask  = (1 << countWidth) - 1
 )
 
+//codec:generate
 type PartSetIDAndAppData struct {
 	// CountWord: MSB AppData(48) Count(16)
 	// Use bitfield not to break existing message protocol
@@ -64,6 +65,7 @@ func (ida *PartSetIDAndAppData) AppData() uint64 {
 	return uint64(ida.CountWord >> countWidth)
 }
 
+//codec:generate
 type PartSetID struct {
 	Count uint16
 	Hash  []byte
@@ -263,6 +265,7 @@ func NewPartSetFromID(h *PartSetID) PartSet {
 	}
 }
 
+//codec:generate
 type partBinary struct {
 	Index uint16
 	Proof [][]byte
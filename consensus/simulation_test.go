@@ -0,0 +1,132 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/test"
+)
+
+var lossyLink = test.LinkConfig{
+	Delay:    10 * time.Millisecond,
+	Jitter:   30 * time.Millisecond,
+	DropRate: 0.05,
+}
+
+func TestSimulation_LossyNetwork(t *testing.T) {
+	s := test.NewSimulation(t, 4, test.UseSeed(7), test.UseDefaultLink(lossyLink))
+	defer s.Close()
+
+	s.Start()
+	s.AssertLiveness(3, time.Minute)
+	s.AssertSafety()
+	delivered, dropped := s.Stats()
+	assert.True(t, delivered > 0)
+	assert.True(t, dropped > 0)
+}
+
+func TestSimulation_PartitionAndHeal(t *testing.T) {
+	s := test.NewSimulation(t, 4, test.UseDefaultLink(lossyLink))
+	defer s.Close()
+
+	s.Start()
+	s.AssertLiveness(2, time.Minute)
+
+	s.Partition([]int{0, 1}, []int{2, 3})
+	s.Run(time.Second)
+	h := s.MinHeight()
+	s.Run(10 * time.Second)
+	for i := range s.Nodes {
+		assert.LessOrEqual(t, s.Height(i), h+1)
+	}
+	s.AssertSafety()
+
+	s.Heal()
+	s.AssertLiveness(h+2, time.Minute)
+	s.AssertSafety()
+}
+
+func TestSimulation_CrashAndRecover(t *testing.T) {
+	s := test.NewSimulation(t, 4)
+	defer s.Close()
+
+	s.Start()
+	s.AssertLiveness(2, time.Minute)
+
+	s.Crash(3)
+	s.AssertLiveness(s.MinHeight()+3, time.Minute)
+
+	s.Recover(3)
+	s.AssertLiveness(s.MinHeight()+2, time.Minute)
+	s.AssertSafety()
+}
+
+func assertReportedBy(t *testing.T, s *test.Simulation, signer int, dst string) {
+	reports := s.DoubleSignReports()
+	if !assert.NotEmpty(t, reports) {
+		return
+	}
+	for _, r := range reports {
+		assert.NotEqual(t, signer, r.Reporter)
+		for _, d := range r.Data {
+			assert.Equal(t, dst, d.Type())
+			assert.Equal(t, signer, s.IndexOfSigner(d.Signer()))
+		}
+	}
+}
+
+func TestSimulation_DoubleVote(t *testing.T) {
+	s := test.NewSimulation(t, 4)
+	defer s.Close()
+
+	s.SetBehavior(1, test.BehaviorDoubleVote)
+	s.Start()
+	s.AssertLiveness(3, time.Minute)
+	s.AssertSafety()
+	assertReportedBy(t, s, 1, module.DSTVote)
+}
+
+func TestSimulation_EquivocateProposal(t *testing.T) {
+	s := test.NewSimulation(t, 4)
+	defer s.Close()
+
+	s.Start()
+	s.AssertLiveness(1, time.Minute)
+
+	// let the proposer of a height no node has started equivocate
+	var h int64
+	for i := range s.Nodes {
+		if hi := s.Height(i); hi > h {
+			h = hi
+		}
+	}
+	blk, err := s.Nodes[0].BM.GetBlockByHeight(s.MinHeight())
+	assert.NoError(t, err)
+	vl := blk.NextValidators()
+	v, _ := vl.Get(int((h + 2) % int64(vl.Len())))
+	p := s.IndexOfSigner(v.Address().ID())
+	s.SetBehavior(p, test.BehaviorEquivocateProposal)
+
+	s.AssertLiveness(h+2, time.Minute)
+	s.AssertSafety()
+	assertReportedBy(t, s, p, module.DSTProposal)
+}
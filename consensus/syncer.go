@@ -189,7 +189,7 @@ func (p *peer) sync() {
 			p.stopped <- struct{}{}
 			break
 		}
-		now := time.Now()
+		now := p.clock.Now()
 		if nextSendTime != nil && now.Before(*nextSendTime) {
 			p.mutex.Unlock()
 			p.log.Tracef("peer.now=%v nextSendTime=%v\n", now.Format(time.StampMicro), nextSendTime.Format(time.StampMicro))
@@ -224,7 +224,7 @@ func (p *peer) sync() {
 		waitTime := nextSendTime.Sub(now)
 		p.log.Tracef("msg size=%v delta=%v waitTime=%v\n", len(msgBS), delta, waitTime)
 		if waitTime > time.Duration(0) {
-			time.AfterFunc(waitTime, func() {
+			p.clock.AfterFunc(waitTime, func() {
 				p.wakeUp()
 			})
 		} else {
@@ -256,16 +256,17 @@ type syncer struct {
 	mutex  *common.Mutex
 	addr   module.Address
 	fsm    fastsync.Manager
+	clock  common.Clock
 
 	ph            module.ProtocolHandler
 	peers         []*peer
-	timer         *time.Timer
+	timer         *common.Timer
 	lastSendTime  time.Time
 	running       bool
 	fetchCanceler func() bool
 }
 
-func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.BlockManager, mutex *common.Mutex, addr module.Address) (Syncer, error) {
+func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.BlockManager, mutex *common.Mutex, addr module.Address, clock common.Clock) (Syncer, error) {
 	fsm, err := fastsync.NewManager(nm, bm, e, logger)
 	if err != nil {
 		return nil, err
@@ -279,6 +280,7 @@ func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.
 		mutex:  mutex,
 		addr:   addr,
 		fsm:    fsm,
+		clock:  clock,
 	}, nil
 }
 
@@ -441,7 +443,7 @@ func (s *syncer) doSendRoundStateMessage(id module.PeerID) {
 
 func (s *syncer) sendRoundStateMessage() {
 	s.doSendRoundStateMessage(nil)
-	s.lastSendTime = time.Now()
+	s.lastSendTime = s.clock.Now()
 	if s.timer != nil {
 		s.timer.Stop()
 		s.timer = nil
@@ -451,8 +453,8 @@ func (s *syncer) sendRoundStateMessage() {
 		return
 	}
 
-	var timer *time.Timer
-	timer = time.AfterFunc(configRoundStateMessageInterval, func() {
+	var timer *common.Timer
+	t := s.clock.AfterFunc(configRoundStateMessageInterval, func() {
 		s.mutex.Lock()
 		defer s.mutex.Unlock()
 
@@ -462,6 +464,7 @@ func (s *syncer) sendRoundStateMessage() {
 
 		s.sendRoundStateMessage()
 	})
+	timer = &t
 	s.timer = timer
 }
 
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"time"
+
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configTimeoutBackoffMax    = time.Second * 30
+	configTimeoutExpShiftLimit = 16
+)
+
+// timeouts is the timeout configuration of a height. Timeouts of a round
+// are derived from base timeouts according to backoff.
+type timeouts struct {
+	propose   time.Duration
+	prevote   time.Duration
+	precommit time.Duration
+	newRound  time.Duration
+	backoff   string
+	delta     time.Duration
+	max       time.Duration
+}
+
+func durationOf(ms int64, def time.Duration) time.Duration {
+	if ms <= 0 {
+		return def
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func makeTimeouts(cfg *module.ConsensusTimeouts, defPropose time.Duration) timeouts {
+	if cfg == nil {
+		cfg = &module.ConsensusTimeouts{}
+	}
+	t := timeouts{
+		propose:   durationOf(cfg.Propose, defPropose),
+		prevote:   durationOf(cfg.Prevote, timeoutPrevote),
+		precommit: durationOf(cfg.Precommit, timeoutPrecommit),
+		newRound:  durationOf(cfg.NewRound, timeoutNewRound),
+		delta:     durationOf(cfg.Delta, 0),
+		max:       durationOf(cfg.Max, configTimeoutBackoffMax),
+	}
+	switch cfg.Backoff {
+	case module.TimeoutBackoffLinear, module.TimeoutBackoffExponential:
+		t.backoff = cfg.Backoff
+	}
+	return t
+}
+
+// forRound returns the timeout of the round for the base timeout.
+func (t *timeouts) forRound(base time.Duration, round int32) time.Duration {
+	if round <= 0 || t.backoff == module.TimeoutBackoffNone {
+		return base
+	}
+	var d time.Duration
+	switch t.backoff {
+	case module.TimeoutBackoffLinear:
+		delta := t.delta
+		if delta == 0 {
+			delta = base
+		}
+		d = base + delta*time.Duration(round)
+	case module.TimeoutBackoffExponential:
+		shift := round
+		if shift > configTimeoutExpShiftLimit {
+			shift = configTimeoutExpShiftLimit
+		}
+		d = base << uint(shift)
+	}
+	if d > t.max {
+		d = t.max
+	}
+	if d < base {
+		d = base
+	}
+	return d
+}
+
+func (t *timeouts) proposeFor(round int32) time.Duration {
+	return t.forRound(t.propose, round)
+}
+
+func (t *timeouts) prevoteFor(round int32) time.Duration {
+	return t.forRound(t.prevote, round)
+}
+
+func (t *timeouts) precommitFor(round int32) time.Duration {
+	return t.forRound(t.precommit, round)
+}
+
+func (t *timeouts) newRoundFor(round int32) time.Duration {
+	return t.forRound(t.newRound, round)
+}
+
+// viewFor returns effective timeouts of the round.
+func (t *timeouts) viewFor(round int32) *module.ConsensusTimeouts {
+	return &module.ConsensusTimeouts{
+		Propose:   t.proposeFor(round).Milliseconds(),
+		Prevote:   t.prevoteFor(round).Milliseconds(),
+		Precommit: t.precommitFor(round).Milliseconds(),
+		NewRound:  t.newRoundFor(round).Milliseconds(),
+		Backoff:   t.backoff,
+		Delta:     t.delta.Milliseconds(),
+		Max:       t.max.Milliseconds(),
+	}
+}
+
+// loadTimeouts returns timeouts for the current height. Timeouts governed
+// by the state of the last block take precedence over the configuration of
+// the chain.
+func (cs *consensus) loadTimeouts() timeouts {
+	cfg := cs.c.ServiceManager().GetConsensusTimeouts(cs.lastBlock.Result())
+	if cfg == nil {
+		cfg = cs.c.ConsensusTimeouts()
+	}
+	return makeTimeouts(cfg, cs.timeoutPropose)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestTimeouts_Default(t *testing.T) {
+	to := makeTimeouts(nil, time.Second)
+	assert.Equal(t, time.Second, to.proposeFor(0))
+	assert.Equal(t, time.Second, to.proposeFor(5))
+	assert.Equal(t, timeoutPrevote, to.prevoteFor(3))
+	assert.Equal(t, timeoutPrecommit, to.precommitFor(3))
+	assert.Equal(t, timeoutNewRound, to.newRoundFor(3))
+}
+
+func TestTimeouts_Linear(t *testing.T) {
+	to := makeTimeouts(&module.ConsensusTimeouts{
+		Propose: 1000,
+		Prevote: 500,
+		Backoff: module.TimeoutBackoffLinear,
+		Delta:   200,
+		Max:     2000,
+	}, time.Second*3)
+	assert.Equal(t, time.Second, to.proposeFor(0))
+	assert.Equal(t, 1400*time.Millisecond, to.proposeFor(2))
+	assert.Equal(t, 2*time.Second, to.proposeFor(10))
+	assert.Equal(t, 900*time.Millisecond, to.prevoteFor(2))
+
+	view := to.viewFor(2)
+	assert.EqualValues(t, 1400, view.Propose)
+	assert.EqualValues(t, 900, view.Prevote)
+	assert.Equal(t, module.TimeoutBackoffLinear, view.Backoff)
+}
+
+func TestTimeouts_Exponential(t *testing.T) {
+	to := makeTimeouts(&module.ConsensusTimeouts{
+		Propose: 100,
+		Backoff: module.TimeoutBackoffExponential,
+	}, time.Second)
+	assert.Equal(t, 100*time.Millisecond, to.proposeFor(0))
+	assert.Equal(t, 400*time.Millisecond, to.proposeFor(2))
+	assert.Equal(t, configTimeoutBackoffMax, to.proposeFor(1000))
+}
+
+func TestConsensusTimeouts_Verify(t *testing.T) {
+	assert.NoError(t, (&module.ConsensusTimeouts{Propose: 100}).Verify())
+	assert.Error(t, (&module.ConsensusTimeouts{Propose: -1}).Verify())
+	assert.Error(t, (&module.ConsensusTimeouts{Backoff: "unknown"}).Verify())
+}
@@ -62,6 +62,7 @@ func (ntsVote ntsVoteBase) String() string {
 	)
 }
 
+//codec:generate
 type voteBase struct {
 	blockVoteBase
 	NTSVoteBases   []ntsVoteBase
@@ -7,6 +7,7 @@ import (
 	"github.com/icon-project/goloop/common/errors"
 )
 
+//codec:generate
 type VoteItem struct {
 	PrototypeIndex int16
 	Timestamp      int64
@@ -14,6 +15,7 @@ type VoteItem struct {
 	NTSDProofParts [][]byte
 }
 
+//codec:generate
 type VoteList struct {
 	Prototypes []voteBase
 	VoteItems  []VoteItem
@@ -349,6 +349,14 @@ type walReader struct {
 }
 
 func OpenWALForRead(id string) (WALReader, error) {
+	w, err := openWALForRead(id)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func openWALForRead(id string) (*walReader, error) {
 	wi, err := readWALInfo(id)
 	if err != nil {
 		return nil, err
@@ -434,7 +442,7 @@ func (w *walReader) CloseAndRepair() error {
 				}
 			}
 			for i := idx + 1; i <= w.wi.tailIdx; i++ {
-				if err := os.Remove(fileFor(w.id, idx)); err != nil {
+				if err := os.Remove(fileFor(w.id, i)); err != nil {
 					return errors.WithStack(err)
 				}
 			}
@@ -19,11 +19,15 @@ package consensus_test
 import (
 	"encoding/binary"
 	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/consensus"
 )
 
@@ -74,3 +78,74 @@ func TestWAL(t *testing.T) {
 	err = wr.Close()
 	assert.NoError(t, err)
 }
+
+func TestWAL_InspectAndTruncate(t *testing.T) {
+	assert := assert.New(t)
+	base, err := os.MkdirTemp("", "goloop-waltest")
+	assert.NoError(err)
+	defer func() {
+		_ = os.RemoveAll(base)
+	}()
+	id := filepath.Join(base, "round")
+	ww, err := consensus.OpenWALForWrite(id, &consensus.WALConfig{
+		FileLimit:            1,
+		HousekeepingInterval: time.Millisecond * 10,
+	})
+	assert.NoError(err)
+	w := wallet.New()
+	nid := codec.MustMarshalToBytes(1)
+	mww := consensus.WalMessageWriter{WALWriter: ww}
+	for r := int32(0); r < 4; r++ {
+		err = mww.WriteMessage(newSignedNilVote(w, consensus.VoteTypePrevote, 10, r, nid, 100))
+		assert.NoError(err)
+		assert.NoError(ww.Sync())
+		time.Sleep(time.Millisecond * 50)
+	}
+	assert.NoError(ww.Close())
+
+	files, err := filepath.Glob(id + "_*")
+	assert.NoError(err)
+	assert.True(len(files) > 1)
+	sort.Strings(files)
+	f, err := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_APPEND, 0600)
+	assert.NoError(err)
+	_, err = f.Write([]byte{0, 0, 0, 1, 0, 0, 0, 5, 1, 2})
+	assert.NoError(err)
+	assert.NoError(f.Close())
+
+	dump, err := consensus.DumpWAL(id, true)
+	assert.NoError(err)
+	assert.Equal("round", dump.ID)
+	assert.Equal(4, dump.Records)
+	assert.Equal(0, dump.Invalid)
+	assert.Equal(dump.ValidSize+10, dump.Size)
+	assert.NotEmpty(dump.Error)
+	for i, rec := range dump.Entries {
+		assert.Equal(i, rec.Index)
+		assert.Empty(rec.Error)
+		assert.EqualValues(i, rec.Message.Round)
+		assert.Equal(consensus.WALMessageVote, rec.Message.Type)
+		assert.True(rec.Message.Nil)
+		assert.True(rec.Message.Address.Equal(w.Address()))
+		assert.Equal(rec.Size, len(rec.Raw)+8)
+	}
+
+	s, err := consensus.TruncateWAL(id, -1)
+	assert.NoError(err)
+	assert.Equal(4, s.Records)
+	s, err = consensus.InspectWAL(id, false, nil)
+	assert.NoError(err)
+	assert.Equal(4, s.Records)
+	assert.Equal(s.Size, s.ValidSize)
+	assert.Empty(s.Error)
+
+	_, err = consensus.TruncateWAL(id, 5)
+	assert.Error(err)
+	s, err = consensus.TruncateWAL(id, 2)
+	assert.NoError(err)
+	assert.Equal(2, s.Records)
+	s, err = consensus.InspectWAL(id, false, nil)
+	assert.NoError(err)
+	assert.Equal(2, s.Records)
+	assert.Equal(s.Size, s.ValidSize)
+}
@@ -0,0 +1,376 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	WALMessageProposal   = "proposal"
+	WALMessageBlockPart  = "blockPart"
+	WALMessageVote       = "vote"
+	WALMessageRoundState = "roundState"
+	WALMessageVoteList   = "voteList"
+)
+
+// WALIDs returns the identifiers of WALs written by the consensus engine.
+func WALIDs() []string {
+	return []string{configRoundWALID, configLockWALID, configCommitWALID}
+}
+
+// IsWALID returns whether id is one of WALIDs.
+func IsWALID(id string) bool {
+	for _, wid := range WALIDs() {
+		if id == wid {
+			return true
+		}
+	}
+	return false
+}
+
+type WALPartSetID struct {
+	Count uint16          `json:"count"`
+	Hash  common.HexBytes `json:"hash"`
+}
+
+// WALMessage is a decoded view of a message stored in a WAL record.
+type WALMessage struct {
+	Type           string          `json:"type"`
+	Height         int64           `json:"height"`
+	Round          int32           `json:"round"`
+	Address        *common.Address `json:"address,omitempty"`
+	VoteType       string          `json:"voteType,omitempty"`
+	BlockID        common.HexBytes `json:"blockID,omitempty"`
+	Nil            bool            `json:"nil,omitempty"`
+	PartSetID      *WALPartSetID   `json:"partSetID,omitempty"`
+	POLRound       *int32          `json:"polRound,omitempty"`
+	Timestamp      int64           `json:"timestamp,omitempty"`
+	Index          *int            `json:"index,omitempty"`
+	Size           int             `json:"size,omitempty"`
+	PrevotesMask   string          `json:"prevotesMask,omitempty"`
+	PrecommitsMask string          `json:"precommitsMask,omitempty"`
+	BlockPartsMask string          `json:"blockPartsMask,omitempty"`
+	Sync           bool            `json:"sync,omitempty"`
+	Votes          []*WALMessage   `json:"votes,omitempty"`
+}
+
+func (m *WALMessage) String() string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%s H=%d R=%d", m.Type, m.Height, m.Round)
+	if m.VoteType != "" {
+		_, _ = fmt.Fprintf(&sb, " %s", m.VoteType)
+	}
+	if m.Address != nil {
+		_, _ = fmt.Fprintf(&sb, " addr=%s", m.Address)
+	}
+	if m.Nil {
+		sb.WriteString(" nil")
+	} else if m.BlockID != nil {
+		_, _ = fmt.Fprintf(&sb, " block=%s", m.BlockID)
+	}
+	if m.PartSetID != nil {
+		_, _ = fmt.Fprintf(&sb, " parts=%d:%s", m.PartSetID.Count, m.PartSetID.Hash)
+	}
+	if m.POLRound != nil {
+		_, _ = fmt.Fprintf(&sb, " pol=%d", *m.POLRound)
+	}
+	if m.Index != nil {
+		_, _ = fmt.Fprintf(&sb, " index=%d size=%d", *m.Index, m.Size)
+	}
+	if m.Type == WALMessageRoundState {
+		_, _ = fmt.Fprintf(&sb, " pv=%s pc=%s bp=%s sync=%v",
+			m.PrevotesMask, m.PrecommitsMask, m.BlockPartsMask, m.Sync)
+	}
+	if m.Type == WALMessageVoteList {
+		_, _ = fmt.Fprintf(&sb, " votes=%d", len(m.Votes))
+	}
+	return sb.String()
+}
+
+// WALRecord is a record of a WAL. Offset is relative to the beginning of
+// the oldest WAL file and Size includes the frame header.
+type WALRecord struct {
+	Index   int             `json:"index"`
+	Offset  int64           `json:"offset"`
+	Size    int             `json:"size"`
+	Message *WALMessage     `json:"message,omitempty"`
+	Raw     common.HexBytes `json:"raw,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// WALSummary is the result of validating a WAL. Size is the total size of
+// WAL files and ValidSize is the size up to the end of the last record
+// having valid checksum. Error describes why the rest is not readable.
+type WALSummary struct {
+	ID        string `json:"id"`
+	Files     int    `json:"files"`
+	Records   int    `json:"records"`
+	Invalid   int    `json:"invalid"`
+	Size      int64  `json:"size"`
+	ValidSize int64  `json:"validSize"`
+	Error     string `json:"error,omitempty"`
+}
+
+// WALDump is a WALSummary with the records of the WAL.
+type WALDump struct {
+	WALSummary
+	Entries []*WALRecord `json:"entries"`
+}
+
+type walVerifyContext struct{}
+
+func (walVerifyContext) ValidNID(nid uint32) bool {
+	return true
+}
+
+func (walVerifyContext) NID() int {
+	return 0
+}
+
+func walPartSetIDOf(psid *PartSetID) *WALPartSetID {
+	if psid == nil {
+		return nil
+	}
+	return &WALPartSetID{
+		Count: psid.Count,
+		Hash:  psid.Hash,
+	}
+}
+
+func walVoteOf(msg *VoteMessage) *WALMessage {
+	wm := &WALMessage{
+		Type:      WALMessageVote,
+		Height:    msg.Height,
+		Round:     msg.Round,
+		Address:   msg.address(),
+		VoteType:  msg.Type.String(),
+		Timestamp: msg.Timestamp,
+	}
+	if msg.BlockPartSetIDAndNTSVoteCount != nil {
+		wm.BlockID = msg.BlockID
+		wm.PartSetID = walPartSetIDOf(msg.BlockPartSetIDAndNTSVoteCount.ID())
+	} else {
+		wm.Nil = true
+	}
+	return wm
+}
+
+func walMessageOf(msg Message) *WALMessage {
+	switch m := msg.(type) {
+	case *ProposalMessage:
+		polRound := m.POLRound
+		return &WALMessage{
+			Type:      WALMessageProposal,
+			Height:    m.Height,
+			Round:     m.Round,
+			Address:   m.address(),
+			PartSetID: walPartSetIDOf(m.BlockPartSetID),
+			POLRound:  &polRound,
+		}
+	case *BlockPartMessage:
+		index := int(m.Index)
+		return &WALMessage{
+			Type:   WALMessageBlockPart,
+			Height: m.Height,
+			Index:  &index,
+			Size:   len(m.BlockPart),
+		}
+	case *VoteMessage:
+		return walVoteOf(m)
+	case *RoundStateMessage:
+		wm := &WALMessage{
+			Type:   WALMessageRoundState,
+			Height: m.Height,
+			Round:  m.Round,
+			Sync:   m.Sync,
+		}
+		if m.PrevotesMask != nil {
+			wm.PrevotesMask = m.PrevotesMask.String()
+		}
+		if m.PrecommitsMask != nil {
+			wm.PrecommitsMask = m.PrecommitsMask.String()
+		}
+		if m.BlockPartsMask != nil {
+			wm.BlockPartsMask = m.BlockPartsMask.String()
+		}
+		return wm
+	case *VoteListMessage:
+		wm := &WALMessage{Type: WALMessageVoteList}
+		if m.VoteList == nil {
+			return wm
+		}
+		for i := 0; i < m.VoteList.Len(); i++ {
+			vote := walVoteOf(m.VoteList.Get(i))
+			if i == 0 {
+				wm.Height, wm.Round, wm.VoteType = vote.Height, vote.Round, vote.VoteType
+			}
+			wm.Votes = append(wm.Votes, vote)
+		}
+		return wm
+	default:
+		return &WALMessage{Type: fmt.Sprintf("%T", msg)}
+	}
+}
+
+func decodeWALRecord(rec *WALRecord, bs []byte) {
+	if len(bs) < 2 {
+		rec.Error = fmt.Sprintf("too short wal message len=%d", len(bs))
+		return
+	}
+	sp := binary.BigEndian.Uint16(bs[0:2])
+	msg, err := UnmarshalMessage(sp, bs[2:])
+	if err != nil {
+		rec.Error = fmt.Sprintf("fail to decode sp=%#04x err=%v", sp, err)
+		return
+	}
+	rec.Message = walMessageOf(msg)
+	if err := msg.Verify(walVerifyContext{}); err != nil {
+		rec.Error = fmt.Sprintf("fail to verify err=%v", err)
+	}
+}
+
+func openWALForInspect(id string) (*walReader, *WALSummary, error) {
+	wr, err := openWALForRead(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &WALSummary{
+		ID:    filepath.Base(id),
+		Files: len(wr.wi.fileSizes),
+	}
+	for _, size := range wr.wi.fileSizes {
+		s.Size += size
+	}
+	return wr, s, nil
+}
+
+// readRecord reads a record from wr. It returns nil record with nil error
+// at the end of the valid records, and the reason is recorded in s.
+func readRecord(wr *walReader, s *WALSummary) (*WALRecord, []byte, error) {
+	offset := wr.validOffset
+	bs, err := wr.ReadBytes()
+	if IsEOF(err) {
+		return nil, nil, nil
+	} else if IsCorruptedWAL(err) {
+		s.Error = fmt.Sprintf("corrupted record at offset=%d", offset)
+		return nil, nil, nil
+	} else if IsUnexpectedEOF(err) {
+		s.Error = fmt.Sprintf("incomplete record at offset=%d", offset)
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	rec := &WALRecord{
+		Index:  s.Records,
+		Offset: offset,
+		Size:   headerLen + len(bs),
+	}
+	s.Records++
+	s.ValidSize = wr.validOffset
+	return rec, bs, nil
+}
+
+// InspectWAL reads and decodes all the records of the WAL. id is in the
+// form /wal/dir/prefix. cb is called for each record if it's not nil.
+// If raw is true, the payload of the record is also delivered. Decoding
+// failures are reported in the record and the summary, and reading stops at
+// the first record which fails checksum or is incomplete.
+func InspectWAL(id string, raw bool, cb func(rec *WALRecord) error) (*WALSummary, error) {
+	wr, s, err := openWALForInspect(id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		log.Must(wr.Close())
+	}()
+
+	for {
+		rec, bs, err := readRecord(wr, s)
+		if err != nil {
+			return nil, err
+		}
+		if rec == nil {
+			break
+		}
+		decodeWALRecord(rec, bs)
+		if rec.Error != "" {
+			s.Invalid++
+		}
+		if raw {
+			rec.Raw = bs
+		}
+		if cb != nil {
+			if err := cb(rec); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+// DumpWAL returns the summary and all the records of the WAL.
+func DumpWAL(id string, raw bool) (*WALDump, error) {
+	var records []*WALRecord
+	s, err := InspectWAL(id, raw, func(rec *WALRecord) error {
+		records = append(records, rec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &WALDump{WALSummary: *s, Entries: records}, nil
+}
+
+// TruncateWAL truncates the WAL after the first records. If records is
+// negative, it only drops corrupted or incomplete records at the end. It
+// returns the summary of the WAL before truncation, and ValidSize is
+// the size after truncation. The WAL shall not be used by others.
+func TruncateWAL(id string, records int) (*WALSummary, error) {
+	wr, s, err := openWALForInspect(id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		log.Must(wr.Close())
+	}()
+
+	for records < 0 || s.Records < records {
+		rec, _, err := readRecord(wr, s)
+		if err != nil {
+			return nil, err
+		}
+		if rec == nil {
+			break
+		}
+	}
+	if records > s.Records {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"NotEnoughRecords(records=%d,valid=%d)", records, s.Records)
+	}
+	if err := wr.CloseAndRepair(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
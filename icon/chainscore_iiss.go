@@ -1048,3 +1048,71 @@ func (s *chainScore) Ex_getPRepCountConfig() (map[string]interface{}, error) {
 	}
 	return es.GetPRepCountConfig()
 }
+
+func (s *chainScore) Ex_estimateReward(
+	address module.Address, terms *common.HexInt, delegations []interface{}, bonds []interface{},
+) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueryMode(); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	n := int64(1)
+	if terms != nil {
+		if !terms.IsInt64() {
+			return nil, scoreresult.InvalidParameterError.Errorf("InvalidTerms(%s)", terms)
+		}
+		n = terms.Int64()
+	}
+	var ds icstate.Delegations
+	if delegations != nil {
+		if ds, err = icstate.NewDelegations(delegations, es.State.GetDelegationSlotMax()); err != nil {
+			return nil, err
+		}
+	}
+	var bs icstate.Bonds
+	if bonds != nil {
+		if bs, err = icstate.NewBonds(bonds, s.cc.Revision().Value()); err != nil {
+			return nil, err
+		}
+	}
+	return es.EstimateReward(s.newCallContext(s.cc), address, n, ds, bs)
+}
+
+func (s *chainScore) Ex_getRewardBreakdown(
+	address module.Address, startHeight, endHeight *common.HexInt,
+) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueryMode(); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	start := int64(0)
+	if startHeight != nil {
+		if !startHeight.IsInt64() {
+			return nil, scoreresult.InvalidParameterError.Errorf("InvalidStartBlockHeight(%s)", startHeight)
+		}
+		start = startHeight.Int64()
+	}
+	end := s.cc.BlockHeight()
+	if endHeight != nil {
+		if !endHeight.IsInt64() {
+			return nil, scoreresult.InvalidParameterError.Errorf("InvalidEndBlockHeight(%s)", endHeight)
+		}
+		end = endHeight.Int64()
+	}
+	if start < 0 || start > end {
+		return nil, scoreresult.InvalidParameterError.Errorf("InvalidRange(start=%d,end=%d)", start, end)
+	}
+	return es.GetRewardBreakdown(address, start, end)
+}
@@ -141,3 +141,13 @@ func (c *wrapper) Term() {
 		c.Consensus.Term()
 	}
 }
+
+func (c *wrapper) InspectRoundState() *consensus.RoundState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if inspector, ok := c.Consensus.(consensus.Inspector); ok {
+		return inspector.InspectRoundState()
+	}
+	return nil
+}
@@ -26,4 +26,8 @@ const (
 	// BlockMerkle basically maps node hash to block merkle node for v1 block.
 	// In addition, it also has merkleTreeData.
 	BlockMerkle db.BucketID = "H"
+
+	// RewardBreakdown maps address and start height of the calculation to
+	// reward breakdown of the account. It's stored only if it's enabled.
+	RewardBreakdown db.BucketID = "R"
 )
@@ -0,0 +1,964 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// Amount is an amount of ICX in loop.
+// It accepts hex ("0x..."), decimal loop and decimal ICX with "icx" suffix.
+type Amount struct {
+	big.Int
+}
+
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		s = string(b)
+	}
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(strings.ToLower(s), "icx") {
+		icx, ok := new(big.Int).SetString(strings.TrimSpace(s[:len(s)-3]), 10)
+		if !ok {
+			return errors.IllegalArgumentError.Errorf("InvalidAmount(%s)", s)
+		}
+		a.Mul(icx, icutils.ToLoop(1))
+		return nil
+	}
+	return intconv.ParseBigInt(&a.Int, s)
+}
+
+func (a *Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(intconv.FormatBigInt(&a.Int))
+}
+
+func (a *Amount) Value() *big.Int {
+	if a == nil {
+		return nil
+	}
+	return &a.Int
+}
+
+type ScenarioConfig struct {
+	TermPeriod                           int64  `json:"termPeriod,omitempty"`
+	MainPReps                            int64  `json:"mainPReps,omitempty"`
+	SubPReps                             int64  `json:"subPReps,omitempty"`
+	ExtraMainPReps                       *int64 `json:"extraMainPReps,omitempty"`
+	BondRequirement                      *int64 `json:"bondRequirement,omitempty"`
+	ValidationPenaltyCondition           int64  `json:"validationPenaltyCondition,omitempty"`
+	ConsistentValidationPenaltyCondition int64  `json:"consistentValidationPenaltyCondition,omitempty"`
+	ConsistentValidationPenaltySlashRate *int64 `json:"consistentValidationPenaltySlashRate,omitempty"`
+	NonVotePenaltySlashRate              *int64 `json:"nonVotePenaltySlashRate,omitempty"`
+	UnbondingPeriodMultiplier            int64  `json:"unbondingPeriodMultiplier,omitempty"`
+	DelegationSlotMax                    int64  `json:"delegationSlotMax,omitempty"`
+	Iglobal                              int64  `json:"iglobal,omitempty"`
+	Iprep                                *int64 `json:"iprep,omitempty"`
+	Ivoter                               *int64 `json:"ivoter,omitempty"`
+	Icps                                 *int64 `json:"icps,omitempty"`
+	Irelay                               *int64 `json:"irelay,omitempty"`
+}
+
+// SimConfig returns SimConfig with default values overwritten by
+// the values specified in the scenario. Rates are numerators with
+// the denominator icmodule.DenomInRate.
+func (c *ScenarioConfig) SimConfig() *SimConfig {
+	cfg := NewSimConfig()
+	if c == nil {
+		return cfg
+	}
+	if c.TermPeriod > 0 {
+		cfg.TermPeriod = c.TermPeriod
+	}
+	if c.MainPReps > 0 {
+		cfg.MainPRepCount = c.MainPReps
+	}
+	if c.SubPReps > 0 {
+		cfg.SubPRepCount = c.SubPReps
+	}
+	if c.ExtraMainPReps != nil {
+		cfg.ExtraMainPRepCount = *c.ExtraMainPReps
+	}
+	if c.BondRequirement != nil {
+		cfg.BondRequirement = icmodule.Rate(*c.BondRequirement)
+	}
+	if c.ValidationPenaltyCondition > 0 {
+		cfg.ValidationPenaltyCondition = c.ValidationPenaltyCondition
+	}
+	if c.ConsistentValidationPenaltyCondition > 0 {
+		cfg.ConsistentValidationPenaltyCondition = c.ConsistentValidationPenaltyCondition
+	}
+	if c.ConsistentValidationPenaltySlashRate != nil {
+		cfg.ConsistentValidationPenaltySlashRate = icmodule.Rate(*c.ConsistentValidationPenaltySlashRate)
+	}
+	if c.NonVotePenaltySlashRate != nil {
+		cfg.NonVotePenaltySlashRate = icmodule.Rate(*c.NonVotePenaltySlashRate)
+	}
+	if c.UnbondingPeriodMultiplier > 0 {
+		cfg.UnbondingPeriodMultiplier = c.UnbondingPeriodMultiplier
+	}
+	if c.DelegationSlotMax > 0 {
+		cfg.DelegationSlotMax = c.DelegationSlotMax
+	}
+	if c.Iglobal > 0 {
+		cfg.Iglobal = c.Iglobal
+	}
+	for _, f := range []struct {
+		dst *int64
+		src *int64
+	}{
+		{&cfg.Iprep, c.Iprep},
+		{&cfg.Ivoter, c.Ivoter},
+		{&cfg.Icps, c.Icps},
+		{&cfg.Irelay, c.Irelay},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	return cfg
+}
+
+type ScenarioAccount struct {
+	Address *common.Address `json:"address,omitempty"`
+	Balance *Amount         `json:"balance,omitempty"`
+}
+
+type ScenarioPRep struct {
+	Owner       string `json:"owner"`
+	Node        string `json:"node,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Country     string `json:"country,omitempty"`
+	City        string `json:"city,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Website     string `json:"website,omitempty"`
+	Details     string `json:"details,omitempty"`
+	P2PEndpoint string `json:"p2pEndpoint,omitempty"`
+}
+
+type ScenarioVote struct {
+	Address string  `json:"address"`
+	Value   *Amount `json:"value"`
+}
+
+// ScenarioTx is a transaction in a scenario step.
+// Only the fields related to its type are used.
+type ScenarioTx struct {
+	Type          string           `json:"type"`
+	From          string           `json:"from"`
+	To            string           `json:"to,omitempty"`
+	Amount        *Amount          `json:"amount,omitempty"`
+	Delegations   []ScenarioVote   `json:"delegations,omitempty"`
+	Bonds         []ScenarioVote   `json:"bonds,omitempty"`
+	BonderList    []string         `json:"bonderList,omitempty"`
+	Revision      int              `json:"revision,omitempty"`
+	PRep          *ScenarioPRep    `json:"prep,omitempty"`
+	Target        string           `json:"target,omitempty"`
+	Rate          int64            `json:"rate,omitempty"`
+	MaxRate       int64            `json:"maxRate,omitempty"`
+	MaxChangeRate int64            `json:"maxChangeRate,omitempty"`
+	Rates         map[string]int64 `json:"rates,omitempty"`
+	Counts        map[string]int64 `json:"counts,omitempty"`
+	Allocation    map[string]int64 `json:"allocation,omitempty"`
+	DSType        string           `json:"dsType,omitempty"`
+	DSHeight      int64            `json:"dsHeight,omitempty"`
+}
+
+// ScenarioExpect is a set of assertions checked after a step.
+type ScenarioExpect struct {
+	BlockHeight int64              `json:"blockHeight,omitempty"`
+	Receipts    []int              `json:"receipts,omitempty"`
+	Balance     map[string]*Amount `json:"balance,omitempty"`
+	Stake       map[string]*Amount `json:"stake,omitempty"`
+	IScore      map[string]*Amount `json:"iscore,omitempty"`
+	Grade       map[string]string  `json:"grade,omitempty"`
+	Status      map[string]string  `json:"status,omitempty"`
+	Jailed      map[string]bool    `json:"jailed,omitempty"`
+	Validators  []string           `json:"validators,omitempty"`
+}
+
+// ScenarioStep is one step of a scenario. Transactions are executed in
+// a new block, then empty blocks are generated by Blocks, To or TermEnd.
+// Validators listed in Absent don't vote for the blocks of this step.
+type ScenarioStep struct {
+	Name    string          `json:"name,omitempty"`
+	Txs     []ScenarioTx    `json:"txs,omitempty"`
+	Blocks  int64           `json:"blocks,omitempty"`
+	To      int64           `json:"to,omitempty"`
+	TermEnd bool            `json:"termEnd,omitempty"`
+	Absent  []string        `json:"absent,omitempty"`
+	Expect  *ScenarioExpect `json:"expect,omitempty"`
+}
+
+type Scenario struct {
+	Revision   int                         `json:"revision,omitempty"`
+	Config     *ScenarioConfig             `json:"config,omitempty"`
+	Accounts   map[string]*ScenarioAccount `json:"accounts"`
+	Validators []string                    `json:"validators,omitempty"`
+	PReps      []*ScenarioPRep             `json:"preps,omitempty"`
+	Track      []string                    `json:"track,omitempty"`
+	Steps      []*ScenarioStep             `json:"steps"`
+}
+
+func LoadScenario(path string) (*Scenario, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sc := new(Scenario)
+	if err = json.Unmarshal(bs, sc); err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidScenario(path=%s)", path)
+	}
+	return sc, nil
+}
+
+type PRepReport struct {
+	Name      string          `json:"name,omitempty"`
+	Owner     *common.Address `json:"owner"`
+	Grade     string          `json:"grade"`
+	Status    string          `json:"status"`
+	Jailed    bool            `json:"jailed"`
+	Power     *common.HexInt  `json:"power"`
+	Bonded    *common.HexInt  `json:"bonded"`
+	Delegated *common.HexInt  `json:"delegated"`
+	VTotal    int64           `json:"vTotal"`
+	VFail     int64           `json:"vFail"`
+}
+
+type SlashReport struct {
+	Height int64           `json:"height"`
+	Owner  *common.Address `json:"owner"`
+	Bonder *common.Address `json:"bonder"`
+	Amount *common.HexInt  `json:"amount"`
+}
+
+type PenaltyReport struct {
+	Height int64           `json:"height"`
+	Owner  *common.Address `json:"owner"`
+	Type   string          `json:"type"`
+}
+
+type ValidatorChange struct {
+	Height  int64             `json:"height"`
+	Added   []*common.Address `json:"added,omitempty"`
+	Removed []*common.Address `json:"removed,omitempty"`
+}
+
+type TermReport struct {
+	Sequence    int                       `json:"sequence"`
+	StartHeight int64                     `json:"startHeight"`
+	EndHeight   int64                     `json:"endHeight"`
+	Revision    int                       `json:"revision"`
+	Rewards     map[string]*common.HexInt `json:"rewards,omitempty"`
+	PReps       []*PRepReport             `json:"preps,omitempty"`
+	Penalties   []*PenaltyReport          `json:"penalties,omitempty"`
+	Slashes     []*SlashReport            `json:"slashes,omitempty"`
+	Validators  []*ValidatorChange        `json:"validatorChanges,omitempty"`
+}
+
+type Report struct {
+	BlockHeight int64         `json:"blockHeight"`
+	Revision    int           `json:"revision"`
+	Terms       []*TermReport `json:"terms"`
+	Failures    []string      `json:"failures,omitempty"`
+}
+
+func (r *Report) Failed() bool {
+	return len(r.Failures) > 0
+}
+
+type scenarioRunner struct {
+	sc    *Scenario
+	sim   Simulator
+	names map[string]module.Address
+	track []string
+
+	report  *Report
+	term    *TermReport
+	iscores map[string]*big.Int
+	claimed map[string]*big.Int
+}
+
+func (r *scenarioRunner) addressOf(name string) (module.Address, error) {
+	if addr, ok := r.names[name]; ok {
+		return addr, nil
+	}
+	addr, err := common.NewAddressFromString(name)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Errorf("UnknownAccount(%s)", name)
+	}
+	return addr, nil
+}
+
+func (r *scenarioRunner) nameOf(addr module.Address) string {
+	for name, a := range r.names {
+		if a.Equal(addr) {
+			return name
+		}
+	}
+	return ""
+}
+
+func (r *scenarioRunner) votesOf(votes []ScenarioVote) ([]*common.Address, []*big.Int, error) {
+	addrs := make([]*common.Address, len(votes))
+	values := make([]*big.Int, len(votes))
+	for i, v := range votes {
+		addr, err := r.addressOf(v.Address)
+		if err != nil {
+			return nil, nil, err
+		}
+		if v.Value == nil {
+			return nil, nil, errors.IllegalArgumentError.Errorf("NoValue(%s)", v.Address)
+		}
+		addrs[i] = common.AddressToPtr(addr)
+		values[i] = v.Value.Value()
+	}
+	return addrs, values, nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func (r *scenarioRunner) prepInfoOf(p *ScenarioPRep, index int) (*icstate.PRepInfo, error) {
+	info := newDummyPRepInfo(index)
+	if p == nil {
+		return info, nil
+	}
+	for _, f := range []struct {
+		dst **string
+		src string
+	}{
+		{&info.Name, p.Name},
+		{&info.Country, p.Country},
+		{&info.City, p.City},
+		{&info.Email, p.Email},
+		{&info.WebSite, p.Website},
+		{&info.Details, p.Details},
+		{&info.P2PEndpoint, p.P2PEndpoint},
+	} {
+		if len(f.src) > 0 {
+			*f.dst = stringPtr(f.src)
+		}
+	}
+	if len(p.Node) > 0 {
+		node, err := r.addressOf(p.Node)
+		if err != nil {
+			return nil, err
+		}
+		info.Node = node
+	}
+	return info, nil
+}
+
+func (r *scenarioRunner) transactionOf(tx *ScenarioTx, index int) (Transaction, error) {
+	sim := r.sim
+	from, err := r.addressOf(tx.From)
+	if err != nil {
+		return nil, err
+	}
+	switch tx.Type {
+	case "transfer":
+		to, err := r.addressOf(tx.To)
+		if err != nil {
+			return nil, err
+		}
+		if tx.Amount == nil {
+			return nil, errors.IllegalArgumentError.New("NoAmount")
+		}
+		return sim.Transfer(from, to, tx.Amount.Value()), nil
+	case "setStake":
+		if tx.Amount == nil {
+			return nil, errors.IllegalArgumentError.New("NoAmount")
+		}
+		return sim.SetStake(from, tx.Amount.Value()), nil
+	case "setDelegation":
+		addrs, values, err := r.votesOf(tx.Delegations)
+		if err != nil {
+			return nil, err
+		}
+		ds := make(icstate.Delegations, len(addrs))
+		for i := range addrs {
+			ds[i] = icstate.NewDelegation(addrs[i], values[i])
+		}
+		return sim.SetDelegation(from, ds), nil
+	case "setBond":
+		addrs, values, err := r.votesOf(tx.Bonds)
+		if err != nil {
+			return nil, err
+		}
+		bonds := make(icstate.Bonds, len(addrs))
+		for i := range addrs {
+			bonds[i] = icstate.NewBond(addrs[i], values[i])
+		}
+		return sim.SetBond(from, bonds), nil
+	case "setBonderList":
+		bl := make(icstate.BonderList, len(tx.BonderList))
+		for i, name := range tx.BonderList {
+			addr, err := r.addressOf(name)
+			if err != nil {
+				return nil, err
+			}
+			bl[i] = common.AddressToPtr(addr)
+		}
+		return sim.SetBonderList(from, bl), nil
+	case "registerPRep":
+		info, err := r.prepInfoOf(tx.PRep, index)
+		if err != nil {
+			return nil, err
+		}
+		return sim.RegisterPRep(from, info), nil
+	case "setPRep":
+		info, err := r.prepInfoOf(tx.PRep, index)
+		if err != nil {
+			return nil, err
+		}
+		return sim.SetPRep(from, info), nil
+	case "unregisterPRep":
+		return sim.UnregisterPRep(from), nil
+	case "disqualifyPRep":
+		target, err := r.addressOf(tx.Target)
+		if err != nil {
+			return nil, err
+		}
+		return sim.DisqualifyPRep(from, target), nil
+	case "setRevision":
+		return sim.SetRevision(from, icmodule.ValueToRevision(tx.Revision)), nil
+	case "claimIScore":
+		return sim.ClaimIScore(from), nil
+	case "setSlashingRates":
+		rates := make(map[string]icmodule.Rate, len(tx.Rates))
+		for k, v := range tx.Rates {
+			rates[k] = icmodule.Rate(v)
+		}
+		return sim.SetSlashingRates(from, rates), nil
+	case "setMinimumBond":
+		if tx.Amount == nil {
+			return nil, errors.IllegalArgumentError.New("NoAmount")
+		}
+		return sim.SetMinimumBond(from, tx.Amount.Value()), nil
+	case "initCommissionRate":
+		return sim.InitCommissionRate(from,
+			icmodule.Rate(tx.Rate), icmodule.Rate(tx.MaxRate), icmodule.Rate(tx.MaxChangeRate)), nil
+	case "setCommissionRate":
+		return sim.SetCommissionRate(from, icmodule.Rate(tx.Rate)), nil
+	case "requestUnjail":
+		return sim.RequestUnjail(from), nil
+	case "handleDoubleSignReport":
+		signer, err := r.addressOf(tx.Target)
+		if err != nil {
+			return nil, err
+		}
+		return sim.HandleDoubleSignReport(from, tx.DSType, tx.DSHeight, signer), nil
+	case "setPRepCountConfig":
+		return sim.SetPRepCountConfig(from, tx.Counts), nil
+	case "setRewardFundAllocation2":
+		values := make(map[icstate.RFundKey]icmodule.Rate, len(tx.Allocation))
+		for k, v := range tx.Allocation {
+			values[icstate.RFundKey(k)] = icmodule.Rate(v)
+		}
+		return sim.SetRewardFundAllocation2(from, values), nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("UnknownTxType(%s)", tx.Type)
+	}
+}
+
+func (r *scenarioRunner) consensusInfo(absent []module.Address) module.ConsensusInfo {
+	vl := r.sim.ValidatorList()
+	voted := make([]bool, len(vl))
+	for i, v := range vl {
+		voted[i] = true
+		for _, addr := range absent {
+			if v.Address().Equal(addr) {
+				voted[i] = false
+				break
+			}
+		}
+	}
+	return NewConsensusInfo(r.sim.Database(), vl, voted)
+}
+
+func (r *scenarioRunner) openTerm() {
+	sim := r.sim
+	tss := sim.TermSnapshot()
+	term := &TermReport{
+		Revision: sim.Revision().Value(),
+	}
+	if tss != nil {
+		term.Sequence = tss.Sequence()
+		term.StartHeight = tss.StartHeight()
+	}
+	r.term = term
+	r.iscores = make(map[string]*big.Int)
+	r.claimed = make(map[string]*big.Int)
+	for _, name := range r.track {
+		r.iscores[name] = sim.QueryIScore(r.names[name])
+	}
+}
+
+func (r *scenarioRunner) closeTerm() {
+	sim := r.sim
+	term := r.term
+	term.EndHeight = sim.BlockHeight()
+	term.Rewards = make(map[string]*common.HexInt)
+	for _, name := range r.track {
+		reward := new(big.Int).Sub(sim.QueryIScore(r.names[name]), r.iscores[name])
+		if claimed, ok := r.claimed[name]; ok {
+			reward.Add(reward, claimed)
+		}
+		term.Rewards[name] = common.NewHexInt(0).SetValue(reward)
+	}
+	for _, grade := range []icstate.Grade{icstate.GradeMain, icstate.GradeSub} {
+		for _, prep := range sim.GetPReps(grade) {
+			term.PReps = append(term.PReps, r.prepReportOf(prep))
+		}
+	}
+	r.report.Terms = append(r.report.Terms, term)
+}
+
+func gradeName(g icstate.Grade) string {
+	switch g {
+	case icstate.GradeMain:
+		return "main"
+	case icstate.GradeSub:
+		return "sub"
+	case icstate.GradeCandidate:
+		return "candidate"
+	default:
+		return "none"
+	}
+}
+
+func statusName(s icstate.Status) string {
+	switch s {
+	case icstate.Active:
+		return "active"
+	case icstate.Unregistered:
+		return "unregistered"
+	case icstate.Disqualified:
+		return "disqualified"
+	default:
+		return "notReady"
+	}
+}
+
+func (r *scenarioRunner) prepReportOf(prep *icstate.PRep) *PRepReport {
+	height := r.sim.BlockHeight()
+	br := r.sim.GetStateContext().GetBondRequirement()
+	pr := &PRepReport{
+		Owner:     common.AddressToPtr(prep.Owner()),
+		Grade:     gradeName(prep.Grade()),
+		Status:    statusName(prep.Status()),
+		Jailed:    prep.IsInJail(),
+		Power:     common.NewHexInt(0).SetValue(prep.GetPower(br)),
+		Bonded:    common.NewHexInt(0).SetValue(prep.Bonded()),
+		Delegated: common.NewHexInt(0).SetValue(prep.Delegated()),
+		VTotal:    prep.GetVTotal(height),
+		VFail:     prep.GetVFail(height),
+	}
+	if info := prep.Info(); info != nil && info.Name != nil {
+		pr.Name = *info.Name
+	}
+	return pr
+}
+
+func (r *scenarioRunner) onEvents(height int64, receipts []Receipt) {
+	for _, rcpt := range receipts {
+		if rcpt == nil {
+			continue
+		}
+		for _, e := range rcpt.Events() {
+			signature, indexed, data, err := e.DecodeParams()
+			if err != nil || !state.SystemAddress.Equal(e.Address) {
+				continue
+			}
+			switch signature {
+			case iiss.EventSlashed:
+				r.term.Slashes = append(r.term.Slashes, &SlashReport{
+					Height: height,
+					Owner:  common.AddressToPtr(indexed[0].(module.Address)),
+					Bonder: common.AddressToPtr(data[0].(module.Address)),
+					Amount: common.NewHexInt(0).SetValue(data[1].(*big.Int)),
+				})
+			case iiss.EventPenaltyImposed:
+				pt := icmodule.PenaltyType(data[1].(*big.Int).Int64())
+				r.term.Penalties = append(r.term.Penalties, &PenaltyReport{
+					Height: height,
+					Owner:  common.AddressToPtr(indexed[0].(module.Address)),
+					Type:   pt.String(),
+				})
+			}
+		}
+	}
+}
+
+func validatorChangeOf(height int64, vl0, vl1 []module.Validator) *ValidatorChange {
+	vc := &ValidatorChange{Height: height}
+	for _, v := range vl1 {
+		if ValidatorIndexOf(vl0, v.Address()) < 0 {
+			vc.Added = append(vc.Added, common.AddressToPtr(v.Address()))
+		}
+	}
+	for _, v := range vl0 {
+		if ValidatorIndexOf(vl1, v.Address()) < 0 {
+			vc.Removed = append(vc.Removed, common.AddressToPtr(v.Address()))
+		}
+	}
+	if len(vc.Added) == 0 && len(vc.Removed) == 0 {
+		return nil
+	}
+	return vc
+}
+
+// goByBlock executes a block and updates the report of the current term
+func (r *scenarioRunner) goByBlock(absent []module.Address, blk Block) ([]Receipt, error) {
+	sim := r.sim
+	if blk != nil {
+		for _, tx := range blk.Txs() {
+			if tx.Type() == TypeClaimIScore {
+				name := r.nameOf(tx.From())
+				if _, ok := r.iscores[name]; ok {
+					claimed := r.claimed[name]
+					if claimed == nil {
+						claimed = new(big.Int)
+					}
+					r.claimed[name] = claimed.Add(claimed, sim.QueryIScore(tx.From()))
+				}
+			}
+		}
+	}
+
+	vl0 := sim.ValidatorList()
+	receipts, err := sim.GoByBlock(r.consensusInfo(absent), blk)
+	if err != nil {
+		return receipts, err
+	}
+	height := sim.BlockHeight()
+	r.onEvents(height, receipts)
+	if vc := validatorChangeOf(height, vl0, sim.ValidatorList()); vc != nil {
+		r.term.Validators = append(r.term.Validators, vc)
+	}
+
+	if tss := sim.TermSnapshot(); tss != nil && tss.StartHeight() != r.term.StartHeight {
+		r.closeTerm()
+		r.openTerm()
+	}
+	return receipts, nil
+}
+
+func (r *scenarioRunner) failf(step int, format string, args ...interface{}) {
+	r.report.Failures = append(r.report.Failures,
+		fmt.Sprintf("step[%d] height=%d: %s", step, r.sim.BlockHeight(), fmt.Sprintf(format, args...)))
+}
+
+func (r *scenarioRunner) checkAmounts(
+	step int, kind string, exp map[string]*Amount, getter func(addr module.Address) *big.Int,
+) error {
+	for name, value := range exp {
+		addr, err := r.addressOf(name)
+		if err != nil {
+			return err
+		}
+		if real := getter(addr); real == nil || real.Cmp(value.Value()) != 0 {
+			r.failf(step, "%s(%s) exp=%s real=%s", kind, name, value.Value(), real)
+		}
+	}
+	return nil
+}
+
+func (r *scenarioRunner) checkExpect(step int, exp *ScenarioExpect, receipts []Receipt) error {
+	sim := r.sim
+	if exp.BlockHeight > 0 && exp.BlockHeight != sim.BlockHeight() {
+		r.failf(step, "blockHeight exp=%d real=%d", exp.BlockHeight, sim.BlockHeight())
+	}
+	if len(exp.Receipts) > 0 {
+		// the first receipt is for the base transaction
+		if len(receipts) != len(exp.Receipts)+1 {
+			r.failf(step, "receipts exp=%d real=%d", len(exp.Receipts), len(receipts)-1)
+		} else {
+			for i, status := range exp.Receipts {
+				if rcpt := receipts[i+1]; rcpt.Status() != status {
+					r.failf(step, "receipt[%d] exp=%d real=%d err=%v", i, status, rcpt.Status(), rcpt.Error())
+				}
+			}
+		}
+	}
+	if err := r.checkAmounts(step, "balance", exp.Balance, sim.GetBalance); err != nil {
+		return err
+	}
+	if err := r.checkAmounts(step, "iscore", exp.IScore, sim.QueryIScore); err != nil {
+		return err
+	}
+	if err := r.checkAmounts(step, "stake", exp.Stake, func(addr module.Address) *big.Int {
+		stake, _ := sim.GetStakeInJSON(addr)["stake"].(*big.Int)
+		return stake
+	}); err != nil {
+		return err
+	}
+
+	preps := make(map[string]*icstate.PRep)
+	prepOf := func(name string) (*icstate.PRep, error) {
+		if prep, ok := preps[name]; ok {
+			return prep, nil
+		}
+		addr, err := r.addressOf(name)
+		if err != nil {
+			return nil, err
+		}
+		prep := sim.GetPRepByOwner(addr)
+		preps[name] = prep
+		return prep, nil
+	}
+	for name, grade := range exp.Grade {
+		prep, err := prepOf(name)
+		if err != nil {
+			return err
+		}
+		if prep == nil {
+			r.failf(step, "grade(%s) exp=%s real=<nil>", name, grade)
+		} else if real := gradeName(prep.Grade()); real != grade {
+			r.failf(step, "grade(%s) exp=%s real=%s", name, grade, real)
+		}
+	}
+	for name, status := range exp.Status {
+		prep, err := prepOf(name)
+		if err != nil {
+			return err
+		}
+		if prep == nil {
+			r.failf(step, "status(%s) exp=%s real=<nil>", name, status)
+		} else if real := statusName(prep.Status()); real != status {
+			r.failf(step, "status(%s) exp=%s real=%s", name, status, real)
+		}
+	}
+	for name, jailed := range exp.Jailed {
+		prep, err := prepOf(name)
+		if err != nil {
+			return err
+		}
+		if prep == nil {
+			r.failf(step, "jailed(%s) exp=%t real=<nil>", name, jailed)
+		} else if prep.IsInJail() != jailed {
+			r.failf(step, "jailed(%s) exp=%t real=%t", name, jailed, prep.IsInJail())
+		}
+	}
+	if exp.Validators != nil {
+		vl := sim.ValidatorList()
+		if len(vl) != len(exp.Validators) {
+			r.failf(step, "validators exp=%d real=%d", len(exp.Validators), len(vl))
+		}
+		for _, name := range exp.Validators {
+			addr, err := r.addressOf(name)
+			if err != nil {
+				return err
+			}
+			if ValidatorIndexOf(vl, addr) < 0 {
+				r.failf(step, "validators NotFound(%s)", name)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *scenarioRunner) runStep(idx int, step *ScenarioStep) error {
+	sim := r.sim
+	absent := make([]module.Address, len(step.Absent))
+	for i, name := range step.Absent {
+		addr, err := r.addressOf(name)
+		if err != nil {
+			return err
+		}
+		absent[i] = addr
+	}
+
+	var receipts []Receipt
+	if len(step.Txs) > 0 {
+		blk := NewBlock()
+		for i := range step.Txs {
+			tx, err := r.transactionOf(&step.Txs[i], i)
+			if err != nil {
+				return errors.Wrapf(err, "step[%d].txs[%d]", idx, i)
+			}
+			blk.AddTransaction(tx)
+		}
+		var err error
+		if receipts, err = r.goByBlock(absent, blk); err != nil {
+			return err
+		}
+	}
+
+	blocks := step.Blocks
+	if step.To > 0 {
+		blocks = step.To - sim.BlockHeight()
+	} else if step.TermEnd {
+		if tss := sim.TermSnapshot(); tss != nil {
+			blocks = tss.GetEndHeight() - sim.BlockHeight()
+		}
+	}
+	for i := int64(0); i < blocks; i++ {
+		if _, err := r.goByBlock(absent, nil); err != nil {
+			return err
+		}
+	}
+
+	if step.Expect != nil {
+		return r.checkExpect(idx, step.Expect, receipts)
+	}
+	return nil
+}
+
+func (r *scenarioRunner) init() error {
+	sc := r.sc
+	r.names = make(map[string]module.Address)
+	balances := make(map[string]*big.Int)
+
+	names := make([]string, 0, len(sc.Accounts))
+	for name := range sc.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		acct := sc.Accounts[name]
+		var addr module.Address
+		if acct != nil && acct.Address != nil {
+			addr = acct.Address
+		} else {
+			addr = newDummyAddress(10000 + i)
+		}
+		r.names[name] = addr
+		if acct != nil && acct.Balance != nil {
+			balances[icutils.ToKey(addr)] = acct.Balance.Value()
+		}
+	}
+
+	cfg := sc.Config.SimConfig()
+	var validators []module.Validator
+	if len(sc.Validators) > 0 {
+		for _, name := range sc.Validators {
+			addr, err := r.addressOf(name)
+			if err != nil {
+				return err
+			}
+			v, err := state.ValidatorFromAddress(addr)
+			if err != nil {
+				return err
+			}
+			validators = append(validators, v)
+		}
+	} else {
+		for i := 0; i < int(cfg.MainPRepCount); i++ {
+			v, _ := state.ValidatorFromAddress(newDummyAddress(4000 + i))
+			validators = append(validators, v)
+		}
+	}
+
+	revision := sc.Revision
+	if revision == 0 {
+		revision = icmodule.Revision13
+	}
+	sim, err := NewSimulator(icmodule.ValueToRevision(revision), validators, balances, cfg)
+	if err != nil {
+		return err
+	}
+	r.sim = sim
+
+	r.track = sc.Track
+	if len(r.track) == 0 {
+		r.track = names
+	}
+	for _, name := range r.track {
+		if _, ok := r.names[name]; !ok {
+			return errors.IllegalArgumentError.Errorf("UnknownTrackedAccount(%s)", name)
+		}
+	}
+	r.report = &Report{}
+	r.openTerm()
+
+	// Apply the remaining revisions which are not applied by NewSimulator()
+	if sim.Revision().Value() < revision {
+		gov := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+		blk := NewBlock()
+		blk.AddTransaction(sim.SetRevision(gov, icmodule.ValueToRevision(revision)))
+		receipts, err := r.goByBlock(nil, blk)
+		if err != nil {
+			return err
+		}
+		if !CheckReceiptSuccess(receipts...) {
+			return errors.InvalidStateError.Errorf("SetRevisionFailure(%d)", revision)
+		}
+	}
+
+	if len(sc.PReps) > 0 {
+		blk := NewBlock()
+		for i, p := range sc.PReps {
+			owner, err := r.addressOf(p.Owner)
+			if err != nil {
+				return err
+			}
+			info, err := r.prepInfoOf(p, i)
+			if err != nil {
+				return err
+			}
+			blk.AddTransaction(sim.RegisterPRep(owner, info))
+		}
+		receipts, err := r.goByBlock(nil, blk)
+		if err != nil {
+			return err
+		}
+		for i, rcpt := range receipts[1:] {
+			if rcpt.Status() != Success {
+				return errors.InvalidStateError.Wrapf(rcpt.Error(), "RegisterPRepFailure(%s)", sc.PReps[i].Owner)
+			}
+		}
+	}
+	return nil
+}
+
+// RunScenario runs the scenario on a new simulator and returns the report.
+// Failed expectations are recorded in the report instead of returning error.
+func RunScenario(sc *Scenario) (*Report, error) {
+	r := &scenarioRunner{sc: sc}
+	if err := r.init(); err != nil {
+		return nil, err
+	}
+	for i, step := range sc.Steps {
+		if err := r.runStep(i, step); err != nil {
+			return r.report, errors.Wrapf(err, "step[%d](%s)", i, step.Name)
+		}
+	}
+	if r.sim.BlockHeight() > r.term.StartHeight {
+		r.closeTerm()
+	}
+	r.report.BlockHeight = r.sim.BlockHeight()
+	r.report.Revision = r.sim.Revision().Value()
+	return r.report, nil
+}
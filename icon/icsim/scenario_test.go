@@ -0,0 +1,118 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+)
+
+const testScenario = `{
+  "revision": 13,
+  "config": {"termPeriod": 20, "mainPReps": 4, "subPReps": 2, "validationPenaltyCondition": 5},
+  "accounts": {
+    "p0": {"balance": "3000icx"}, "p1": {"balance": "3000icx"}, "p2": {"balance": "3000icx"},
+    "p3": {"balance": "3000icx"}, "p4": {"balance": "3000icx"},
+    "alice": {"balance": "1000000icx"}
+  },
+  "preps": [{"owner": "p0"}, {"owner": "p1"}, {"owner": "p2"}, {"owner": "p3"}, {"owner": "p4"}],
+  "steps": [
+    {"name": "stake", "txs": [
+      {"type": "setStake", "from": "alice", "amount": "500000icx"},
+      {"type": "setDelegation", "from": "alice", "delegations": [
+        {"address": "p0", "value": "100000icx"}, {"address": "p1", "value": "100000icx"},
+        {"address": "p2", "value": "100000icx"}, {"address": "p3", "value": "100000icx"},
+        {"address": "p4", "value": "50000icx"}]},
+      {"type": "setStake", "from": "p0", "amount": "1000icx"},
+      {"type": "setStake", "from": "p1", "amount": "1000icx"},
+      {"type": "setStake", "from": "p2", "amount": "1000icx"},
+      {"type": "setStake", "from": "p3", "amount": "1000icx"},
+      {"type": "setStake", "from": "p4", "amount": "1000icx"},
+      {"type": "setStake", "from": "alice", "amount": "2000000icx"}
+    ], "expect": {"receipts": [1, 1, 1, 1, 1, 1, 1, 0], "stake": {"alice": "500000icx"}}},
+    {"name": "bond", "txs": [
+      {"type": "setBonderList", "from": "p0", "bonderList": ["p0"]},
+      {"type": "setBonderList", "from": "p1", "bonderList": ["p1"]},
+      {"type": "setBonderList", "from": "p2", "bonderList": ["p2"]},
+      {"type": "setBonderList", "from": "p3", "bonderList": ["p3"]},
+      {"type": "setBonderList", "from": "p4", "bonderList": ["p4"]},
+      {"type": "setBond", "from": "p0", "bonds": [{"address": "p0", "value": "1000icx"}]},
+      {"type": "setBond", "from": "p1", "bonds": [{"address": "p1", "value": "1000icx"}]},
+      {"type": "setBond", "from": "p2", "bonds": [{"address": "p2", "value": "1000icx"}]},
+      {"type": "setBond", "from": "p3", "bonds": [{"address": "p3", "value": "1000icx"}]},
+      {"type": "setBond", "from": "p4", "bonds": [{"address": "p4", "value": "1000icx"}]}
+    ], "termEnd": true},
+    {"name": "decentralized", "blocks": 2,
+      "expect": {"blockHeight": 22, "validators": ["p0", "p1", "p2", "p3"], "grade": {"p4": "sub"}}},
+    {"name": "absent", "absent": ["p3"], "blocks": 5,
+      "expect": {"validators": ["p0", "p1", "p2", "p4"], "grade": {"p3": "candidate", "p4": "main"}}},
+    {"name": "next term", "termEnd": true,
+      "expect": {"validators": ["p0", "p1", "p2", "p3"], "status": {"p3": "active"}}}
+  ]
+}`
+
+func TestRunScenario(t *testing.T) {
+	sc := new(Scenario)
+	assert.NoError(t, json.Unmarshal([]byte(testScenario), sc))
+
+	report, err := RunScenario(sc)
+	assert.NoError(t, err)
+	assert.False(t, report.Failed(), "failures=%v", report.Failures)
+	assert.Equal(t, int64(40), report.BlockHeight)
+	assert.Equal(t, 13, report.Revision)
+	assert.Len(t, report.Terms, 2)
+
+	// decentralization and the penalty are reported in the term where they happened
+	term := report.Terms[1]
+	assert.Equal(t, int64(21), term.StartHeight)
+	assert.Equal(t, int64(40), term.EndHeight)
+	assert.Len(t, term.PReps, 5)
+	assert.Len(t, term.Penalties, 1)
+	assert.Equal(t, "validationFailure", term.Penalties[0].Type)
+	assert.Len(t, term.Validators, 2)
+	assert.Equal(t, 1, len(report.Terms[0].Validators))
+	assert.Len(t, report.Terms[0].Validators[0].Added, 4)
+}
+
+func TestRunScenario_Failures(t *testing.T) {
+	sc := &Scenario{
+		Accounts: map[string]*ScenarioAccount{
+			"alice": {Balance: &Amount{*icutils.ToLoop(100)}},
+		},
+		Steps: []*ScenarioStep{
+			{
+				Txs:    []ScenarioTx{{Type: "setStake", From: "alice", Amount: &Amount{*icutils.ToLoop(10)}}},
+				Blocks: 3,
+				Expect: &ScenarioExpect{
+					Stake:   map[string]*Amount{"alice": {*icutils.ToLoop(20)}},
+					Balance: map[string]*Amount{"alice": {*icutils.ToLoop(90)}},
+				},
+			},
+		},
+	}
+	report, err := RunScenario(sc)
+	assert.NoError(t, err)
+	assert.Len(t, report.Failures, 1)
+
+	sc.Steps[0].Txs[0].Type = "unknown"
+	_, err = RunScenario(sc)
+	assert.Error(t, err)
+}
@@ -801,7 +801,7 @@ func (sim *simulatorImpl) setCommissionRate(es *iiss.ExtensionStateImpl, cc *cal
 }
 
 func (sim *simulatorImpl) RequestUnjail(from module.Address) Transaction {
-	return NewTransaction(TypeSetCommissionRate, from)
+	return NewTransaction(TypeRequestUnjail, from)
 }
 
 func (sim *simulatorImpl) GoByRequestUnjail(
@@ -0,0 +1,379 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/icon/icdb"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+)
+
+// BreakdownConfig is configuration for reward breakdown.
+// Terms is the number of calculations to keep. Zero means no limit.
+type BreakdownConfig struct {
+	Enabled bool `json:"enabled"`
+	Terms   int  `json:"terms,omitempty"`
+}
+
+type VotedReward struct {
+	PRep   *common.Address
+	Status icmodule.EnableStatus
+	IScore *big.Int
+}
+
+func (v *VotedReward) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"address": v.PRep,
+		"status":  v.Status.String(),
+		"iscore":  v.IScore,
+	}
+}
+
+type Penalty struct {
+	Height int64
+	Status icmodule.EnableStatus
+}
+
+func (p *Penalty) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"blockHeight": p.Height,
+		"status":      p.Status.String(),
+	}
+}
+
+// Breakdown is the reward of an account for a calculation.
+type Breakdown struct {
+	StartHeight  int64
+	EndHeight    int64
+	BlockProduce *big.Int
+	PRep         *big.Int
+	Commission   *big.Int
+	Wage         *big.Int
+	Voter        *big.Int
+	Voted        []*VotedReward
+	Penalties    []*Penalty
+}
+
+func (b *Breakdown) ToJSON() map[string]interface{} {
+	voted := make([]interface{}, len(b.Voted))
+	for i, v := range b.Voted {
+		voted[i] = v.ToJSON()
+	}
+	penalties := make([]interface{}, len(b.Penalties))
+	for i, p := range b.Penalties {
+		penalties[i] = p.ToJSON()
+	}
+	return map[string]interface{}{
+		"startBlockHeight": b.StartHeight,
+		"endBlockHeight":   b.EndHeight,
+		"blockProduce":     b.BlockProduce,
+		"prep":             b.PRep,
+		"commission":       b.Commission,
+		"wage":             b.Wage,
+		"voter":            b.Voter,
+		"voted":            voted,
+		"penalties":        penalties,
+	}
+}
+
+func newBreakdown(startHeight, endHeight int64) *Breakdown {
+	return &Breakdown{
+		StartHeight:  startHeight,
+		EndHeight:    endHeight,
+		BlockProduce: new(big.Int),
+		PRep:         new(big.Int),
+		Commission:   new(big.Int),
+		Wage:         new(big.Int),
+		Voter:        new(big.Int),
+	}
+}
+
+// Breakdowns collects Breakdown of accounts during a calculation.
+// All methods can be called with nil, then it does nothing.
+type Breakdowns struct {
+	startHeight int64
+	endHeight   int64
+	items       map[string]*Breakdown
+}
+
+func (b *Breakdowns) StartHeight() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.startHeight
+}
+
+func (b *Breakdowns) Get(addr module.Address) *Breakdown {
+	if b == nil {
+		return nil
+	}
+	return b.items[icutils.ToKey(addr)]
+}
+
+func (b *Breakdowns) getOrNew(addr module.Address) *Breakdown {
+	key := icutils.ToKey(addr)
+	bd, ok := b.items[key]
+	if !ok {
+		bd = newBreakdown(b.startHeight, b.endHeight)
+		b.items[key] = bd
+	}
+	return bd
+}
+
+func (b *Breakdowns) AddReward(addr module.Address, t RewardType, reward *big.Int) {
+	if b == nil || reward.Sign() == 0 {
+		return
+	}
+	bd := b.getOrNew(addr)
+	switch t {
+	case RTBlockProduce:
+		bd.BlockProduce.Add(bd.BlockProduce, reward)
+	case RTPRep:
+		bd.PRep.Add(bd.PRep, reward)
+	case RTVoter:
+		bd.Voter.Add(bd.Voter, reward)
+	}
+}
+
+func (b *Breakdowns) SetPRepReward(addr module.Address, commission, wage *big.Int) {
+	if b == nil || (commission.Sign() == 0 && wage.Sign() == 0) {
+		return
+	}
+	bd := b.getOrNew(addr)
+	bd.Commission.Set(commission)
+	bd.Wage.Set(wage)
+}
+
+func (b *Breakdowns) AddPenalty(addr module.Address, height int64, status icmodule.EnableStatus) {
+	if b == nil {
+		return
+	}
+	switch status {
+	case icmodule.ESDisableTemp, icmodule.ESDisablePermanent, icmodule.ESJail:
+		bd := b.getOrNew(addr)
+		bd.Penalties = append(bd.Penalties, &Penalty{height, status})
+	}
+}
+
+// VotedRewardRecorder returns a function to be used with Voter.CalculateRewardWith.
+func (b *Breakdowns) VotedRewardRecorder(addr module.Address) func(prep *PRep, reward *big.Int) {
+	if b == nil {
+		return nil
+	}
+	return func(prep *PRep, reward *big.Int) {
+		bd := b.getOrNew(addr)
+		bd.Voted = append(bd.Voted, &VotedReward{
+			PRep:   common.AddressToPtr(prep.Owner()),
+			Status: prep.Status(),
+			IScore: reward,
+		})
+	}
+}
+
+func NewBreakdowns(startHeight, endHeight int64) *Breakdowns {
+	return &Breakdowns{
+		startHeight: startHeight,
+		endHeight:   endHeight,
+		items:       make(map[string]*Breakdown),
+	}
+}
+
+var (
+	breakdownTermsKey  = []byte("terms")
+	breakdownTermKey   = []byte("t")
+	breakdownRewardKey = []byte("r")
+)
+
+type breakdownTerm struct {
+	StartHeight int64
+	EndHeight   int64
+}
+
+// BreakdownStore stores Breakdowns to the database. It's not a part of
+// the state, so it's not synchronized and it's not kept on pruning.
+type BreakdownStore struct {
+	bk db.Bucket
+}
+
+func (s *BreakdownStore) getTerms() ([]breakdownTerm, error) {
+	var terms []breakdownTerm
+	bs, err := s.bk.Get(breakdownTermsKey)
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	if _, err = codec.BC.UnmarshalFromBytes(bs, &terms); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (s *BreakdownStore) setTerms(terms []breakdownTerm) error {
+	bs, err := codec.BC.MarshalToBytes(terms)
+	if err != nil {
+		return err
+	}
+	return s.bk.Set(breakdownTermsKey, bs)
+}
+
+func termKeyOf(height int64) []byte {
+	return append(append([]byte{}, breakdownTermKey...), intconv.Int64ToBytes(height)...)
+}
+
+func rewardKeyOf(addr []byte, height int64) []byte {
+	key := append(append([]byte{}, breakdownRewardKey...), addr...)
+	return append(key, intconv.Int64ToBytes(height)...)
+}
+
+func (s *BreakdownStore) deleteTerm(height int64) error {
+	key := termKeyOf(height)
+	bs, err := s.bk.Get(key)
+	if err != nil {
+		return err
+	}
+	if bs != nil {
+		var addrs [][]byte
+		if _, err = codec.BC.UnmarshalFromBytes(bs, &addrs); err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			if err = s.bk.Delete(rewardKeyOf(addr, height)); err != nil {
+				return err
+			}
+		}
+	}
+	return s.bk.Delete(key)
+}
+
+// Store writes Breakdowns of a calculation. If limit is positive, old
+// calculations are removed to keep limit calculations.
+func (s *BreakdownStore) Store(b *Breakdowns, limit int) error {
+	terms, err := s.getTerms()
+	if err != nil {
+		return err
+	}
+	idx := sort.Search(len(terms), func(i int) bool {
+		return terms[i].StartHeight >= b.startHeight
+	})
+	if idx < len(terms) && terms[idx].StartHeight == b.startHeight {
+		if err = s.deleteTerm(b.startHeight); err != nil {
+			return err
+		}
+		terms[idx].EndHeight = b.endHeight
+	} else {
+		terms = append(terms, breakdownTerm{})
+		copy(terms[idx+1:], terms[idx:])
+		terms[idx] = breakdownTerm{b.startHeight, b.endHeight}
+	}
+
+	addrs := make([][]byte, 0, len(b.items))
+	for key, bd := range b.items {
+		bs, err := codec.BC.MarshalToBytes(bd)
+		if err != nil {
+			return err
+		}
+		if err = s.bk.Set(rewardKeyOf([]byte(key), b.startHeight), bs); err != nil {
+			return err
+		}
+		addrs = append(addrs, []byte(key))
+	}
+	bs, err := codec.BC.MarshalToBytes(addrs)
+	if err != nil {
+		return err
+	}
+	if err = s.bk.Set(termKeyOf(b.startHeight), bs); err != nil {
+		return err
+	}
+
+	if limit > 0 && len(terms) > limit {
+		for _, term := range terms[:len(terms)-limit] {
+			if err = s.deleteTerm(term.StartHeight); err != nil {
+				return err
+			}
+		}
+		terms = terms[len(terms)-limit:]
+	}
+	return s.setTerms(terms)
+}
+
+// Prune removes Breakdowns of the calculations ended before height.
+func (s *BreakdownStore) Prune(height int64) error {
+	terms, err := s.getTerms()
+	if err != nil {
+		return err
+	}
+	idx := 0
+	for idx < len(terms) && terms[idx].EndHeight < height {
+		if err = s.deleteTerm(terms[idx].StartHeight); err != nil {
+			return err
+		}
+		idx += 1
+	}
+	if idx == 0 {
+		return nil
+	}
+	return s.setTerms(terms[idx:])
+}
+
+// Get returns Breakdowns of addr for the calculations overlapping
+// with the range from startHeight to endHeight.
+func (s *BreakdownStore) Get(addr module.Address, startHeight, endHeight int64) ([]*Breakdown, error) {
+	if startHeight > endHeight {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidRange(start=%d,end=%d)", startHeight, endHeight)
+	}
+	terms, err := s.getTerms()
+	if err != nil {
+		return nil, err
+	}
+	key := icutils.ToKey(addr)
+	var result []*Breakdown
+	for _, term := range terms {
+		if term.EndHeight < startHeight || term.StartHeight > endHeight {
+			continue
+		}
+		bs, err := s.bk.Get(rewardKeyOf([]byte(key), term.StartHeight))
+		if err != nil {
+			return nil, err
+		}
+		if bs == nil {
+			continue
+		}
+		bd := new(Breakdown)
+		if _, err = codec.BC.UnmarshalFromBytes(bs, bd); err != nil {
+			return nil, err
+		}
+		result = append(result, bd)
+	}
+	return result, nil
+}
+
+func NewBreakdownStore(dbase db.Database) (*BreakdownStore, error) {
+	bk, err := dbase.GetBucket(icdb.RewardBreakdown)
+	if err != nil {
+		return nil, err
+	}
+	return &BreakdownStore{bk: bk}, nil
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+)
+
+func TestBreakdowns_Nil(t *testing.T) {
+	var bds *Breakdowns
+	addr := common.MustNewAddressFromString("hx1")
+
+	bds.AddReward(addr, RTVoter, big.NewInt(100))
+	bds.SetPRepReward(addr, big.NewInt(10), big.NewInt(20))
+	bds.AddPenalty(addr, 10, icmodule.ESJail)
+	assert.Nil(t, bds.VotedRewardRecorder(addr))
+	assert.Nil(t, bds.Get(addr))
+	assert.Equal(t, int64(0), bds.StartHeight())
+}
+
+func TestBreakdowns_Collect(t *testing.T) {
+	bds := NewBreakdowns(100, 110)
+	a1 := common.MustNewAddressFromString("hx1")
+	a2 := common.MustNewAddressFromString("hx2")
+	prep := NewPRep(a2, icmodule.ESEnable, big.NewInt(100), big.NewInt(100), 0, true)
+
+	bds.AddReward(a1, RTBlockProduce, big.NewInt(1))
+	bds.AddReward(a1, RTVoter, big.NewInt(10))
+	bds.AddReward(a1, RTVoter, big.NewInt(20))
+	bds.AddReward(a2, RTVoter, new(big.Int))
+	bds.VotedRewardRecorder(a1)(prep, big.NewInt(30))
+	bds.SetPRepReward(a2, big.NewInt(5), big.NewInt(7))
+	bds.AddPenalty(a2, 105, icmodule.ESEnable)
+	bds.AddPenalty(a2, 106, icmodule.ESDisableTemp)
+
+	bd := bds.Get(a1)
+	assert.Equal(t, int64(100), bd.StartHeight)
+	assert.Equal(t, int64(110), bd.EndHeight)
+	assert.Equal(t, int64(1), bd.BlockProduce.Int64())
+	assert.Equal(t, int64(30), bd.Voter.Int64())
+	assert.Equal(t, 1, len(bd.Voted))
+	assert.True(t, a2.Equal(bd.Voted[0].PRep))
+	assert.Equal(t, int64(30), bd.Voted[0].IScore.Int64())
+
+	bd = bds.Get(a2)
+	assert.Equal(t, int64(0), bd.Voter.Int64())
+	assert.Equal(t, int64(5), bd.Commission.Int64())
+	assert.Equal(t, int64(7), bd.Wage.Int64())
+	assert.Equal(t, []*Penalty{{106, icmodule.ESDisableTemp}}, bd.Penalties)
+}
+
+func TestBreakdownStore(t *testing.T) {
+	store, err := NewBreakdownStore(db.NewMapDB())
+	assert.NoError(t, err)
+
+	a1 := common.MustNewAddressFromString("hx1")
+	a2 := common.MustNewAddressFromString("hx2")
+	for i := int64(0); i < 4; i++ {
+		bds := NewBreakdowns(i*10, i*10+9)
+		bds.AddReward(a1, RTVoter, big.NewInt(i+1))
+		if i%2 == 0 {
+			bds.AddReward(a2, RTPRep, big.NewInt(i+1))
+		}
+		assert.NoError(t, store.Store(bds, 3))
+	}
+
+	// the first term is removed
+	items, err := store.Get(a1, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(items))
+	for i, item := range items {
+		assert.Equal(t, int64(i+1)*10, item.StartHeight)
+		assert.Equal(t, int64(i+2), item.Voter.Int64())
+	}
+
+	items, err = store.Get(a2, 15, 25)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, int64(20), items[0].StartHeight)
+	assert.Equal(t, int64(3), items[0].PRep.Int64())
+
+	// storing the same term again replaces old one
+	bds := NewBreakdowns(30, 39)
+	bds.AddReward(a2, RTPRep, big.NewInt(100))
+	assert.NoError(t, store.Store(bds, 3))
+	items, err = store.Get(a1, 30, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(items))
+	items, err = store.Get(a2, 30, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, int64(100), items[0].PRep.Int64())
+
+	_, err = store.Get(a1, 10, 0)
+	assert.Error(t, err)
+}
+
+func TestBreakdownStore_Prune(t *testing.T) {
+	store, err := NewBreakdownStore(db.NewMapDB())
+	assert.NoError(t, err)
+
+	a1 := common.MustNewAddressFromString("hx1")
+	for i := int64(0); i < 4; i++ {
+		bds := NewBreakdowns(i*10, i*10+9)
+		bds.AddReward(a1, RTVoter, big.NewInt(i+1))
+		assert.NoError(t, store.Store(bds, 0))
+	}
+
+	// nothing is ended before the first term
+	assert.NoError(t, store.Prune(9))
+	items, err := store.Get(a1, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(items))
+
+	assert.NoError(t, store.Prune(25))
+	items, err = store.Get(a1, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, int64(20), items[0].StartHeight)
+	assert.Equal(t, int64(30), items[1].StartHeight)
+
+	// entries of removed terms are deleted
+	bds := NewBreakdowns(40, 49)
+	bds.AddReward(a1, RTVoter, big.NewInt(5))
+	assert.NoError(t, store.Store(bds, 0))
+	items, err = store.Get(a1, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(items))
+	bs, err := store.bk.Get(rewardKeyOf([]byte(icutils.ToKey(a1)), 0))
+	assert.NoError(t, err)
+	assert.Nil(t, bs)
+}
@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/icon-project/goloop/block"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/containerdb"
 	"github.com/icon-project/goloop/common/db"
@@ -44,6 +45,8 @@ type calculator struct {
 	global      icstage.Global
 	temp        *icreward.State
 	stats       *Stats
+	bdConfig    *BreakdownConfig
+	breakdowns  *Breakdowns
 
 	lock    sync.Mutex
 	waiters []*sync.Cond
@@ -118,6 +121,10 @@ func (c *calculator) setResult(result *icreward.Snapshot, err error) {
 	c.waiters = nil
 }
 
+func (c *calculator) Breakdowns() *Breakdowns {
+	return c.breakdowns
+}
+
 func (c *calculator) Stats() *Stats {
 	return c.stats
 }
@@ -156,6 +163,7 @@ func (c *calculator) UpdateIScore(addr module.Address, reward *big.Int, t Reward
 	}
 	c.log.Tracef("Update IScore %s by %d: %+v + %s = %+v", addr, t, iScore, reward, nIScore)
 	c.stats.IncreaseReward(t, reward)
+	c.breakdowns.AddReward(addr, t, reward)
 	return nil
 }
 
@@ -187,9 +195,32 @@ func (c *calculator) run() (ret error) {
 
 	c.log.Infof("Calculation statistics: %s", c.stats)
 	c.setResult(c.temp.GetSnapshot(), nil)
+	c.storeBreakdowns()
 	return nil
 }
 
+func (c *calculator) storeBreakdowns() {
+	if c.breakdowns == nil {
+		return
+	}
+	store, err := NewBreakdownStore(c.database)
+	if err == nil {
+		err = store.Store(c.breakdowns, c.bdConfig.Terms)
+	}
+	if err == nil {
+		// results of the calculations are applied to the states, so
+		// breakdowns of the calculations ended before the oldest state kept
+		// by the retention policy are removed like the states.
+		var height int64
+		if _, height, _, err = block.GetPruningStatus(c.database); err == nil && height > 0 {
+			err = store.Prune(height)
+		}
+	}
+	if err != nil {
+		c.log.Warnf("Failed to store reward breakdown. %+v", err)
+	}
+}
+
 func processClaim(ctx Context) error {
 	back := ctx.Back()
 	temp := ctx.Temp()
@@ -302,7 +333,7 @@ func processCommissionRate(ctx Context) error {
 
 const InitBlockHeight = -1
 
-func New(database db.Database, back *icstage.Snapshot, reward *icreward.Snapshot, logger log.Logger) *calculator {
+func New(database db.Database, back *icstage.Snapshot, reward *icreward.Snapshot, bdConfig *BreakdownConfig, logger log.Logger) *calculator {
 	var err error
 	var global icstage.Global
 	var startHeight int64
@@ -327,8 +358,12 @@ func New(database db.Database, back *icstage.Snapshot, reward *icreward.Snapshot
 		global:      global,
 		startHeight: startHeight,
 		stats:       NewStats(),
+		bdConfig:    bdConfig,
 	}
 	if startHeight != InitBlockHeight {
+		if bdConfig != nil && bdConfig.Enabled {
+			c.breakdowns = NewBreakdowns(startHeight, startHeight+int64(global.GetOffsetLimit()))
+		}
 		go c.run()
 	}
 	return c
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/icon/iiss/icreward"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+)
+
+// EstimateTermReward calculates reward of the PReps in pi for a term without
+// any vote event. So the votes of PReps are regarded as constant during the term.
+func EstimateTermReward(pi *PRepInfo, totalReward, totalMinWage, minBond *big.Int) error {
+	pi.Sort()
+	pi.InitAccumulated()
+	pi.UpdateTotalAccumulatedPower()
+	return pi.CalculateReward(totalReward, totalMinWage, minBond)
+}
+
+// EstimateAccountReward returns PRep reward and voter reward of owner for a term.
+// pi should be calculated by EstimateTermReward and votings should be reflected
+// to the votes of PReps in pi.
+func EstimateAccountReward(pi *PRepInfo, owner module.Address, votings ...icreward.Voting) (*big.Int, *big.Int) {
+	prepReward := new(big.Int)
+	if prep := pi.GetPRep(icutils.ToKey(owner)); prep != nil && prep.IsRewardable(pi.ElectedPRepCount()) {
+		prepReward.Set(prep.GetReward())
+	}
+
+	voter := NewVoter(owner, pi.log)
+	for _, voting := range votings {
+		if voting != nil {
+			voter.ApplyVoting(voting, pi.GetTermPeriod())
+		}
+	}
+	return prepReward, voter.CalculateReward(pi)
+}
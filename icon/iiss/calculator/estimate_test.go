@@ -0,0 +1,108 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icreward"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestEstimateAccountReward(t *testing.T) {
+	a1, _ := common.NewAddressFromString("hx1")
+	a2, _ := common.NewAddressFromString("hx2")
+	a3, _ := common.NewAddressFromString("hx3")
+	voter, _ := common.NewAddressFromString("hx100")
+
+	preps := []prep{
+		{a1, icmodule.ESEnable, 100, 100, true, 1000},
+		{a2, icmodule.ESEnable, 100, 300, true, 0},
+		{a3, icmodule.ESJail, 100, 1000, true, 0},
+	}
+	// term period is 10 and 2 PReps are elected
+	pi := newTestPRepInfo(preps, icmodule.ToRate(5), 9, 2)
+
+	// reward for a term : 10 * 1000 IScore for PRep, 20 * 1000 IScore for wage
+	monthBlock := big.NewInt(icmodule.MonthBlock)
+	err := EstimateTermReward(pi, monthBlock, new(big.Int).Mul(monthBlock, big.NewInt(2)), big.NewInt(100))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		owner       module.Address
+		delegations []interface{}
+		bonds       []interface{}
+		prepReward  int64
+		voterReward int64
+	}{
+		{
+			"PRep without votes",
+			a1,
+			nil,
+			nil,
+			// commission: 10000 * 2000 / 6000 * 10%, wage: 20000 / 2
+			333 + 10000,
+			0,
+		},
+		{
+			"Voter",
+			voter,
+			[]interface{}{
+				map[string]interface{}{"address": a1.String(), "value": "0x32"},
+			},
+			[]interface{}{
+				map[string]interface{}{"address": a2.String(), "value": "0x64"},
+			},
+			0,
+			// a1: 3000 * 500 / 2000, a2: 6666 * 1000 / 4000
+			750 + 1666,
+		},
+		{
+			"Voter of jailed PRep",
+			voter,
+			[]interface{}{
+				map[string]interface{}{"address": a3.String(), "value": "0x64"},
+			},
+			nil,
+			0,
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, err := icstate.NewDelegations(tt.delegations, 100)
+			assert.NoError(t, err)
+			bs, err := icstate.NewBonds(tt.bonds, icmodule.RevisionIISS4R1)
+			assert.NoError(t, err)
+
+			prepReward, voterReward := EstimateAccountReward(
+				pi, tt.owner,
+				&icreward.Delegating{Delegations: ds},
+				&icreward.Bonding{Bonds: bs},
+			)
+			assert.Equal(t, tt.prepReward, prepReward.Int64())
+			assert.Equal(t, tt.voterReward, voterReward.Int64())
+		})
+	}
+}
@@ -134,6 +134,7 @@ func (r *iiss4Reward) processEvents() error {
 			obj := icstage.ToEventEnable(o)
 			r.Logger().Tracef("get event at %d %+v", int(r.g.GetStartHeight())+keyOffset, obj)
 			r.pi.SetStatus(obj.Target(), obj.Status())
+			r.Breakdowns().AddPenalty(obj.Target(), r.g.GetStartHeight()+int64(keyOffset), obj.Status())
 		case icstage.TypeEventDelegation, icstage.TypeEventBond:
 			obj := icstage.ToEventVote(o)
 			r.Logger().Tracef("get event at %d %+v", int(r.g.GetStartHeight())+keyOffset, obj)
@@ -177,6 +178,7 @@ func (r *iiss4Reward) processPrepReward() error {
 	}
 
 	for _, prep := range r.pi.PReps() {
+		r.Breakdowns().SetPRepReward(prep.Owner(), prep.Commission(), prep.Wage())
 		if err = r.UpdateIScore(prep.Owner(), prep.GetReward(), RTPRep); err != nil {
 			return err
 		}
@@ -227,7 +229,7 @@ func (r *iiss4Reward) processVoterReward() error {
 			r.ve.SetCalculated(addr)
 		}
 
-		iscore := voter.CalculateReward(r.pi)
+		iscore := voter.CalculateRewardWith(r.pi, r.Breakdowns().VotedRewardRecorder(addr))
 		if err = r.UpdateIScore(voter.Owner(), iscore, RTVoter); err != nil {
 			return err
 		}
@@ -273,7 +275,7 @@ func (r *iiss4Reward) processVoterReward() error {
 			r.ve.SetCalculated(addr)
 		}
 
-		iscore := voter.CalculateReward(r.pi)
+		iscore := voter.CalculateRewardWith(r.pi, r.Breakdowns().VotedRewardRecorder(addr))
 		if err = r.UpdateIScore(voter.Owner(), iscore, RTVoter); err != nil {
 			return err
 		}
@@ -293,7 +295,7 @@ func (r *iiss4Reward) processVoterReward() error {
 		}
 		r.ve.SetCalculated(addr)
 
-		iscore := voter.CalculateReward(r.pi)
+		iscore := voter.CalculateRewardWith(r.pi, r.Breakdowns().VotedRewardRecorder(addr))
 		if err = r.UpdateIScore(voter.Owner(), iscore, RTVoter); err != nil {
 			return err
 		}
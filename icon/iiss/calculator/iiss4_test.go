@@ -40,10 +40,11 @@ type testCalculator struct {
 	reward *icreward.State
 	log    log.Logger
 
-	back  *icstage.Snapshot
-	base  *icreward.Snapshot
-	temp  *icreward.State
-	stats *Stats
+	back       *icstage.Snapshot
+	base       *icreward.Snapshot
+	temp       *icreward.State
+	stats      *Stats
+	breakdowns *Breakdowns
 }
 
 func (t *testCalculator) Back() *icstage.Snapshot {
@@ -62,6 +63,10 @@ func (t *testCalculator) Stats() *Stats {
 	return t.stats
 }
 
+func (t *testCalculator) Breakdowns() *Breakdowns {
+	return t.breakdowns
+}
+
 func (t *testCalculator) Logger() log.Logger {
 	return t.log
 }
@@ -76,6 +81,7 @@ func (t *testCalculator) UpdateIScore(addr module.Address, reward *big.Int, type
 		return err
 	}
 	t.stats.IncreaseReward(type_, reward)
+	t.breakdowns.AddReward(addr, type_, reward)
 	return nil
 }
 
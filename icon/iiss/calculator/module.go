@@ -30,6 +30,7 @@ type Context interface {
 	Base() *icreward.Snapshot
 	Temp() *icreward.State
 	Stats() *Stats
+	Breakdowns() *Breakdowns
 	Logger() log.Logger
 	UpdateIScore(addr module.Address, reward *big.Int, t RewardType) error
 }
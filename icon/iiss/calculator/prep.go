@@ -116,6 +116,14 @@ func (p *PRep) VoterReward() *big.Int {
 	return p.voterReward
 }
 
+func (p *PRep) Commission() *big.Int {
+	return p.commission
+}
+
+func (p *PRep) Wage() *big.Int {
+	return p.wage
+}
+
 func (p *PRep) GetReward() *big.Int {
 	return new(big.Int).Add(p.commission, p.wage)
 }
@@ -197,6 +197,11 @@ func (v *Voter) ApplyEvent(event *VoteEvent, period int) {
 }
 
 func (v *Voter) CalculateReward(pInfo *PRepInfo) *big.Int {
+	return v.CalculateRewardWith(pInfo, nil)
+}
+
+// CalculateRewardWith calculates voter reward and calls onReward with the reward from each PRep, if it's not nil.
+func (v *Voter) CalculateRewardWith(pInfo *PRepInfo, onReward func(prep *PRep, reward *big.Int)) *big.Int {
 	iScore := new(big.Int)
 
 	v.log.Tracef("Voter reward of %s", v.owner)
@@ -208,6 +213,9 @@ func (v *Voter) CalculateReward(pInfo *PRepInfo) *big.Int {
 			v.log.Tracef("vote reward for %s: %d = %d * %d / %d",
 				prep.Owner(), r, prep.VoterReward(), av, prep.AccumulatedVoted())
 			iScore.Add(iScore, r)
+			if onReward != nil {
+				onReward(prep, r)
+			}
 		}
 	}
 	v.log.Tracef("Voter reward of %s = %d", v.owner, iScore)
@@ -25,8 +25,18 @@ import (
 )
 
 type CalculatorHolder struct {
-	lock   sync.Mutex
-	runner Calculator
+	lock     sync.Mutex
+	runner   Calculator
+	bdConfig *calculator.BreakdownConfig
+}
+
+// SetBreakdownConfig sets configuration for reward breakdown, which is
+// applied from the next calculation.
+func (h *CalculatorHolder) SetBreakdownConfig(cfg *calculator.BreakdownConfig) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.bdConfig = cfg
 }
 
 func (h *CalculatorHolder) Start(ess state.ExtensionSnapshot, logger log.Logger) {
@@ -34,7 +44,7 @@ func (h *CalculatorHolder) Start(ess state.ExtensionSnapshot, logger log.Logger)
 	defer h.lock.Unlock()
 
 	if ess != nil {
-		h.runner = updateCalculator(h.runner, ess, logger)
+		h.runner = updateCalculator(h.runner, ess, h.bdConfig, logger)
 	} else {
 		if h.runner != nil {
 			h.runner.Stop()
@@ -50,7 +60,7 @@ func (h *CalculatorHolder) Get() Calculator {
 	return h.runner
 }
 
-func updateCalculator(c Calculator, ess state.ExtensionSnapshot, logger log.Logger) Calculator {
+func updateCalculator(c Calculator, ess state.ExtensionSnapshot, bdConfig *calculator.BreakdownConfig, logger log.Logger) Calculator {
 	essi := ess.(*ExtensionSnapshotImpl)
 	back := essi.Back2()
 	reward := essi.Reward()
@@ -60,5 +70,5 @@ func updateCalculator(c Calculator, ess state.ExtensionSnapshot, logger log.Logg
 		}
 		c.Stop()
 	}
-	return calculator.New(essi.DB(), back, reward, logger)
+	return calculator.New(essi.DB(), back, reward, bdConfig, logger)
 }
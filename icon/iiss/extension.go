@@ -30,6 +30,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/merkle"
 	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/calculator"
 	"github.com/icon-project/goloop/icon/iiss/icobject"
 	"github.com/icon-project/goloop/icon/iiss/icreward"
 	"github.com/icon-project/goloop/icon/iiss/icstage"
@@ -127,6 +128,11 @@ func (s *ExtensionSnapshotImpl) NewState(readonly bool) state.ExtensionState {
 	}
 }
 
+func (s *ExtensionSnapshotImpl) ManagesValidators() bool {
+	es := icstate.NewStateFromSnapshot(s.state, true, icutils.NewIconLogger(nil))
+	return es.GetValidatorsSnapshot() != nil
+}
+
 func NewExtensionSnapshot(database db.Database, hash []byte) state.ExtensionSnapshot {
 	if hash == nil {
 		return &ExtensionSnapshotImpl{
@@ -1140,6 +1146,16 @@ func (es *ExtensionStateImpl) updateValidators(wc icmodule.WorldContext, isTermE
 	return err
 }
 
+// ReplaceValidators implements state.ValidatorReplacer. The P-Reps of the
+// current validators are bound to the new validators.
+func (es *ExtensionStateImpl) ReplaceValidators(validators []module.Validator) error {
+	nodes := make([]module.Address, len(validators))
+	for i, v := range validators {
+		nodes[i] = v.Address()
+	}
+	return es.State.ReplaceValidators(nodes)
+}
+
 type scForGetPRepTerm struct {
 	icmodule.StateContext
 }
@@ -2052,3 +2068,108 @@ func (es *ExtensionStateImpl) SetMinimumBond(cc icmodule.CallContext, nBond *big
 	EmitMinimumBondSetEvent(cc, nBond)
 	return nil
 }
+
+// EstimateReward projects I-Score of owner for the next terms.
+// Rewards are calculated with current votes of PReps, and the votes are regarded
+// as constant during the terms. If delegations or bonds is not nil, it's used
+// instead of those of owner.
+func (es *ExtensionStateImpl) EstimateReward(
+	cc icmodule.CallContext, owner module.Address, terms int64, delegations icstate.Delegations, bonds icstate.Bonds,
+) (map[string]interface{}, error) {
+	if terms <= 0 {
+		return nil, scoreresult.InvalidParameterError.Errorf("InvalidTerms(%d)", terms)
+	}
+	sc := NewStateContext(cc, es)
+	term := es.State.GetTermSnapshot()
+	if term == nil || !term.IsDecentralized() || sc.TermIISSVersion() < icstate.IISSVersion4 {
+		return nil, icmodule.NotReadyError.New("RewardEstimationNotSupported")
+	}
+
+	account := es.State.GetAccountSnapshot(owner)
+	if account == nil {
+		account = icstate.GetEmptyAccountSnapshot()
+	}
+	if delegations == nil {
+		delegations = account.Delegations()
+	}
+	if bonds == nil {
+		bonds = account.Bonds()
+	}
+	dDelta := account.Delegations().Delta(delegations)
+	bDelta := account.Bonds().Delta(bonds)
+
+	period := es.State.GetTermPeriod()
+	pi := calculator.NewPRepInfo(
+		es.State.GetBondRequirement(), term.GetElectedPRepCount(), int(period-1), es.logger)
+	dsaMask := sc.GetActiveDSAMask()
+	for _, prep := range es.State.GetPReps(true) {
+		key := icutils.ToKey(prep.Owner())
+		delegated := new(big.Int).Set(prep.Delegated())
+		if delta, ok := dDelta[key]; ok {
+			delegated.Add(delegated, delta)
+		}
+		bonded := new(big.Int).Set(prep.Bonded())
+		if delta, ok := bDelta[key]; ok {
+			bonded.Add(bonded, delta)
+		}
+		status := icmodule.ESEnable
+		if !prep.IsJailInfoElectable() {
+			status = icmodule.ESJail
+		}
+		pi.Add(prep.Owner(), status, delegated, bonded, prep.CommissionRate(), prep.HasPubKey(dsaMask))
+	}
+
+	rf := es.State.GetRewardFund(cc.Revision().Value())
+	minBond := es.State.GetMinimumBond()
+	if minBond == nil {
+		minBond = new(big.Int)
+	}
+	if err := calculator.EstimateTermReward(
+		pi, rf.GetAmount(icstate.KeyIprep), rf.GetAmount(icstate.KeyIwage), minBond,
+	); err != nil {
+		return nil, err
+	}
+	prepReward, voterReward := calculator.EstimateAccountReward(
+		pi, owner,
+		&icreward.Delegating{Delegations: delegations},
+		&icreward.Bonding{Bonds: bonds},
+	)
+
+	reward := new(big.Int).Add(prepReward, voterReward)
+	total := new(big.Int).Mul(reward, big.NewInt(terms))
+	return map[string]interface{}{
+		"blockHeight":      cc.BlockHeight(),
+		"startBlockHeight": term.GetEndHeight() + 1,
+		"termPeriod":       period,
+		"terms":            terms,
+		"prepReward":       prepReward,
+		"voterReward":      voterReward,
+		"iscorePerTerm":    reward,
+		"iscore":           total,
+		"estimatedICX":     icutils.IScoreToICX(total),
+	}, nil
+}
+
+// GetRewardBreakdown returns reward breakdowns of owner for the calculations
+// overlapping with the range from startHeight to endHeight.
+// Breakdowns are stored only if it's enabled by platform configuration.
+func (es *ExtensionStateImpl) GetRewardBreakdown(
+	owner module.Address, startHeight, endHeight int64,
+) (map[string]interface{}, error) {
+	store, err := calculator.NewBreakdownStore(es.database)
+	if err != nil {
+		return nil, err
+	}
+	bds, err := store.Get(owner, startHeight, endHeight)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]interface{}, len(bds))
+	for i, bd := range bds {
+		items[i] = bd.ToJSON()
+	}
+	return map[string]interface{}{
+		"address":    owner,
+		"breakdowns": items,
+	}, nil
+}
@@ -70,6 +70,14 @@ func (p *PRep) Info() *PRepInfo {
 	return pb.info()
 }
 
+func (p *PRep) CommissionRate() icmodule.Rate {
+	pb := p.getPRepBaseState()
+	if pb == nil {
+		return 0
+	}
+	return pb.CommissionRate()
+}
+
 func (p *PRep) HasPubKey(dsaMask int64) bool {
 	return p.GetDSAMask()&dsaMask == dsaMask
 }
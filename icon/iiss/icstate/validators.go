@@ -361,6 +361,41 @@ func (s *State) changeValidatorNodeAddress(
 	return s.SetValidatorsSnapshot(vs.GetSnapshot())
 }
 
+// ReplaceValidators makes the nodes the validators for forking the chain.
+// The i-th node becomes the node of the owner of the i-th validator, and
+// the number of main P-Reps is set to the number of the nodes, so that they
+// keep validating on the following terms unless the ranking of the P-Reps
+// changes. It ignores the rules applied by transactions on purpose.
+func (s *State) ReplaceValidators(nodes []module.Address) error {
+	vss := s.GetValidatorsSnapshot()
+	if vss == nil {
+		return errors.InvalidStateError.New("NoValidatorsSnapshot")
+	}
+	if len(nodes) == 0 || len(nodes) > vss.Len() {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidValidatorCount(count=%d,max=%d)", len(nodes), vss.Len())
+	}
+	for i, node := range nodes {
+		owner := s.GetOwnerByNode(vss.Get(i))
+		pb := s.GetPRepBaseByOwner(owner, false)
+		if pb == nil {
+			return errors.InvalidStateError.Errorf("PRepNotFound(owner=%s)", owner)
+		}
+		if err := s.updatePRepInfoOf(owner, pb, &PRepInfo{Node: node}); err != nil {
+			return err
+		}
+	}
+	if err := s.SetMainPRepCount(int64(len(nodes))); err != nil {
+		return err
+	}
+	if err := s.SetExtraMainPRepCount(0); err != nil {
+		return err
+	}
+	vd := newValidatorsData(nodes)
+	vd.lastHeight = vss.lastHeight
+	return s.SetValidatorsSnapshot(&ValidatorsSnapshot{validatorsData: vd})
+}
+
 func (s *State) replaceMainPRepByOwner(sc icmodule.StateContext, owner module.Address) error {
 	node := s.GetNodeByOwner(owner)
 	blockHeight := sc.BlockHeight()
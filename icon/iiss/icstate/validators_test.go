@@ -1,11 +1,13 @@
 package icstate
 
 import (
+	"math/big"
 	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
 )
@@ -181,3 +183,54 @@ func TestValidatorsState_Set(t *testing.T) {
 		assert.Panicsf(t, func() { vss2.IsUpdated(bh - 1) }, "ValidatorsState.IsUpdate() did not panic")
 	}
 }
+
+func TestState_ReplaceValidators(t *testing.T) {
+	size := 4
+	s := newDummyState(false)
+	err := s.ReplaceValidators(newDummyAddresses(1))
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	pss := make(PRepSnapshots, size)
+	for i := 0; i < size; i++ {
+		owner := newDummyAddress(i + 1)
+		ri := newDummyPRepInfo(i)
+		ri.Node = newDummyAddress(i + 101)
+		assert.NoError(t, s.RegisterPRep(owner, ri, big.NewInt(0), 0))
+		pss[i] = NewPRepSnapshot(owner, big.NewInt(int64(size-i)))
+	}
+	assert.NoError(t, s.SetValidatorsSnapshot(NewValidatorsSnapshotWithPRepSnapshot(pss, s, size)))
+	assert.NoError(t, s.SetMainPRepCount(int64(size)))
+
+	for _, n := range []int{0, size + 1} {
+		err = s.ReplaceValidators(newDummyAddresses(n))
+		assert.True(t, errors.IllegalArgumentError.Equals(err))
+	}
+
+	// node used by other PRep
+	err = s.ReplaceValidators([]module.Address{newDummyAddress(2)})
+	assert.Error(t, err)
+
+	nodes := []module.Address{newDummyAddress(201), newDummyAddress(202)}
+	assert.NoError(t, s.ReplaceValidators(nodes))
+
+	vss := s.GetValidatorsSnapshot()
+	assert.Equal(t, len(nodes), vss.Len())
+	for i, node := range nodes {
+		owner := newDummyAddress(i + 1)
+		assert.True(t, node.Equal(vss.Get(i)))
+		assert.True(t, node.Equal(s.GetNodeByOwner(owner)))
+		assert.True(t, owner.Equal(s.GetOwnerByNode(node)))
+	}
+	for i := len(nodes); i < size; i++ {
+		assert.True(t, newDummyAddress(i+101).Equal(s.GetNodeByOwner(newDummyAddress(i+1))))
+	}
+	assert.Equal(t, int64(len(nodes)), s.GetMainPRepCount())
+	assert.Zero(t, s.GetExtraMainPRepCount())
+
+	// the validators follow the ranking of P-Reps on the next term
+	vss = NewValidatorsSnapshotWithPRepSnapshot(pss, s, int(s.GetMainPRepCount()))
+	assert.Equal(t, len(nodes), vss.Len())
+	for i, node := range nodes {
+		assert.True(t, node.Equal(vss.Get(i)))
+	}
+}
@@ -254,6 +254,10 @@ func (sm *ServiceManager) GetMinimizeBlockGen(result []byte) bool {
 	return true
 }
 
+func (sm *ServiceManager) GetConsensusTimeouts(result []byte) *module.ConsensusTimeouts {
+	return nil
+}
+
 func (sm *ServiceManager) GetNextBlockVersion(result []byte) int {
 	return module.BlockVersion2
 }
@@ -274,6 +278,10 @@ func (sm *ServiceManager) ExportResult(result []byte, vh []byte, dst db.Database
 	return errors.ErrInvalidState
 }
 
+func (sm *ServiceManager) ExportState(result []byte, vh []byte, dst db.Database) error {
+	return errors.ErrInvalidState
+}
+
 func (sm *ServiceManager) ImportResult(result []byte, vh []byte, src db.Database) error {
 	return errors.ErrInvalidState
 }
@@ -377,6 +385,10 @@ func (sm *ServiceManager) GetSCOREStatus(result []byte, addr module.Address) (mo
 	return nil, common.ErrInvalidState
 }
 
+func (sm *ServiceManager) GetAccountProof(result []byte, addr module.Address, keys [][]byte) (module.AccountProof, error) {
+	return nil, common.ErrInvalidState
+}
+
 func NewServiceManagerWithExecutor(chain module.Chain, ex *Executor, ps BlockV1ProofStorage, vs []*common.Address, cb ImportCallback) (*ServiceManager, error) {
 	logger := chain.Logger()
 	dbase := chain.Database()
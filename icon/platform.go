@@ -36,6 +36,7 @@ import (
 	"github.com/icon-project/goloop/icon/icconsensus"
 	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/calculator"
 	"github.com/icon-project/goloop/icon/iiss/iccache"
 	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/icon/merkle/hexary"
@@ -51,6 +52,7 @@ import (
 
 type platform struct {
 	calculator iiss.CalculatorHolder
+	monitor    *prepMonitor
 	base       string
 }
 
@@ -184,6 +186,15 @@ func (p *platform) OnExecutionEnd(wc state.WorldContext, er base.ExecutionResult
 	return es.OnExecutionEnd(iiss.NewWorldContext(wc, logger), totalFee, p.calculator.Get())
 }
 
+func (p *platform) OnResultFinalization(height int64, ess state.ExtensionSnapshot, logger log.Logger) {
+	if ess == nil {
+		return
+	}
+	if es, ok := ess.NewState(true).(*iiss.ExtensionStateImpl); ok {
+		p.monitor.Check(es, height, logger)
+	}
+}
+
 func (p *platform) OnTransactionEnd(wc state.WorldContext, logger log.Logger, rct txresult.Receipt) error {
 	success := rct.Status() == module.StatusSuccess
 	// Apply stored tx batch data
@@ -220,6 +231,7 @@ func (p *platform) NewBlockHandlers(c base.Chain) []base.BlockHandler {
 }
 
 func (p *platform) NewConsensus(c base.Chain, walDir string) (module.Consensus, error) {
+	p.monitor.SetNode(c.Wallet().Address())
 	if p.DefaultBlockVersionFor(c.CID()) != module.BlockVersion1 {
 		return basic.Platform.NewConsensus(c, walDir)
 	}
@@ -284,9 +296,29 @@ func (p *platform) SetBlockV1Proof(root []byte, size int64, votes *blockv0.Block
 	return os.WriteFile(file, bs, os.FileMode(0500))
 }
 
+type platformConfig struct {
+	RewardBreakdown *calculator.BreakdownConfig `json:"rewardBreakdown,omitempty"`
+	PRepMonitor     *PRepMonitorConfig          `json:"prepMonitor,omitempty"`
+}
+
+func (p *platform) Configure(raw json.RawMessage) error {
+	var cfg platformConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return errors.IllegalArgumentError.Wrapf(err, "InvalidPlatformConfig(%s)", raw)
+	}
+	if cfg.RewardBreakdown != nil && cfg.RewardBreakdown.Terms < 0 {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidRewardBreakdownTerms(%d)", cfg.RewardBreakdown.Terms)
+	}
+	p.calculator.SetBreakdownConfig(cfg.RewardBreakdown)
+	p.monitor.Configure(cfg.PRepMonitor)
+	return nil
+}
+
 func NewPlatform(base string, cid int) (base.Platform, error) {
 	return &platform{
-		base: base,
+		base:    base,
+		monitor: newPRepMonitor(cid),
 	}, nil
 }
 
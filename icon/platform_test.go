@@ -17,15 +17,25 @@
 package icon
 
 import (
+	"fmt"
+	"math/big"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/icon/blockv0"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
 	"github.com/icon-project/goloop/icon/lcimporter"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/state"
 )
 
 func TestPlatform_BlockV1Proof(t *testing.T) {
@@ -54,3 +64,90 @@ func TestPlatform_BlockV1Proof(t *testing.T) {
 	assert.Equal(t, root, mh2.RootHash)
 	assert.Equal(t, height, mh2.Leaves)
 }
+
+func validatorsOfNodes(t *testing.T, nodes ...module.Address) []module.Validator {
+	vs := make([]module.Validator, len(nodes))
+	for i, node := range nodes {
+		v, err := state.ValidatorFromAddress(node)
+		assert.NoError(t, err)
+		vs[i] = v
+	}
+	return vs
+}
+
+func extensionStateOf(t *testing.T, wss state.WorldSnapshot) *iiss.ExtensionStateImpl {
+	es, ok := wss.GetExtensionSnapshot().NewState(true).(*iiss.ExtensionStateImpl)
+	assert.True(t, ok)
+	return es
+}
+
+func TestPlatform_ForkResult(t *testing.T) {
+	base, err := os.MkdirTemp("", "platform*")
+	assert.NoError(t, err)
+	defer func(t *testing.T) {
+		assert.NoError(t, os.RemoveAll(base))
+	}(t)
+
+	plt, err := NewPlatform(base, 1)
+	assert.NoError(t, err)
+
+	// decentralized state with 4 main P-Reps
+	src := db.NewMapDB()
+	es := iiss.NewExtensionSnapshot(src, nil).NewState(false).(*iiss.ExtensionStateImpl)
+	var owners, nodes []module.Address
+	pss := make(icstate.PRepSnapshots, 4)
+	for i := range pss {
+		owner := common.MustNewAddressFromString(fmt.Sprintf("hx%040x", i+1))
+		node := common.MustNewAddressFromString(fmt.Sprintf("hx%040x", i+0x101))
+		name := fmt.Sprintf("prep%d", i)
+		assert.NoError(t, es.State.RegisterPRep(owner, &icstate.PRepInfo{Name: &name, Node: node}, new(big.Int), 0))
+		pss[i] = icstate.NewPRepSnapshot(owner, big.NewInt(int64(100-i)))
+		owners = append(owners, owner)
+		nodes = append(nodes, node)
+	}
+	assert.NoError(t, es.State.SetValidatorsSnapshot(
+		icstate.NewValidatorsSnapshotWithPRepSnapshot(pss, es.State, len(pss))))
+	assert.NoError(t, es.State.SetMainPRepCount(int64(len(pss))))
+
+	ws := state.NewWorldState(src, nil, nil, es.GetSnapshot(), nil)
+	assert.NoError(t, ws.GetValidatorState().Set(validatorsOfNodes(t, nodes...)))
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	result := codec.BC.MustMarshalToBytes([][]byte{
+		wss.StateHash(), nil, nil, wss.ExtensionData(),
+	})
+
+	newNode := common.MustNewAddressFromString("hx0000000000000000000000000000000000000201")
+	dst := db.NewMapDB()
+	nr, vh, err := service.ForkResult(src, plt, result, 0x99, 0x99, validatorsOfNodes(t, newNode), dst)
+	assert.NoError(t, err)
+
+	vl, err := state.ValidatorSnapshotFromHash(dst, vh)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, vl.Len())
+	assert.Equal(t, 0, vl.IndexOf(newNode))
+
+	// the extension keeps the new validators
+	nwss, err := service.NewWorldSnapshot(dst, plt, nr, vl)
+	assert.NoError(t, err)
+	nes := extensionStateOf(t, nwss)
+	vss := nes.State.GetValidatorsSnapshot()
+	assert.Equal(t, 1, vss.Len())
+	assert.True(t, newNode.Equal(vss.Get(0)))
+	assert.True(t, newNode.Equal(nes.State.GetNodeByOwner(owners[0])))
+	assert.True(t, owners[0].Equal(nes.State.GetOwnerByNode(newNode)))
+	assert.True(t, nodes[1].Equal(nes.State.GetNodeByOwner(owners[1])))
+	assert.EqualValues(t, 1, nes.State.GetMainPRepCount())
+
+	// source isn't modified
+	swss, err := service.NewWorldSnapshot(src, plt, result, nil)
+	assert.NoError(t, err)
+	ses := extensionStateOf(t, swss)
+	assert.Equal(t, len(nodes), ses.State.GetValidatorsSnapshot().Len())
+	assert.True(t, nodes[0].Equal(ses.State.GetNodeByOwner(owners[0])))
+
+	// more validators than the P-Reps of the validators
+	_, _, err = service.ForkResult(src, plt, result, 0x99, 0x99,
+		validatorsOfNodes(t, append(nodes, newNode)...), db.NewMapDB())
+	assert.Error(t, err)
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icon
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/server/notify"
+)
+
+const defaultPRepWarningRate = 50
+
+const (
+	// PRepWarningValidationFailure is raised when consecutive validation failures
+	// of the PRep reach the warning level of validationPenaltyCondition.
+	PRepWarningValidationFailure = "validationFailure"
+	// PRepWarningConsistentPenalty is raised instead of PRepWarningValidationFailure
+	// if the next validation penalty triggers the consistent validation penalty.
+	PRepWarningConsistentPenalty = "consistentValidationPenalty"
+	// PRepWarningPenalized is raised when the validation penalty is imposed.
+	PRepWarningPenalized = "penalized"
+)
+
+// PRepMonitorConfig is configuration for the PRep monitor.
+// The PRep of the node is always monitored if it's enabled.
+type PRepMonitorConfig struct {
+	Enabled bool              `json:"enabled"`
+	PReps   []*common.Address `json:"preps,omitempty"`
+	All     bool              `json:"all,omitempty"`
+	// WarningRate is the rate of validationPenaltyCondition in percent
+	// to raise PRepWarningValidationFailure.
+	WarningRate int `json:"warningRate,omitempty"`
+}
+
+type PRepWarning struct {
+	Height              common.HexInt64 `json:"height"`
+	Address             *common.Address `json:"address"`
+	Type                string          `json:"type"`
+	VFailCont           common.HexInt64 `json:"validationFailureCont"`
+	Condition           common.HexInt64 `json:"validationPenaltyCondition"`
+	Penalties           common.HexInt32 `json:"penalties"`
+	ConsistentCondition common.HexInt64 `json:"consistentValidationPenaltyCondition"`
+}
+
+type prepWatch struct {
+	warned    bool
+	penalized bool
+}
+
+type prepMonitor struct {
+	lock sync.Mutex
+
+	cid        int
+	cfg        PRepMonitorConfig
+	node       module.Address
+	lastHeight int64
+	watches    map[string]*prepWatch
+	metric     *metric.PRepMetric
+}
+
+func (m *prepMonitor) Configure(cfg *PRepMonitorConfig) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if cfg == nil {
+		m.cfg = PRepMonitorConfig{}
+	} else {
+		m.cfg = *cfg
+	}
+	if m.cfg.WarningRate <= 0 || m.cfg.WarningRate > 100 {
+		m.cfg.WarningRate = defaultPRepWarningRate
+	}
+	m.watches = make(map[string]*prepWatch)
+}
+
+// SetNode sets the address of the node to monitor the PRep of the node.
+func (m *prepMonitor) SetNode(node module.Address) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.node = node
+}
+
+func (m *prepMonitor) targets(es *iiss.ExtensionStateImpl) []module.Address {
+	var owners []module.Address
+	keys := make(map[string]bool)
+	add := func(owner module.Address) {
+		if owner == nil {
+			return
+		}
+		key := icutils.ToKey(owner)
+		if !keys[key] {
+			keys[key] = true
+			owners = append(owners, owner)
+		}
+	}
+	if m.node != nil {
+		add(es.State.GetOwnerByNode(m.node))
+	}
+	for _, owner := range m.cfg.PReps {
+		add(owner)
+	}
+	if m.cfg.All {
+		if vss := es.State.GetValidatorsSnapshot(); vss != nil {
+			for i := 0; i < vss.Len(); i++ {
+				add(es.State.GetOwnerByNode(vss.Get(i)))
+			}
+		}
+	}
+	return owners
+}
+
+// Check examines validation status of the PReps at the block height and emits
+// warnings through logs, metrics and notifications. It's called on
+// finalization of the result of each block, and it ignores blocks already
+// checked.
+func (m *prepMonitor) Check(es *iiss.ExtensionStateImpl, height int64, logger log.Logger) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.cfg.Enabled || height <= m.lastHeight {
+		return
+	}
+	m.lastHeight = height
+
+	term := es.State.GetTermSnapshot()
+	if term == nil || !term.IsDecentralized() {
+		return
+	}
+	condition := es.State.GetValidationPenaltyCondition()
+	cCondition := es.State.GetConsistentValidationPenaltyCondition()
+	for _, owner := range m.targets(es) {
+		ps := es.State.GetPRepStatusByOwner(owner, false)
+		if ps == nil || !ps.IsActive() {
+			continue
+		}
+		vFailCont := ps.GetVFailCont(height)
+		penalties := ps.GetVPenaltyCount()
+		if m.metric != nil {
+			m.metric.OnStatus(owner.String(), vFailCont, int64(penalties))
+		}
+		w := m.evaluate(owner, height, vFailCont, penalties, ps.IsAlreadyPenalized(), condition, cCondition)
+		if w == nil {
+			continue
+		}
+		logger.Warnf("PRepWarning(type=%s,prep=%s,height=%d,fail_cont=%d/%d,penalties=%d/%d)",
+			w.Type, owner, height, vFailCont, condition, w.Penalties.Value, cCondition)
+		if m.metric != nil {
+			m.metric.OnWarning(owner.String(), w.Type)
+		}
+		notify.Publish(m.cid, notify.TopicPRep, w)
+	}
+}
+
+// evaluate returns a warning if the status of the PRep gets worse than before.
+func (m *prepMonitor) evaluate(
+	owner module.Address, height, vFailCont int64, penalties int, penalized bool, condition, cCondition int64,
+) *PRepWarning {
+	key := icutils.ToKey(owner)
+	watch, ok := m.watches[key]
+	if !ok {
+		watch = new(prepWatch)
+		m.watches[key] = watch
+	}
+
+	var wType string
+	if penalized {
+		if !watch.penalized {
+			wType = PRepWarningPenalized
+		}
+		watch.warned = false
+	} else if threshold := m.warningLevel(condition); threshold > 0 && vFailCont >= threshold {
+		if !watch.warned {
+			if cCondition > 0 && int64(penalties)+1 >= cCondition {
+				wType = PRepWarningConsistentPenalty
+			} else {
+				wType = PRepWarningValidationFailure
+			}
+			watch.warned = true
+		}
+	} else {
+		watch.warned = false
+	}
+	watch.penalized = penalized
+
+	if len(wType) == 0 {
+		return nil
+	}
+	return &PRepWarning{
+		Height:              common.HexInt64{Value: height},
+		Address:             common.AddressToPtr(owner),
+		Type:                wType,
+		VFailCont:           common.HexInt64{Value: vFailCont},
+		Condition:           common.HexInt64{Value: condition},
+		Penalties:           common.HexInt32{Value: int32(penalties)},
+		ConsistentCondition: common.HexInt64{Value: cCondition},
+	}
+}
+
+func (m *prepMonitor) warningLevel(condition int64) int64 {
+	if condition <= 0 {
+		return 0
+	}
+	level := condition * int64(m.cfg.WarningRate) / 100
+	if level < 1 {
+		level = 1
+	}
+	return level
+}
+
+func newPRepMonitor(cid int) *prepMonitor {
+	m := &prepMonitor{
+		cid:    cid,
+		metric: metric.NewPRepMetric(metric.GetMetricContextByCID(cid)),
+	}
+	m.Configure(nil)
+	return m
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+)
+
+func TestPRepMonitor_Evaluate(t *testing.T) {
+	m := newPRepMonitor(1)
+	m.Configure(&PRepMonitorConfig{Enabled: true, WarningRate: 50})
+	owner := common.MustNewAddressFromString("hx1")
+
+	type status struct {
+		vFailCont int64
+		penalties int
+		penalized bool
+	}
+	tests := []struct {
+		name   string
+		status status
+		wType  string
+	}{
+		{"Normal", status{0, 0, false}, ""},
+		{"BelowLevel", status{14, 0, false}, ""},
+		{"ReachLevel", status{15, 0, false}, PRepWarningValidationFailure},
+		{"AlreadyWarned", status{20, 0, false}, ""},
+		{"Penalized", status{30, 1, true}, PRepWarningPenalized},
+		{"StillPenalized", status{31, 1, true}, ""},
+		{"Recovered", status{0, 1, false}, ""},
+		{"ReachLevelAgain", status{15, 1, false}, PRepWarningValidationFailure},
+		{"Success", status{0, 4, false}, ""},
+		{"BeforeConsistentPenalty", status{16, 4, false}, PRepWarningConsistentPenalty},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := m.evaluate(owner, int64(i+1), tt.status.vFailCont, tt.status.penalties, tt.status.penalized, 30, 5)
+			if len(tt.wType) == 0 {
+				assert.Nil(t, w)
+				return
+			}
+			assert.NotNil(t, w)
+			assert.Equal(t, tt.wType, w.Type)
+			assert.True(t, owner.Equal(w.Address))
+			assert.Equal(t, int64(i+1), w.Height.Value)
+			assert.Equal(t, tt.status.vFailCont, w.VFailCont.Value)
+		})
+	}
+}
+
+func TestPRepMonitor_WarningLevel(t *testing.T) {
+	m := newPRepMonitor(1)
+	assert.Equal(t, int64(0), m.warningLevel(0))
+	assert.Equal(t, int64(1), m.warningLevel(1))
+	assert.Equal(t, int64(330), m.warningLevel(660))
+
+	m.Configure(&PRepMonitorConfig{Enabled: true, WarningRate: 80})
+	assert.Equal(t, int64(528), m.warningLevel(660))
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 )
 
 const (
@@ -107,6 +108,39 @@ type BlockDataFactory interface {
 // Some of items may not be counted.
 type ProgressCallback func(height int64, resolved, unresolved int) error
 
+// MinRetention is the minimum number of blocks for the retention of
+// states and receipts. The block manager requires the states of the last
+// two blocks.
+const MinRetention = 2
+
+// RetentionPolicy is the policy of the pruning running in the background.
+// State and Receipts are the number of recent blocks to keep their states
+// and receipts, and zero means keeping them forever. Blocks are always
+// kept. Interval is the delay between pruning steps in milliseconds, and
+// zero means the default value.
+type RetentionPolicy struct {
+	State    int64 `json:"state,omitempty"`
+	Receipts int64 `json:"receipts,omitempty"`
+	Interval int64 `json:"interval,omitempty"`
+}
+
+func (p *RetentionPolicy) Verify() error {
+	if p.State < 0 || p.Receipts < 0 || p.Interval < 0 {
+		return errors.IllegalArgumentError.Errorf("NegativeValue(%+v)", *p)
+	}
+	if (p.State > 0 && p.State < MinRetention) ||
+		(p.Receipts > 0 && p.Receipts < MinRetention) {
+		return errors.IllegalArgumentError.Errorf(
+			"TooSmallRetention(%+v,min=%d)", *p, MinRetention)
+	}
+	return nil
+}
+
+// Enabled returns whether it prunes anything.
+func (p *RetentionPolicy) Enabled() bool {
+	return p != nil && (p.State > 0 || p.Receipts > 0)
+}
+
 type BlockManager interface {
 	GetBlockByHeight(height int64) (Block, error)
 	GetLastBlock() (Block, error)
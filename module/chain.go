@@ -38,6 +38,12 @@ type Chain interface {
 	ChildrenLimit() int
 	NephewsLimit() int
 	ValidateTxOnSend() bool
+	// ConsensusTimeouts returns timeouts of the consensus configured for
+	// the chain. It returns nil if it's not configured.
+	ConsensusTimeouts() *ConsensusTimeouts
+	// RetentionPolicy returns the policy of the pruning configured for
+	// the chain. It returns nil if it's not configured.
+	RetentionPolicy() *RetentionPolicy
 	Genesis() []byte
 	GenesisStorage() GenesisStorage
 	CommitVoteSetDecoder() CommitVoteSetDecoder
@@ -92,6 +98,7 @@ const (
 	GenesisUnknown GenesisType = iota
 	GenesisNormal
 	GenesisPruned
+	GenesisForked
 )
 
 type GenesisStorage interface {
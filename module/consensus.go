@@ -1,5 +1,7 @@
 package module
 
+import "github.com/icon-project/goloop/common/errors"
+
 type ConsensusStatus struct {
 	Height   int64
 	Round    int32
@@ -24,3 +26,38 @@ type Consensus interface {
 		blk Block, nid int64, flag uint,
 	) (btpBlk BTPBlockHeader, proof []byte, err error)
 }
+
+const (
+	TimeoutBackoffNone        = ""
+	TimeoutBackoffLinear      = "linear"
+	TimeoutBackoffExponential = "exponential"
+)
+
+// ConsensusTimeouts is timeout configuration of the consensus engine.
+// Timeouts are in milliseconds, and zero means the default value of the
+// engine. Backoff decides how timeouts grow as the round increases.
+// For linear backoff, Delta is added for each round (default: the base
+// timeout). For exponential backoff, it doubles for each round. Max limits
+// the grown timeouts if it's positive.
+type ConsensusTimeouts struct {
+	Propose   int64  `json:"propose,omitempty"`
+	Prevote   int64  `json:"prevote,omitempty"`
+	Precommit int64  `json:"precommit,omitempty"`
+	NewRound  int64  `json:"newRound,omitempty"`
+	Backoff   string `json:"backoff,omitempty"`
+	Delta     int64  `json:"delta,omitempty"`
+	Max       int64  `json:"max,omitempty"`
+}
+
+func (t *ConsensusTimeouts) Verify() error {
+	if t.Propose < 0 || t.Prevote < 0 || t.Precommit < 0 || t.NewRound < 0 ||
+		t.Delta < 0 || t.Max < 0 {
+		return errors.IllegalArgumentError.Errorf("NegativeTimeout(%+v)", *t)
+	}
+	switch t.Backoff {
+	case TimeoutBackoffNone, TimeoutBackoffLinear, TimeoutBackoffExponential:
+		return nil
+	default:
+		return errors.IllegalArgumentError.Errorf("InvalidBackoff(%s)", t.Backoff)
+	}
+}
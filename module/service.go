@@ -242,6 +242,10 @@ type SCOREStatus interface {
 	ToJSON(height int64, version JSONVersion) (interface{}, error)
 }
 
+type AccountProof interface {
+	ToJSON(version JSONVersion) (interface{}, error)
+}
+
 // Options for finalize
 const (
 	FinalizeNormalTransaction = 1 << iota
@@ -353,6 +357,10 @@ type ServiceManager interface {
 	// GetSCOREStatus returns status of the contract
 	GetSCOREStatus(result []byte, addr Address) (SCOREStatus, error)
 
+	// GetAccountProof returns merkle proofs of the account and the values
+	// for the keys in the storage of the account
+	GetAccountProof(result []byte, addr Address, keys [][]byte) (AccountProof, error)
+
 	// GetMembers returns network member list
 	GetMembers(result []byte) (MemberList, error)
 
@@ -365,6 +373,10 @@ type ServiceManager interface {
 	// GetMinimizeBlockGen returns minimize empty block generation flag
 	GetMinimizeBlockGen(result []byte) bool
 
+	// GetConsensusTimeouts returns timeouts of the consensus governed by
+	// the state. It returns nil if it's not set.
+	GetConsensusTimeouts(result []byte) *ConsensusTimeouts
+
 	// GetNextBlockVersion returns version of next block
 	GetNextBlockVersion(result []byte) int
 
@@ -399,6 +411,11 @@ type ServiceManager interface {
 	// should be exported to the database
 	ExportResult(result []byte, vh []byte, dst db.Database) error
 
+	// ExportState exports the entries related with the state of the result
+	// to the database. It's same as ExportResult except that it doesn't
+	// export receipts.
+	ExportState(result []byte, vh []byte, dst db.Database) error
+
 	// ImportResult imports all related entries related with the result
 	// should be imported from the database
 	ImportResult(result []byte, vh []byte, src db.Database) error
@@ -0,0 +1,192 @@
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	AuditSourceAdmin = "admin"
+	AuditSourceCli   = "cli"
+
+	auditMaxParamsSize = 64 * 1024
+	auditRecordKey     = "auditRecord"
+)
+
+// AuditRecord is the record of the mutating request to the admin API.
+type AuditRecord struct {
+	Timestamp time.Time       `json:"timestamp"`
+	User      string          `json:"user,omitempty"`
+	Source    string          `json:"source"`
+	Method    string          `json:"method"`
+	Path      string          `json:"path"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Status    int             `json:"status"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// AuditLog keeps the records of the mutating requests in the file.
+// Records are only appended, one JSON object per line.
+type AuditLog struct {
+	filePath string
+	mtx      sync.Mutex
+}
+
+func (l *AuditLog) Append(r *AuditRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	f, err := os.OpenFile(l.filePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "fail to open file=%s", l.filePath)
+	}
+	defer f.Close()
+	if _, err = f.Write(b); err != nil {
+		return errors.Wrapf(err, "fail to write file=%s", l.filePath)
+	}
+	return nil
+}
+
+// Query returns the records of the user after the time. It returns the
+// latest records up to the limit if the limit is positive.
+func (l *AuditLog) Query(user string, since time.Time, limit int) ([]*AuditRecord, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	records := make([]*AuditRecord, 0)
+	f, err := os.Open(l.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, errors.Wrapf(err, "fail to open file=%s", l.filePath)
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 4096), 2*auditMaxParamsSize)
+	for s.Scan() {
+		r := new(AuditRecord)
+		if err = json.Unmarshal(s.Bytes(), r); err != nil {
+			log.Warnf("fail to parse audit record err=%+v", err)
+			continue
+		}
+		if user != "" && !strings.EqualFold(r.User, user) {
+			continue
+		}
+		if r.Timestamp.Before(since) {
+			continue
+		}
+		records = append(records, r)
+		if limit > 0 && len(records) > limit {
+			records = records[1:]
+		}
+	}
+	if err = s.Err(); err != nil {
+		return nil, errors.Wrapf(err, "fail to read file=%s", l.filePath)
+	}
+	return records, nil
+}
+
+// MiddlewareFunc returns the middleware recording the requests other than
+// GET. It should be placed before the authentication, so failed requests
+// are also recorded. Parameters are recorded by ParamsMiddlewareFunc placed
+// after the authentication, so the requests rejected by the authentication
+// are recorded without parameters.
+func (l *AuditLog) MiddlewareFunc(source string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			if req.Method == http.MethodGet {
+				return next(ctx)
+			}
+			r := &AuditRecord{
+				Timestamp: time.Now(),
+				Source:    source,
+				Method:    req.Method,
+				Path:      req.URL.Path,
+			}
+			ctx.Set(auditRecordKey, r)
+			err := next(ctx)
+			if user, ok := ctx.Get(AuthUserKey).(string); ok {
+				r.User = user
+			}
+			if err != nil {
+				r.Error = err.Error()
+				if he, ok := err.(*echo.HTTPError); ok {
+					r.Status = he.Code
+				} else {
+					r.Status = http.StatusInternalServerError
+				}
+			} else {
+				r.Status = ctx.Response().Status
+			}
+			if r.Status == http.StatusUnauthorized || r.Status == http.StatusForbidden {
+				r.Params = nil
+			}
+			if ae := l.Append(r); ae != nil {
+				log.Errorf("fail to append audit record err=%+v", ae)
+			}
+			return err
+		}
+	}
+}
+
+// ParamsMiddlewareFunc returns the middleware keeping the parameters of the
+// request in the record of MiddlewareFunc. It should be placed after the
+// authentication, so the body of the request is read only for the accepted
+// ones.
+func (l *AuditLog) ParamsMiddlewareFunc() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if r, ok := ctx.Get(auditRecordKey).(*AuditRecord); ok {
+				r.Params = auditParams(ctx)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// auditParams returns the parameters of the request. For the multipart
+// requests, it returns the "json" field only, because the others are
+// files.
+func auditParams(ctx echo.Context) json.RawMessage {
+	req := ctx.Request()
+	var b []byte
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		b = []byte(ctx.FormValue("json"))
+	} else if req.Body != nil {
+		var err error
+		b, err = io.ReadAll(io.LimitReader(req.Body, auditMaxParamsSize+1))
+		if err != nil {
+			return nil
+		}
+		req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), req.Body))
+	}
+	if len(b) == 0 || len(b) > auditMaxParamsSize || !json.Valid(b) {
+		return nil
+	}
+	return b
+}
+
+func NewAuditLog(filePath string) *AuditLog {
+	return &AuditLog{filePath: filePath}
+}
@@ -0,0 +1,180 @@
+package node
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/wallet"
+)
+
+func TestAuditLog_AppendQuery(t *testing.T) {
+	l := NewAuditLog(path.Join(t.TempDir(), "audit.log"))
+
+	records, err := l.Query("", time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, records, 0)
+
+	base := time.Now().Truncate(time.Second)
+	users := []string{"hxa", "hxb", "hxa", "hxb", "hxa"}
+	for i, user := range users {
+		assert.NoError(t, l.Append(&AuditRecord{
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			User:      user,
+			Source:    AuditSourceAdmin,
+			Method:    http.MethodPost,
+			Path:      "/admin/chain",
+			Params:    []byte(fmt.Sprintf(`{"seq":%d}`, i)),
+			Status:    http.StatusOK,
+		}))
+	}
+
+	records, err = l.Query("", time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, records, 5)
+	assert.True(t, records[0].Timestamp.Equal(base))
+	assert.Equal(t, `{"seq":0}`, string(records[0].Params))
+
+	records, err = l.Query("HXA", time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	for _, r := range records {
+		assert.Equal(t, "hxa", r.User)
+	}
+
+	records, err = l.Query("", base.Add(3*time.Second), 0)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	// latest ones up to the limit
+	records, err = l.Query("hxa", time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, `{"seq":2}`, string(records[0].Params))
+	assert.Equal(t, `{"seq":4}`, string(records[1].Params))
+}
+
+func TestAuditLog_MiddlewareFunc(t *testing.T) {
+	l := NewAuditLog(path.Join(t.TempDir(), "audit.log"))
+	s := newAuthTestServer(
+		[]echo.MiddlewareFunc{l.MiddlewareFunc(AuditSourceAdmin)},
+		[]echo.MiddlewareFunc{l.ParamsMiddlewareFunc()},
+	)
+	admin := wallet.New().Address().String()
+	operator := wallet.New().Address().String()
+	assert.NoError(t, s.a.AddUser(admin))
+	assert.NoError(t, s.a.AddUserWithInfo(&UserInfo{ID: operator, Role: RoleOperator}))
+
+	var body string
+	s.e.POST("/admin/echo", func(ctx echo.Context) error {
+		b, err := io.ReadAll(ctx.Request().Body)
+		if err != nil {
+			return err
+		}
+		body = string(b)
+		return ctx.String(http.StatusOK, "OK")
+	}, l.MiddlewareFunc(AuditSourceCli), l.ParamsMiddlewareFunc())
+
+	do := func(method, url, id, params string) int {
+		req := httptest.NewRequest(method, url, strings.NewReader(params))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if id != "" {
+			withCertUser(req, id)
+		}
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// GET isn't recorded
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/admin/free", "", ""))
+
+	const params = `{"key":"value"}`
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/admin/default", admin, params))
+	// rejected requests are recorded without parameters
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/admin/default", "", params))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/admin/default", operator, params))
+	// the handler reads the whole body
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/admin/echo", "", params))
+	assert.Equal(t, params, body)
+	// invalid JSON isn't kept
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/admin/echo", "", "{invalid"))
+
+	// the body of the rejected request isn't read
+	body = "--boundary\r\nContent-Disposition: form-data; name=\"json\"\r\n\r\n" +
+		params + "\r\n--boundary--\r\n"
+	br := &readCounter{r: strings.NewReader(body)}
+	req := httptest.NewRequest(http.MethodPost, "/admin/default", br)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEMultipartForm+"; boundary=boundary")
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Zero(t, br.n)
+
+	// the accepted multipart request keeps the "json" field
+	br = &readCounter{r: strings.NewReader(body)}
+	req = httptest.NewRequest(http.MethodPost, "/admin/default", br)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEMultipartForm+"; boundary=boundary")
+	withCertUser(req, admin)
+	rec = httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotZero(t, br.n)
+
+	records, err := l.Query("", time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, records, 7)
+
+	r := records[0]
+	assert.Equal(t, admin, r.User)
+	assert.Equal(t, AuditSourceAdmin, r.Source)
+	assert.Equal(t, http.MethodPost, r.Method)
+	assert.Equal(t, "/admin/default", r.Path)
+	assert.JSONEq(t, params, string(r.Params))
+	assert.Equal(t, http.StatusOK, r.Status)
+	assert.Empty(t, r.Error)
+
+	r = records[1]
+	assert.Empty(t, r.User)
+	assert.Nil(t, r.Params)
+	assert.Equal(t, http.StatusUnauthorized, r.Status)
+	assert.NotEmpty(t, r.Error)
+
+	r = records[2]
+	assert.Equal(t, operator, r.User)
+	assert.Nil(t, r.Params)
+	assert.Equal(t, http.StatusForbidden, r.Status)
+
+	r = records[3]
+	assert.Equal(t, AuditSourceCli, r.Source)
+	assert.JSONEq(t, params, string(r.Params))
+
+	assert.Nil(t, records[4].Params)
+
+	r = records[5]
+	assert.Nil(t, r.Params)
+	assert.Equal(t, http.StatusUnauthorized, r.Status)
+
+	r = records[6]
+	assert.Equal(t, admin, r.User)
+	assert.JSONEq(t, params, string(r.Params))
+}
+
+type readCounter struct {
+	r io.Reader
+	n int
+}
+
+func (c *readCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -21,15 +22,96 @@ import (
 
 const (
 	AuthScheme = "goloop"
+	// AuthUserKey is the key of the context for the authenticated user.
+	AuthUserKey = "user"
 )
 
+// Role is the permission level of the user. Higher role includes
+// the permissions of the lower ones.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleViewer
+	RoleOperator
+	RoleAdmin
+)
+
+var roleNames = []string{"none", "viewer", "operator", "admin"}
+
+func (r Role) String() string {
+	if r >= RoleNone && int(r) < len(roleNames) {
+		return roleNames[r]
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+func ParseRole(s string) (Role, error) {
+	for i, name := range roleNames {
+		if name == s {
+			return Role(i), nil
+		}
+	}
+	return RoleNone, errors.IllegalArgumentError.Errorf("InvalidRole(role=%s)", s)
+}
+
+func (r Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Role) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	role, err := ParseRole(s)
+	if err != nil {
+		return err
+	}
+	*r = role
+	return nil
+}
+
+// UserInfo is the user with the role for all chains and the roles for
+// specific chains. Chains are keyed by chain-id in "0x" + lowercase HEX.
+type UserInfo struct {
+	ID     string          `json:"id"`
+	Role   Role            `json:"role"`
+	Chains map[string]Role `json:"chains,omitempty"`
+}
+
+// RoleFor returns the role of the user for the chain. Use empty cid for
+// the operations not related to any chain.
+func (u *UserInfo) RoleFor(cid string) Role {
+	role := u.Role
+	if cr, ok := u.Chains[cid]; ok && cr > role {
+		role = cr
+	}
+	return role
+}
+
+func (u *UserInfo) clone() *UserInfo {
+	nu := &UserInfo{ID: u.ID, Role: u.Role}
+	if len(u.Chains) > 0 {
+		nu.Chains = make(map[string]Role, len(u.Chains))
+		for k, v := range u.Chains {
+			nu.Chains[k] = v
+		}
+	}
+	return nu
+}
+
 type Auth struct {
-	skips map[string]map[string]bool
+	roles map[string]map[string]Role
 	users map[string]int64
+	infos map[string]*UserInfo
 	addrs map[string]string
 	filePath string
 	prefix string
 	SkipIfEmptyUsers bool
+	// ChainResolver returns chain-id of the chain for cid or channel
+	// in the path of the request.
+	ChainResolver func(selector string) (string, bool)
 	mtx   sync.Mutex
 }
 
@@ -43,44 +125,136 @@ func (a *Auth) MiddlewareFunc() echo.MiddlewareFunc {
 	//})
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
-			if a.skipper(ctx) {
+			role := a.requiredRole(ctx)
+			if a.skipper(role) {
 				return next(ctx)
 			}
-			key, err := a.extractor(ctx)
+			id, err := a.authenticate(ctx)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+				return err
 			}
-			valid, err := a.validator(key, ctx)
+			ctx.Set(AuthUserKey, id)
+			cid, err := a.chainOf(ctx)
 			if err != nil {
 				return err
-			} else if valid {
-				return next(ctx)
 			}
-			return echo.ErrUnauthorized
+			if ur := a.roleOf(id, cid); ur < role {
+				return echo.NewHTTPError(http.StatusForbidden,
+					fmt.Sprintf("Forbidden(user=%s,role=%s,required=%s)", id, ur, role))
+			}
+			return next(ctx)
 		}
 	}
 }
 
-func (a *Auth) SetSkip(r *echo.Route, skip bool) {
-	m, ok := a.skips[r.Method]
+// authenticate returns ID of the user of the request. The request without
+// signature is authenticated with the verified TLS client certificate
+// whose subject common name is the address of the user.
+func (a *Auth) authenticate(ctx echo.Context) (string, error) {
+	if ctx.Request().Header.Get(echo.HeaderAuthorization) == "" {
+		if id := a.certUser(ctx); id != "" {
+			return id, nil
+		}
+	}
+	key, err := a.extractor(ctx)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	id, err := a.validator(key, ctx)
+	if err != nil {
+		return "", err
+	} else if id == "" {
+		return "", echo.ErrUnauthorized
+	}
+	return id, nil
+}
+
+func (a *Auth) certUser(ctx echo.Context) string {
+	cs := ctx.Request().TLS
+	if cs == nil || len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	cn := cs.VerifiedChains[0][0].Subject.CommonName
+	addr := &common.Address{}
+	if err := addr.SetString(cn); err != nil {
+		log.Traceln("invalid address in client certificate", cn)
+		return ""
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if id, ok := a.addrs[addr.String()]; ok {
+		return id
+	}
+	log.Traceln("not found user for client certificate", cn)
+	return ""
+}
+
+// SetRole sets the role required for the route. Routes without the role
+// require RoleNone for GET and RoleAdmin for others.
+func (a *Auth) SetRole(r *echo.Route, role Role) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	m, ok := a.roles[r.Method]
 	if !ok {
-		m = make(map[string]bool)
-		a.skips[r.Method] = m
+		m = make(map[string]Role)
+		a.roles[r.Method] = m
 	}
-	m[r.Path] = skip
+	m[r.Path] = role
 }
 
-func (a *Auth) skipper(ctx echo.Context) bool {
-	if a.SkipIfEmptyUsers && a.IsEmptyUsers() {
-		return true
+func (a *Auth) SetSkip(r *echo.Route, skip bool) {
+	if skip {
+		a.SetRole(r, RoleNone)
+	} else {
+		a.SetRole(r, RoleViewer)
 	}
+}
+
+func (a *Auth) requiredRole(ctx echo.Context) Role {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
 	method := ctx.Request().Method
-	if m, ok := a.skips[method]; ok {
-		if skip, has := m[ctx.Path()]; has {
-			return skip
+	if m, ok := a.roles[method]; ok {
+		if role, has := m[ctx.Path()]; has {
+			return role
 		}
 	}
-	return method == http.MethodGet
+	if method == http.MethodGet {
+		return RoleNone
+	}
+	return RoleAdmin
+}
+
+func (a *Auth) skipper(role Role) bool {
+	if a.SkipIfEmptyUsers && a.IsEmptyUsers() {
+		return true
+	}
+	return role == RoleNone
+}
+
+func (a *Auth) chainOf(ctx echo.Context) (string, error) {
+	selector := ctx.Param(ParamCID)
+	if selector == "" || a.ChainResolver == nil {
+		return "", nil
+	}
+	if cid, ok := a.ChainResolver(selector); ok {
+		return cid, nil
+	}
+	return "", echo.NewHTTPError(http.StatusNotFound,
+		fmt.Sprintf("Chain(%s: cid or channel) not found", selector))
+}
+
+func (a *Auth) roleOf(id, cid string) Role {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if u, ok := a.infos[id]; ok {
+		return u.RoleFor(cid)
+	}
+	return RoleNone
 }
 
 func (a *Auth) extractor(ctx echo.Context) (string, error) {
@@ -113,7 +287,9 @@ func parse(s string) map[string]string {
 	return m
 }
 
-func (a *Auth) validator(s string, ctx echo.Context) (b bool, err error) {
+// validator returns ID of the user signed the request. It returns empty
+// string for unknown users or reused signatures.
+func (a *Auth) validator(s string, ctx echo.Context) (id string, err error) {
 	log.Traceln("validator:", s)
 	m := parse(s)
 	var timestamp int64
@@ -145,19 +321,35 @@ func (a *Auth) validator(s string, ctx echo.Context) (b bool, err error) {
 		if ts := a.users[id]; ts < timestamp {
 			a.users[id] = timestamp
 			log.Traceln("valid signature", ts, timestamp)
-			return true, nil
+			return id, nil
 		}
 		log.Traceln("old signature", a.users[id], timestamp)
-		return false, nil
+		return "", nil
 	}
 	log.Traceln("not found user", addr)
-	return false, nil
+	return "", nil
 }
 
+// AddUser adds the user with RoleAdmin for all chains.
 func (a *Auth) AddUser(id string) error {
+	return a.AddUserWithInfo(&UserInfo{ID: id, Role: RoleAdmin})
+}
+
+func (a *Auth) AddUserWithInfo(u *UserInfo) error {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
+	if err := a._addUser(u); err != nil {
+		return err
+	}
+	if err := a._export(); err != nil {
+		panic(err)
+	}
+	return nil
+}
+
+func (a *Auth) _addUser(u *UserInfo) error {
+	id := u.ID
 	if _, ok := a.users[id]; ok {
 		return errors.Wrapf(ErrAlreadyExists, "User(id=%s) already exists", id)
 	}
@@ -172,13 +364,48 @@ func (a *Auth) AddUser(id string) error {
 	}
 
 	a.users[id] = time.Now().Unix()
+	a.infos[id] = u.clone()
 	a.addrs[addr.String()] = id
+	return nil
+}
+
+// SetUserRole sets the role of the user for the chain. Use empty cid for
+// the role for all chains. RoleNone for the chain removes the role for
+// the chain.
+func (a *Auth) SetUserRole(id string, cid string, role Role) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	u, ok := a.infos[id]
+	if !ok {
+		return errors.Wrapf(ErrNotExists, "User(id=%s) not exists", id)
+	}
+	if cid == "" {
+		u.Role = role
+	} else if role == RoleNone {
+		delete(u.Chains, cid)
+	} else {
+		if u.Chains == nil {
+			u.Chains = make(map[string]Role)
+		}
+		u.Chains[cid] = role
+	}
 	if err := a._export(); err != nil {
 		panic(err)
 	}
 	return nil
 }
 
+func (a *Auth) GetUser(id string) (*UserInfo, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if u, ok := a.infos[id]; ok {
+		return u.clone(), nil
+	}
+	return nil, errors.Wrapf(ErrNotExists, "User(id=%s) not exists", id)
+}
+
 func (a *Auth) RemoveUser(id string) error {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
@@ -188,6 +415,7 @@ func (a *Auth) RemoveUser(id string) error {
 	}
 
 	delete(a.users, id)
+	delete(a.infos, id)
 	var addr string
 	for k, v := range a.addrs {
 		if v == id {
@@ -225,7 +453,13 @@ func (a *Auth) GetUsers() []string {
 
 func (a *Auth) _export() error {
 	if a.filePath != "" {
-		users := a._users()
+		users := make([]*UserInfo, 0, len(a.infos))
+		for _, id := range a._users() {
+			users = append(users, a.infos[id])
+		}
+		sort.Slice(users, func(i, j int) bool {
+			return users[i].ID < users[j].ID
+		})
 		if b, err := json.Marshal(users); err != nil {
 			return err
 		}else {
@@ -239,8 +473,9 @@ func (a *Auth) _export() error {
 
 func NewAuth(filePath, prefix string) *Auth {
 	a := &Auth{
-		skips: make(map[string]map[string]bool),
+		roles: make(map[string]map[string]Role),
 		users: make(map[string]int64),
+		infos: make(map[string]*UserInfo),
 		addrs: make(map[string]string),
 		filePath: filePath,
 		prefix: prefix,
@@ -256,12 +491,19 @@ func NewAuth(filePath, prefix string) *Auth {
 		if b, err := os.ReadFile(filePath); err != nil {
 			panic(err)
 		} else {
-			var users []string
+			var users []json.RawMessage
 			if err = json.Unmarshal(b, &users); err != nil {
 				panic(err)
 			}
 			for _, user := range users {
-				if err = a.AddUser(user); err != nil {
+				u := &UserInfo{Role: RoleAdmin}
+				// users were stored as addresses before roles
+				if err = json.Unmarshal(user, &u.ID); err != nil {
+					if err = json.Unmarshal(user, u); err != nil {
+						panic(err)
+					}
+				}
+				if err = a._addUser(u); err != nil {
 					panic(err)
 				}
 			}
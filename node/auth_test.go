@@ -0,0 +1,255 @@
+package node
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+)
+
+func TestRole(t *testing.T) {
+	for _, role := range []Role{RoleNone, RoleViewer, RoleOperator, RoleAdmin} {
+		r, err := ParseRole(role.String())
+		assert.NoError(t, err)
+		assert.Equal(t, role, r)
+
+		b, err := json.Marshal(role)
+		assert.NoError(t, err)
+		assert.Equal(t, `"`+role.String()+`"`, string(b))
+		var r2 Role
+		assert.NoError(t, json.Unmarshal(b, &r2))
+		assert.Equal(t, role, r2)
+	}
+	assert.True(t, RoleNone < RoleViewer && RoleViewer < RoleOperator && RoleOperator < RoleAdmin)
+	assert.Equal(t, "Role(9)", Role(9).String())
+
+	_, err := ParseRole("root")
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	var r Role
+	assert.Error(t, json.Unmarshal([]byte(`"root"`), &r))
+	assert.Error(t, json.Unmarshal([]byte(`3`), &r))
+}
+
+func TestUserInfo_RoleFor(t *testing.T) {
+	u := &UserInfo{
+		Role: RoleViewer,
+		Chains: map[string]Role{
+			"0x1": RoleAdmin,
+			"0x2": RoleNone,
+		},
+	}
+	assert.Equal(t, RoleViewer, u.RoleFor(""))
+	assert.Equal(t, RoleAdmin, u.RoleFor("0x1"))
+	// the role for all chains is kept with lower role for the chain
+	assert.Equal(t, RoleViewer, u.RoleFor("0x2"))
+	assert.Equal(t, RoleViewer, u.RoleFor("0x3"))
+}
+
+func TestAuth_Users(t *testing.T) {
+	file := path.Join(t.TempDir(), "auth.json")
+	a := NewAuth(file, "")
+	assert.True(t, a.IsEmptyUsers())
+
+	id := wallet.New().Address().String()
+	assert.NoError(t, a.AddUser(id))
+	assert.ErrorIs(t, a.AddUser(id), ErrAlreadyExists)
+	u, err := a.GetUser(id)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleAdmin, u.Role)
+
+	assert.NoError(t, a.SetUserRole(id, "", RoleViewer))
+	assert.NoError(t, a.SetUserRole(id, "0x1", RoleOperator))
+	u, err = a.GetUser(id)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleViewer, u.RoleFor(""))
+	assert.Equal(t, RoleOperator, u.RoleFor("0x1"))
+
+	// returned one is a copy
+	u.Chains["0x1"] = RoleAdmin
+	assert.Equal(t, RoleOperator, a.roleOf(id, "0x1"))
+
+	// stored in the file
+	a2 := NewAuth(file, "")
+	u2, err := a2.GetUser(id)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleOperator, u2.RoleFor("0x1"))
+
+	assert.NoError(t, a.SetUserRole(id, "0x1", RoleNone))
+	u, err = a.GetUser(id)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleViewer, u.RoleFor("0x1"))
+
+	assert.ErrorIs(t, a.SetUserRole("hx01", "", RoleAdmin), ErrNotExists)
+	assert.NoError(t, a.RemoveUser(id))
+	assert.ErrorIs(t, a.RemoveUser(id), ErrNotExists)
+	assert.True(t, a.IsEmptyUsers())
+}
+
+func TestAuth_LegacyUsers(t *testing.T) {
+	file := path.Join(t.TempDir(), "auth.json")
+	id1 := wallet.New().Address().String()
+	id2 := wallet.New().Address().String()
+	legacy, err := json.Marshal([]interface{}{
+		id1,
+		&UserInfo{ID: id2, Role: RoleViewer, Chains: map[string]Role{"0x1": RoleOperator}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(file, legacy, 0644))
+
+	a := NewAuth(file, "")
+	// users stored as addresses are admins
+	u1, err := a.GetUser(id1)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleAdmin, u1.Role)
+	u2, err := a.GetUser(id2)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleViewer, u2.Role)
+	assert.Equal(t, RoleOperator, u2.RoleFor("0x1"))
+
+	// it's stored in the new format on change
+	assert.NoError(t, a.SetUserRole(id2, "", RoleOperator))
+	b, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	var users []*UserInfo
+	assert.NoError(t, json.Unmarshal(b, &users))
+	assert.Len(t, users, 2)
+	for _, u := range users {
+		if u.ID == id1 {
+			assert.Equal(t, RoleAdmin, u.Role)
+		} else {
+			assert.Equal(t, id2, u.ID)
+			assert.Equal(t, RoleOperator, u.Role)
+		}
+	}
+}
+
+// withCertUser sets the TLS state of the request having the verified client
+// certificate of the user.
+func withCertUser(req *http.Request, id string) *http.Request {
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{
+			{Subject: pkix.Name{CommonName: id}},
+		}},
+	}
+	return req
+}
+
+type authTestServer struct {
+	e *echo.Echo
+	a *Auth
+}
+
+func newAuthTestServer(pre, post []echo.MiddlewareFunc) *authTestServer {
+	e := echo.New()
+	a := NewAuth("", "")
+	a.ChainResolver = func(selector string) (string, bool) {
+		if selector == "0x1" || selector == "ch1" {
+			return "0x1", true
+		}
+		return "", false
+	}
+	m := append(append(pre, a.MiddlewareFunc()), post...)
+	g := e.Group("/admin", m...)
+	ok := func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	}
+	g.GET("/free", ok)
+	g.POST("/default", ok)
+	a.SetRole(g.GET("/view", ok), RoleViewer)
+	a.SetRole(g.POST("/chain/:"+ParamCID+"/start", ok), RoleOperator)
+	a.SetSkip(g.POST("/skip", ok), true)
+	return &authTestServer{e: e, a: a}
+}
+
+func (s *authTestServer) do(method, url, id string) int {
+	req := httptest.NewRequest(method, url, nil)
+	if id != "" {
+		withCertUser(req, id)
+	}
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAuth_MiddlewareFunc(t *testing.T) {
+	s := newAuthTestServer(nil, nil)
+
+	viewer := wallet.New().Address().String()
+	operator := wallet.New().Address().String()
+	admin := wallet.New().Address().String()
+	unknown := wallet.New().Address().String()
+	assert.NoError(t, s.a.AddUserWithInfo(&UserInfo{ID: viewer, Role: RoleViewer,
+		Chains: map[string]Role{"0x1": RoleOperator}}))
+	assert.NoError(t, s.a.AddUserWithInfo(&UserInfo{ID: operator, Role: RoleOperator}))
+	assert.NoError(t, s.a.AddUser(admin))
+
+	cases := []struct {
+		method string
+		url    string
+		id     string
+		code   int
+	}{
+		{http.MethodGet, "/admin/free", "", http.StatusOK},
+		{http.MethodPost, "/admin/skip", "", http.StatusOK},
+		{http.MethodGet, "/admin/view", "", http.StatusUnauthorized},
+		{http.MethodGet, "/admin/view", unknown, http.StatusUnauthorized},
+		{http.MethodGet, "/admin/view", viewer, http.StatusOK},
+		{http.MethodPost, "/admin/default", operator, http.StatusForbidden},
+		{http.MethodPost, "/admin/default", admin, http.StatusOK},
+		{http.MethodPost, "/admin/chain/0x1/start", operator, http.StatusOK},
+		// role for the chain, selected by chain-id or channel
+		{http.MethodPost, "/admin/chain/0x1/start", viewer, http.StatusOK},
+		{http.MethodPost, "/admin/chain/ch1/start", viewer, http.StatusOK},
+		{http.MethodPost, "/admin/chain/0x2/start", viewer, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.code, s.do(c.method, c.url, c.id), "%s %s", c.method, c.url)
+	}
+
+	assert.NoError(t, s.a.SetUserRole(viewer, "0x1", RoleNone))
+	assert.Equal(t, http.StatusForbidden, s.do(http.MethodPost, "/admin/chain/0x1/start", viewer))
+
+	// any request is allowed without users if it's configured
+	for _, id := range []string{viewer, operator, admin} {
+		assert.NoError(t, s.a.RemoveUser(id))
+	}
+	assert.Equal(t, http.StatusUnauthorized, s.do(http.MethodPost, "/admin/default", ""))
+	s.a.SkipIfEmptyUsers = true
+	assert.Equal(t, http.StatusOK, s.do(http.MethodPost, "/admin/default", ""))
+}
+
+func TestAuth_RequiredRoleOfRest(t *testing.T) {
+	e := echo.New()
+	r := &Rest{a: NewAuth("", "")}
+	r.RegisterChainHandlers(e.Group(UrlChain))
+	r.RegisterUserHandlers(e.Group(UrlUser))
+
+	role := func(method, path string) Role {
+		ctx := e.NewContext(httptest.NewRequest(method, path, nil), httptest.NewRecorder())
+		e.Router().Find(method, path, ctx)
+		return r.a.requiredRole(ctx)
+	}
+	assert.Equal(t, RoleNone, role(http.MethodGet, UrlChain))
+	assert.Equal(t, RoleViewer, role(http.MethodGet, UrlChain+"/0x1/genesis"))
+	assert.Equal(t, RoleViewer, role(http.MethodGet, UrlChain+"/0x1/consensus/events"))
+	assert.Equal(t, RoleViewer, role(http.MethodGet, UrlChain+"/0x1/prep/warnings"))
+	assert.Equal(t, RoleOperator, role(http.MethodPost, UrlChain+"/0x1/start"))
+	assert.Equal(t, RoleOperator, role(http.MethodPost, UrlChain+"/0x1/check"))
+	for _, op := range []string{"reset", "import", "prune"} {
+		assert.Equal(t, RoleAdmin, role(http.MethodPost, UrlChain+"/0x1/"+op), op)
+	}
+	assert.Equal(t, RoleAdmin, role(http.MethodPost, UrlChain))
+	assert.Equal(t, RoleAdmin, role(http.MethodDelete, UrlChain+"/0x1"))
+	assert.Equal(t, RoleAdmin, role(http.MethodPost, UrlUser))
+}
@@ -28,6 +28,11 @@ type StaticConfig struct {
 	Engines       string `json:"engines"`
 	BackupDir     string `json:"backup_dir"`
 
+	RPCTLSCert       string `json:"rpc_tls_cert,omitempty"`
+	RPCTLSKey        string `json:"rpc_tls_key,omitempty"`
+	RPCTLSClientCA   string `json:"rpc_tls_client_ca,omitempty"`
+	RPCTLSClientAuth string `json:"rpc_tls_client_auth,omitempty"`
+
 	AuthSkipIfEmptyUsers bool `json:"auth_skip_if_empty_users,omitempty"`
 	NIDForP2P            bool `json:"nid_for_p2p,omitempty"`
 
@@ -79,6 +84,15 @@ func (c *StaticConfig) SetFilePath(path string) string {
 	if c.BackupDir != "" {
 		c.BackupDir = c.ResolveRelative(ResolveAbsolute(o, c.BackupDir))
 	}
+	if c.RPCTLSCert != "" {
+		c.RPCTLSCert = c.ResolveRelative(ResolveAbsolute(o, c.RPCTLSCert))
+	}
+	if c.RPCTLSKey != "" {
+		c.RPCTLSKey = c.ResolveRelative(ResolveAbsolute(o, c.RPCTLSKey))
+	}
+	if c.RPCTLSClientCA != "" {
+		c.RPCTLSClientCA = c.ResolveRelative(ResolveAbsolute(o, c.RPCTLSClientCA))
+	}
 	return o
 }
 
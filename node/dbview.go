@@ -0,0 +1,154 @@
+package node
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/trie/ompt"
+	"github.com/icon-project/goloop/module"
+)
+
+// bucketNames are aliases for the well known buckets. MerkleTrie bucket
+// can be accessed only with its alias since its ID is empty.
+var bucketNames = map[string]db.BucketID{
+	"MerkleTrie":               db.MerkleTrie,
+	"BytesByHash":              db.BytesByHash,
+	"TransactionLocatorByHash": db.TransactionLocatorByHash,
+	"BlockHeaderHashByHeight":  db.BlockHeaderHashByHeight,
+	"ChainProperty":            db.ChainProperty,
+	"ArchiveIndex":             db.ArchiveIndex,
+	"ReferenceCount":           db.ReferenceCount,
+}
+
+func bucketIDOf(name string) db.BucketID {
+	if id, ok := bucketNames[name]; ok {
+		return id
+	}
+	return db.BucketID(name)
+}
+
+type DBEntry struct {
+	Key     string      `json:"key"`
+	Value   string      `json:"value"`
+	Type    string      `json:"type,omitempty"`
+	Decoded interface{} `json:"decoded,omitempty"`
+}
+
+type DBScanView struct {
+	Entries []*DBEntry `json:"entries"`
+	Next    string     `json:"next,omitempty"`
+}
+
+type DBCountView struct {
+	Count int64 `json:"count"`
+}
+
+func hexOf(bs []byte) string {
+	if bs == nil {
+		return ""
+	}
+	return "0x" + hex.EncodeToString(bs)
+}
+
+// unmarshalAll decodes the value only if it consumes all bytes, so it's
+// used to guess the format of the value.
+func unmarshalAll(bs []byte, v interface{}) bool {
+	remain, err := codec.BC.UnmarshalFromBytes(bs, v)
+	return err == nil && len(remain) == 0
+}
+
+func decodeBlockHeader(bs []byte) interface{} {
+	var h block.V2HeaderFormat
+	if !unmarshalAll(bs, &h) || h.Version != module.BlockVersion2 {
+		return nil
+	}
+	return map[string]interface{}{
+		"version":                h.Version,
+		"height":                 h.Height,
+		"timestamp":              h.Timestamp,
+		"proposer":               hexOf(h.Proposer),
+		"prevID":                 hexOf(h.PrevID),
+		"votesHash":              hexOf(h.VotesHash),
+		"nextValidatorsHash":     hexOf(h.NextValidatorsHash),
+		"patchTransactionsHash":  hexOf(h.PatchTransactionsHash),
+		"normalTransactionsHash": hexOf(h.NormalTransactionsHash),
+		"logsBloom":              hexOf(h.LogsBloom),
+		"result":                 hexOf(h.Result),
+		"nsFilter":               hexOf(h.NSFilter),
+	}
+}
+
+func hexListOf(l [][]byte) []string {
+	s := make([]string, len(l))
+	for i, bs := range l {
+		s[i] = hexOf(bs)
+	}
+	return s
+}
+
+func decodeBlockBody(bs []byte) interface{} {
+	var b block.V2BodyFormat
+	if !unmarshalAll(bs, &b) {
+		return nil
+	}
+	return map[string]interface{}{
+		"patchTransactions":  hexListOf(b.PatchTransactions),
+		"normalTransactions": hexListOf(b.NormalTransactions),
+		"votes":              hexOf(b.Votes),
+		"btpDigest":          hexOf(b.BTPDigest),
+	}
+}
+
+// decodeDBValue returns the type and decoded value of the entry of the
+// bucket if the format of the value is known.
+func decodeDBValue(id db.BucketID, key, value []byte) (string, interface{}) {
+	switch id {
+	case db.MerkleTrie:
+		if v, err := ompt.ViewNode(value); err == nil && v != nil {
+			return "trieNode", v
+		}
+	case db.BytesByHash:
+		if v := decodeBlockHeader(value); v != nil {
+			return "blockHeader", v
+		}
+		if v := decodeBlockBody(value); v != nil {
+			return "blockBody", v
+		}
+	case db.TransactionLocatorByHash:
+		var loc module.TransactionLocator
+		if unmarshalAll(value, &loc) {
+			return "txLocator", map[string]interface{}{
+				"blockHeight": loc.BlockHeight,
+				"group":       loc.TransactionGroup,
+				"index":       loc.IndexInGroup,
+			}
+		}
+	case db.BlockHeaderHashByHeight:
+		var height int64
+		if unmarshalAll(key, &height) {
+			return "blockHash", map[string]interface{}{
+				"height": height,
+				"hash":   hexOf(value),
+			}
+		}
+	}
+	return "", nil
+}
+
+func newDBEntry(id db.BucketID, key, value []byte, decode bool) *DBEntry {
+	e := &DBEntry{
+		Key:   hex.EncodeToString(key),
+		Value: hex.EncodeToString(value),
+	}
+	if decode {
+		e.Type, e.Decoded = decodeDBValue(id, key, value)
+	}
+	return e
+}
+
+func parseHexParam(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/icon-project/goloop/chain/gs"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
@@ -375,6 +376,18 @@ func (n *Node) JoinChain(
 		return nil, errors.Wrap(err, "fail to get NID for genesis")
 	}
 
+	if p.ConsensusTimeouts != nil {
+		if err := p.ConsensusTimeouts.Verify(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Retention != nil {
+		if err := p.Retention.Verify(); err != nil {
+			return nil, err
+		}
+	}
+
 	channel := chain.GetChannel(p.Channel, nid)
 
 	if err := n._canAdd(cid, nid, channel, false); err != nil {
@@ -393,6 +406,7 @@ func (n *Node) JoinChain(
 		NID:              nid,
 		DBType:           p.DBType,
 		Platform:         p.Platform,
+		PlatformConfig:   p.PlatformConfig,
 		Channel:          channel,
 		SecureSuites:     p.SecureSuites,
 		SecureAeads:      p.SecureAeads,
@@ -413,6 +427,10 @@ func (n *Node) JoinChain(
 		ChildrenLimit:    p.ChildrenLimit,
 		NephewsLimit:     p.NephewsLimit,
 		ValidateTxOnSend: p.ValidateTxOnSend,
+		Archive:          p.Archive,
+
+		ConsensusTimeouts: p.ConsensusTimeouts,
+		Retention:         p.Retention,
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -702,6 +720,32 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 				return errors.Errorf("InvalidNodeCacheOption(%s)", value)
 			}
 			c.cfg.NodeCache = value
+		case "consensusTimeouts":
+			if len(value) == 0 || value == "null" {
+				c.cfg.ConsensusTimeouts = nil
+				break
+			}
+			t := new(module.ConsensusTimeouts)
+			if err := json.Unmarshal([]byte(value), t); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			}
+			if err := t.Verify(); err != nil {
+				return err
+			}
+			c.cfg.ConsensusTimeouts = t
+		case "retention":
+			if len(value) == 0 || value == "null" {
+				c.cfg.Retention = nil
+				break
+			}
+			p := new(module.RetentionPolicy)
+			if err := json.Unmarshal([]byte(value), p); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			}
+			if err := p.Verify(); err != nil {
+				return err
+			}
+			c.cfg.Retention = p
 		case "defaultWaitTimeout":
 			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
 				return errors.Wrapf(err, "invalid value type")
@@ -770,6 +814,12 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.ValidateTxOnSend = bc
 			}
+		case "archive":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.Archive = bc
+			}
 		default:
 			return errors.Errorf("not found key %s", key)
 		}
@@ -804,6 +854,76 @@ func (n *Node) RunChainTask(cid int, task string, params json.RawMessage) error
 	return c.RunTask(task, params)
 }
 
+func (n *Node) _walIDFor(c *Chain, id string) (string, error) {
+	if !consensus.IsWALID(id) {
+		return "", errors.IllegalArgumentError.Errorf("InvalidWALID(id=%s)", id)
+	}
+	return path.Join(c.cfg.AbsBaseDir(), chain.DefaultWALDir, id), nil
+}
+
+func (n *Node) InspectChainWALs(cid int) ([]*consensus.WALSummary, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	l := make([]*consensus.WALSummary, 0)
+	for _, id := range consensus.WALIDs() {
+		walID, _ := n._walIDFor(c, id)
+		s, err := consensus.InspectWAL(walID, false, nil)
+		if consensus.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		l = append(l, s)
+	}
+	return l, nil
+}
+
+func (n *Node) DumpChainWAL(cid int, id string, raw bool) (*consensus.WALDump, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	walID, err := n._walIDFor(c, id)
+	if err != nil {
+		return nil, err
+	}
+	dump, err := consensus.DumpWAL(walID, raw)
+	if consensus.IsNotExist(err) {
+		return nil, errors.NotFoundError.Wrapf(err, "WALNotFound(id=%s)", id)
+	}
+	return dump, err
+}
+
+func (n *Node) TruncateChainWAL(cid int, id string, records int) (*consensus.WALSummary, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	if !c.IsStopped() {
+		return nil, errors.InvalidStateError.New("ChainIsNotStopped")
+	}
+	walID, err := n._walIDFor(c, id)
+	if err != nil {
+		return nil, err
+	}
+	s, err := consensus.TruncateWAL(walID, records)
+	if consensus.IsNotExist(err) {
+		return nil, errors.NotFoundError.Wrapf(err, "WALNotFound(id=%s)", id)
+	}
+	return s, err
+}
+
 func (n *Node) GetChains() []*Chain {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -943,6 +1063,16 @@ func NewNode(
 		JSONRPCDefaultChannel: rcfg.RPCDefaultChannel,
 		JSONRPCBatchLimit:     rcfg.RPCBatchLimit,
 		WSMaxSession:          rcfg.WSMaxSession,
+		TLSClientAuth:         cfg.RPCTLSClientAuth,
+	}
+	if cfg.RPCTLSCert != "" {
+		config.TLSCertFile = cfg.ResolveAbsolute(cfg.RPCTLSCert)
+	}
+	if cfg.RPCTLSKey != "" {
+		config.TLSKeyFile = cfg.ResolveAbsolute(cfg.RPCTLSKey)
+	}
+	if cfg.RPCTLSClientCA != "" {
+		config.TLSClientCAFile = cfg.ResolveAbsolute(cfg.RPCTLSClientCA)
 	}
 	srv := server.NewManager(config, w, l)
 
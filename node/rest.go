@@ -20,6 +20,7 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
@@ -37,6 +38,7 @@ const (
 	ParamID     = "id"
 	UrlUserRes  = "/:" + ParamID
 	TaskID      = "task"
+	ParamWAL    = "wal"
 
 	UrlDB    = "/db"
 	ParamBK  = "bucket"
@@ -46,6 +48,7 @@ const (
 type Rest struct {
 	n *Node
 	a *Auth
+	l *AuditLog
 }
 
 type SystemView struct {
@@ -84,25 +87,30 @@ type ChainInspectView struct {
 }
 
 type ChainConfig struct {
-	DBType           string `json:"dbType"`
-	Platform         string `json:"platform"`
-	SeedAddr         string `json:"seedAddress"`
-	Role             uint   `json:"role"`
-	ConcurrencyLevel int    `json:"concurrencyLevel,omitempty"`
-	NormalTxPoolSize int    `json:"normalTxPool,omitempty"`
-	PatchTxPoolSize  int    `json:"patchTxPool,omitempty"`
-	MaxBlockTxBytes  int    `json:"maxBlockTxBytes,omitempty"`
-	NodeCache        string `json:"nodeCache,omitempty"`
-	Channel          string `json:"channel"`
-	SecureSuites     string `json:"secureSuites"`
-	SecureAeads      string `json:"secureAeads"`
-	DefWaitTimeout   int64  `json:"defaultWaitTimeout"`
-	MaxWaitTimeout   int64  `json:"maxWaitTimeout"`
-	TxTimeout        int64  `json:"txTimeout"`
-	AutoStart        bool   `json:"autoStart"`
-	ChildrenLimit    *int   `json:"childrenLimit,omitempty"`
-	NephewsLimit     *int   `json:"nephewsLimit,omitempty"`
-	ValidateTxOnSend bool   `json:"validateTxOnSend,omitempty"`
+	DBType           string          `json:"dbType"`
+	Platform         string          `json:"platform"`
+	PlatformConfig   json.RawMessage `json:"platformConfig,omitempty"`
+	SeedAddr         string          `json:"seedAddress"`
+	Role             uint            `json:"role"`
+	ConcurrencyLevel int             `json:"concurrencyLevel,omitempty"`
+	NormalTxPoolSize int             `json:"normalTxPool,omitempty"`
+	PatchTxPoolSize  int             `json:"patchTxPool,omitempty"`
+	MaxBlockTxBytes  int             `json:"maxBlockTxBytes,omitempty"`
+	NodeCache        string          `json:"nodeCache,omitempty"`
+	Channel          string          `json:"channel"`
+	SecureSuites     string          `json:"secureSuites"`
+	SecureAeads      string          `json:"secureAeads"`
+	DefWaitTimeout   int64           `json:"defaultWaitTimeout"`
+	MaxWaitTimeout   int64           `json:"maxWaitTimeout"`
+	TxTimeout        int64           `json:"txTimeout"`
+	AutoStart        bool            `json:"autoStart"`
+	ChildrenLimit    *int            `json:"childrenLimit,omitempty"`
+	NephewsLimit     *int            `json:"nephewsLimit,omitempty"`
+	ValidateTxOnSend bool            `json:"validateTxOnSend,omitempty"`
+	Archive          bool            `json:"archive,omitempty"`
+
+	ConsensusTimeouts *module.ConsensusTimeouts `json:"consensusTimeouts,omitempty"`
+	Retention         *module.RetentionPolicy   `json:"retention,omitempty"`
 }
 
 type ChainResetParam struct {
@@ -120,6 +128,23 @@ type ChainPruneParam struct {
 	Height int64  `json:"height"`
 }
 
+type ChainArchiveParam struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end,omitempty"`
+}
+
+type ChainForkParam struct {
+	Height     int64    `json:"height"`
+	NID        string   `json:"nid"`
+	CID        string   `json:"cid,omitempty"`
+	Validators []string `json:"validators,omitempty"`
+	File       string   `json:"file,omitempty"`
+}
+
+type ChainWALTruncateParam struct {
+	Records int `json:"records"`
+}
+
 type ChainBackupParam struct {
 	Manual bool `json:"manual,omitempty"`
 }
@@ -134,6 +159,16 @@ type RestoreBackupParam struct {
 	Overwrite bool   `json:"overwrite"`
 }
 
+type UserParam struct {
+	Id   string `json:"id"`
+	Role *Role  `json:"role,omitempty"`
+}
+
+type UserRoleParam struct {
+	Role  Role   `json:"role"`
+	Chain string `json:"chain,omitempty"`
+}
+
 func NewChainView(c *Chain) *ChainView {
 	state, height, lastErr := c.State()
 	v := &ChainView{
@@ -168,6 +203,7 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 	v := &ChainConfig{
 		DBType:           cfg.DBType,
 		Platform:         cfg.Platform,
+		PlatformConfig:   cfg.PlatformConfig,
 		SeedAddr:         cfg.SeedAddr,
 		Role:             cfg.Role,
 		ConcurrencyLevel: cfg.ConcurrencyLevel,
@@ -185,6 +221,10 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 		ChildrenLimit:    cfg.ChildrenLimit,
 		NephewsLimit:     cfg.NephewsLimit,
 		ValidateTxOnSend: cfg.ValidateTxOnSend,
+		Archive:          cfg.Archive,
+
+		ConsensusTimeouts: cfg.ConsensusTimeouts,
+		Retention:         cfg.Retention,
 	}
 	return v
 }
@@ -198,20 +238,29 @@ func RegisterInspectFunc(name string, f InspectFunc) error {
 }
 
 func RegisterRest(n *Node) {
+	baseDir := n.cfg.ResolveAbsolute(n.cfg.BaseDir)
 	r := Rest{
 		n: n,
-		a: NewAuth(path.Join(n.cfg.ResolveAbsolute(n.cfg.BaseDir), "auth.json"), server.UrlAdmin),
+		a: NewAuth(path.Join(baseDir, "auth.json"), server.UrlAdmin),
+		l: NewAuditLog(path.Join(baseDir, "audit.log")),
 	}
 	r.a.SkipIfEmptyUsers = n.cfg.AuthSkipIfEmptyUsers
-	ag := n.srv.AdminEchoGroup(r.a.MiddlewareFunc())
+	r.a.ChainResolver = r.resolveChain
+	ag := n.srv.AdminEchoGroup(r.l.MiddlewareFunc(AuditSourceAdmin),
+		r.a.MiddlewareFunc(), r.l.ParamsMiddlewareFunc())
 	r.RegisterChainHandlers(ag.Group(UrlChain))
 	r.RegisterSystemHandlers(ag.Group(UrlSystem))
+	r.RegisterUserHandlers(ag.Group(UrlUser))
+	r.RegisterDBHandlers(ag.Group(UrlDB))
 
-	r.RegisterChainHandlers(n.cliSrv.e.Group(UrlChain))
-	r.RegisterSystemHandlers(n.cliSrv.e.Group(UrlSystem))
-	r.RegisterUserHandlers(n.cliSrv.e.Group(UrlUser))
+	audit := []echo.MiddlewareFunc{
+		r.l.MiddlewareFunc(AuditSourceCli), r.l.ParamsMiddlewareFunc(),
+	}
+	r.RegisterChainHandlers(n.cliSrv.e.Group(UrlChain, audit...))
+	r.RegisterSystemHandlers(n.cliSrv.e.Group(UrlSystem, audit...))
+	r.RegisterUserHandlers(n.cliSrv.e.Group(UrlUser, audit...))
 	r.RegisterStatsHandlers(n.cliSrv.e.Group(UrlStats))
-	r.RegisterDBHandlers(n.cliSrv.e.Group(UrlDB))
+	r.RegisterDBHandlers(n.cliSrv.e.Group(UrlDB, audit...))
 
 	_ = RegisterInspectFunc("metrics", metric.Inspect)
 	_ = RegisterInspectFunc("network", network.Inspect)
@@ -224,26 +273,44 @@ func RegisterRest(n *Node) {
 	n.srv.RegisterMetricsHandler(n.cliSrv.e.Group("/metrics"))
 }
 
-func (r *Rest) RegisterChainHandlers(g *echo.Group) {
-	g.GET("", r.GetChains)
-	g.POST("", r.JoinChain)
-
-	g.GET(UrlChainRes, r.GetChain, r.ChainInjector)
-	g.DELETE(UrlChainRes, r.LeaveChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/start", r.StartChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/stop", r.StopChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/reset", r.ResetChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/verify", r.VerifyChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/import", r.ImportChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/prune", r.PruneChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/backup", r.BackupChain, r.ChainInjector)
-	route := g.GET(UrlChainRes+"/genesis", r.GetChainGenesis, r.ChainInjector)
+func (r *Rest) setRole(route *echo.Route, role Role) {
 	if r.a != nil {
-		r.a.SetSkip(route, false)
+		r.a.SetRole(route, role)
 	}
-	g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector)
-	g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/:"+TaskID, r.RunChainTask, r.ChainInjector)
+}
+
+// resolveChain returns chain-id of the chain for the selector, which is
+// used for the roles of the user for the chain.
+func (r *Rest) resolveChain(selector string) (string, bool) {
+	if c := r.n.GetChainBySelector(selector); c != nil {
+		return fmt.Sprintf("%#x", c.CID()), true
+	}
+	return "", false
+}
+
+func (r *Rest) RegisterChainHandlers(g *echo.Group) {
+	r.setRole(g.GET("", r.GetChains), RoleNone)
+	r.setRole(g.POST("", r.JoinChain), RoleAdmin)
+
+	r.setRole(g.GET(UrlChainRes, r.GetChain, r.ChainInjector), RoleNone)
+	r.setRole(g.DELETE(UrlChainRes, r.LeaveChain, r.ChainInjector), RoleAdmin)
+	r.setRole(g.POST(UrlChainRes+"/start", r.StartChain, r.ChainInjector), RoleOperator)
+	r.setRole(g.POST(UrlChainRes+"/stop", r.StopChain, r.ChainInjector), RoleOperator)
+	r.setRole(g.POST(UrlChainRes+"/reset", r.ResetChain, r.ChainInjector), RoleAdmin)
+	r.setRole(g.POST(UrlChainRes+"/verify", r.VerifyChain, r.ChainInjector), RoleOperator)
+	r.setRole(g.POST(UrlChainRes+"/import", r.ImportChain, r.ChainInjector), RoleAdmin)
+	r.setRole(g.POST(UrlChainRes+"/prune", r.PruneChain, r.ChainInjector), RoleAdmin)
+	r.setRole(g.POST(UrlChainRes+"/backup", r.BackupChain, r.ChainInjector), RoleOperator)
+	r.setRole(g.GET(UrlChainRes+"/genesis", r.GetChainGenesis, r.ChainInjector), RoleViewer)
+	r.setRole(g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector), RoleNone)
+	r.setRole(g.GET(UrlChainRes+"/consensus", r.GetConsensusState, r.ChainInjector), RoleViewer)
+	r.setRole(g.GET(UrlChainRes+"/consensus/events", r.RunConsensusSession, r.ChainInjector), RoleViewer)
+	r.setRole(g.GET(UrlChainRes+"/prep/warnings", r.RunPRepSession, r.ChainInjector), RoleViewer)
+	r.setRole(g.GET(UrlChainRes+"/wal", r.InspectChainWALs, r.ChainInjector), RoleViewer)
+	r.setRole(g.GET(UrlChainRes+"/wal/:"+ParamWAL, r.DumpChainWAL, r.ChainInjector), RoleViewer)
+	r.setRole(g.POST(UrlChainRes+"/wal/:"+ParamWAL+"/truncate", r.TruncateChainWAL, r.ChainInjector), RoleOperator)
+	r.setRole(g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector), RoleOperator)
+	r.setRole(g.POST(UrlChainRes+"/:"+TaskID, r.RunChainTask, r.ChainInjector), RoleOperator)
 }
 
 func (r *Rest) ChainInjector(next echo.HandlerFunc) echo.HandlerFunc {
@@ -439,6 +506,60 @@ func (r *Rest) GetChainConfig(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, NewChainConfig(c.cfg))
 }
 
+func (r *Rest) GetConsensusState(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	var rs *consensus.RoundState
+	if inspector, ok := c.Consensus().(consensus.Inspector); ok {
+		rs = inspector.InspectRoundState()
+	}
+	if rs == nil {
+		return ctx.String(http.StatusServiceUnavailable, "ConsensusNotRunning")
+	}
+	return ctx.JSON(http.StatusOK, rs)
+}
+
+// RunConsensusSession streams the events of the consensus through websocket.
+func (r *Rest) RunConsensusSession(ctx echo.Context) error {
+	return r.n.srv.RunConsensusSession(ctx)
+}
+
+// RunPRepSession streams the warnings of the PRep monitor through websocket.
+func (r *Rest) RunPRepSession(ctx echo.Context) error {
+	return r.n.srv.RunPRepSession(ctx)
+}
+
+func (r *Rest) InspectChainWALs(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	l, err := r.n.InspectChainWALs(c.CID())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (r *Rest) DumpChainWAL(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	raw, _ := strconv.ParseBool(ctx.QueryParam("raw"))
+	dump, err := r.n.DumpChainWAL(c.CID(), ctx.Param(ParamWAL), raw)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, dump)
+}
+
+func (r *Rest) TruncateChainWAL(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainWALTruncateParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	s, err := r.n.TruncateChainWAL(c.CID(), ctx.Param(ParamWAL), param.Records)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, s)
+}
+
 func (r *Rest) ConfigureChain(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	p := &ConfigureParam{}
@@ -469,9 +590,10 @@ func (r *Rest) RunChainTask(ctx echo.Context) error {
 }
 
 func (r *Rest) RegisterSystemHandlers(g *echo.Group) {
-	g.GET("", r.GetSystem)
-	g.GET("/configure", r.GetSystemConfig)
-	g.POST("/configure", r.ConfigureSystem)
+	r.setRole(g.GET("", r.GetSystem), RoleNone)
+	r.setRole(g.GET("/configure", r.GetSystemConfig), RoleNone)
+	r.setRole(g.POST("/configure", r.ConfigureSystem), RoleAdmin)
+	r.setRole(g.GET("/audit", r.GetAuditLog), RoleViewer)
 	r.RegistryBackupHandlers(g.Group("/backup"))
 	r.RegistryRestoreHandlers(g.Group("/restore"))
 }
@@ -512,7 +634,7 @@ func (r *Rest) ConfigureSystem(ctx echo.Context) error {
 }
 
 func (r *Rest) RegistryBackupHandlers(g *echo.Group) {
-	g.GET("", r.GetBackups)
+	r.setRole(g.GET("", r.GetBackups), RoleNone)
 }
 
 func (r *Rest) GetBackups(ctx echo.Context) error {
@@ -524,9 +646,9 @@ func (r *Rest) GetBackups(ctx echo.Context) error {
 }
 
 func (r *Rest) RegistryRestoreHandlers(g *echo.Group) {
-	g.POST("", r.RestoreBackup)
-	g.GET("", r.GetRestore)
-	g.DELETE("", r.StopRestore)
+	r.setRole(g.POST("", r.RestoreBackup), RoleAdmin)
+	r.setRole(g.GET("", r.GetRestore), RoleNone)
+	r.setRole(g.DELETE("", r.StopRestore), RoleAdmin)
 }
 
 func (r *Rest) GetRestore(ctx echo.Context) error {
@@ -552,10 +674,36 @@ func (r *Rest) StopRestore(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) GetAuditLog(ctx echo.Context) error {
+	var since time.Time
+	if s := ctx.QueryParam("since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return ctx.String(http.StatusBadRequest, "InvalidSince(since:"+s+")")
+		}
+		since = t
+	}
+	limit := 0
+	if s := ctx.QueryParam("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			return ctx.String(http.StatusBadRequest, "InvalidLimit(limit:"+s+")")
+		}
+		limit = v
+	}
+	records, err := r.l.Query(ctx.QueryParam("user"), since, limit)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, records)
+}
+
 func (r *Rest) RegisterUserHandlers(g *echo.Group) {
-	g.GET("", r.Users)
-	g.POST("", r.AddUser)
-	g.DELETE(UrlUserRes, r.RemoveUser)
+	r.setRole(g.GET("", r.Users), RoleViewer)
+	r.setRole(g.POST("", r.AddUser), RoleAdmin)
+	r.setRole(g.GET(UrlUserRes, r.GetUser), RoleViewer)
+	r.setRole(g.DELETE(UrlUserRes, r.RemoveUser), RoleAdmin)
+	r.setRole(g.POST(UrlUserRes+"/role", r.SetUserRole), RoleAdmin)
 }
 
 func (r *Rest) Users(ctx echo.Context) error {
@@ -563,13 +711,15 @@ func (r *Rest) Users(ctx echo.Context) error {
 }
 
 func (r *Rest) AddUser(ctx echo.Context) error {
-	param := struct {
-		Id string `json:"id"`
-	}{}
-	if err := ctx.Bind(&param); err != nil {
+	param := &UserParam{}
+	if err := ctx.Bind(param); err != nil {
 		return echo.ErrBadRequest
 	}
-	if err := r.a.AddUser(param.Id); err != nil {
+	u := &UserInfo{ID: param.Id, Role: RoleAdmin}
+	if param.Role != nil {
+		u.Role = *param.Role
+	}
+	if err := r.a.AddUserWithInfo(u); err != nil {
 		if we, ok := err.(errors.Unwrapper); ok {
 			switch we.Unwrap() {
 			case ErrAlreadyExists:
@@ -581,6 +731,41 @@ func (r *Rest) AddUser(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) GetUser(ctx echo.Context) error {
+	u, err := r.a.GetUser(ctx.Param(ParamID))
+	if err != nil {
+		return ctx.String(http.StatusNotFound, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, u)
+}
+
+func (r *Rest) SetUserRole(ctx echo.Context) error {
+	param := &UserRoleParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	var cid string
+	if param.Chain != "" {
+		if c, ok := r.resolveChain(param.Chain); ok {
+			cid = c
+		} else if v, ok := cidOfSelector(param.Chain); ok {
+			cid = fmt.Sprintf("%#x", v)
+		} else {
+			return ctx.String(http.StatusBadRequest, "InvalidChain(chain:"+param.Chain+")")
+		}
+	}
+	if err := r.a.SetUserRole(ctx.Param(ParamID), cid, param.Role); err != nil {
+		if we, ok := err.(errors.Unwrapper); ok {
+			switch we.Unwrap() {
+			case ErrNotExists:
+				return ctx.String(http.StatusNotFound, err.Error())
+			}
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) RemoveUser(ctx echo.Context) error {
 	p := ctx.Param(ParamID)
 	if err := r.a.RemoveUser(p); err != nil {
@@ -665,13 +850,15 @@ func (r *Rest) ResponseStatsView(resp *echo.Response) error {
 
 func (r *Rest) RegisterDBHandlers(g *echo.Group) {
 	bg := g.Group("/:"+ParamCID+"/:"+ParamBK, r.ChainInjector, r.BucketInjector)
-	bg.GET("/:"+ParamKey, r.BucketGetValue)
+	r.setRole(bg.GET("", r.BucketScan), RoleViewer)
+	r.setRole(bg.GET("/count", r.BucketCount), RoleViewer)
+	r.setRole(bg.GET("/:"+ParamKey, r.BucketGetValue), RoleViewer)
 }
 
 func (r *Rest) BucketInjector(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		chain := ctx.Get("chain").(*Chain)
-		bkID := db.BucketID(ctx.Param(ParamBK))
+		bkID := bucketIDOf(ctx.Param(ParamBK))
 		var ret error
 		chain.DoDBTask(func(database db.Database) {
 			if database == nil {
@@ -684,6 +871,7 @@ func (r *Rest) BucketInjector(next echo.HandlerFunc) echo.HandlerFunc {
 				return
 			}
 			ctx.Set("bucket", bk)
+			ctx.Set("bucketID", bkID)
 			ret = next(ctx)
 			return
 		})
@@ -708,6 +896,96 @@ func (r *Rest) BucketGetValue(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, value)
 }
 
+const (
+	DefaultDBScanLimit = 100
+	MaxDBScanLimit     = 1000
+)
+
+func bucketRangeOf(ctx echo.Context) (*db.Range, error) {
+	r := &db.Range{}
+	for _, p := range []struct {
+		name  string
+		value *[]byte
+	}{
+		{"prefix", &r.Prefix},
+		{"start", &r.Start},
+		{"end", &r.Limit},
+	} {
+		s := ctx.QueryParam(p.name)
+		bs, err := parseHexParam(s)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidKey(%s:%s)", p.name, s)
+		}
+		*p.value = bs
+	}
+	return r, nil
+}
+
+func (r *Rest) BucketScan(ctx echo.Context) error {
+	bk := ctx.Get("bucket").(db.Bucket)
+	bkID := ctx.Get("bucketID").(db.BucketID)
+	rg, err := bucketRangeOf(ctx)
+	if err != nil {
+		return ctx.String(http.StatusBadRequest, err.Error())
+	}
+	limit := DefaultDBScanLimit
+	if s := ctx.QueryParam("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 || v > MaxDBScanLimit {
+			return ctx.String(http.StatusBadRequest, "InvalidLimit(limit:"+s+")")
+		}
+		limit = v
+	}
+	decode, _ := strconv.ParseBool(ctx.QueryParam("decode"))
+
+	itr, err := db.NewIterator(bk, rg)
+	if err != nil {
+		return ctx.String(http.StatusNotImplemented, err.Error())
+	}
+	defer itr.Release()
+	v := &DBScanView{Entries: make([]*DBEntry, 0)}
+	for itr.Next() {
+		if len(v.Entries) == limit {
+			v.Next = hex.EncodeToString(itr.Key())
+			break
+		}
+		v.Entries = append(v.Entries, newDBEntry(bkID, itr.Key(), itr.Value(), decode))
+	}
+	if err := itr.Error(); err != nil {
+		return ctx.String(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+func (r *Rest) BucketCount(ctx echo.Context) error {
+	bk := ctx.Get("bucket").(db.Bucket)
+	rg, err := bucketRangeOf(ctx)
+	if err != nil {
+		return ctx.String(http.StatusBadRequest, err.Error())
+	}
+	itr, err := db.NewIterator(bk, rg)
+	if err != nil {
+		return ctx.String(http.StatusNotImplemented, err.Error())
+	}
+	defer itr.Release()
+	done := ctx.Request().Context().Done()
+	v := &DBCountView{}
+	for itr.Next() {
+		v.Count += 1
+		if v.Count%1024 == 0 {
+			select {
+			case <-done:
+				return ctx.Request().Context().Err()
+			default:
+			}
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return ctx.String(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
 func EqualsSyscallErrno(err error, sen syscall.Errno) bool {
 	if oe, ok := err.(*net.OpError); ok {
 		if se, ok := oe.Err.(*os.SyscallError); ok {
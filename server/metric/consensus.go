@@ -10,11 +10,15 @@ import (
 )
 
 var (
-	msHeight     = stats.Int64("consensus_height", "height", stats.UnitDimensionless)
-	msRound      = stats.Int64("consensus_round", "round", stats.UnitDimensionless)
-	msHeightD    = stats.Int64("consensus_height_duration", "block_duration", stats.UnitMilliseconds)
-	msRoundD     = stats.Int64("consensus_round_duration", "block_duration", stats.UnitMilliseconds)
-	consensusMks = []tag.Key{}
+	msHeight       = stats.Int64("consensus_height", "height", stats.UnitDimensionless)
+	msRound        = stats.Int64("consensus_round", "round", stats.UnitDimensionless)
+	msHeightD      = stats.Int64("consensus_height_duration", "block_duration", stats.UnitMilliseconds)
+	msRoundD       = stats.Int64("consensus_round_duration", "block_duration", stats.UnitMilliseconds)
+	msTmoPropose   = stats.Int64("consensus_timeout_propose", "propose_timeout", stats.UnitMilliseconds)
+	msTmoPrevote   = stats.Int64("consensus_timeout_prevote", "prevote_timeout", stats.UnitMilliseconds)
+	msTmoPrecommit = stats.Int64("consensus_timeout_precommit", "precommit_timeout", stats.UnitMilliseconds)
+	msTmoNewRound  = stats.Int64("consensus_timeout_new_round", "new_round_timeout", stats.UnitMilliseconds)
+	consensusMks   = []tag.Key{}
 )
 
 func RegisterConsensus() {
@@ -22,12 +26,16 @@ func RegisterConsensus() {
 	RegisterMetricView(msRound, view.LastValue(), consensusMks)
 	RegisterMetricView(msHeightD, view.LastValue(), consensusMks)
 	RegisterMetricView(msRoundD, view.LastValue(), consensusMks)
+	RegisterMetricView(msTmoPropose, view.LastValue(), consensusMks)
+	RegisterMetricView(msTmoPrevote, view.LastValue(), consensusMks)
+	RegisterMetricView(msTmoPrecommit, view.LastValue(), consensusMks)
+	RegisterMetricView(msTmoNewRound, view.LastValue(), consensusMks)
 }
 
 type ConsensusMetric struct {
-	ctx context.Context
+	ctx      context.Context
 	heightTs time.Time
-	roundTs time.Time
+	roundTs  time.Time
 }
 
 func (m *ConsensusMetric) OnHeight(height int64) {
@@ -44,8 +52,18 @@ func (m *ConsensusMetric) OnRound(round int32) {
 	stats.Record(m.ctx, msRound.M(int64(round)), msRoundD.M(int64(d/time.Millisecond)))
 }
 
+// OnTimeouts records timeouts applied to the current round.
+func (m *ConsensusMetric) OnTimeouts(propose, prevote, precommit, newRound time.Duration) {
+	stats.Record(m.ctx,
+		msTmoPropose.M(propose.Milliseconds()),
+		msTmoPrevote.M(prevote.Milliseconds()),
+		msTmoPrecommit.M(precommit.Milliseconds()),
+		msTmoNewRound.M(newRound.Milliseconds()),
+	)
+}
+
 func NewConsensusMetric(ctx context.Context) *ConsensusMetric {
 	return &ConsensusMetric{
-		ctx : ctx,
+		ctx: ctx,
 	}
 }
@@ -57,8 +57,11 @@ var (
 		"icx_getVotesByHeight":       msRetrieve,
 		"icx_getProofForResult":      msRetrieve,
 		"icx_getProofForEvents":      msRetrieve,
+		"icx_getProof":               msRetrieve,
+		"icx_getStorageAt":           msRetrieve,
 		"icx_getScoreStatus":         msRetrieve,
 		"icx_getNetworkInfo":         msRetrieve,
+		"icx_estimateReward":         msRetrieve,
 		"btp_getNetworkInfo":         msRetrieve,
 		"btp_getNetworkTypeInfo":     msRetrieve,
 		"btp_getMessages":            msRetrieve,
@@ -0,0 +1,45 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	mkPRep        = NewMetricKey("prep")
+	mkWarning     = NewMetricKey("warning")
+	msVFailCont   = stats.Int64("prep_validation_fail_cont", "consecutive validation failures", stats.UnitDimensionless)
+	msVPenalties  = stats.Int64("prep_validation_penalties", "validation penalties in the mask", stats.UnitDimensionless)
+	msPRepWarning = stats.Int64("prep_warning", "penalty warnings", stats.UnitDimensionless)
+	prepMks       = []tag.Key{mkPRep}
+)
+
+func RegisterPRep() {
+	RegisterMetricView(msVFailCont, view.LastValue(), prepMks)
+	RegisterMetricView(msVPenalties, view.LastValue(), prepMks)
+	RegisterMetricView(msPRepWarning, view.Count(), []tag.Key{mkPRep, mkWarning})
+}
+
+type PRepMetric struct {
+	ctx context.Context
+}
+
+func (m *PRepMetric) OnStatus(prep string, vFailCont, penalties int64) {
+	ctx := GetMetricContext(m.ctx, &mkPRep, prep)
+	stats.Record(ctx, msVFailCont.M(vFailCont), msVPenalties.M(penalties))
+}
+
+func (m *PRepMetric) OnWarning(prep string, warning string) {
+	ctx := GetMetricContext(m.ctx, &mkPRep, prep)
+	ctx = GetMetricContext(ctx, &mkWarning, warning)
+	stats.Record(ctx, msPRepWarning.M(1))
+}
+
+func NewPRepMetric(ctx context.Context) *PRepMetric {
+	return &PRepMetric{
+		ctx: ctx,
+	}
+}
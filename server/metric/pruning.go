@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metric
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	msPruningTracked  = stats.Int64("pruning_tracked_height", "last height tracked", stats.UnitDimensionless)
+	msPruningState    = stats.Int64("pruning_state_height", "lowest height keeping state", stats.UnitDimensionless)
+	msPruningReceipts = stats.Int64("pruning_receipts_height", "lowest height keeping receipts", stats.UnitDimensionless)
+	msPruningDeleted  = stats.Int64("pruning_deleted", "deleted entries", stats.UnitDimensionless)
+	msPruningStepD    = stats.Int64("pruning_step_duration", "step duration", stats.UnitMilliseconds)
+	pruningMks        = []tag.Key{}
+)
+
+func RegisterPruning() {
+	RegisterMetricView(msPruningTracked, view.LastValue(), pruningMks)
+	RegisterMetricView(msPruningState, view.LastValue(), pruningMks)
+	RegisterMetricView(msPruningReceipts, view.LastValue(), pruningMks)
+	RegisterMetricView(msPruningDeleted, view.Sum(), pruningMks)
+	RegisterMetricView(msPruningStepD, view.LastValue(), pruningMks)
+}
+
+type PruningMetric struct {
+	ctx context.Context
+}
+
+// OnStep records the status after a step of the pruning.
+func (m *PruningMetric) OnStep(tracked, state, receipts int64, deleted int, d time.Duration) {
+	stats.Record(m.ctx,
+		msPruningTracked.M(tracked),
+		msPruningState.M(state),
+		msPruningReceipts.M(receipts),
+		msPruningDeleted.M(int64(deleted)),
+		msPruningStepD.M(d.Milliseconds()),
+	)
+}
+
+func NewPruningMetric(ctx context.Context) *PruningMetric {
+	return &PruningMetric{
+		ctx: ctx,
+	}
+}
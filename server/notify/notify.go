@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"sync"
+)
+
+const (
+	// TopicPRep is for warnings of the PRep monitor.
+	TopicPRep = "prep"
+	// TopicConsensus is for step transitions and votes of the consensus.
+	TopicConsensus = "consensus"
+)
+
+// Subscription receives notifications published for the chain and the topic.
+// Notifications are dropped if C is full.
+type Subscription struct {
+	C <-chan interface{}
+
+	ch    chan interface{}
+	cid   int
+	topic string
+}
+
+// Close stops receiving notifications.
+func (s *Subscription) Close() {
+	unsubscribe(s)
+}
+
+type subKey struct {
+	cid   int
+	topic string
+}
+
+var (
+	subsMtx sync.RWMutex
+	subs    = make(map[subKey][]*Subscription)
+)
+
+func Subscribe(cid int, topic string, size int) *Subscription {
+	subsMtx.Lock()
+	defer subsMtx.Unlock()
+
+	ch := make(chan interface{}, size)
+	s := &Subscription{C: ch, ch: ch, cid: cid, topic: topic}
+	key := subKey{cid, topic}
+	subs[key] = append(subs[key], s)
+	return s
+}
+
+func unsubscribe(s *Subscription) {
+	subsMtx.Lock()
+	defer subsMtx.Unlock()
+
+	key := subKey{s.cid, s.topic}
+	list := subs[key]
+	for i, e := range list {
+		if e == s {
+			last := len(list) - 1
+			list[i] = list[last]
+			list[last] = nil
+			list = list[:last]
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(subs, key)
+	} else {
+		subs[key] = list
+	}
+}
+
+// HasSubscriber returns whether there is any subscription for the chain and the topic.
+func HasSubscriber(cid int, topic string) bool {
+	subsMtx.RLock()
+	defer subsMtx.RUnlock()
+
+	return len(subs[subKey{cid, topic}]) > 0
+}
+
+// Publish sends v to the subscriptions for the chain and the topic.
+// It doesn't block, so slow subscribers may lose some notifications.
+func Publish(cid int, topic string, v interface{}) {
+	subsMtx.RLock()
+	defer subsMtx.RUnlock()
+
+	for _, s := range subs[subKey{cid, topic}] {
+		select {
+		case s.ch <- v:
+		default:
+		}
+	}
+}
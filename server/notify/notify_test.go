@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishSubscribe(t *testing.T) {
+	assert.False(t, HasSubscriber(1, "prep"))
+
+	s1 := Subscribe(1, "prep", 1)
+	s2 := Subscribe(1, "other", 1)
+	s3 := Subscribe(2, "prep", 1)
+	assert.True(t, HasSubscriber(1, "prep"))
+
+	Publish(1, "prep", 10)
+	// it shall not block even if the buffer is full
+	Publish(1, "prep", 11)
+
+	assert.Equal(t, 10, <-s1.C)
+	assert.Len(t, s2.C, 0)
+	assert.Len(t, s3.C, 0)
+
+	s1.Close()
+	assert.False(t, HasSubscriber(1, "prep"))
+	Publish(1, "prep", 12)
+	assert.Len(t, s1.C, 0)
+
+	s2.Close()
+	s3.Close()
+}
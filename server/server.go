@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -10,6 +11,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/metric"
@@ -31,6 +33,14 @@ type Config struct {
 	JSONRPCDefaultChannel string
 	JSONRPCBatchLimit     int
 	WSMaxSession          int
+
+	// TLS is enabled if TLSCertFile and TLSKeyFile are set. Client
+	// certificates are verified with TLSClientCAFile according to
+	// TLSClientAuth (none, optional or require).
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	TLSClientAuth   string
 }
 
 type Manager struct {
@@ -49,6 +59,10 @@ type Manager struct {
 	logger                log.Logger
 	metricsHandler        echo.HandlerFunc
 	mtr                   *metric.JsonrpcMetric
+	tlsCertFile           string
+	tlsKeyFile            string
+	tlsClientCAFile       string
+	tlsClientAuth         string
 }
 
 func NewManager(
@@ -77,6 +91,10 @@ func NewManager(
 		logger:                logger,
 		metricsHandler:        echo.WrapHandler(metric.PrometheusExporter()),
 		mtr:                   mtr,
+		tlsCertFile:           config.TLSCertFile,
+		tlsKeyFile:            config.TLSKeyFile,
+		tlsClientCAFile:       config.TLSClientCAFile,
+		tlsClientAuth:         config.TLSClientAuth,
 	}
 	m.SetMessageDump(config.JSONRPCDump)
 	m.SetIncludeDebug(config.JSONRPCIncludeDebug)
@@ -203,9 +221,32 @@ func (srv *Manager) Start() error {
 	// metric
 	srv.RegisterMetricsHandler(srv.e.Group("/metrics"))
 
+	if srv.tlsCertFile != "" || srv.tlsKeyFile != "" {
+		return srv.startTLS()
+	}
 	return srv.e.Start(srv.addr)
 }
 
+func (srv *Manager) startTLS() error {
+	clientAuth, err := ParseTLSClientAuth(srv.tlsClientAuth, srv.tlsClientCAFile != "")
+	if err != nil {
+		return err
+	}
+	if clientAuth != tls.NoClientCert && srv.tlsClientCAFile == "" {
+		return errors.IllegalArgumentError.Errorf(
+			"NoClientCAForClientAuth(auth=%s)", srv.tlsClientAuth)
+	}
+	r, err := newTLSReloader(srv.tlsCertFile, srv.tlsKeyFile, srv.tlsClientCAFile, srv.logger)
+	if err != nil {
+		return err
+	}
+	s := srv.e.TLSServer
+	s.Addr = srv.addr
+	s.TLSConfig = r.TLSConfig(clientAuth)
+	srv.logger.Infof("serving TLS cert=%s clientAuth=%s", srv.tlsCertFile, clientAuth)
+	return srv.e.StartServer(s)
+}
+
 func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	g.Use(middleware.Recover())
 
@@ -257,6 +298,19 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv))
 }
 
+// RunPRepSession runs websocket session for the PRep monitor. It's not
+// registered to the JSON-RPC APIs, so the chain shall be injected by the
+// caller registering it to the admin APIs.
+func (srv *Manager) RunPRepSession(ctx echo.Context) error {
+	return srv.wssm.RunPRepSession(ctx)
+}
+
+// RunConsensusSession runs websocket session for the events of the
+// consensus. The chain shall be injected like RunPRepSession.
+func (srv *Manager) RunConsensusSession(ctx echo.Context) error {
+	return srv.wssm.RunConsensusSession(ctx)
+}
+
 func (srv *Manager) RegisterMetricsHandler(g *echo.Group) {
 	g.GET("", srv.metricsHandler, func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	TLSClientAuthNone     = "none"
+	TLSClientAuthOptional = "optional"
+	TLSClientAuthRequire  = "require"
+
+	tlsReloadInterval = time.Second
+)
+
+// ParseTLSClientAuth returns the client authentication type for the name.
+// Empty name means TLSClientAuthOptional if there is client CA, otherwise
+// TLSClientAuthNone.
+func ParseTLSClientAuth(name string, hasCA bool) (tls.ClientAuthType, error) {
+	switch name {
+	case "":
+		if hasCA {
+			return tls.VerifyClientCertIfGiven, nil
+		}
+		return tls.NoClientCert, nil
+	case TLSClientAuthNone:
+		return tls.NoClientCert, nil
+	case TLSClientAuthOptional:
+		return tls.VerifyClientCertIfGiven, nil
+	case TLSClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, errors.IllegalArgumentError.Errorf(
+			"InvalidTLSClientAuth(name=%s)", name)
+	}
+}
+
+// tlsReloader keeps the certificate and the client CAs loaded from the
+// files, and reloads them on modification of the files. On failure of
+// reloading, it keeps the previous ones.
+type tlsReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	logger   log.Logger
+
+	mtx      sync.Mutex
+	checked  time.Time
+	modTimes [3]time.Time
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+}
+
+func (r *tlsReloader) files() [3]string {
+	return [3]string{r.certFile, r.keyFile, r.caFile}
+}
+
+func (r *tlsReloader) fileModTimes() ([3]time.Time, error) {
+	var mts [3]time.Time
+	for i, f := range r.files() {
+		if f == "" {
+			continue
+		}
+		fi, err := os.Stat(f)
+		if err != nil {
+			return mts, errors.WithStack(err)
+		}
+		mts[i] = fi.ModTime()
+	}
+	return mts, nil
+}
+
+func (r *tlsReloader) load() error {
+	mts, err := r.fileModTimes()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return errors.IllegalArgumentError.Wrapf(err,
+			"InvalidKeyPair(cert=%s,key=%s)", r.certFile, r.keyFile)
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.IllegalArgumentError.Errorf("NoCertificates(ca=%s)", r.caFile)
+		}
+	}
+	r.cert = &cert
+	r.pool = pool
+	r.modTimes = mts
+	return nil
+}
+
+func (r *tlsReloader) reloadIfChanged() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.checked) < tlsReloadInterval {
+		return
+	}
+	r.checked = now
+	mts, err := r.fileModTimes()
+	if err != nil {
+		r.logger.Warnf("fail to check TLS files err=%+v", err)
+		return
+	}
+	if mts == r.modTimes {
+		return
+	}
+	if err := r.load(); err != nil {
+		r.logger.Warnf("fail to reload TLS files err=%+v", err)
+		return
+	}
+	r.logger.Infof("reload TLS files cert=%s ca=%s", r.certFile, r.caFile)
+}
+
+func (r *tlsReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.cert, r.pool
+}
+
+// TLSConfig returns the configuration of the server using the current
+// certificate and client CAs for each connection.
+func (r *tlsReloader) TLSConfig(clientAuth tls.ClientAuthType) *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: clientAuth,
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.reloadIfChanged()
+		cert, pool := r.current()
+		cfg := base.Clone()
+		cfg.GetConfigForClient = nil
+		cfg.Certificates = []tls.Certificate{*cert}
+		cfg.ClientCAs = pool
+		return cfg, nil
+	}
+	return base
+}
+
+func newTLSReloader(certFile, keyFile, caFile string, logger log.Logger) (*tlsReloader, error) {
+	r := &tlsReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		logger:   logger,
+		checked:  time.Now(),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
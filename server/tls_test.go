@@ -0,0 +1,191 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, cn string, serial int64, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) writeTo(t *testing.T, certFile, keyFile string) {
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der})
+	assert.NoError(t, os.WriteFile(certFile, certPEM, 0600))
+	if keyFile != "" {
+		b, err := x509.MarshalECPrivateKey(c.key)
+		assert.NoError(t, err)
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b})
+		assert.NoError(t, os.WriteFile(keyFile, keyPEM, 0600))
+	}
+}
+
+func (c *testCert) tlsCert() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func handshake(server, client *tls.Config) (tls.ConnectionState, tls.ConnectionState, error) {
+	sc, cc := net.Pipe()
+	ss := tls.Server(sc, server)
+	cs := tls.Client(cc, client)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ss.Handshake()
+		_ = ss.Close()
+	}()
+	cerr := cs.Handshake()
+	if cerr == nil {
+		// read alert for the rejected certificate in TLS 1.3
+		_ = cs.SetReadDeadline(time.Now().Add(time.Second))
+		_, _ = cs.Read(make([]byte, 1))
+	}
+	_ = cs.Close()
+	serr := <-errCh
+	if serr != nil {
+		return tls.ConnectionState{}, tls.ConnectionState{}, serr
+	}
+	return ss.ConnectionState(), cs.ConnectionState(), cerr
+}
+
+func TestTLSReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := path.Join(dir, "server.crt")
+	keyFile := path.Join(dir, "server.key")
+	caFile := path.Join(dir, "ca.crt")
+
+	ca := newTestCert(t, "ca", 1, nil)
+	ca.writeTo(t, caFile, "")
+	srvCert := newTestCert(t, "server1", 2, ca)
+	srvCert.writeTo(t, certFile, keyFile)
+	user := newTestCert(t, "hx0000000000000000000000000000000000000001", 3, ca)
+	other := newTestCert(t, "other", 4, newTestCert(t, "other-ca", 5, nil))
+
+	_, err := newTLSReloader(certFile, path.Join(dir, "none.key"), caFile, log.New())
+	assert.Error(t, err)
+
+	r, err := newTLSReloader(certFile, keyFile, caFile, log.New())
+	assert.NoError(t, err)
+	server := r.TLSConfig(tls.RequireAndVerifyClientCert)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := &tls.Config{
+		RootCAs:      roots,
+		ServerName:   "localhost",
+		Certificates: []tls.Certificate{user.tlsCert()},
+	}
+	ss, cs, err := handshake(server, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "server1", cs.PeerCertificates[0].Subject.CommonName)
+	assert.Equal(t, user.cert.Subject.CommonName,
+		ss.VerifiedChains[0][0].Subject.CommonName)
+
+	// client certificate is required and should be signed by the CA
+	_, _, err = handshake(server, &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	assert.Error(t, err)
+	_, _, err = handshake(server, &tls.Config{
+		RootCAs:      roots,
+		ServerName:   "localhost",
+		Certificates: []tls.Certificate{other.tlsCert()},
+	})
+	assert.Error(t, err)
+
+	// reload on change of the files
+	srvCert2 := newTestCert(t, "server2", 6, ca)
+	srvCert2.writeTo(t, certFile, keyFile)
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, future, future))
+	r.checked = time.Time{}
+	_, cs, err = handshake(server, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "server2", cs.PeerCertificates[0].Subject.CommonName)
+
+	// keep the previous one on failure
+	assert.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0600))
+	future = future.Add(time.Minute)
+	assert.NoError(t, os.Chtimes(keyFile, future, future))
+	r.checked = time.Time{}
+	_, cs, err = handshake(server, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "server2", cs.PeerCertificates[0].Subject.CommonName)
+}
+
+func TestParseTLSClientAuth(t *testing.T) {
+	cases := []struct {
+		name  string
+		hasCA bool
+		auth  tls.ClientAuthType
+		err   bool
+	}{
+		{"", false, tls.NoClientCert, false},
+		{"", true, tls.VerifyClientCertIfGiven, false},
+		{TLSClientAuthNone, true, tls.NoClientCert, false},
+		{TLSClientAuthOptional, true, tls.VerifyClientCertIfGiven, false},
+		{TLSClientAuthRequire, true, tls.RequireAndVerifyClientCert, false},
+		{"invalid", true, tls.NoClientCert, true},
+	}
+	for _, c := range cases {
+		auth, err := ParseTLSClientAuth(c.name, c.hasCA)
+		assert.Equal(t, c.auth, auth, c.name)
+		assert.Equal(t, c.err, err != nil, c.name)
+	}
+}
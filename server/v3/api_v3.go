@@ -2,8 +2,10 @@ package v3
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -20,7 +22,9 @@ import (
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/archive"
 	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
 	"github.com/icon-project/goloop/service/trace"
 	"github.com/icon-project/goloop/service/txresult"
 )
@@ -38,6 +42,7 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getBlockByHash", getBlockByHash)
 	mr.RegisterMethod("icx_call", call)
 	mr.RegisterMethod("icx_getBalance", getBalance)
+	mr.RegisterMethod("icx_getStorageAt", getStorageAt)
 	mr.RegisterMethod("icx_getScoreApi", getScoreApi)
 	mr.RegisterMethod("icx_getTotalSupply", getTotalSupply)
 	mr.RegisterMethod("icx_getTransactionResult", getTransactionResult)
@@ -51,8 +56,10 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getVotesByHeight", getVotesByHeight)
 	mr.RegisterMethod("icx_getProofForResult", getProofForResult)
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
+	mr.RegisterMethod("icx_getProof", getProof)
 	mr.RegisterMethod("icx_getScoreStatus", getScoreStatus)
 	mr.RegisterMethod("icx_getNetworkInfo", getNetworkInfo)
+	mr.RegisterMethod("icx_estimateReward", estimateReward)
 
 	mr.RegisterMethod("btp_getNetworkInfo", getBTPNetworkInfo)
 	mr.RegisterMethod("btp_getNetworkTypeInfo", getBTPNetworkTypeInfo)
@@ -170,6 +177,24 @@ func (c *contextWithBM) GetBlockByHeight(height jsonrpc.HexInt) (module.Block, e
 	}
 }
 
+// GetArchiveIndex returns the archive index and the height if the state
+// at the height is indexed. It returns nil index if the archive mode isn't
+// enabled or the height isn't indexed, then the state should be used.
+func (c *contextWithChain) GetArchiveIndex(height jsonrpc.HexInt) (*archive.Index, int64, error) {
+	idx := archive.IndexOf(c.chain.Database())
+	if idx == nil || height == "" {
+		return nil, 0, nil
+	}
+	h, err := height.Int64()
+	if err != nil {
+		return nil, 0, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	if !idx.Contains(h) {
+		return nil, 0, nil
+	}
+	return idx, h, nil
+}
+
 func (c *contextWithBM) GetBlockByID(id []byte) (module.Block, error) {
 	blk, err := c.bm.GetBlock(id)
 	if err != nil {
@@ -305,13 +330,17 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
 
-	blk, err := c.GetBlockByHeight(param.Height)
+	return c.call(param.Height, params.RawMessage())
+}
+
+func (c *contextWithSM) call(height jsonrpc.HexInt, js []byte) (interface{}, error) {
+	blk, err := c.GetBlockByHeight(height)
 	if err != nil {
 		return nil, err
 	}
 
 	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
-	result, err := c.sm.Call(blk.Result(), blk.NextValidators(), params.RawMessage(), bi)
+	result, err := c.sm.Call(blk.Result(), blk.NextValidators(), js, bi)
 	if err != nil {
 		if service.InvalidQueryError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
@@ -325,6 +354,46 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	}
 }
 
+// estimateReward calls estimateReward of the system SCORE, which is
+// supported by ICON platform.
+func estimateReward(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param EstimateRewardParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	callParams := map[string]interface{}{
+		"address": param.Address,
+	}
+	if param.Terms != "" {
+		callParams["terms"] = param.Terms
+	}
+	if param.Delegations != nil {
+		callParams["delegations"] = *param.Delegations
+	}
+	if param.Bonds != nil {
+		callParams["bonds"] = *param.Bonds
+	}
+	js, err := json.Marshal(&CallParam{
+		ToAddress: jsonrpc.Address(state.SystemAddress.String()),
+		DataType:  "call",
+		Data: map[string]interface{}{
+			"method": "estimateReward",
+			"params": callParams,
+		},
+		Height: param.Height,
+	})
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return c.call(param.Height, js)
+}
+
 func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -337,6 +406,19 @@ func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, erro
 	}
 
 	var balance common.HexInt
+	if idx, height, err := c.GetArchiveIndex(param.Height); err != nil {
+		return nil, err
+	} else if idx != nil {
+		ass, _, err := idx.GetAccount(param.Address.Address().ID(), height)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		}
+		if ass != nil {
+			balance.Set(ass.GetBalance())
+		}
+		return &balance, nil
+	}
+
 	blk, err := c.GetBlockByHeight(param.Height)
 	if err != nil {
 		return nil, err
@@ -350,6 +432,50 @@ func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, erro
 	return &balance, nil
 }
 
+func getStorageAt(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithBM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param StorageParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	id := param.Address.Address().ID()
+	key, err := hex.DecodeString(strings.TrimPrefix(string(param.Key), "0x"))
+	if err != nil || len(key) == 0 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidKey(key=%s)", param.Key)
+	}
+
+	var value []byte
+	if idx, height, err := c.GetArchiveIndex(param.Height); err != nil {
+		return nil, err
+	} else if idx != nil {
+		if value, _, err = idx.GetValue(id, key, height); err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		}
+	} else {
+		blk, err := c.GetBlockByHeight(param.Height)
+		if err != nil {
+			return nil, err
+		}
+		wss, err := service.NewWorldSnapshotFromResult(c.chain.Database(), blk.Result())
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		}
+		if ass := wss.GetAccountSnapshot(id); ass != nil {
+			if value, err = ass.GetValue(key); err != nil {
+				return nil, c.AsRPCError(err)
+			}
+		}
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return "0x" + hex.EncodeToString(value), nil
+}
+
 func getScoreApi(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -704,6 +830,40 @@ func getProofForEvents(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{
 	return proofs, nil
 }
 
+func getProof(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param AccountProofParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	keys := make([][]byte, len(param.Keys))
+	for i, k := range param.Keys {
+		bs, err := hex.DecodeString(strings.TrimPrefix(string(k), "0x"))
+		if err != nil || len(bs) == 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidKey(idx=%d,key=%s)", i, k)
+		}
+		keys[i] = bs
+	}
+
+	b, err := c.GetBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.sm.GetAccountProof(b.Result(), param.Address.Address(), keys)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	jso, err := p.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return jso, nil
+}
+
 func getScoreStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1149,6 +1309,7 @@ func DebugMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository
 
 	mr.RegisterMethod("debug_getTrace", getTrace)
 	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	mr.RegisterMethod("debug_dryRunGovernance", dryRunGovernance)
 
 	return mr
 }
@@ -1283,6 +1444,70 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	return steps, nil
 }
 
+const (
+	dryRunMaxBlocks     = 100
+	dryRunMaxConcurrent = 2
+	dryRunTimeout       = time.Second * 60
+)
+
+// dryRunSlots limits the number of dry runs executed at once.
+var dryRunSlots = make(chan struct{}, dryRunMaxConcurrent)
+
+func dryRunGovernance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param DryRunParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	runner, ok := c.sm.(service.DryRunner)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.New("DryRunNotSupported")
+	}
+
+	height, err := param.Height.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	if err = c.CheckBaseHeight(height); err != nil {
+		return nil, err
+	}
+	blocks := int64(1)
+	if param.Blocks != "" {
+		if blocks, err = param.Blocks.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+	}
+	if blocks < 1 || blocks > dryRunMaxBlocks {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidBlocks(blocks=%d,max=%d)", blocks, dryRunMaxBlocks)
+	}
+
+	select {
+	case dryRunSlots <- struct{}{}:
+		defer func() { <-dryRunSlots }()
+	default:
+		return nil, jsonrpc.ErrorLackOfResource.Errorf(
+			"TooManyDryRuns(max=%d)", dryRunMaxConcurrent)
+	}
+	tctx, cancel := context.WithTimeout(c.Request().Context(), dryRunTimeout)
+	defer cancel()
+	res, err := runner.DryRun(tctx, c.bm, height, int(blocks), param.Calls)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, c.debug)
+	} else if errors.TimeoutError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeSystemTimeout.Wrap(err, c.debug)
+	} else if scoreresult.InvalidParameterError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return res, nil
+}
+
 type MissingTransactionInfo interface {
 	ReplaceID(height int64, id []byte) []byte
 	GetLocationOf(id []byte) (int64, int, bool)
@@ -3,6 +3,7 @@ package v3
 import (
 	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
 )
 
 const (
@@ -33,11 +34,36 @@ type CallParam struct {
 	Height      jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
 }
 
+type VoteParam struct {
+	Address jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	Value   jsonrpc.HexInt  `json:"value" validate:"required,t_int"`
+}
+
+type EstimateRewardParam struct {
+	Address     jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	Terms       jsonrpc.HexInt  `json:"terms,omitempty" validate:"optional,t_int"`
+	Delegations *[]VoteParam    `json:"delegations,omitempty"`
+	Bonds       *[]VoteParam    `json:"bonds,omitempty"`
+	Height      jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+}
+
+type DryRunParam struct {
+	Height jsonrpc.HexInt            `json:"height" validate:"required,t_int"`
+	Blocks jsonrpc.HexInt            `json:"blocks,omitempty" validate:"optional,t_int"`
+	Calls  []*service.GovernanceCall `json:"calls"`
+}
+
 type AddressParam struct {
 	Address jsonrpc.Address `json:"address" validate:"required,t_addr"`
 	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
 }
 
+type StorageParam struct {
+	Address jsonrpc.Address  `json:"address" validate:"required,t_addr_score"`
+	Key     jsonrpc.HexBytes `json:"key" validate:"required"`
+	Height  jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,t_int"`
+}
+
 type ScoreAddressParam struct {
 	Address jsonrpc.Address `json:"address" validate:"required,t_addr_score"`
 	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
@@ -88,6 +114,12 @@ type ProofEventsParam struct {
 	Events    []jsonrpc.HexInt `json:"events" validate:"gt=0,dive,t_int"`
 }
 
+type AccountProofParam struct {
+	Address jsonrpc.Address    `json:"address" validate:"required,t_addr"`
+	Keys    []jsonrpc.HexBytes `json:"keys,omitempty" validate:"max=64"`
+	Height  jsonrpc.HexInt     `json:"height,omitempty" validate:"optional,t_int"`
+}
+
 type RosettaTraceParam struct {
 	Tx     jsonrpc.HexBytes `json:"tx,omitempty" validate:"optional,t_rhash"`
 	Block  jsonrpc.HexBytes `json:"block,omitempty" validate:"optional,t_hash"`
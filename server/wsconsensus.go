@@ -0,0 +1,58 @@
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/notify"
+)
+
+const consensusNotificationBuf = 256
+
+type ConsensusRequest struct {
+	// StepOnly disables notifications for votes.
+	StepOnly bool `json:"stepOnly,omitempty"`
+}
+
+// RunConsensusSession sends step transitions and arrivals of votes of the
+// consensus to the client.
+func (wm *wsSessionManager) RunConsensusSession(ctx echo.Context) error {
+	var cr ConsensusRequest
+	wss, err := wm.initSession(ctx, &cr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	if wss.chain.Consensus() == nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+
+	sub := notify.Subscribe(wss.chain.CID(), notify.TopicConsensus, consensusNotificationBuf)
+	defer sub.Close()
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+loop:
+	for {
+		select {
+		case err = <-ech:
+			break loop
+		case n := <-sub.C:
+			if ev, ok := n.(*consensus.Event); ok && cr.StepOnly && ev.Type != consensus.EventStep {
+				continue
+			}
+			if err = wss.WriteJSON(n); err != nil {
+				wm.logger.Infof("fail to write json ConsensusNotification err:%+v\n", err)
+				break loop
+			}
+		}
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
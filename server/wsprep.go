@@ -0,0 +1,52 @@
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/notify"
+)
+
+const prepNotificationBuf = 64
+
+type PRepRequest struct {
+}
+
+// RunPRepSession sends warnings of the PRep monitor to the client.
+// The PRep monitor shall be enabled by the platform configuration of the chain.
+func (wm *wsSessionManager) RunPRepSession(ctx echo.Context) error {
+	var pr PRepRequest
+	wss, err := wm.initSession(ctx, &pr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	if wss.chain.ServiceManager() == nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+
+	sub := notify.Subscribe(wss.chain.CID(), notify.TopicPRep, prepNotificationBuf)
+	defer sub.Close()
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+loop:
+	for {
+		select {
+		case err = <-ech:
+			break loop
+		case n := <-sub.C:
+			if err = wss.WriteJSON(n); err != nil {
+				wm.logger.Infof("fail to write json PRepNotification err:%+v\n", err)
+				break loop
+			}
+		}
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/service/archive"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// NewWorldSnapshotFromResult returns the world snapshot of the result.
+// It's only for accessing accounts, so the extension and the validators
+// aren't loaded.
+func NewWorldSnapshotFromResult(dbase db.Database, result []byte) (state.WorldSnapshot, error) {
+	return newWorldSnapshot(dbase, nil, result, nil)
+}
+
+// archiveResult indexes the changes of the state of the transition
+// finalized if the archive mode is enabled. The parent should be the one
+// before the finalization. Failure of the index doesn't affect the
+// transition, so it's only logged. The index stops on failure until the
+// archive task fills it, so it's logged once.
+func (m *manager) archiveResult(t, parent *transition) {
+	idx := archive.IndexOf(m.db)
+	if idx == nil || t.syncer != nil || parent == nil {
+		return
+	}
+	height := t.bi.Height() + 1
+	if err := idx.Append(height, parent.worldSnapshot, t.worldSnapshot); err != nil {
+		m.log.Errorf("FAIL to archive state height=%d (stopped until the archive task fills it) err=%+v",
+			height, err)
+	}
+}
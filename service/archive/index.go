@@ -0,0 +1,365 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package archive maintains the index of the states by heights for
+// the archive mode.
+//
+// The index keeps the values of the accounts and the storages whenever
+// they are changed. The state at a height is the state used for the queries
+// at the height, which is the result of the block at the height. Entries are
+// stored in the order of the keys then the heights in reverse, so the value
+// at a height is found by a seek.
+//
+//	a | key of account | ^height                  -> account bytes
+//	s | key of account | sha3(storage key) | ^height -> value
+//
+// Empty value means that the account or the value is removed.
+package archive
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const FlagIndex = "archive.index"
+
+const (
+	prefixAccount = 'a'
+	prefixValue   = 's'
+	keyRange      = "range"
+	heightLen     = 8
+)
+
+type indexRange struct {
+	Base int64
+	Last int64
+}
+
+// Index is the index of the states in the range of heights.
+type Index struct {
+	mutex   sync.Mutex
+	dbase   db.Database
+	bk      db.Bucket
+	base    int64
+	last    int64
+	stalled bool
+}
+
+func heightBytes(height int64) []byte {
+	bs := make([]byte, heightLen)
+	binary.BigEndian.PutUint64(bs, ^uint64(height))
+	return bs
+}
+
+func accountPrefix(key []byte) []byte {
+	prefix := make([]byte, 0, 1+len(key)+heightLen)
+	prefix = append(prefix, prefixAccount)
+	return append(prefix, key...)
+}
+
+func valuePrefix(key, k []byte) []byte {
+	prefix := make([]byte, 0, 1+len(key)+crypto.HashLen+heightLen)
+	prefix = append(prefix, prefixValue)
+	prefix = append(prefix, key...)
+	return append(prefix, crypto.SHA3Sum256(k)...)
+}
+
+// Range returns the range of the heights indexed. ok is false if nothing
+// is indexed.
+func (idx *Index) Range() (base int64, last int64, ok bool) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	return idx.base, idx.last, idx.last >= 0
+}
+
+// Stalled returns whether appending is stopped by a gap after the range.
+// Fill resumes it.
+func (idx *Index) Stalled() bool {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	return idx.stalled
+}
+
+// Contains returns whether the state at the height is indexed.
+func (idx *Index) Contains(height int64) bool {
+	base, last, ok := idx.Range()
+	return ok && base <= height && height <= last
+}
+
+func (idx *Index) setRange(base, last int64) error {
+	bs, err := codec.BC.MarshalToBytes(&indexRange{Base: base, Last: last})
+	if err != nil {
+		return err
+	}
+	if err := idx.bk.Set([]byte(keyRange), bs); err != nil {
+		return err
+	}
+	idx.base, idx.last = base, last
+	idx.stalled = false
+	return nil
+}
+
+type indexWriter struct {
+	bk     db.Bucket
+	height []byte
+}
+
+func (w *indexWriter) OnAccount(key []byte, ass state.AccountSnapshot) error {
+	value := []byte{}
+	if ass != nil {
+		value = ass.Bytes()
+	}
+	return w.bk.Set(append(accountPrefix(key), w.height...), value)
+}
+
+func (w *indexWriter) OnValue(key []byte, k, v []byte) error {
+	if v == nil {
+		v = []byte{}
+	}
+	return w.bk.Set(append(valuePrefix(key, k), w.height...), v)
+}
+
+// write stores the changes of the state at the height from prev, which is
+// the state at the previous height. All values of the state are stored if
+// prev is nil. Writing same state again makes no difference.
+func (idx *Index) write(height int64, prev, ws state.WorldSnapshot) error {
+	return state.DiffWorldSnapshot(prev, ws, &indexWriter{
+		bk:     idx.bk,
+		height: heightBytes(height),
+	})
+}
+
+// Append indexes the state at the height. prev is the state at the
+// previous height. It only writes the changes, so nothing is indexed until
+// Fill sets the base, which writes the whole state. If the height isn't
+// next to the range, it returns an error, and it stops appending until
+// Fill covers the gap.
+func (idx *Index) Append(height int64, prev, ws state.WorldSnapshot) error {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	if idx.last < 0 || idx.stalled || height <= idx.last {
+		return nil
+	}
+	if height != idx.last+1 {
+		idx.stalled = true
+		return errors.InvalidStateError.Errorf(
+			"NotContinuous(last=%d,height=%d)", idx.last, height)
+	}
+	if err := idx.write(height, prev, ws); err != nil {
+		return err
+	}
+	return idx.setRange(idx.base, height)
+}
+
+// StateFunc returns the state at the height.
+type StateFunc func(height int64) (state.WorldSnapshot, error)
+
+// Fill indexes the states from start to end which aren't indexed yet, so
+// the index covers the range after it. The range should be connected to
+// the range already indexed. onProgress is called with the height before
+// it's indexed, and the error returned stops the fill.
+func (idx *Index) Fill(start, end int64, stateAt StateFunc, onProgress func(height int64) error) error {
+	if start < 0 || end < start {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidRange(start=%d,end=%d)", start, end)
+	}
+	base, last, ok := idx.Range()
+	if ok && (start > last+1 || end < base-1) {
+		return errors.IllegalArgumentError.Errorf(
+			"NotContinuous(start=%d,end=%d,base=%d,last=%d)",
+			start, end, base, last)
+	}
+
+	fill := func(from, to int64, prev state.WorldSnapshot, update func(h int64) error) error {
+		for height := from; height <= to; height++ {
+			if err := onProgress(height); err != nil {
+				return err
+			}
+			ws, err := stateAt(height)
+			if err != nil {
+				return err
+			}
+			if err := idx.write(height, prev, ws); err != nil {
+				return err
+			}
+			if update != nil {
+				if err := update(height); err != nil {
+					return err
+				}
+			}
+			prev = ws
+		}
+		return nil
+	}
+	setRange := func(base, last int64) error {
+		idx.mutex.Lock()
+		defer idx.mutex.Unlock()
+		return idx.setRange(base, last)
+	}
+
+	if !ok {
+		return fill(start, end, nil, func(h int64) error {
+			return setRange(start, h)
+		})
+	}
+	// the index begins from the full state at the start, and the base is
+	// written again for the values removed at the base.
+	if start < base {
+		if err := fill(start, base, nil, nil); err != nil {
+			return err
+		}
+		if err := setRange(start, last); err != nil {
+			return err
+		}
+	}
+	if end > last {
+		prev, err := stateAt(last)
+		if err != nil {
+			return err
+		}
+		return fill(last+1, end, prev, func(h int64) error {
+			idx.mutex.Lock()
+			defer idx.mutex.Unlock()
+			if h <= idx.last {
+				return nil
+			}
+			return idx.setRange(idx.base, h)
+		})
+	}
+	return nil
+}
+
+// seek returns the value of the latest entry at or before the height
+// among the entries having the prefix.
+func (idx *Index) seek(prefix []byte, height int64) ([]byte, error) {
+	itr, err := db.NewIterator(idx.bk, &db.Range{
+		Prefix: prefix,
+		Start:  append(append([]byte{}, prefix...), heightBytes(height)...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+	if itr.Next() {
+		return append([]byte{}, itr.Value()...), nil
+	}
+	return nil, itr.Error()
+}
+
+// GetAccount returns the account at the height. ok is false if the height
+// isn't indexed, and the account is nil if it doesn't exist.
+func (idx *Index) GetAccount(id []byte, height int64) (ass state.AccountSnapshot, ok bool, err error) {
+	if !idx.Contains(height) {
+		return nil, false, nil
+	}
+	bs, err := idx.seek(accountPrefix(state.AccountKeyOf(id)), height)
+	if err != nil || len(bs) == 0 {
+		return nil, true, err
+	}
+	ass, err = state.AccountSnapshotFromBytes(idx.dbase, bs)
+	return ass, true, err
+}
+
+// GetValue returns the value of the storage of the account at the height.
+// ok is false if the height isn't indexed, and the value is nil if it
+// doesn't exist.
+func (idx *Index) GetValue(id, k []byte, height int64) (value []byte, ok bool, err error) {
+	if !idx.Contains(height) {
+		return nil, false, nil
+	}
+	bs, err := idx.seek(valuePrefix(state.AccountKeyOf(id), k), height)
+	if err != nil || len(bs) == 0 {
+		return nil, true, err
+	}
+	return bs, true, nil
+}
+
+// NewIndex returns the index stored in the database.
+func NewIndex(dbase db.Database) (*Index, error) {
+	bk, err := dbase.GetBucket(db.ArchiveIndex)
+	if err != nil {
+		return nil, err
+	}
+	idx := &Index{
+		dbase: dbase,
+		bk:    bk,
+		base:  -1,
+		last:  -1,
+	}
+	bs, err := bk.Get([]byte(keyRange))
+	if err != nil {
+		return nil, errors.CriticalIOError.Wrap(err, "FailToGetArchiveRange")
+	}
+	if len(bs) > 0 {
+		var r indexRange
+		if _, err := codec.BC.UnmarshalFromBytes(bs, &r); err != nil {
+			return nil, errors.CriticalFormatError.Wrap(err, "InvalidArchiveRange")
+		}
+		idx.base, idx.last = r.Base, r.Last
+	}
+	return idx, nil
+}
+
+// Attach attaches the index to the database. The index is updated on
+// finalization of the results.
+func Attach(dbase db.Database) (db.Database, error) {
+	idx, err := NewIndex(dbase)
+	if err != nil {
+		return nil, err
+	}
+	return db.WithFlags(dbase, db.Flags{
+		FlagIndex: idx,
+	}), nil
+}
+
+// IndexOf returns the index attached to the database. It returns nil if
+// the archive mode isn't enabled.
+func IndexOf(dbase db.Database) *Index {
+	if idx, ok := db.GetFlag(dbase, FlagIndex).(*Index); ok {
+		return idx
+	}
+	return nil
+}
+
+// Copy copies the index entries from src to dst.
+func Copy(src, dst db.Database) error {
+	sbk, err := src.GetBucket(db.ArchiveIndex)
+	if err != nil {
+		return err
+	}
+	dbk, err := dst.GetBucket(db.ArchiveIndex)
+	if err != nil {
+		return err
+	}
+	itr, err := db.NewIterator(sbk, nil)
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+	for itr.Next() {
+		if err := dbk.Set(itr.Key(), itr.Value()); err != nil {
+			return err
+		}
+	}
+	return itr.Error()
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package archive
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/service/state"
+)
+
+var (
+	testAccounts = [][]byte{[]byte("acct1"), []byte("acct2"), []byte("acct3")}
+	testKeys     = [][]byte{[]byte("k1"), []byte("k2")}
+)
+
+type testHistory struct {
+	states   []state.WorldSnapshot
+	balances []map[string]int64
+	values   []map[string]string
+}
+
+// newTestHistory makes the states for the heights. An account is changed
+// at every height, and some values are removed.
+func newTestHistory(t *testing.T, dbase db.Database, heights int) *testHistory {
+	h := new(testHistory)
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	balances := map[string]int64{}
+	values := map[string]string{}
+	for height := 0; height < heights; height++ {
+		if height > 0 {
+			id := testAccounts[height%len(testAccounts)]
+			as := ws.GetAccountState(id)
+			as.SetBalance(big.NewInt(int64(height)))
+			balances[string(id)] = int64(height)
+
+			k := testKeys[height%len(testKeys)]
+			vk := string(id) + "/" + string(k)
+			if height%5 == 0 {
+				_, err := as.DeleteValue(k)
+				assert.NoError(t, err)
+				delete(values, vk)
+			} else {
+				v := fmt.Sprintf("value%d", height)
+				_, err := as.SetValue(k, []byte(v))
+				assert.NoError(t, err)
+				values[vk] = v
+			}
+		}
+		wss := ws.GetSnapshot()
+		assert.NoError(t, wss.Flush())
+		h.states = append(h.states, wss)
+
+		bm, vm := map[string]int64{}, map[string]string{}
+		for k, v := range balances {
+			bm[k] = v
+		}
+		for k, v := range values {
+			vm[k] = v
+		}
+		h.balances = append(h.balances, bm)
+		h.values = append(h.values, vm)
+	}
+	return h
+}
+
+func (h *testHistory) stateAt(height int64) (state.WorldSnapshot, error) {
+	return h.states[height], nil
+}
+
+func (h *testHistory) check(t *testing.T, idx *Index, from, to int64) {
+	for height := from; height <= to; height++ {
+		for _, id := range testAccounts {
+			ass, ok, err := idx.GetAccount(id, height)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			balance, exists := h.balances[height][string(id)]
+			if exists {
+				if assert.NotNil(t, ass, "height=%d id=%s", height, id) {
+					assert.EqualValues(t, balance, ass.GetBalance().Int64())
+				}
+			} else {
+				assert.Nil(t, ass, "height=%d id=%s", height, id)
+			}
+			for _, k := range testKeys {
+				v, ok, err := idx.GetValue(id, k, height)
+				assert.NoError(t, err)
+				assert.True(t, ok)
+				assert.Equal(t, h.values[height][string(id)+"/"+string(k)], string(v),
+					"height=%d id=%s key=%s", height, id, k)
+			}
+		}
+	}
+}
+
+func noProgress(height int64) error {
+	return nil
+}
+
+func TestIndex_Append(t *testing.T) {
+	dbase := db.NewMapDB()
+	h := newTestHistory(t, dbase, 25)
+
+	idx, err := NewIndex(dbase)
+	assert.NoError(t, err)
+	_, _, ok := idx.Range()
+	assert.False(t, ok)
+
+	// nothing is indexed until the base is set
+	assert.NoError(t, idx.Append(5, h.states[4], h.states[5]))
+	_, _, ok = idx.Range()
+	assert.False(t, ok)
+
+	assert.NoError(t, idx.Fill(4, 4, h.stateAt, noProgress))
+	for height := 5; height < 20; height++ {
+		err := idx.Append(int64(height), h.states[height-1], h.states[height])
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, idx.Append(10, h.states[9], h.states[10]))
+
+	base, last, ok := idx.Range()
+	assert.True(t, ok)
+	assert.EqualValues(t, 4, base)
+	assert.EqualValues(t, 19, last)
+	assert.False(t, idx.Contains(3))
+	h.check(t, idx, 4, 19)
+
+	_, ok, err = idx.GetAccount(testAccounts[0], 3)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// the range is restored from the database
+	idx2, err := NewIndex(dbase)
+	assert.NoError(t, err)
+	base, last, ok = idx2.Range()
+	assert.True(t, ok)
+	assert.EqualValues(t, 4, base)
+	assert.EqualValues(t, 19, last)
+
+	// a gap stops appending until it's filled
+	assert.Error(t, idx.Append(21, h.states[20], h.states[21]))
+	assert.True(t, idx.Stalled())
+	assert.NoError(t, idx.Append(22, h.states[21], h.states[22]))
+	_, last, _ = idx.Range()
+	assert.EqualValues(t, 19, last)
+
+	assert.NoError(t, idx.Fill(20, 22, h.stateAt, noProgress))
+	assert.False(t, idx.Stalled())
+	assert.NoError(t, idx.Append(23, h.states[22], h.states[23]))
+	_, last, _ = idx.Range()
+	assert.EqualValues(t, 23, last)
+	h.check(t, idx, 4, 23)
+}
+
+func TestIndex_Fill(t *testing.T) {
+	dbase := db.NewMapDB()
+	h := newTestHistory(t, dbase, 30)
+
+	idx, err := NewIndex(dbase)
+	assert.NoError(t, err)
+	assert.NoError(t, idx.Fill(12, 15, h.stateAt, noProgress))
+	h.check(t, idx, 12, 15)
+
+	assert.Error(t, idx.Fill(20, 25, h.stateAt, noProgress))
+	assert.Error(t, idx.Fill(3, 9, h.stateAt, noProgress))
+
+	assert.NoError(t, idx.Fill(3, 20, h.stateAt, noProgress))
+	base, last, _ := idx.Range()
+	assert.EqualValues(t, 3, base)
+	assert.EqualValues(t, 20, last)
+	h.check(t, idx, 3, 20)
+
+	for height := 21; height < 30; height++ {
+		err := idx.Append(int64(height), h.states[height-1], h.states[height])
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, idx.Fill(0, 29, h.stateAt, noProgress))
+	h.check(t, idx, 0, 29)
+
+	stopped := fmt.Errorf("stopped")
+	idx2, err := Attach(db.NewMapDB())
+	assert.NoError(t, err)
+	err = IndexOf(idx2).Fill(0, 10, h.stateAt, func(height int64) error {
+		if height == 5 {
+			return stopped
+		}
+		return nil
+	})
+	assert.Equal(t, stopped, err)
+	base, last, _ = IndexOf(idx2).Range()
+	assert.EqualValues(t, 0, base)
+	assert.EqualValues(t, 4, last)
+	assert.Nil(t, IndexOf(dbase))
+}
@@ -0,0 +1,372 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// GovernanceCall is a call applied to the forked state before re-executing
+// blocks. From is the governance address and To is the chain SCORE by default.
+type GovernanceCall struct {
+	From   *common.Address `json:"from,omitempty"`
+	To     *common.Address `json:"to,omitempty"`
+	Value  *common.HexInt  `json:"value,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type EventLogSummary struct {
+	Address *common.Address   `json:"scoreAddress"`
+	Indexed []common.HexBytes `json:"indexed"`
+	Data    []common.HexBytes `json:"data"`
+}
+
+type ReceiptSummary struct {
+	Status    common.HexInt32    `json:"status"`
+	StepUsed  common.HexInt      `json:"stepUsed"`
+	EventLogs []*EventLogSummary `json:"eventLogs"`
+}
+
+type ReceiptDifference struct {
+	Height   common.HexInt64 `json:"height"`
+	Group    string          `json:"group"`
+	TxIndex  common.HexInt32 `json:"txIndex"`
+	TxHash   common.HexBytes `json:"txHash"`
+	Expected *ReceiptSummary `json:"expected"`
+	Actual   *ReceiptSummary `json:"actual"`
+}
+
+type DryRunCallResult struct {
+	Method   string        `json:"method"`
+	StepUsed common.HexInt `json:"stepUsed"`
+}
+
+type DryRunBlockResult struct {
+	Height        common.HexInt64 `json:"height"`
+	Transactions  common.HexInt32 `json:"transactions"`
+	ResultMatched bool            `json:"resultMatched"`
+}
+
+type DryRunResult struct {
+	Height      common.HexInt64      `json:"height"`
+	Calls       []*DryRunCallResult  `json:"calls"`
+	Blocks      []*DryRunBlockResult `json:"blocks"`
+	Differences []*ReceiptDifference `json:"differences"`
+}
+
+// DryRunner executes blocks on the state modified by governance calls
+// without writing anything to the database.
+type DryRunner interface {
+	// DryRun forks the state before the transactions of the block at the
+	// height, applies the calls, and re-executes transactions of the blocks
+	// from the height. Receipts differing from the ones in the chain are
+	// reported.
+	DryRun(ctx context.Context, bm module.BlockManager, height int64, blocks int, calls []*GovernanceCall) (*DryRunResult, error)
+}
+
+func (m *manager) DryRun(
+	ctx context.Context, bm module.BlockManager, height int64, blocks int, calls []*GovernanceCall,
+) (*DryRunResult, error) {
+	if blocks < 1 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidBlocks(blocks=%d)", blocks)
+	}
+	blk, err := bm.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	itr, err := m.CreateInitialTransition(blk.Result(), blk.NextValidators())
+	if err != nil {
+		return nil, err
+	}
+	csi, err := bm.NewConsensusInfo(blk)
+	if err != nil {
+		return nil, err
+	}
+	wss, callResults, err := m.applyGovernanceCalls(itr.(*transition).worldSnapshot, blk, csi, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DryRunResult{
+		Height:      common.HexInt64{Value: height},
+		Calls:       callResults,
+		Blocks:      []*DryRunBlockResult{},
+		Differences: []*ReceiptDifference{},
+	}
+	parent := itr.(*transition).withWorldSnapshot(wss)
+	for i := 0; i < blocks; i++ {
+		nblk, err := bm.GetBlockByHeight(blk.Height() + 1)
+		if errors.NotFoundError.Equals(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			if csi, err = bm.NewConsensusInfo(blk); err != nil {
+				return nil, err
+			}
+		}
+		tr := newTransition(parent, nil, blk.NormalTransactions(), blk, csi, true)
+		if ptxs := nblk.PatchTransactions(); len(ptxs.Hash()) > 0 {
+			tr = patchTransition(tr, ptxs, nblk, true)
+		}
+		if err := executeTransition(ctx, tr); err != nil {
+			return nil, err
+		}
+
+		br := &DryRunBlockResult{
+			Height:        common.HexInt64{Value: blk.Height()},
+			ResultMatched: bytes.Equal(tr.Result(), nblk.Result()),
+		}
+		for _, g := range []module.TransactionGroup{module.TransactionGroupPatch, module.TransactionGroupNormal} {
+			var txs module.TransactionList
+			var rcts module.ReceiptList
+			if g == module.TransactionGroupPatch {
+				txs, rcts = tr.PatchTransactions(), tr.PatchReceipts()
+			} else {
+				txs, rcts = tr.NormalTransactions(), tr.NormalReceipts()
+			}
+			expected, err := m.ReceiptListFromResult(nblk.Result(), g)
+			if err != nil {
+				return nil, err
+			}
+			diffs, count, err := compareReceipts(nblk.Height()-1, g, txs, expected, rcts)
+			if err != nil {
+				return nil, err
+			}
+			br.Transactions.Value += int32(count)
+			res.Differences = append(res.Differences, diffs...)
+		}
+		res.Blocks = append(res.Blocks, br)
+		parent, blk = tr, nblk
+	}
+	return res, nil
+}
+
+type dryRunCallData struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+func (m *manager) applyGovernanceCalls(
+	wss state.WorldSnapshot, bi module.BlockInfo, csi module.ConsensusInfo, calls []*GovernanceCall,
+) (state.WorldSnapshot, []*DryRunCallResult, error) {
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, nil, err
+	}
+	wc := state.NewWorldContext(ws, bi, csi, m.plt)
+	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
+
+	results := make([]*DryRunCallResult, 0, len(calls))
+	for idx, call := range calls {
+		from := module.Address(call.From)
+		if call.From == nil {
+			from = wc.Governance()
+		}
+		to := module.Address(call.To)
+		if call.To == nil {
+			to = state.SystemAddress
+		}
+		value := new(big.Int)
+		if call.Value != nil {
+			value = call.Value.Value()
+		}
+		data, err := json.Marshal(&dryRunCallData{call.Method, call.Params})
+		if err != nil {
+			return nil, nil, scoreresult.InvalidParameterError.Wrapf(err, "InvalidGovernanceCall(idx=%d)", idx)
+		}
+
+		// calls with transaction ID are handled as invocations, not queries.
+		ctx.SetTransactionInfo(&state.TransactionInfo{
+			Group:     module.TransactionGroupNormal,
+			Index:     int32(idx),
+			Hash:      crypto.SHA3Sum256(append(intconv.Int64ToBytes(int64(idx)), data...)),
+			From:      from,
+			Timestamp: bi.Timestamp(),
+		})
+		ctx.UpdateSystemInfo()
+
+		limit := ctx.GetStepLimit(state.StepLimitTypeInvoke)
+		handler, err := ctx.ContractManager().GetHandler(from, to, value, contract.CTypeCall, data)
+		if err != nil {
+			return nil, nil, scoreresult.InvalidParameterError.Wrapf(err, "GovernanceCallFailed(idx=%d)", idx)
+		}
+		cc := contract.NewCallContext(ctx, limit, false)
+		status, used, _, _ := cc.Call(handler, limit)
+		cc.Dispose()
+		if status != nil {
+			return nil, nil, scoreresult.InvalidParameterError.Wrapf(status, "GovernanceCallFailed(idx=%d)", idx)
+		}
+		results = append(results, &DryRunCallResult{
+			Method:   call.Method,
+			StepUsed: common.HexInt{Int: *used},
+		})
+	}
+	return ws.GetSnapshot(), results, nil
+}
+
+func executeTransition(ctx context.Context, tr *transition) error {
+	cb := make(dryRunCallback, 2)
+	cancel, err := tr.Execute(cb)
+	if err != nil {
+		return err
+	}
+	// wait for OnValidate and OnExecute
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ctx.Done():
+			cancel()
+			return errors.TimeoutError.Wrap(ctx.Err(), "DryRunCanceled")
+		case err := <-cb:
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type dryRunCallback chan error
+
+func (cb dryRunCallback) OnValidate(tr module.Transition, err error) {
+	cb <- err
+}
+
+func (cb dryRunCallback) OnExecute(tr module.Transition, err error) {
+	cb <- err
+}
+
+// compareReceipts returns differences between expected and actual receipts
+// of the transactions with the number of the transactions.
+func compareReceipts(
+	height int64, g module.TransactionGroup, txs module.TransactionList, expected, actual module.ReceiptList,
+) ([]*ReceiptDifference, int, error) {
+	var diffs []*ReceiptDifference
+	count := 0
+	for itr := txs.Iterator(); itr.Has(); _ = itr.Next() {
+		tx, idx, err := itr.Get()
+		if err != nil {
+			return nil, 0, err
+		}
+		count += 1
+		er, err := expected.Get(idx)
+		if err != nil {
+			return nil, 0, err
+		}
+		ar, err := actual.Get(idx)
+		if err != nil {
+			return nil, 0, err
+		}
+		es, err := summarizeReceipt(er)
+		if err != nil {
+			return nil, 0, err
+		}
+		as, err := summarizeReceipt(ar)
+		if err != nil {
+			return nil, 0, err
+		}
+		if es.Equal(as) {
+			continue
+		}
+		diffs = append(diffs, &ReceiptDifference{
+			Height:   common.HexInt64{Value: height},
+			Group:    groupName(g),
+			TxIndex:  common.HexInt32{Value: int32(idx)},
+			TxHash:   tx.ID(),
+			Expected: es,
+			Actual:   as,
+		})
+	}
+	return diffs, count, nil
+}
+
+func groupName(g module.TransactionGroup) string {
+	if g == module.TransactionGroupPatch {
+		return "patch"
+	}
+	return "normal"
+}
+
+func summarizeReceipt(r module.Receipt) (*ReceiptSummary, error) {
+	s := &ReceiptSummary{
+		Status:    common.HexInt32{Value: int32(r.Status())},
+		EventLogs: []*EventLogSummary{},
+	}
+	s.StepUsed.Set(r.StepUsed())
+	for itr := r.EventLogIterator(); itr.Has(); _ = itr.Next() {
+		ev, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		el := &EventLogSummary{
+			Address: common.AddressToPtr(ev.Address()),
+		}
+		for _, v := range ev.Indexed() {
+			el.Indexed = append(el.Indexed, v)
+		}
+		for _, v := range ev.Data() {
+			el.Data = append(el.Data, v)
+		}
+		s.EventLogs = append(s.EventLogs, el)
+	}
+	return s, nil
+}
+
+func (s *ReceiptSummary) Equal(s2 *ReceiptSummary) bool {
+	if s.Status.Value != s2.Status.Value || s.StepUsed.Cmp(&s2.StepUsed.Int) != 0 {
+		return false
+	}
+	if len(s.EventLogs) != len(s2.EventLogs) {
+		return false
+	}
+	for i, el := range s.EventLogs {
+		el2 := s2.EventLogs[i]
+		if !el.Address.Equal(el2.Address) ||
+			!equalBytesList(el.Indexed, el2.Indexed) ||
+			!equalBytesList(el.Data, el2.Data) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalBytesList(a, b []common.HexBytes) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
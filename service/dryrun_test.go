@@ -0,0 +1,183 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/test"
+)
+
+func TestReceiptSummary_Equal(t *testing.T) {
+	newSummary := func(status int32, steps int64, data ...string) *service.ReceiptSummary {
+		s := &service.ReceiptSummary{Status: common.HexInt32{Value: status}}
+		s.StepUsed.SetInt64(steps)
+		for _, d := range data {
+			s.EventLogs = append(s.EventLogs, &service.EventLogSummary{
+				Address: common.MustNewAddressFromString("cx1"),
+				Indexed: []common.HexBytes{[]byte("Event(int)")},
+				Data:    []common.HexBytes{[]byte(d)},
+			})
+		}
+		return s
+	}
+	s1 := newSummary(1, 100, "a")
+	assert.True(t, s1.Equal(newSummary(1, 100, "a")))
+	assert.False(t, s1.Equal(newSummary(0, 100, "a")))
+	assert.False(t, s1.Equal(newSummary(1, 101, "a")))
+	assert.False(t, s1.Equal(newSummary(1, 100, "b")))
+	assert.False(t, s1.Equal(newSummary(1, 100)))
+	assert.False(t, s1.Equal(newSummary(1, 100, "a", "a")))
+}
+
+const dryRunTestGenesis = `{
+	"accounts": [
+		{ "name": "god", "address": "%s", "balance": "0x1000000" },
+		{ "name": "treasury", "address": "hx1000000000000000000000000000000000000000", "balance": "0x0" }
+	],
+	"chain": {
+		"validatorList": [ "%s" ],
+		"fee": {
+			"stepLimit": { "invoke": "0x100000", "query": "0x100000" },
+			"stepCosts": { "default": "0x100", "input": "0x10", "apiCall": "0x1000" }
+		}
+	},
+	"message": "dry run test",
+	"nid": "0x1"
+}`
+
+var dryRunTestReceiver = common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+
+func newDryRunTestNode(t *testing.T) *test.Node {
+	w := wallet.New()
+	return test.NewNode(t,
+		test.UseWallet(w),
+		test.UseGenesis(fmt.Sprintf(dryRunTestGenesis, w.Address(), w.Address())),
+		test.UseSMFactory(func(ctx *test.NodeContext) module.ServiceManager {
+			sm, err := service.NewManager(ctx.C, ctx.C.NetworkManager(), ctx.EM, ctx.Platform, path.Join(ctx.Base, "contract"))
+			assert.NoError(t, err)
+			return sm
+		}),
+	)
+}
+
+func newDryRunTestTx(t *testing.T, nd *test.Node, nonce int64, to module.Address, data map[string]interface{}) string {
+	tx := map[string]interface{}{
+		"version":   "0x3",
+		"from":      nd.Chain.Wallet().Address(),
+		"to":        to,
+		"stepLimit": "0x100000",
+		"timestamp": intconv.FormatInt(nd.GetLastBlock().Timestamp() + 1),
+		"nid":       "0x1",
+		"nonce":     intconv.FormatInt(nonce),
+	}
+	if data == nil {
+		tx["value"] = "0x10"
+	} else {
+		tx["dataType"] = "call"
+		tx["data"] = data
+	}
+	js, err := json.Marshal(tx)
+	assert.NoError(t, err)
+	bs, err := transaction.SerializeJSON(js, nil, nil)
+	assert.NoError(t, err)
+	sig, err := nd.Chain.Wallet().Sign(crypto.SHA3Sum256(append([]byte("icx_sendTransaction."), bs...)))
+	assert.NoError(t, err)
+	tx["signature"] = sig
+	js, err = json.Marshal(tx)
+	assert.NoError(t, err)
+	return string(js)
+}
+
+func TestManager_DryRun(t *testing.T) {
+	nd := newDryRunTestNode(t)
+	defer nd.Close()
+
+	// block 1 transfers, and block 2 calls unknown method of chain SCORE
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	nd.ProposeFinalizeBlockWithTX(nd.NewVoteListForLastBlock(),
+		newDryRunTestTx(t, nd, 1, dryRunTestReceiver, nil))
+	nd.ProposeFinalizeBlockWithTX(nd.NewVoteListForLastBlock(),
+		newDryRunTestTx(t, nd, 2, state.SystemAddress, map[string]interface{}{"method": "unknownMethod"}))
+	nd.ProposeFinalizeBlock(nd.NewVoteListForLastBlock())
+	last := nd.GetLastBlock()
+	assert.EqualValues(t, 4, last.Height())
+
+	sm := nd.SM
+	rl, err := sm.ReceiptListFromResult(last.Result(), module.TransactionGroupNormal)
+	assert.NoError(t, err)
+	rct, err := rl.Get(0)
+	assert.NoError(t, err)
+	assert.Equal(t, module.StatusMethodNotFound, rct.Status())
+	balance, err := sm.GetBalance(last.Result(), dryRunTestReceiver)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0x10, balance.Int64())
+
+	runner := sm.(service.DryRunner)
+	res, err := runner.DryRun(context.Background(), nd.BM, 2, 2, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Calls)
+	assert.Empty(t, res.Differences)
+	if assert.Len(t, res.Blocks, 2) {
+		for _, br := range res.Blocks {
+			assert.True(t, br.ResultMatched)
+			assert.EqualValues(t, 1, br.Transactions.Value)
+		}
+	}
+
+	// changing the step costs changes step usage of the transactions
+	res, err = runner.DryRun(context.Background(), nd.BM, 2, 2, []*service.GovernanceCall{{
+		Method: "setStepCost",
+		Params: json.RawMessage(`{"type":"default","cost":"0x200"}`),
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, res.Calls, 1)
+	if assert.Len(t, res.Differences, 2) {
+		for i, diff := range res.Differences {
+			assert.EqualValues(t, 2+i, diff.Height.Value)
+			assert.Equal(t, diff.Expected.Status, diff.Actual.Status)
+			assert.Equal(t, 1, diff.Actual.StepUsed.Cmp(&diff.Expected.StepUsed.Int))
+		}
+		assert.EqualValues(t, module.StatusMethodNotFound, res.Differences[1].Actual.Status.Value)
+	}
+
+	// the state is not changed by dry runs
+	assert.Equal(t, last.ID(), nd.GetLastBlock().ID())
+	balance, err = sm.GetBalance(last.Result(), dryRunTestReceiver)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0x10, balance.Int64())
+	res, err = runner.DryRun(context.Background(), nd.BM, 2, 2, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Differences)
+	nd.ProposeFinalizeBlock(nd.NewVoteListForLastBlock())
+	assert.EqualValues(t, 5, nd.GetLastBlock().Height())
+}
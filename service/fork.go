@@ -0,0 +1,90 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/chain/base"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// ForkResult makes the state of the result for the new chain, then
+// exports it to dst. The network ID and the chain ID in the state are
+// replaced, and the validators are used for the new validator list.
+// Receipts aren't included in the new result. It returns the new result
+// and the hash of the validator list.
+// If the extension of the platform manages the validators by itself, the
+// validators are also replaced in the extension. It fails if the extension
+// can't replace them since the validators would be restored by it.
+func ForkResult(
+	dbase db.Database, plt base.Platform, result []byte,
+	nid, cid int, validators []module.Validator, dst db.Database,
+) ([]byte, []byte, error) {
+	if len(validators) == 0 {
+		return nil, nil, errors.IllegalArgumentError.New("NoValidators")
+	}
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// changes are kept in the layer, so the source isn't modified.
+	ldb := db.NewLayerDB(dbase)
+	es := plt.NewExtensionSnapshot(ldb, r.ExtensionData)
+	ws := state.NewWorldState(ldb, r.StateHash, nil, es, r.BTPData)
+	if vm, ok := es.(state.ValidatorManager); ok && vm.ManagesValidators() {
+		vr, ok := ws.GetExtensionState().(state.ValidatorReplacer)
+		if !ok {
+			return nil, nil, errors.UnsupportedError.New("ValidatorsManagedByExtension")
+		}
+		if err := vr.ReplaceValidators(validators); err != nil {
+			return nil, nil, err
+		}
+	}
+	as := ws.GetAccountState(state.SystemID)
+	if err := scoredb.NewVarDB(as, state.VarNetwork).Set(nid); err != nil {
+		return nil, nil, err
+	}
+	if err := scoredb.NewVarDB(as, state.VarChainID).Set(cid); err != nil {
+		return nil, nil, err
+	}
+	if err := ws.GetValidatorState().Set(validators); err != nil {
+		return nil, nil, err
+	}
+	wss := ws.GetSnapshot()
+	if err := wss.Flush(); err != nil {
+		return nil, nil, err
+	}
+	vh := wss.GetValidatorSnapshot().Hash()
+
+	nr := &transitionResult{
+		StateHash:     wss.StateHash(),
+		ExtensionData: wss.ExtensionData(),
+		BTPData:       r.BTPData,
+	}
+	e := merkle.NewCopyContext(ldb, dst)
+	ess := plt.NewExtensionWithBuilder(e.Builder(), nr.ExtensionData)
+	state.NewWorldSnapshotWithBuilder(e.Builder(), nr.StateHash, vh, ess, nr.BTPData)
+	if err := e.Run(); err != nil {
+		return nil, nil, err
+	}
+	return nr.Bytes(), vh, nil
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type forkTestExtension struct {
+	managed bool
+}
+
+func (e *forkTestExtension) Bytes() []byte                               { return nil }
+func (e *forkTestExtension) Flush() error                                { return nil }
+func (e *forkTestExtension) NewState(readonly bool) state.ExtensionState { return nil }
+func (e *forkTestExtension) ManagesValidators() bool                     { return e.managed }
+
+type forkTestPlatform struct {
+	testPlatform
+	es state.ExtensionSnapshot
+}
+
+func (p *forkTestPlatform) NewExtensionSnapshot(dbase db.Database, raw []byte) state.ExtensionSnapshot {
+	return p.es
+}
+
+func (p *forkTestPlatform) NewExtensionWithBuilder(builder merkle.Builder, raw []byte) state.ExtensionSnapshot {
+	return p.es
+}
+
+func newForkTestValidators(t *testing.T, addrs ...string) []module.Validator {
+	var vs []module.Validator
+	for _, addr := range addrs {
+		v, err := state.ValidatorFromAddress(common.MustNewAddressFromString(addr))
+		assert.NoError(t, err)
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+func newForkTestResult(t *testing.T, dbase db.Database) []byte {
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	as := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(as, state.VarNetwork).Set(1))
+	assert.NoError(t, scoredb.NewVarDB(as, state.VarChainID).Set(1))
+	user := common.MustNewAddressFromString("hx01")
+	ws.GetAccountState(user.ID()).SetBalance(big.NewInt(100))
+	assert.NoError(t, ws.GetValidatorState().Set(newForkTestValidators(t, "hx10", "hx11")))
+
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	tr := &transitionResult{
+		StateHash: wss.StateHash(),
+	}
+	return tr.Bytes()
+}
+
+func TestForkResult(t *testing.T) {
+	src := db.NewMapDB()
+	result := newForkTestResult(t, src)
+	validators := newForkTestValidators(t, "hx20")
+
+	dst := db.NewMapDB()
+	plt := &forkTestPlatform{}
+	nr, vh, err := ForkResult(src, plt, result, 0x99, 0x98, validators, dst)
+	assert.NoError(t, err)
+
+	vss, err := state.ValidatorSnapshotFromHash(dst, vh)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, vss.Len())
+	v, _ := vss.Get(0)
+	assert.True(t, v.Address().Equal(validators[0].Address()))
+
+	wss, err := NewWorldSnapshot(dst, plt, nr, vss)
+	assert.NoError(t, err)
+	as := wss.GetAccountSnapshot(state.SystemID)
+	assert.EqualValues(t, 0x99, scoredb.NewVarDB(scoredb.NewStateStoreWith(as), state.VarNetwork).Int64())
+	assert.EqualValues(t, 0x98, scoredb.NewVarDB(scoredb.NewStateStoreWith(as), state.VarChainID).Int64())
+	user := common.MustNewAddressFromString("hx01")
+	assert.EqualValues(t, 100, wss.GetAccountSnapshot(user.ID()).GetBalance().Int64())
+
+	// source isn't modified
+	swss, err := NewWorldSnapshot(src, plt, result, nil)
+	assert.NoError(t, err)
+	as = swss.GetAccountSnapshot(state.SystemID)
+	assert.EqualValues(t, 1, scoredb.NewVarDB(scoredb.NewStateStoreWith(as), state.VarNetwork).Int64())
+}
+
+func TestForkResult_Invalid(t *testing.T) {
+	src := db.NewMapDB()
+	result := newForkTestResult(t, src)
+	validators := newForkTestValidators(t, "hx20")
+
+	_, _, err := ForkResult(src, &forkTestPlatform{}, result, 0x99, 0x99, nil, db.NewMapDB())
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	plt := &forkTestPlatform{es: &forkTestExtension{managed: true}}
+	_, _, err = ForkResult(src, plt, result, 0x99, 0x99, validators, db.NewMapDB())
+	assert.True(t, errors.UnsupportedError.Equals(err))
+
+	plt = &forkTestPlatform{es: &forkTestExtension{managed: false}}
+	_, _, err = ForkResult(src, plt, result, 0x99, 0x99, validators, db.NewMapDB())
+	assert.NoError(t, err)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/archive"
 )
 
 func Inspect(c module.Chain, informal bool) map[string]interface{} {
@@ -19,6 +20,9 @@ func Inspect(c module.Chain, informal bool) map[string]interface{} {
 	m["normalTxPool"] = inspectTxPool(mgr.tm.normalTxPool)
 	m["patchTxPool"] = inspectTxPool(mgr.tm.patchTxPool)
 	m["resultCache"] = inspectResultCache(mgr.trc)
+	if idx := archive.IndexOf(mgr.db); idx != nil {
+		m["archive"] = inspectArchive(idx)
+	}
 	return m
 }
 
@@ -36,3 +40,21 @@ func inspectTxPool(p *TransactionPool) map[string]interface{} {
 	m["used"] = p.Used()
 	return m
 }
+
+func inspectArchive(idx *archive.Index) map[string]interface{} {
+	m := make(map[string]interface{})
+	base, last, ok := idx.Range()
+	switch {
+	case !ok:
+		m["state"] = "empty"
+	case idx.Stalled():
+		m["state"] = "stalled"
+	default:
+		m["state"] = "appending"
+	}
+	if ok {
+		m["base"] = base
+		m["last"] = last
+	}
+	return m
+}
@@ -317,9 +317,12 @@ func (m *manager) Finalize(t module.Transition, opt int) error {
 		}
 		if opt&module.FinalizeResult == module.FinalizeResult {
 			keepParent := (opt & module.KeepingParent) != 0
+			parent := tst.parent
 			if err := tst.finalizeResult(false, keepParent); err != nil {
 				return err
 			}
+			m.archiveResult(tst, parent)
+			m.handleResultFinalization(tst)
 			m.tm.NotifyFinalized(tst.patchTransactions, tst.patchReceipts, tst.normalTransactions, tst.normalReceipts)
 			now := time.Now()
 			m.patchMetric.OnFinalize(tst.patchTransactions.Hash(), now)
@@ -331,6 +334,16 @@ func (m *manager) Finalize(t module.Transition, opt int) error {
 	return nil
 }
 
+// handleResultFinalization passes the extension of the transition finalized
+// to the platform if it handles finalized results.
+func (m *manager) handleResultFinalization(t *transition) {
+	h, ok := m.plt.(base.ResultFinalizationHandler)
+	if !ok || t.syncer != nil || t.worldSnapshot == nil {
+		return
+	}
+	h.OnResultFinalization(t.bi.Height(), t.worldSnapshot.GetExtensionSnapshot(), m.log)
+}
+
 // TransactionFromBytes returns a Transaction instance from bytes.
 func (m *manager) TransactionFromBytes(b []byte, blockVersion int) (module.Transaction, error) {
 	tx, err := transaction.NewTransaction(b)
@@ -688,6 +701,73 @@ func (m *manager) GetSCOREStatus(result []byte, addr module.Address) (module.SCO
 	}, nil
 }
 
+type accountProof struct {
+	addr module.Address
+	*state.AccountProof
+}
+
+func proofToJSON(proof [][]byte) []common.HexBytes {
+	jso := make([]common.HexBytes, len(proof))
+	for i, p := range proof {
+		jso[i] = p
+	}
+	return jso
+}
+
+func (p *accountProof) ToJSON(version module.JSONVersion) (interface{}, error) {
+	ret := make(map[string]interface{})
+	ret["address"] = p.addr
+	ret["stateHash"] = common.HexBytes(p.StateHash)
+	if ass := p.Account; ass != nil {
+		account := make(map[string]interface{})
+		account["balance"] = common.NewHexInt(0).SetValue(ass.GetBalance())
+		if p.StorageHash != nil {
+			account["storageHash"] = common.HexBytes(p.StorageHash)
+		}
+		if c := ass.Contract(); c != nil {
+			account["codeHash"] = common.HexBytes(c.CodeHash())
+		}
+		ret["account"] = account
+	}
+	if p.Proof != nil {
+		ret["accountProof"] = proofToJSON(p.Proof)
+	}
+	storage := make([]interface{}, len(p.Storage))
+	for i, sp := range p.Storage {
+		value := make(map[string]interface{})
+		value["key"] = common.HexBytes(sp.Key)
+		if sp.Value != nil {
+			value["value"] = common.HexBytes(sp.Value)
+		}
+		if sp.Proof != nil {
+			value["proof"] = proofToJSON(sp.Proof)
+		}
+		storage[i] = value
+	}
+	ret["storageProof"] = storage
+	return ret, nil
+}
+
+func (m *manager) GetAccountProof(result []byte, addr module.Address, keys [][]byte) (module.AccountProof, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, err
+	}
+	ap, err := state.GetAccountProof(wss, addr.ID(), keys)
+	if err != nil {
+		return nil, err
+	}
+	if ap.Account != nil && ap.Account.IsContract() != addr.IsContract() {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidAddressPrefix(valid=%s)",
+			common.NewAddressWithTypeAndID(!addr.IsContract(), addr.ID()))
+	}
+	return &accountProof{
+		addr:         addr,
+		AccountProof: ap,
+	}, nil
+}
+
 func (m *manager) GetMembers(result []byte) (module.MemberList, error) {
 	wss, err := m.trc.GetWorldSnapshot(result, nil)
 	if err != nil {
@@ -720,6 +800,14 @@ func (m *manager) GetMinimizeBlockGen(result []byte) bool {
 	return scoredb.NewVarDB(as, state.VarMinimizeBlockGen).Bool()
 }
 
+func (m *manager) GetConsensusTimeouts(result []byte) *module.ConsensusTimeouts {
+	as, err := m.getSystemByteStoreState(result)
+	if err != nil {
+		return nil
+	}
+	return state.GetConsensusTimeouts(as)
+}
+
 func (m *manager) GetNextBlockVersion(result []byte) int {
 	if result == nil {
 		return m.plt.DefaultBlockVersionFor(m.chain.CID())
@@ -857,6 +945,17 @@ func (m *manager) ExportResult(result []byte, vh []byte, d db.Database) error {
 	return e.Run()
 }
 
+func (m *manager) ExportState(result []byte, vh []byte, d db.Database) error {
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return err
+	}
+	e := merkle.PrepareCopyContext(m.db, d)
+	ess := m.plt.NewExtensionWithBuilder(e.Builder(), r.ExtensionData)
+	state.NewWorldSnapshotWithBuilder(e.Builder(), r.StateHash, vh, ess, r.BTPData)
+	return e.Run()
+}
+
 func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error {
 	r, err := newTransitionResultFromBytes(result)
 	if err != nil {
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/service/state"
+	ssync "github.com/icon-project/goloop/service/sync2"
+)
+
+type finalizationTestPlatform struct {
+	testPlatform
+	heights []int64
+	ess     []state.ExtensionSnapshot
+}
+
+func (p *finalizationTestPlatform) OnResultFinalization(height int64, ess state.ExtensionSnapshot, logger log.Logger) {
+	p.heights = append(p.heights, height)
+	p.ess = append(p.ess, ess)
+}
+
+type finalizationTestWS struct {
+	state.WorldSnapshot
+	ess state.ExtensionSnapshot
+}
+
+func (ws *finalizationTestWS) GetExtensionSnapshot() state.ExtensionSnapshot {
+	return ws.ess
+}
+
+type finalizationTestSyncer struct {
+	ssync.Syncer
+}
+
+func TestManager_HandleResultFinalization(t *testing.T) {
+	plt := new(finalizationTestPlatform)
+	m := &manager{plt: plt, log: log.New()}
+	ess := new(forkTestExtension)
+
+	m.handleResultFinalization(&transition{
+		bi:            common.NewBlockInfo(10, 0),
+		worldSnapshot: &finalizationTestWS{ess: ess},
+	})
+	assert.Equal(t, []int64{10}, plt.heights)
+	assert.Equal(t, []state.ExtensionSnapshot{ess}, plt.ess)
+
+	// results synchronized from the others are not passed
+	m.handleResultFinalization(&transition{
+		bi:            common.NewBlockInfo(11, 0),
+		worldSnapshot: &finalizationTestWS{ess: ess},
+		syncer:        new(finalizationTestSyncer),
+	})
+	assert.Equal(t, []int64{10}, plt.heights)
+
+	// platforms not handling finalized results are skipped
+	m.plt = new(testPlatform)
+	m.handleResultFinalization(&transition{
+		bi:            common.NewBlockInfo(12, 0),
+		worldSnapshot: &finalizationTestWS{ess: ess},
+	})
+	assert.Equal(t, []int64{10}, plt.heights)
+}
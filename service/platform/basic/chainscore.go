@@ -576,6 +576,28 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, Revision9, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setConsensusTimeouts",
+		scoreapi.FlagExternal, 0,
+		[]scoreapi.Parameter{
+			{"propose", scoreapi.Integer, nil, nil},
+			{"prevote", scoreapi.Integer, nil, nil},
+			{"precommit", scoreapi.Integer, nil, nil},
+			{"newRound", scoreapi.Integer, nil, nil},
+			{"backoff", scoreapi.String, nil, nil},
+			{"delta", scoreapi.Integer, nil, nil},
+			{"max", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, Revision9, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getConsensusTimeouts",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, Revision9, 0},
 }
 
 func (s *ChainScore) GetAPI() *scoreapi.Info {
@@ -612,6 +634,15 @@ type Chain struct {
 	DepositTerm        *common.HexInt64  `json:"depositTerm"`
 	DepositIssueRate   *common.HexInt64  `json:"depositIssueRate"`
 	FeeSharingEnabled  *common.HexInt16  `json:"feeSharingEnabled"`
+	ConsensusTimeouts  *struct {
+		Propose   common.HexInt64 `json:"propose"`
+		Prevote   common.HexInt64 `json:"prevote"`
+		Precommit common.HexInt64 `json:"precommit"`
+		NewRound  common.HexInt64 `json:"newRound"`
+		Backoff   string          `json:"backoff"`
+		Delta     common.HexInt64 `json:"delta"`
+		Max       common.HexInt64 `json:"max"`
+	} `json:"consensusTimeouts"`
 }
 
 func (s *ChainScore) Install(param []byte) error {
@@ -692,6 +723,24 @@ func (s *ChainScore) Install(param []byte) error {
 		}
 	}
 
+	if ct := chain.ConsensusTimeouts; ct != nil {
+		timeouts := &module.ConsensusTimeouts{
+			Propose:   ct.Propose.Value,
+			Prevote:   ct.Prevote.Value,
+			Precommit: ct.Precommit.Value,
+			NewRound:  ct.NewRound.Value,
+			Backoff:   ct.Backoff,
+			Delta:     ct.Delta.Value,
+			Max:       ct.Max.Value,
+		}
+		if err := timeouts.Verify(); err != nil {
+			return scoreresult.IllegalFormatError.Wrap(err, "InvalidConsensusTimeouts")
+		}
+		if err := state.SetConsensusTimeouts(as, timeouts); err != nil {
+			return err
+		}
+	}
+
 	if chain.DepositTerm != nil {
 		if chain.DepositTerm.Value < 0 {
 			return scoreresult.IllegalFormatError.Errorf("InvalidDepositTerm(%s)", chain.DepositTerm)
@@ -1445,6 +1494,53 @@ func (s *ChainScore) Ex_setMinimizeBlockGen(b bool) error {
 	return mbg.Set(b)
 }
 
+func (s *ChainScore) Ex_getConsensusTimeouts() (map[string]interface{}, error) {
+	if err := s.tryChargeCall(); err != nil {
+		return nil, err
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	t := state.GetConsensusTimeouts(as)
+	if t == nil {
+		return map[string]interface{}{}, nil
+	}
+	return map[string]interface{}{
+		"propose":   t.Propose,
+		"prevote":   t.Prevote,
+		"precommit": t.Precommit,
+		"newRound":  t.NewRound,
+		"backoff":   t.Backoff,
+		"delta":     t.Delta,
+		"max":       t.Max,
+	}, nil
+}
+
+// Ex_setConsensusTimeouts sets timeouts of the consensus in milliseconds.
+// Zero values fall back to the defaults, and calling it without parameters
+// clears the governed timeouts.
+func (s *ChainScore) Ex_setConsensusTimeouts(
+	propose, prevote, precommit, newRound int64, backoff string, delta, max int64,
+) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	t := &module.ConsensusTimeouts{
+		Propose:   propose,
+		Prevote:   prevote,
+		Precommit: precommit,
+		NewRound:  newRound,
+		Backoff:   backoff,
+		Delta:     delta,
+		Max:       max,
+	}
+	if *t == (module.ConsensusTimeouts{}) {
+		t = nil
+	} else if err := t.Verify(); err != nil {
+		return scoreresult.InvalidParameterError.Wrap(err, "InvalidConsensusTimeouts")
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	return state.SetConsensusTimeouts(as, t)
+}
+
 func (s *ChainScore) Ex_setUseSystemDeposit(address module.Address, yn bool) error {
 	if err := s.checkGovernance(true); err != nil {
 		return err
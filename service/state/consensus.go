@@ -0,0 +1,56 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+)
+
+// GetConsensusTimeouts returns consensus timeouts stored in the system
+// store. It returns nil if it's not set or it's broken.
+func GetConsensusTimeouts(store containerdb.BytesStoreState) *module.ConsensusTimeouts {
+	bs := scoredb.NewVarDB(store, VarConsensusTimeouts).Bytes()
+	if len(bs) == 0 {
+		return nil
+	}
+	t := new(module.ConsensusTimeouts)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, t); err != nil {
+		return nil
+	}
+	return t
+}
+
+// SetConsensusTimeouts stores consensus timeouts in the system store.
+// nil value clears it.
+func SetConsensusTimeouts(store containerdb.BytesStoreState, t *module.ConsensusTimeouts) error {
+	vdb := scoredb.NewVarDB(store, VarConsensusTimeouts)
+	if t == nil {
+		_, err := vdb.Delete()
+		return err
+	}
+	if err := t.Verify(); err != nil {
+		return err
+	}
+	bs, err := codec.BC.MarshalToBytes(t)
+	if err != nil {
+		return err
+	}
+	return vdb.Set(bs)
+}
@@ -16,12 +16,28 @@
 
 package state
 
+import "github.com/icon-project/goloop/module"
+
 type ExtensionSnapshot interface {
 	Bytes() []byte
 	Flush() error
 	NewState(readonly bool) ExtensionState
 }
 
+// ValidatorManager is implemented by the extension snapshot which may manage
+// the validators by itself.
+type ValidatorManager interface {
+	// ManagesValidators returns true if the extension replaces the validators
+	// of the world state with its own.
+	ManagesValidators() bool
+}
+
+// ValidatorReplacer is implemented by the extension state managing the
+// validators which can replace them with new ones for forking the chain.
+type ValidatorReplacer interface {
+	ReplaceValidators(validators []module.Validator) error
+}
+
 type ExtensionState interface {
 	GetSnapshot() ExtensionSnapshot
 	Reset(snapshot ExtensionSnapshot)
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+// StorageProof is the proof of the value for the key in the storage of
+// the account. Value is nil if there is no value for the key, then Proof
+// is the proof for the absence of the key (nil if the storage is empty).
+type StorageProof struct {
+	Key   []byte
+	Value []byte
+	Proof [][]byte
+}
+
+// AccountProof is the proof of the account in the world state with the
+// proofs of the values in the storage of the account. Account is nil if
+// there is no account, then Proof is the proof for the absence of the
+// account (nil if the world state is empty).
+type AccountProof struct {
+	StateHash   []byte
+	Account     AccountSnapshot
+	Proof       [][]byte
+	StorageHash []byte
+	Storage     []StorageProof
+}
+
+// StorageHashOf returns the hash of the storage of the account.
+// It returns nil if the account has no storage.
+func StorageHashOf(ass AccountSnapshot) []byte {
+	if s, ok := ass.(*accountSnapshotImpl); ok {
+		if store := s.Store(); store != nil {
+			return store.Hash()
+		}
+	}
+	return nil
+}
+
+// GetAccountProof returns the proof of the account with the id in the world
+// snapshot and the proofs of the values for the keys in its storage.
+// Keys are the keys of the storage as built by containerdb.KeyBuilder.
+func GetAccountProof(wss WorldSnapshot, id []byte, keys [][]byte) (*AccountProof, error) {
+	ws, ok := wss.(*worldSnapshotImpl)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("UnsupportedSnapshot(type=%T)", wss)
+	}
+	ap := &AccountProof{
+		StateHash: ws.StateHash(),
+		Storage:   make([]StorageProof, len(keys)),
+	}
+	for i, k := range keys {
+		ap.Storage[i].Key = k
+	}
+	ass := ws.GetAccountSnapshot(id)
+	if ass == nil {
+		ap.Proof = ws.accounts.GetAbsenceProof(addressIDToKey(id))
+		if ap.Proof == nil && ws.accounts.Hash() != nil {
+			return nil, errors.InvalidStateError.Errorf("NoAbsenceProofForAccount(id=%x)", id)
+		}
+		return ap, nil
+	}
+	ap.Account = ass
+	ap.Proof = ws.accounts.GetProof(addressIDToKey(id))
+	if ap.Proof == nil {
+		return nil, errors.InvalidStateError.Errorf("NoProofForAccount(id=%x)", id)
+	}
+	store := ass.(*accountSnapshotImpl).Store()
+	if store == nil {
+		return ap, nil
+	}
+	ap.StorageHash = store.Hash()
+	for i, k := range keys {
+		value, err := store.Get(k)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			ap.Storage[i].Proof = store.GetAbsenceProof(k)
+		} else {
+			ap.Storage[i].Value = value
+			ap.Storage[i].Proof = store.GetProof(k)
+		}
+		if ap.Storage[i].Proof == nil {
+			return nil, errors.InvalidStateError.Errorf("NoProofForStorage(key=%x)", k)
+		}
+	}
+	return ap, nil
+}
+
+// ProveAccount returns the account with the id in the world state with
+// the hash after verifying it with the proof from GetAccountProof.
+// It returns errors.NotFoundError if the proof proves the absence of the
+// account. It doesn't require any database, so it can be used by the clients.
+func ProveAccount(stateHash []byte, id []byte, proof [][]byte) (AccountSnapshot, error) {
+	if len(stateHash) == 0 {
+		return nil, errors.NotFoundError.Errorf("EmptyWorldState(id=%x)", id)
+	}
+	accounts := trie_manager.NewImmutableForObject(db.NewMapDB(), stateHash, AccountType)
+	obj, err := accounts.Prove(addressIDToKey(id), proof)
+	if errors.NotFoundError.Equals(err) {
+		return nil, errors.NotFoundError.Wrapf(err, "AccountNotFound(id=%x)", id)
+	} else if err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err, "InvalidAccountProof(id=%x)", id)
+	}
+	if ass, ok := obj.(*accountSnapshotImpl); !ok || ass == nil {
+		return nil, errors.NotFoundError.Errorf("AccountNotFound(id=%x)", id)
+	} else {
+		return ass, nil
+	}
+}
+
+// ProveStorage returns the value for the key in the storage with the hash
+// after verifying it with the proof from GetAccountProof.
+// It returns errors.NotFoundError if the proof proves the absence of the
+// value. It doesn't require any database, so it can be used by the clients.
+func ProveStorage(storageHash []byte, key []byte, proof [][]byte) ([]byte, error) {
+	if len(storageHash) == 0 {
+		return nil, errors.NotFoundError.Errorf("EmptyStorage(key=%x)", key)
+	}
+	store := trie_manager.NewImmutable(db.NewMapDB(), storageHash)
+	value, err := store.Prove(key, proof)
+	if errors.NotFoundError.Equals(err) {
+		return nil, errors.NotFoundError.Wrapf(err, "ValueNotFound(key=%x)", key)
+	} else if err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err, "InvalidStorageProof(key=%x)", key)
+	}
+	if value == nil {
+		return nil, errors.NotFoundError.Errorf("ValueNotFound(key=%x)", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestAccountProof(t *testing.T) {
+	database := db.NewMapDB()
+	ws := NewWorldState(database, nil, nil, nil, nil)
+	for i := 0; i < 16; i++ {
+		ws.GetAccountState([]byte(fmt.Sprintf("account%d", i))).SetBalance(big.NewInt(int64(i + 1)))
+	}
+	id := []byte("account3")
+	as := ws.GetAccountState(id)
+	keys := [][]byte{
+		containerdb.ToKey(containerdb.HashBuilder, "var").Build(),
+		containerdb.ToKey(containerdb.HashBuilder, "dict", "key").Build(),
+		containerdb.ToKey(containerdb.HashBuilder, "none").Build(),
+	}
+	_, err := as.SetValue(keys[0], []byte("value0"))
+	assert.NoError(t, err)
+	_, err = as.SetValue(keys[1], []byte("value1"))
+	assert.NoError(t, err)
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+
+	ap, err := GetAccountProof(wss, id, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, wss.StateHash(), ap.StateHash)
+	assert.NotNil(t, ap.Proof)
+	assert.Equal(t, StorageHashOf(ap.Account), ap.StorageHash)
+
+	ass, err := ProveAccount(ap.StateHash, id, ap.Proof)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ass.GetBalance().Cmp(big.NewInt(4)))
+	assert.Equal(t, ap.StorageHash, StorageHashOf(ass))
+
+	for i, exp := range []string{"value0", "value1"} {
+		sp := ap.Storage[i]
+		value, err := ProveStorage(ap.StorageHash, sp.Key, sp.Proof)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(exp), value)
+	}
+	assert.Nil(t, ap.Storage[2].Value)
+	assert.NotNil(t, ap.Storage[2].Proof)
+	_, err = ProveStorage(ap.StorageHash, keys[2], ap.Storage[2].Proof)
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	// proof for the absence can't hide the value
+	_, err = ProveStorage(ap.StorageHash, keys[0], ap.Storage[2].Proof)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	_, err = ProveStorage(ap.StorageHash, keys[0], nil)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	// proof for other account and key
+	_, err = ProveAccount(ap.StateHash, []byte("account4"), ap.Proof)
+	assert.Error(t, err)
+	_, err = ProveStorage(ap.StorageHash, keys[0], ap.Storage[1].Proof)
+	assert.Error(t, err)
+
+	// tampered proof
+	proof := make([][]byte, len(ap.Proof))
+	copy(proof, ap.Proof)
+	last := append([]byte{}, proof[len(proof)-1]...)
+	last[len(last)-1] ^= 0xff
+	proof[len(proof)-1] = last
+	_, err = ProveAccount(ap.StateHash, id, proof)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	// no account
+	ap, err = GetAccountProof(wss, []byte("unknown"), keys)
+	assert.NoError(t, err)
+	assert.Nil(t, ap.Account)
+	assert.NotNil(t, ap.Proof)
+	assert.Len(t, ap.Storage, len(keys))
+	_, err = ProveAccount(ap.StateHash, []byte("unknown"), ap.Proof)
+	assert.True(t, errors.NotFoundError.Equals(err))
+	_, err = ProveAccount(ap.StateHash, id, ap.Proof)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+}
@@ -34,6 +34,7 @@ const (
 	VarCommitTimeout      = "commit_timeout"
 	VarRoundLimitFactor   = "round_limit_factor"
 	VarMinimizeBlockGen   = "minimize_block_gen"
+	VarConsensusTimeouts  = "consensus_timeouts"
 	VarTxHashToAddress    = "tx_to_address"
 	VarDepositTerm        = "deposit_term"
 	VarDepositIssueRate   = "deposit_issue_rate"
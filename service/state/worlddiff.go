@@ -0,0 +1,90 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+// WorldDifferenceHandler receives the differences of the accounts and
+// their storages. Accounts are identified by their keys in the world
+// (refer AccountKeyOf). Removed account and value are passed as nil.
+type WorldDifferenceHandler interface {
+	OnAccount(key []byte, ass AccountSnapshot) error
+	OnValue(key []byte, k, v []byte) error
+}
+
+// AccountKeyOf returns the key of the account in the world.
+func AccountKeyOf(id []byte) []byte {
+	return addressIDToKey(id)
+}
+
+// AccountSnapshotFromBytes decodes the account snapshot.
+func AccountSnapshotFromBytes(dbase db.Database, bs []byte) (AccountSnapshot, error) {
+	ass := newAccountSnapshot(dbase)
+	if err := ass.Reset(dbase, bs); err != nil {
+		return nil, errors.CriticalFormatError.Wrap(err, "InvalidAccountSnapshot")
+	}
+	return ass, nil
+}
+
+func accountsOf(wss WorldSnapshot) (trie.ImmutableForObject, error) {
+	switch ws := wss.(type) {
+	case nil:
+		return nil, nil
+	case *worldSnapshotImpl:
+		return ws.accounts, nil
+	default:
+		return nil, errors.UnsupportedError.Errorf("UnknownWorldSnapshot(type=%T)", wss)
+	}
+}
+
+func storeOf(obj trie.Object) trie.Immutable {
+	if ass, ok := obj.(*accountSnapshotImpl); ok && ass != nil {
+		return ass.Store()
+	}
+	return nil
+}
+
+// DiffWorldSnapshot calls the handler for the accounts and the values
+// changed from ws1 to ws2. Nil ws1 is handled as empty, so all accounts
+// and values of ws2 are passed. Only the sub-tries changed are visited.
+func DiffWorldSnapshot(ws1, ws2 WorldSnapshot, h WorldDifferenceHandler) error {
+	a1, err := accountsOf(ws1)
+	if err != nil {
+		return err
+	}
+	a2, err := accountsOf(ws2)
+	if err != nil {
+		return err
+	}
+	return trie_manager.DiffImmutableForObject(a1, a2, func(op int, key []byte, v1, v2 trie.Object) error {
+		var ass AccountSnapshot
+		if v2 != nil {
+			ass = v2.(*accountSnapshotImpl)
+		}
+		if err := h.OnAccount(key, ass); err != nil {
+			return err
+		}
+		return trie_manager.DiffImmutable(storeOf(v1), storeOf(v2), func(op int, k, e, r []byte) error {
+			return h.OnValue(key, k, r)
+		})
+	})
+}
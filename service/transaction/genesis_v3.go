@@ -33,6 +33,7 @@ type accountInfo struct {
 	Address common.Address      `json:"address"`
 	Balance *common.HexInt      `json:"balance"`
 	Score   *preInstalledScores `json:"score"`
+	Storage map[string]string   `json:"storage"`
 }
 
 type genesisV3JSON struct {
@@ -286,6 +287,10 @@ func (g *genesisV3) Execute(ctx contract.Context, wcs state.WorldSnapshot, estim
 		ctx.Logger().Warnf("Fail to install scores err=%+v\n", err)
 		return nil, err
 	}
+	if err := g.initStorages(cc); err != nil {
+		ctx.Logger().Warnf("Fail to initialize storages err=%+v\n", err)
+		return nil, err
+	}
 	cc.GetEventLogs(r)
 	r.SetResult(module.StatusSuccess, big.NewInt(0), big.NewInt(0), nil)
 	return r, nil
@@ -343,6 +348,39 @@ func (g *genesisV3) installContracts(cc contract.CallContext) error {
 	return nil
 }
 
+// initStorages sets the initial values of the storages of the accounts.
+// It's applied after installation of the contracts, so it overwrites
+// the values set by the contracts on installation.
+func (g *genesisV3) initStorages(cc contract.CallContext) error {
+	for _, acc := range g.Accounts {
+		if len(acc.Storage) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(acc.Storage))
+		for k := range acc.Storage {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		as := cc.GetAccountState(acc.Address.ID())
+		for _, k := range keys {
+			key, err := hex.DecodeString(strings.TrimPrefix(k, "0x"))
+			if err != nil || len(key) == 0 {
+				return InvalidGenesisError.Errorf(
+					"InvalidStorageKey(addr=%s,key=%q)", &acc.Address, k)
+			}
+			value, err := hex.DecodeString(strings.TrimPrefix(acc.Storage[k], "0x"))
+			if err != nil {
+				return InvalidGenesisError.Errorf(
+					"InvalidStorageValue(addr=%s,key=%q)", &acc.Address, k)
+			}
+			if _, err := as.SetValue(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (g *genesisV3) Dispose() {
 }
 
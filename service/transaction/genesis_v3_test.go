@@ -2,12 +2,21 @@ package transaction
 
 import (
 	"encoding/json"
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/state"
 )
 
 func Test_serialize(t *testing.T) {
@@ -84,3 +93,141 @@ func TestGenesisV3_ICONMainNet(t *testing.T) {
 	assert.Equal(t, ICONMainNetCID, gtx.CID())
 	assert.Equal(t, ICONMainNetCID, gtx.NID())
 }
+
+type genesisTestPlatform struct{}
+
+func (p genesisTestPlatform) ToRevision(value int) module.Revision {
+	return module.LatestRevision
+}
+
+// genesisTestScore is the chain SCORE for the test, which writes the
+// values on installation.
+type genesisTestScore struct {
+	cc     contract.CallContext
+	values map[string]string
+}
+
+func (s *genesisTestScore) Install(param []byte) error {
+	as := s.cc.GetAccountState(state.SystemID)
+	for k, v := range s.values {
+		if _, err := as.SetValue([]byte(k), []byte(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *genesisTestScore) Update(param []byte) error {
+	return nil
+}
+
+func (s *genesisTestScore) GetAPI() *scoreapi.Info {
+	return scoreapi.NewInfo(nil)
+}
+
+type genesisTestCM struct {
+	contract.ContractManager
+	score *genesisTestScore
+}
+
+func (cm *genesisTestCM) GetSystemScore(contentID string, cc contract.CallContext, from module.Address, value *big.Int) (contract.SystemScore, error) {
+	cm.score.cc = cc
+	return cm.score, nil
+}
+
+func executeGenesisForTest(t *testing.T, js string, values map[string]string) (state.WorldContext, error) {
+	dbase := db.NewMapDB()
+	cm, err := contract.NewContractManager(dbase, t.TempDir(), log.New())
+	assert.NoError(t, err)
+	cm = &genesisTestCM{
+		ContractManager: cm,
+		score:           &genesisTestScore{values: values},
+	}
+
+	tx, err := parseV3Genesis([]byte(js), false)
+	assert.NoError(t, err)
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	wc := state.NewWorldContext(ws, common.NewBlockInfo(0, 0), nil, genesisTestPlatform{})
+	ctx := contract.NewContext(wc, cm, nil, nil, log.New(), nil, eeproxy.ForTransaction)
+	ctx.SetTransactionInfo(&state.TransactionInfo{
+		Group:     module.TransactionGroupNormal,
+		Timestamp: tx.Timestamp(),
+		Hash:      tx.ID(),
+		From:      tx.From(),
+	})
+	rct, err := tx.(*genesisV3).Execute(ctx, ctx.GetSnapshot(), false)
+	if err != nil {
+		return nil, err
+	}
+	assert.Equal(t, module.StatusSuccess, rct.Status())
+	return ctx, nil
+}
+
+func TestGenesisV3_ExecuteWithStorage(t *testing.T) {
+	js := `{
+		"accounts": [
+			{
+				"name": "god",
+				"address": "hx736846756bcdea54366decfdbdae354789815103",
+				"balance": "0x1234",
+				"storage": { "0x6b6579": "0x01" }
+			},
+			{
+				"name": "system",
+				"address": "cx0000000000000000000000000000000000000000",
+				"storage": {
+					"0x696e7374616c6c6564": "0x0102",
+					"0x6e6577": "0x03"
+				}
+			}
+		],
+		"message": "storage test"
+	}`
+	wc, err := executeGenesisForTest(t, js, map[string]string{
+		"installed": "by install",
+		"kept":      "by install",
+	})
+	assert.NoError(t, err)
+
+	god := common.MustNewAddressFromString("hx736846756bcdea54366decfdbdae354789815103")
+	as := wc.GetAccountState(god.ID())
+	assert.Equal(t, 0, as.GetBalance().Cmp(big.NewInt(0x1234)))
+	v, err := as.GetValue([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01}, v)
+
+	// values of the genesis overwrite the ones set on installation
+	as = wc.GetAccountState(state.SystemID)
+	v, err = as.GetValue([]byte("installed"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02}, v)
+	v, err = as.GetValue([]byte("kept"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("by install"), v)
+	v, err = as.GetValue([]byte("new"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x03}, v)
+}
+
+func TestGenesisV3_ExecuteWithInvalidStorage(t *testing.T) {
+	for name, storage := range map[string]string{
+		"EmptyKey":     `{ "0x": "0x01" }`,
+		"InvalidKey":   `{ "0xzz": "0x01" }`,
+		"InvalidValue": `{ "0x01": "0xzz" }`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			js := `{
+				"accounts": [
+					{
+						"name": "god",
+						"address": "hx736846756bcdea54366decfdbdae354789815103",
+						"balance": "0x1234",
+						"storage": ` + storage + `
+					}
+				]
+			}`
+			_, err := executeGenesisForTest(t, js, nil)
+			assert.True(t, InvalidGenesisError.Equals(err), err)
+		})
+	}
+}
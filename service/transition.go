@@ -285,6 +285,27 @@ func newInitTransition(dbase db.Database,
 	return tr, nil
 }
 
+// withWorldSnapshot returns a completed transition sharing everything with
+// the transition except the world snapshot. It's used for executing
+// following transitions on the modified state.
+func (t *transition) withWorldSnapshot(wss state.WorldSnapshot) *transition {
+	return &transition{
+		id:                 new(transitionID),
+		bi:                 t.bi,
+		patchTransactions:  t.patchTransactions,
+		normalTransactions: t.normalTransactions,
+		patchReceipts:      t.patchReceipts,
+		normalReceipts:     t.normalReceipts,
+		transitionContext:  t.transitionContext,
+		step:               stepComplete,
+		result:             t.result,
+		worldSnapshot:      wss,
+		ptxIDs:             t.ptxIDs,
+		ntxIDs:             t.ntxIDs,
+		dsrTracker:         t.dsrTracker,
+	}
+}
+
 func (t *transition) PatchTransactions() module.TransactionList {
 	return t.patchTransactions
 }
@@ -0,0 +1,59 @@
+// Code generated by codecgen; DO NOT EDIT.
+
+package txresult
+
+import (
+	"io"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+)
+
+func (o *eventLog) RLPEncodeSelf(e codec.Encoder) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.eventLogData.Addr); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.eventLogData.Indexed); err != nil {
+		return err
+	}
+	if err := e2.Encode(&o.eventLogData.Data); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *eventLog) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := codec.DecodeStruct(d)
+	if err != nil {
+		return err
+	}
+	if err := codec.DecodeField(d2, &o.eventLogData.Addr); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.eventLogData.Addr = common.Address{}
+	}
+	if err := codec.DecodeField(d2, &o.eventLogData.Indexed); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.eventLogData.Indexed = nil
+	}
+	if err := codec.DecodeField(d2, &o.eventLogData.Data); err != nil {
+		if err != io.EOF {
+			return err
+		}
+		o.eventLogData.Data = nil
+	}
+	return nil
+}
+
+func init() {
+	codec.RegisterGenerated(
+		(*eventLog)(nil),
+	)
+}
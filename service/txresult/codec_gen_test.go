@@ -0,0 +1,23 @@
+package txresult
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/icon-project/goloop/common/codec/codectest"
+)
+
+func TestCodecGen(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		obj := new(eventLog)
+		codectest.Fill(r, obj)
+		if !codectest.AssertEquivalent(t, obj) {
+			break
+		}
+	}
+}
+
+func FuzzCodecGen(f *testing.F) {
+	codectest.Fuzz(f, func() interface{} { return new(eventLog) })
+}
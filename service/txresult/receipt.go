@@ -29,6 +29,8 @@ const (
 
 var ReceiptType = reflect.TypeOf((*receipt)(nil))
 
+//go:generate go run github.com/icon-project/goloop/cmd/codecgen
+
 type eventLogJSON struct {
 	Addr    common.Address `json:"scoreAddress"`
 	Indexed []interface{}  `json:"indexed"`
@@ -41,6 +43,7 @@ type eventLogData struct {
 	Data    [][]byte
 }
 
+//codec:generate
 type eventLog struct {
 	eventLogData
 }
@@ -411,6 +414,25 @@ func (r *receipt) GetProofOfEvent(i int) ([][]byte, error) {
 	return proof, nil
 }
 
+func (r *receipt) ProveEvent(i int, proof [][]byte) (module.EventLog, error) {
+	if r.version < Version2 {
+		if i < 0 || i >= len(r.data.EventLogs) {
+			return nil, errors.NotFoundError.Errorf("EventNotFound(idx=%d)", i)
+		}
+		return r.data.EventLogs[i], nil
+	}
+	k := codec.BC.MustMarshalToBytes(uint(i))
+	obj, err := r.eventLogs.Prove(k, proof)
+	if err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err, "InvalidEventProof(idx=%d)", i)
+	}
+	if ev, ok := obj.(*eventLog); !ok || ev == nil {
+		return nil, errors.NotFoundError.Errorf("EventNotFound(idx=%d)", i)
+	} else {
+		return ev, nil
+	}
+}
+
 // AddPayment add payment information
 // addr is payer. steps is total steps paid by the payer.
 // feeSteps is amount of steps for fee.
@@ -521,6 +543,10 @@ type Receipt interface {
 	SetResult(status module.Status, used, price *big.Int, addr module.Address)
 	SetReason(e error)
 	Reason() error
+	// ProveEvent returns the event log at index i after verifying it with
+	// the proof from GetProofOfEvent. Receipts before UseMPTOnEvents keep
+	// event logs in themselves, so the proof is ignored for them.
+	ProveEvent(i int, proof [][]byte) (module.EventLog, error)
 	Flush() error
 }
 
@@ -98,3 +98,26 @@ func NewReceiptListWithBuilder(builder merkle.Builder, h []byte) module.ReceiptL
 	snapshot.Resolve(builder)
 	return &receiptList{snapshot}
 }
+
+// ProveReceipt returns the receipt at index n of the receipt list with the
+// hash h after verifying it with the proof from ReceiptList.GetProof.
+// It doesn't require any database, so it can be used by the clients.
+func ProveReceipt(h []byte, n int, proof [][]byte) (Receipt, error) {
+	if len(h) == 0 {
+		return nil, errors.NotFoundError.Errorf("EmptyReceiptList(idx=%d)", n)
+	}
+	b, err := codec.BC.MarshalToBytes(uint(n))
+	if err != nil {
+		return nil, err
+	}
+	immutable := trie_manager.NewImmutableForObject(db.NewMapDB(), h, ReceiptType)
+	obj, err := immutable.Prove(b, proof)
+	if err != nil {
+		return nil, errors.InvalidStateError.Wrapf(err, "InvalidReceiptProof(idx=%d)", n)
+	}
+	if rct, ok := obj.(*receipt); !ok || rct == nil {
+		return nil, errors.NotFoundError.Errorf("ReceiptNotFound(idx=%d)", n)
+	} else {
+		return rct, nil
+	}
+}
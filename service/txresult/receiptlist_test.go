@@ -7,8 +7,11 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 )
 
@@ -65,3 +68,61 @@ func testReceiptListByRev(t *testing.T, rev module.Revision) {
 		idx++
 	}
 }
+
+func TestProveReceipt(t *testing.T) {
+	for _, rev := range receiptRevisions {
+		t.Run(fmt.Sprintf("Revision:%#x", rev), func(t *testing.T) {
+			testProveReceiptByRev(t, rev)
+		})
+	}
+}
+
+func testProveReceiptByRev(t *testing.T, rev module.Revision) {
+	mdb := db.NewMapDB()
+	rslice := make([]Receipt, 0)
+
+	addr := common.MustNewAddressFromString("cx0003737589788888888888888888888888888888")
+	for i := 0; i < 5; i++ {
+		r := NewReceipt(mdb, rev, addr)
+		for j := 0; j < i; j++ {
+			r.AddLog(addr, [][]byte{[]byte("Event(int)")}, [][]byte{{byte(j)}})
+		}
+		r.SetResult(module.StatusSuccess, big.NewInt(int64(i*100)), big.NewInt(10), nil)
+		assert.NoError(t, r.Flush())
+		rslice = append(rslice, r)
+	}
+	rl := NewReceiptListFromSlice(mdb, rslice)
+	assert.NoError(t, rl.Flush())
+	hash := rl.Hash()
+
+	for idx, r := range rslice {
+		proof, err := rl.GetProof(idx)
+		assert.NoError(t, err)
+
+		r2, err := ProveReceipt(hash, idx, proof)
+		assert.NoError(t, err)
+		assert.Equal(t, r.Bytes(), r2.Bytes())
+
+		for j := 0; j < idx; j++ {
+			var evProof [][]byte
+			if rev >= module.UseMPTOnEvents {
+				evProof, err = r.GetProofOfEvent(j)
+				assert.NoError(t, err)
+			}
+			ev, err := r2.ProveEvent(j, evProof)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte{byte(j)}, ev.Data()[0])
+			assert.True(t, addr.Equal(ev.Address()))
+		}
+		_, err = r2.ProveEvent(idx, nil)
+		assert.Error(t, err)
+
+		// proof for the other receipt
+		if idx > 0 {
+			_, err = ProveReceipt(hash, idx-1, proof)
+			assert.Error(t, err)
+		}
+	}
+	_, err := ProveReceipt(nil, 0, nil)
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
 )
 
 type Chain struct {
@@ -47,6 +48,7 @@ type Chain struct {
 	gs        module.GenesisStorage
 	cvd       module.CommitVoteSetDecoder
 	gsBytes   []byte
+	retention *module.RetentionPolicy
 
 	mu    sync.Mutex
 	bwMap map[string]module.BaseWallet
@@ -114,7 +116,15 @@ func (c *Chain) NephewsLimit() int {
 }
 
 func (c *Chain) ValidateTxOnSend() bool {
-	panic("implement me")
+	return false
+}
+
+func (c *Chain) ConsensusTimeouts() *module.ConsensusTimeouts {
+	return nil
+}
+
+func (c *Chain) RetentionPolicy() *module.RetentionPolicy {
+	return c.retention
 }
 
 var defaultGenesis = "{\n  \"accounts\": [\n    {\n      \"name\": \"god\",\n      \"address\": \"hx54f7853dc6481b670caf69c5a27c7c8fe5be8269\",\n      \"balance\": \"0x2961fff8ca4a62327800000\"\n    },\n    {\n      \"name\": \"treasury\",\n      \"address\": \"hx1000000000000000000000000000000000000000\",\n      \"balance\": \"0x0\"\n    }\n  ],\n  \"message\": \"A rhizome has no beginning or end; it is always in the middle, between things, interbeing, intermezzo. The tree is filiation, but the rhizome is alliance, uniquely alliance. The tree imposes the verb \\\"to be\\\" but the fabric of the rhizome is the conjunction, \\\"and ... and ...and...\\\"This conjunction carries enough force to shake and uproot the verb \\\"to be.\\\" Where are you going? Where are you coming from? What are you heading for? These are totally useless questions.\\n\\n - Mille Plateaux, Gilles Deleuze & Felix Guattari\\n\\n\\\"Hyperconnect the world\\\"\"\n}\n"
@@ -219,7 +229,7 @@ func (c *Chain) Verify() error {
 }
 
 func (c *Chain) MetricContext() context.Context {
-	return nil
+	return metric.GetMetricContextByCID(c.CID())
 }
 
 func (c *Chain) Logger() log.Logger {
@@ -95,12 +95,12 @@ func (cl *Clock) PassTime(d time.Duration) {
 }
 
 func (cl *Clock) SetTime(t time.Time) {
-	var timers []*afterFuncTimer
+	var fs []func()
 
 	cl.Lock()
 	defer func() {
-		for _, tm := range timers {
-			tm.f()
+		for _, f := range fs {
+			f()
 		}
 	}()
 	defer cl.Unlock()
@@ -116,7 +116,8 @@ func (cl *Clock) SetTime(t time.Time) {
 			cl.afterFuncTimers[i] = cl.afterFuncTimers[last]
 			cl.afterFuncTimers[last] = nil
 			cl.afterFuncTimers = cl.afterFuncTimers[:last]
-			timers = append(timers, tm)
+			fs = append(fs, tm.f)
+			tm.f = nil
 			continue
 		}
 		i++
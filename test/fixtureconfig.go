@@ -49,6 +49,7 @@ type FixtureConfig struct {
 	Wallet            module.Wallet
 	AddDefaultNode    *bool
 	WAL               func() consensus.WALManager
+	RetentionPolicy   *module.RetentionPolicy
 }
 
 func NewFixtureConfig(t T, o ...FixtureOption) *FixtureConfig {
@@ -151,5 +152,8 @@ func (cf *FixtureConfig) Override(cf2 *FixtureConfig) *FixtureConfig {
 	if cf2.WAL != nil {
 		res.WAL = cf2.WAL
 	}
+	if cf2.RetentionPolicy != nil {
+		res.RetentionPolicy = cf2.RetentionPolicy
+	}
 	return &res
 }
@@ -69,6 +69,10 @@ func UseWallet(w module.Wallet) FixtureOption {
 	return UseConfig(&FixtureConfig{Wallet: w})
 }
 
+func UseRetentionPolicy(p *module.RetentionPolicy) FixtureOption {
+	return UseConfig(&FixtureConfig{RetentionPolicy: p})
+}
+
 func AddDefaultNode(v bool) FixtureOption {
 	return UseConfig(&FixtureConfig{AddDefaultNode: &v})
 }
@@ -84,3 +88,7 @@ func SetTimeoutPropose(to time.Duration) FixtureOption {
 func UseSMFactory(f func(ctx *NodeContext) module.ServiceManager) FixtureOption {
 	return UseConfig(&FixtureConfig{NewSM: f})
 }
+
+func UseCSFactory(f func(ctx *NodeContext) module.Consensus) FixtureOption {
+	return UseConfig(&FixtureConfig{NewCS: f})
+}
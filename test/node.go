@@ -74,6 +74,10 @@ func NewNode(t T, o ...FixtureOption) *Node {
 	}
 	c, err := NewChain(t, w, dbase, logger, cf.CVSD, cf.Genesis)
 	assert.NoError(t, err)
+	if cf.GenesisStorage != nil {
+		c.gs = cf.GenesisStorage
+	}
+	c.retention = cf.RetentionPolicy
 	c.Logger().SetLevel(log.TraceLevel)
 
 	// set up sm
@@ -53,6 +53,7 @@ type ServiceManager struct {
 	nextBlockVersion int
 	pool             []module.Transaction
 	txWaiters        []func()
+	dsReports        []*DoubleSignReport
 }
 
 func NewServiceManager(
@@ -222,6 +223,19 @@ func (sm *ServiceManager) GetMinimizeBlockGen(result []byte) bool {
 	return scoredb.NewVarDB(as, state.VarMinimizeBlockGen).Bool()
 }
 
+func (sm *ServiceManager) GetConsensusTimeouts(result []byte) *module.ConsensusTimeouts {
+	ws, err := service.NewWorldSnapshot(sm.dbase, sm.plt, result, nil)
+	if err != nil {
+		return nil
+	}
+	ass := ws.GetAccountSnapshot(state.SystemID)
+	as := scoredb.NewStateStoreWith(ass)
+	if as == nil {
+		return nil
+	}
+	return state.GetConsensusTimeouts(as)
+}
+
 func (sm *ServiceManager) GetNextBlockVersion(result []byte) int {
 	if result == nil {
 		return sm.plt.DefaultBlockVersionFor(sm.chain.CID())
@@ -268,7 +282,16 @@ func (sm *ServiceManager) getSystemByteStoreState(result []byte) (containerdb.By
 }
 
 func (sm *ServiceManager) ImportResult(result []byte, vh []byte, src db.Database) error {
-	panic("implement me")
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return err
+	}
+	e := merkle.NewCopyContext(src, sm.dbase)
+	txresult.NewReceiptListWithBuilder(e.Builder(), r.NormalReceiptHash)
+	txresult.NewReceiptListWithBuilder(e.Builder(), r.PatchReceiptHash)
+	es := sm.plt.NewExtensionWithBuilder(e.Builder(), r.ExtensionData)
+	state.NewWorldSnapshotWithBuilder(e.Builder(), r.StateHash, vh, es, r.BTPData)
+	return e.Run()
 }
 
 func (sm *ServiceManager) GenesisTransactionFromBytes(b []byte, blockVersion int) (module.Transaction, error) {
@@ -388,6 +411,17 @@ func (sm *ServiceManager) ExportResult(result []byte, vh []byte, dst db.Database
 	return e.Run()
 }
 
+func (sm *ServiceManager) ExportState(result []byte, vh []byte, dst db.Database) error {
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return err
+	}
+	e := merkle.PrepareCopyContext(sm.dbase, dst)
+	ess := sm.plt.NewExtensionWithBuilder(e.Builder(), r.ExtensionData)
+	state.NewWorldSnapshotWithBuilder(e.Builder(), r.StateHash, vh, ess, r.BTPData)
+	return e.Run()
+}
+
 func (sm *ServiceManager) BTPDigestFromResult(result []byte) (module.BTPDigest, error) {
 	dh, err := service.BTPDigestHashFromResult(result)
 	if err != nil {
@@ -468,3 +502,26 @@ func (sm *ServiceManager) NextProofContextMapFromResult(result []byte) (module.B
 	btpContext := state.NewBTPContext(nil, sbss)
 	return btp.NewProofContextMap(btpContext)
 }
+
+// DoubleSignReport is a report sent by SendDoubleSignReport.
+type DoubleSignReport struct {
+	Result []byte
+	VH     []byte
+	Data   []module.DoubleSignData
+}
+
+func (sm *ServiceManager) SendDoubleSignReport(result []byte, vh []byte, data []module.DoubleSignData) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.dsReports = append(sm.dsReports, &DoubleSignReport{result, vh, data})
+	return nil
+}
+
+// DoubleSignReports returns double sign reports sent so far.
+func (sm *ServiceManager) DoubleSignReports() []*DoubleSignReport {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return append([]*DoubleSignReport(nil), sm.dsReports...)
+}
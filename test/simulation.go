@@ -0,0 +1,761 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/test/clock"
+)
+
+const (
+	defaultSimulationTick      = 10 * time.Millisecond
+	defaultSimulationIdleLimit = 10 * time.Second
+)
+
+// LinkConfig describes how packets are transmitted over a link. A packet is
+// delivered after Delay plus a random duration less than Jitter, so Jitter
+// also reorders packets. A packet is dropped with probability DropRate.
+// Packets are delivered on ticks of the clock, so the minimum latency is a
+// tick.
+type LinkConfig struct {
+	Delay    time.Duration
+	Jitter   time.Duration
+	DropRate float64
+}
+
+// Behavior is the behavior of a validator in a Simulation.
+type Behavior int
+
+const (
+	// BehaviorHonest follows the protocol.
+	BehaviorHonest Behavior = iota
+	// BehaviorDoubleVote sends a conflicting vote after each vote.
+	BehaviorDoubleVote
+	// BehaviorEquivocateProposal sends a conflicting proposal to the
+	// peers with odd index before the proposal.
+	BehaviorEquivocateProposal
+)
+
+// SimDoubleSignReport is a DoubleSignReport sent by the node at Reporter.
+type SimDoubleSignReport struct {
+	Reporter int
+	*DoubleSignReport
+}
+
+type SimulationOption func(s *Simulation)
+
+// UseSeed sets the seed deciding fates of packets. Fate of a packet is
+// determined by the seed, the link and the content of the packet.
+func UseSeed(seed int64) SimulationOption {
+	return func(s *Simulation) {
+		s.seed = seed
+	}
+}
+
+// UseTick sets the amount of time passed on each step of the simulation.
+func UseTick(tick time.Duration) SimulationOption {
+	return func(s *Simulation) {
+		s.tick = tick
+	}
+}
+
+// UseIdleLimit sets the real time limit to wait for block operations of
+// nodes to be done on each step of the simulation. It prevents a broken
+// node from blocking the test forever.
+func UseIdleLimit(limit time.Duration) SimulationOption {
+	return func(s *Simulation) {
+		s.idleLimit = limit
+	}
+}
+
+// UseDefaultLink sets the configuration of links without specific one.
+func UseDefaultLink(lc LinkConfig) SimulationOption {
+	return func(s *Simulation) {
+		s.defLink = lc
+	}
+}
+
+// UseFixtureOptions adds options used to create the fixture of nodes.
+func UseFixtureOptions(o ...FixtureOption) SimulationOption {
+	return func(s *Simulation) {
+		s.fixtureOptions = append(s.fixtureOptions, o...)
+	}
+}
+
+// Simulation runs validator nodes connected by an in-memory network with
+// a controllable clock. Consensus timers and network latencies are driven
+// by Clock, and faults of the network and validators can be injected.
+//
+// On each step, expired timers fire and due packets are handed to reactors
+// of receivers one by one in the order of their delivery time. After each
+// event, it waits for block operations (propose and import) started by
+// nodes to be done, so that nodes see the same sequence of events on every
+// run. Only the syncer of consensus sends messages on its own goroutines,
+// and those messages are delivered from the next step.
+type Simulation struct {
+	T       T
+	Fixture *Fixture
+	Nodes   []*Node
+	Clock   *clock.Clock
+
+	seed           int64
+	tick           time.Duration
+	idleLimit      time.Duration
+	fixtureOptions []FixtureOption
+
+	mu        sync.Mutex
+	defLink   LinkConfig
+	links     map[[2]int]LinkConfig
+	groups    []int
+	crashed   []bool
+	behaviors []Behavior
+	sent      map[string]int
+	conflicts map[string]*Packet
+	inFlight  []*simPacket
+	seq       int
+	dropped   int
+	delivered int
+
+	pending int
+	idle    chan struct{}
+}
+
+// simPacket is a packet in flight to be delivered at the time.
+type simPacket struct {
+	at       time.Time
+	seq      int
+	from, to int
+	pk       *Packet
+	cb       func(bool, error)
+}
+
+type simLink struct {
+	s        *Simulation
+	from, to int
+}
+
+func (l *simLink) ID() module.PeerID {
+	return l.s.Nodes[l.to].NM.ID()
+}
+
+func (l *simLink) attach(p Peer) {
+	// do nothing
+}
+
+func (l *simLink) detach(p Peer) {
+	// do nothing
+}
+
+func (l *simLink) notifyPacket(pk *Packet, cb func(rebroadcast bool, err error)) {
+	l.s.transmit(l.from, l.to, pk, cb)
+}
+
+// NewSimulation creates n validator nodes connected with each other. The
+// nodes are not started until Start is called.
+func NewSimulation(t T, n int, o ...SimulationOption) *Simulation {
+	s := &Simulation{
+		T:         t,
+		Clock:     &clock.Clock{},
+		seed:      1,
+		tick:      defaultSimulationTick,
+		idleLimit: defaultSimulationIdleLimit,
+		links:     make(map[[2]int]LinkConfig),
+		groups:    make([]int, n),
+		crashed:   make([]bool, n),
+		behaviors: make([]Behavior, n),
+		sent:      make(map[string]int),
+		conflicts: make(map[string]*Packet),
+	}
+	for _, op := range o {
+		op(s)
+	}
+	s.Clock.SetTime(time.Now().Truncate(time.Second))
+
+	fo := []FixtureOption{
+		AddDefaultNode(false),
+		AddValidatorNodes(n),
+		UseBMFactory(s.newBlockManager),
+		UseCSFactory(s.newConsensus),
+	}
+	s.Fixture = NewFixture(t, append(fo, s.fixtureOptions...)...)
+	s.Nodes = s.Fixture.Nodes
+	for i, node := range s.Nodes {
+		for j := range s.Nodes {
+			if i != j {
+				node.NM.attach(&simLink{s, i, j})
+			}
+		}
+	}
+	return s
+}
+
+func (s *Simulation) newConsensus(ctx *NodeContext) module.Consensus {
+	wal := path.Join(ctx.Base, "wal")
+	cs := consensus.New(
+		ctx.C, wal, ctx.Config.WAL(), nil, nil, nil, ctx.Config.TimeoutPropose,
+	)
+	cs.SetClock(s.Clock)
+	return cs
+}
+
+func (s *Simulation) newBlockManager(ctx *NodeContext) module.BlockManager {
+	bm, err := block.NewManager(ctx.C, nil, nil)
+	assert.NoError(ctx.Config.T, err)
+	return &simBlockManager{bm, s}
+}
+
+// simBlockManager tracks asynchronous operations of the block manager, so
+// that the simulation can wait for them to be done.
+type simBlockManager struct {
+	module.BlockManager
+	s *Simulation
+}
+
+type simCanceler struct {
+	module.Canceler
+	done func()
+}
+
+func (c *simCanceler) Cancel() bool {
+	if c.Canceler.Cancel() {
+		c.done()
+		return true
+	}
+	return false
+}
+
+// track returns the callback ending the operation after cb, and the
+// function ending the operation without a callback.
+func (bm *simBlockManager) track(cb func(module.BlockCandidate, error)) (func(module.BlockCandidate, error), func()) {
+	bm.s.beginOperation()
+	var once sync.Once
+	done := func() {
+		once.Do(bm.s.endOperation)
+	}
+	return func(bc module.BlockCandidate, err error) {
+		defer done()
+		cb(bc, err)
+	}, done
+}
+
+func (bm *simBlockManager) trackCanceler(c module.Canceler, err error, done func()) (module.Canceler, error) {
+	if err != nil {
+		done()
+		return c, err
+	}
+	return &simCanceler{c, done}, nil
+}
+
+func (bm *simBlockManager) Propose(parentID []byte, votes module.CommitVoteSet, cb func(module.BlockCandidate, error)) (module.Canceler, error) {
+	cb, done := bm.track(cb)
+	c, err := bm.BlockManager.Propose(parentID, votes, cb)
+	return bm.trackCanceler(c, err, done)
+}
+
+func (bm *simBlockManager) Import(r io.Reader, flags int, cb func(module.BlockCandidate, error)) (module.Canceler, error) {
+	cb, done := bm.track(cb)
+	c, err := bm.BlockManager.Import(r, flags, cb)
+	return bm.trackCanceler(c, err, done)
+}
+
+func (bm *simBlockManager) ImportBlock(blk module.BlockData, flags int, cb func(module.BlockCandidate, error)) (module.Canceler, error) {
+	cb, done := bm.track(cb)
+	c, err := bm.BlockManager.ImportBlock(blk, flags, cb)
+	return bm.trackCanceler(c, err, done)
+}
+
+func (s *Simulation) beginOperation() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending++
+}
+
+func (s *Simulation) endOperation() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending--
+	if s.pending == 0 && s.idle != nil {
+		close(s.idle)
+		s.idle = nil
+	}
+}
+
+// waitIdle waits for block operations of nodes to be done.
+func (s *Simulation) waitIdle() {
+	s.mu.Lock()
+	if s.pending == 0 {
+		s.mu.Unlock()
+		return
+	}
+	if s.idle == nil {
+		s.idle = make(chan struct{})
+	}
+	idle := s.idle
+	s.mu.Unlock()
+
+	select {
+	case <-idle:
+	case <-time.After(s.idleLimit):
+		s.T.Errorf("block operations are not done in %v", s.idleLimit)
+	}
+}
+
+// Start starts consensus of all nodes.
+func (s *Simulation) Start() {
+	for _, n := range s.Nodes {
+		assert.NoError(s.T, n.CS.Start())
+	}
+}
+
+func (s *Simulation) Close() {
+	s.Fixture.Close()
+}
+
+// SetLink sets the configuration of the link from a node to another.
+func (s *Simulation) SetLink(from, to int, lc LinkConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.links[[2]int{from, to}] = lc
+}
+
+// SetDefaultLink sets the configuration of links without specific one.
+func (s *Simulation) SetDefaultLink(lc LinkConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defLink = lc
+}
+
+// Partition splits nodes into groups. Packets between nodes in different
+// groups are dropped including ones in flight. Nodes not in any group
+// belong to another group.
+func (s *Simulation) Partition(groups ...[]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.groups {
+		s.groups[i] = 0
+	}
+	for g, nodes := range groups {
+		for _, i := range nodes {
+			s.groups[i] = g + 1
+		}
+	}
+}
+
+// Heal removes the partition.
+func (s *Simulation) Heal() {
+	s.Partition()
+}
+
+// Crash makes the node unreachable, which is indistinguishable from a crash
+// for others. Its consensus keeps running, but it's excluded from liveness
+// checks until Recover is called.
+func (s *Simulation) Crash(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.crashed[i] = true
+}
+
+// Recover makes the crashed node reachable again. It catches up by syncing
+// with others.
+func (s *Simulation) Recover(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.crashed[i] = false
+}
+
+// SetBehavior sets the behavior of the validator.
+func (s *Simulation) SetBehavior(i int, b Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.behaviors[i] = b
+}
+
+// Stats returns the number of delivered and dropped packets.
+func (s *Simulation) Stats() (delivered int, dropped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.delivered, s.dropped
+}
+
+func (s *Simulation) reachableInLock(from, to int) bool {
+	return !s.crashed[from] && !s.crashed[to] && s.groups[from] == s.groups[to]
+}
+
+func (s *Simulation) linkInLock(from, to int) LinkConfig {
+	if lc, ok := s.links[[2]int{from, to}]; ok {
+		return lc
+	}
+	return s.defLink
+}
+
+func toUnitFloat(bs []byte) float64 {
+	return float64(binary.BigEndian.Uint64(bs)>>11) / float64(1<<53)
+}
+
+// fateInLock returns whether the packet is dropped and its latency. The
+// n-th transmission of the same packet over the same link always has the
+// same fate for the seed.
+func (s *Simulation) fateInLock(from, to int, pk *Packet) (bool, time.Duration) {
+	lc := s.linkInLock(from, to)
+	key := codec.MustMarshalToBytes([]interface{}{
+		s.seed, from, to, uint16(pk.MPI), uint16(pk.PI), pk.Data,
+	})
+	ks := string(crypto.SHA3Sum256(key))
+	cnt := s.sent[ks]
+	s.sent[ks] = cnt + 1
+	h := crypto.SHA3Sum256(codec.MustMarshalToBytes([]interface{}{[]byte(ks), cnt}))
+
+	if lc.DropRate > 0 && toUnitFloat(h[0:8]) < lc.DropRate {
+		return true, 0
+	}
+	delay := lc.Delay
+	if lc.Jitter > 0 {
+		delay += time.Duration(toUnitFloat(h[8:16]) * float64(lc.Jitter))
+	}
+	return false, delay
+}
+
+func (s *Simulation) transmit(from, to int, pk *Packet, cb func(bool, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.packetsToSendInLock(from, to, pk) {
+		if !s.reachableInLock(from, to) {
+			s.dropped++
+			continue
+		}
+		drop, delay := s.fateInLock(from, to, p)
+		if drop {
+			s.dropped++
+			continue
+		}
+		s.seq++
+		s.inFlight = append(s.inFlight, &simPacket{
+			at:   s.Clock.Now().Add(delay),
+			seq:  s.seq,
+			from: from,
+			to:   to,
+			pk:   p,
+			cb:   cb,
+		})
+	}
+}
+
+// duePackets removes packets to be delivered by now from packets in flight,
+// and returns them in the order of delivery.
+func (s *Simulation) duePackets() []*simPacket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Clock.Now()
+	var due, rest []*simPacket
+	for _, sp := range s.inFlight {
+		if sp.at.After(now) {
+			rest = append(rest, sp)
+		} else {
+			due = append(due, sp)
+		}
+	}
+	s.inFlight = rest
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].at.Equal(due[j].at) {
+			return due[i].at.Before(due[j].at)
+		}
+		return due[i].seq < due[j].seq
+	})
+	return due
+}
+
+// deliver hands the packet to the reactor of the receiver if it's still
+// reachable.
+func (s *Simulation) deliver(sp *simPacket) {
+	s.mu.Lock()
+	ok := s.reachableInLock(sp.from, sp.to)
+	if ok {
+		s.delivered++
+	} else {
+		s.dropped++
+	}
+	s.mu.Unlock()
+	if ok {
+		s.Nodes[sp.to].NM.handlePacket(sp.pk, sp.cb)
+	}
+}
+
+// packetsToSendInLock returns packets to be sent instead of pk according to
+// the behavior of the sender.
+func (s *Simulation) packetsToSendInLock(from, to int, pk *Packet) []*Packet {
+	if pk.MPI != module.ProtoConsensus {
+		return []*Packet{pk}
+	}
+	switch s.behaviors[from] {
+	case BehaviorDoubleVote:
+		if pk.PI == consensus.ProtoVote {
+			if cpk := s.conflictOfInLock(from, pk); cpk != nil {
+				return []*Packet{pk, cpk}
+			}
+		}
+	case BehaviorEquivocateProposal:
+		if pk.PI == consensus.ProtoProposal && to%2 == 1 {
+			if cpk := s.conflictOfInLock(from, pk); cpk != nil {
+				return []*Packet{cpk, pk}
+			}
+		}
+	}
+	return []*Packet{pk}
+}
+
+// conflictOfInLock returns a packet having a message conflicting with the
+// message in pk, signed by the node at index i.
+func (s *Simulation) conflictOfInLock(i int, pk *Packet) *Packet {
+	if cpk, ok := s.conflicts[string(pk.Data)]; ok {
+		return cpk
+	}
+	msg, err := consensus.UnmarshalMessage(uint16(pk.PI), pk.Data)
+	if err != nil {
+		log.Warnf("fail to decode message for conflict err=%+v", err)
+		return nil
+	}
+	node := s.Nodes[i]
+	w := node.Chain.Wallet()
+	var data []byte
+	switch m := msg.(type) {
+	case *consensus.VoteMessage:
+		var vote *consensus.VoteMessage
+		if m.BlockPartSetIDAndNTSVoteCount != nil {
+			vote = consensus.NewVoteMessage(
+				w, m.Type, m.Height, m.Round,
+				codec.MustMarshalToBytes(node.Chain.NID()), nil,
+				m.Timestamp, nil, nil, 0,
+			)
+		} else {
+			id := crypto.SHA3Sum256(pk.Data)
+			vote = consensus.NewVoteMessage(
+				w, m.Type, m.Height, m.Round,
+				id, &consensus.PartSetID{Count: 1, Hash: id},
+				m.Timestamp, nil, nil, 0,
+			)
+		}
+		data = codec.MustMarshalToBytes(vote)
+	case *consensus.ProposalMessage:
+		m.BlockPartSetID = &consensus.PartSetID{
+			Count: m.BlockPartSetID.Count,
+			Hash:  crypto.SHA3Sum256(m.BlockPartSetID.Hash),
+		}
+		if err := m.Sign(w); err != nil {
+			log.Warnf("fail to sign conflicting proposal err=%+v", err)
+			return nil
+		}
+		data = codec.MustMarshalToBytes(m)
+	default:
+		return nil
+	}
+	cpk := &Packet{
+		SendType: pk.SendType,
+		Src:      pk.Src,
+		DstSpec:  pk.DstSpec,
+		MPI:      pk.MPI,
+		PI:       pk.PI,
+		Data:     data,
+	}
+	s.conflicts[string(pk.Data)] = cpk
+	return cpk
+}
+
+// Step passes a tick, and then delivers packets due by then. It waits for
+// nodes to handle each event before the next one.
+func (s *Simulation) Step() {
+	s.Clock.PassTime(s.tick)
+	s.waitIdle()
+	for _, sp := range s.duePackets() {
+		s.deliver(sp)
+		s.waitIdle()
+	}
+}
+
+// Run runs the simulation for d.
+func (s *Simulation) Run(d time.Duration) {
+	end := s.Clock.Now().Add(d)
+	for s.Clock.Now().Before(end) {
+		s.Step()
+	}
+}
+
+// RunUntil runs the simulation until cond returns true. It returns false
+// if cond doesn't become true in limit.
+func (s *Simulation) RunUntil(cond func() bool, limit time.Duration) bool {
+	end := s.Clock.Now().Add(limit)
+	for !cond() {
+		if !s.Clock.Now().Before(end) {
+			return false
+		}
+		s.Step()
+	}
+	return true
+}
+
+// Height returns the height of the last block of the node.
+func (s *Simulation) Height(i int) int64 {
+	blk, err := s.Nodes[i].BM.GetLastBlock()
+	if err != nil {
+		return -1
+	}
+	return blk.Height()
+}
+
+// MinHeight returns the minimum height of the last blocks of nodes which
+// are not crashed.
+func (s *Simulation) MinHeight() int64 {
+	s.mu.Lock()
+	crashed := append([]bool(nil), s.crashed...)
+	s.mu.Unlock()
+
+	min := int64(-1)
+	for i := range s.Nodes {
+		if crashed[i] {
+			continue
+		}
+		if h := s.Height(i); min < 0 || h < min {
+			min = h
+		}
+	}
+	return min
+}
+
+// RunUntilHeight runs the simulation until nodes which are not crashed
+// finalize the block at the height.
+func (s *Simulation) RunUntilHeight(h int64, limit time.Duration) bool {
+	return s.RunUntil(func() bool {
+		return s.MinHeight() >= h
+	}, limit)
+}
+
+// CheckSafety returns an error if two nodes finalized different blocks at
+// the same height.
+func (s *Simulation) CheckSafety() error {
+	var max int64
+	for i := range s.Nodes {
+		if h := s.Height(i); h > max {
+			max = h
+		}
+	}
+	for h := int64(1); h <= max; h++ {
+		var id []byte
+		var owner int
+		for i, n := range s.Nodes {
+			blk, err := n.BM.GetBlockByHeight(h)
+			if err != nil {
+				continue
+			}
+			if id == nil {
+				id, owner = blk.ID(), i
+			} else if !bytes.Equal(id, blk.ID()) {
+				return errors.InvalidStateError.Errorf(
+					"ConflictingBlocks(height=%d,node%d=%x,node%d=%x)",
+					h, owner, id, i, blk.ID())
+			}
+		}
+	}
+	return nil
+}
+
+// AssertSafety asserts that no two nodes finalized different blocks at the
+// same height.
+func (s *Simulation) AssertSafety() bool {
+	return assert.NoError(s.T, s.CheckSafety())
+}
+
+// AssertLiveness asserts that nodes which are not crashed finalize the
+// block at the height in limit.
+func (s *Simulation) AssertLiveness(h int64, limit time.Duration) bool {
+	if s.RunUntilHeight(h, limit) {
+		return true
+	}
+	heights := make([]int64, len(s.Nodes))
+	for i := range s.Nodes {
+		heights[i] = s.Height(i)
+	}
+	s.T.Errorf("no liveness height=%d limit=%v heights=%v", h, limit, heights)
+	return false
+}
+
+// DoubleSignReports returns double sign reports sent by nodes using
+// ServiceManager of this package. Reports on messages signed by the reporter
+// are excluded. A byzantine node may report itself when its conflicting
+// messages are relayed back to it.
+func (s *Simulation) DoubleSignReports() []*SimDoubleSignReport {
+	var reports []*SimDoubleSignReport
+	for i, n := range s.Nodes {
+		sm, ok := n.SM.(interface {
+			DoubleSignReports() []*DoubleSignReport
+		})
+		if !ok {
+			continue
+		}
+		for _, r := range sm.DoubleSignReports() {
+			if s.isReportOnSelf(i, r) {
+				continue
+			}
+			reports = append(reports, &SimDoubleSignReport{i, r})
+		}
+	}
+	return reports
+}
+
+func (s *Simulation) isReportOnSelf(i int, r *DoubleSignReport) bool {
+	for _, d := range r.Data {
+		if s.IndexOfSigner(d.Signer()) != i {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexOfSigner returns the index of the node having the signer or -1.
+func (s *Simulation) IndexOfSigner(signer []byte) int {
+	for i, n := range s.Nodes {
+		if bytes.Equal(n.Address().ID(), signer) {
+			return i
+		}
+	}
+	return -1
+}